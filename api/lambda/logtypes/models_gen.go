@@ -36,6 +36,14 @@ type LogTypesAPI interface {
 
 	ListCustomLogs() (ListCustomLogsResponse, error)
 
+	ListCustomLogRevisions(input ListCustomLogRevisionsInput) (ListCustomLogRevisionsResponse, error)
+
+	RevertCustomLog(input RevertCustomLogInput) (RevertCustomLogResponse, error)
+
+	InferCustomLogSchema(input InferCustomLogSchemaInput) (InferCustomLogSchemaResponse, error)
+
+	TestSchema(input TestSchemaInput) (TestSchemaResponse, error)
+
 	ListManagedSchemaUpdates(input ListManagedSchemaUpdatesInput) (ListManagedSchemaUpdatesResponse, error)
 
 	UpdateManagedSchemas(input UpdateManagedSchemasInput) (UpdateManagedSchemasResponse, error)
@@ -53,6 +61,10 @@ type LogTypesAPIPayload struct {
 	PutCustomLog             *PutCustomLogInput
 	DelCustomLog             *DelCustomLogInput
 	ListCustomLogs           *struct{}
+	ListCustomLogRevisions   *ListCustomLogRevisionsInput
+	RevertCustomLog          *RevertCustomLogInput
+	InferCustomLogSchema     *InferCustomLogSchemaInput
+	TestSchema               *TestSchemaInput
 	ListManagedSchemaUpdates *ListManagedSchemaUpdatesInput
 	UpdateManagedSchemas     *UpdateManagedSchemasInput
 	GetSchema                *GetSchemaInput
@@ -118,10 +130,58 @@ type GetSchemaResponse struct {
 	} `json:"error,omitempty" description:"An error that occurred while fetching the record"`
 }
 
+type InferCustomLogSchemaInput struct {
+	S3Bucket   string `json:"s3Bucket,omitempty" validate:"required_without=Sample" description:"The S3 bucket to sample log files from (must belong to an S3 source)"`
+	S3Prefix   string `json:"s3Prefix,omitempty" description:"The S3 prefix to sample log files from"`
+	MaxObjects int    `json:"maxObjects,omitempty" validate:"omitempty,min=1,max=100" description:"Max number of S3 objects to sample (defaults to 10)"`
+	MaxEvents  int    `json:"maxEvents,omitempty" validate:"omitempty,min=1,max=10000" description:"Max number of log entries to sample (defaults to 1000)"`
+	Sample     string `json:"sample,omitempty" validate:"required_without=S3Bucket" description:"Sample log entries to use instead of S3 objects"`
+	Parser     string `json:"parser,omitempty" description:"The parser configuration (csv, regex, fastmatch, ...) in YAML or JSON format (omit for JSON logs)"`
+}
+
+type InferCustomLogSchemaResponse struct {
+	Spec       string `json:"logSpec,omitempty" description:"The inferred schema spec in YAML format (field is omitted if an error occurred)"`
+	NumObjects int    `json:"numObjects,omitempty" description:"The number of S3 objects that were sampled"`
+	NumEvents  int    `json:"numEvents,omitempty" description:"The number of log entries that were sampled"`
+	Error      struct {
+		Code    string `json:"code" validate:"required"`
+		Message string `json:"message" validate:"required"`
+	} `json:"error,omitempty" description:"An error that occurred during the operation"`
+}
+
 type ListAvailableLogTypesResponse struct {
 	LogTypes []string `json:"logTypes"`
 }
 
+type ListCustomLogRevisionsInput struct {
+	LogType string `json:"logType" validate:"required,startswith=Custom." description:"The log type id"`
+}
+
+type ListCustomLogRevisionsResponse struct {
+	Revisions []struct {
+		Record struct {
+			Name         string    `json:"logType" dynamodbav:"logType" validate:"required" description:"The schema id"`
+			Revision     int64     `json:"revision" validate:"required,min=1" description:"Schema record revision"`
+			Release      string    `json:"release,omitempty" description:"Managed schema release version"`
+			UpdatedAt    time.Time `json:"updatedAt" description:"Last update timestamp of the record"`
+			CreatedAt    time.Time `json:"createdAt" description:"Creation timestamp of the record"`
+			Managed      bool      `json:"managed,omitempty" description:"Schema is managed by Panther"`
+			Disabled     bool      `json:"disabled,omitempty" dynamodbav:"IsDeleted"  description:"Log record is deleted"`
+			Description  string    `json:"description" description:"Log type description"`
+			ReferenceURL string    `json:"referenceURL" description:"A URL with reference docs for the schema"`
+			Spec         string    `json:"logSpec" dynamodbav:"logSpec" validate:"required" description:"The schema spec in YAML or JSON format"`
+		} `json:"record" description:"The custom log record at this revision"`
+		Changes []struct {
+			Type string `json:"type" description:"The type of change"`
+			Path string `json:"path" description:"The path of the changed value"`
+		} `json:"changes,omitempty" description:"The schema changes from the previous revision"`
+	} `json:"revisions,omitempty" description:"The revisions of the custom log record from oldest to newest (field is omitted if an error occurred)"`
+	Error struct {
+		Code    string `json:"code" validate:"required"`
+		Message string `json:"message" validate:"required"`
+	} `json:"error,omitempty" description:"An error that occurred during the operation"`
+}
+
 type ListCustomLogsResponse struct {
 	Records []struct {
 		Name         string    `json:"logType" dynamodbav:"logType" validate:"required" description:"The schema id"`
@@ -190,6 +250,59 @@ type PutCustomLogResponse struct {
 	} `json:"error,omitempty" description:"An error that occurred during the operation"`
 }
 
+type RevertCustomLogInput struct {
+	LogType        string `json:"logType" validate:"required,startswith=Custom." description:"The log type id"`
+	Revision       int64  `json:"revision" validate:"required,min=1" description:"Current revision of the custom log record"`
+	TargetRevision int64  `json:"targetRevision" validate:"required,min=1" description:"The revision to revert the custom log record to"`
+}
+
+type RevertCustomLogResponse struct {
+	Result struct {
+		Name         string    `json:"logType" dynamodbav:"logType" validate:"required" description:"The schema id"`
+		Revision     int64     `json:"revision" validate:"required,min=1" description:"Schema record revision"`
+		Release      string    `json:"release,omitempty" description:"Managed schema release version"`
+		UpdatedAt    time.Time `json:"updatedAt" description:"Last update timestamp of the record"`
+		CreatedAt    time.Time `json:"createdAt" description:"Creation timestamp of the record"`
+		Managed      bool      `json:"managed,omitempty" description:"Schema is managed by Panther"`
+		Disabled     bool      `json:"disabled,omitempty" dynamodbav:"IsDeleted"  description:"Log record is deleted"`
+		Description  string    `json:"description" description:"Log type description"`
+		ReferenceURL string    `json:"referenceURL" description:"A URL with reference docs for the schema"`
+		Spec         string    `json:"logSpec" dynamodbav:"logSpec" validate:"required" description:"The schema spec in YAML or JSON format"`
+	} `json:"record,omitempty" description:"The new revision of the record (field is omitted if an error occurred)"`
+	Error struct {
+		Code    string `json:"code" validate:"required"`
+		Message string `json:"message" validate:"required"`
+	} `json:"error,omitempty" description:"An error that occurred during the operation"`
+}
+
+type TestSchemaInput struct {
+	LogType string `json:"logType,omitempty" validate:"omitempty,startswith=Custom." description:"The log type id to use for the parsed events (defaults to Custom.Test)"`
+	Spec    string `json:"logSpec" validate:"required" description:"The schema spec in YAML or JSON format"`
+	Sample  string `json:"sample" validate:"required" description:"Sample log entries to parse (up to 1000 entries are tested)"`
+}
+
+type TestSchemaResponse struct {
+	Results []struct {
+		Line   int      `json:"line" description:"The position of the log entry in the sample (starting from 1)"`
+		Log    string   `json:"log" description:"The log entry"`
+		Events []string `json:"events,omitempty" description:"The parsed events in JSON format including p_* fields"`
+		Error  string   `json:"error,omitempty" description:"The decode or validation error if the log entry could not be parsed"`
+	} `json:"results,omitempty" description:"The result of parsing each log entry (field is omitted if an error occurred)"`
+	Stats struct {
+		LogType                string `json:"logType" description:"The log type used to parse the log entries"`
+		ParserTimeMicroseconds uint64 `json:"parserTimeMicroseconds" description:"Total time spent parsing log entries"`
+		BytesProcessedCount    uint64 `json:"bytesProcessedCount" description:"Total size of the log entries"`
+		LogLineCount           uint64 `json:"logLineCount" description:"The number of log entries"`
+		EventCount             uint64 `json:"eventCount" description:"The number of parsed events"`
+		EventFilteredCount     uint64 `json:"eventFilteredCount" description:"The number of events dropped by event transforms"`
+		NumFailed              uint64 `json:"numFailed" description:"The number of log entries that failed to parse"`
+	} `json:"stats" description:"Aggregate parser stats for all log entries"`
+	Error struct {
+		Code    string `json:"code" validate:"required"`
+		Message string `json:"message" validate:"required"`
+	} `json:"error,omitempty" description:"An error that occurred during the operation"`
+}
+
 type UpdateManagedSchemasInput struct {
 	Release     string `json:"release" validate:"required" description:"The release of the schema"`
 	ManifestURL string `json:"manifestURL,omitempty" validate:"omitempty,url" description:"The URL to download the manifest archive from"`
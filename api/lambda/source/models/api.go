@@ -18,7 +18,12 @@ package models
  * along with this program.  If not, see <https://www.gnu.org/licenses/>.
  */
 
-import "time"
+import (
+	"time"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/sampling"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/transform"
+)
 
 // LambdaInput is the collection of all possible args to the Lambda function.
 type LambdaInput struct {
@@ -35,6 +40,7 @@ type LambdaInput struct {
 
 	UpdateIntegrationLastScanEnd   *UpdateIntegrationLastScanEndInput   `json:"updateIntegrationLastScanEnd"`
 	UpdateIntegrationLastScanStart *UpdateIntegrationLastScanStartInput `json:"updateIntegrationLastScanStart"`
+	UpdatePollerCheckpoint         *UpdatePollerCheckpointInput         `json:"updatePollerCheckpoint"`
 
 	FullScan     *FullScanInput     `json:"fullScan"`
 	UpdateStatus *UpdateStatusInput `json:"updateStatus"`
@@ -47,7 +53,7 @@ type LambdaInput struct {
 // CheckIntegrationInput is used to check the health of a potential configuration.
 type CheckIntegrationInput struct {
 	AWSAccountID     string `genericapi:"redact" json:"awsAccountId" validate:"omitempty,len=12,numeric"`
-	IntegrationType  string `json:"integrationType" validate:"oneof=aws-scan aws-s3 aws-sqs"`
+	IntegrationType  string `json:"integrationType" validate:"oneof=aws-scan aws-s3 aws-sqs http saas-poller"`
 	IntegrationLabel string `json:"integrationLabel" validate:"required,integrationLabel"`
 
 	// Checks for cloudsec integrations
@@ -61,6 +67,12 @@ type CheckIntegrationInput struct {
 
 	// Checks for Sqs configuration
 	SqsConfig *SqsConfig `json:"sqsConfig,omitempty"`
+
+	// Checks for HTTP configuration
+	HTTPConfig *HTTPConfig `json:"httpConfig,omitempty"`
+
+	// Checks for SaaS poller configuration
+	PollerConfig *PollerConfig `json:"pollerConfig,omitempty"`
 }
 
 //
@@ -75,7 +87,7 @@ type PutIntegrationInput struct {
 // PutIntegrationSettings are all the settings for the new integration.
 type PutIntegrationSettings struct {
 	IntegrationLabel           string           `json:"integrationLabel" validate:"required,integrationLabel,excludesall='<>&\""`
-	IntegrationType            string           `json:"integrationType" validate:"oneof=aws-scan aws-s3 aws-sqs"`
+	IntegrationType            string           `json:"integrationType" validate:"oneof=aws-scan aws-s3 aws-sqs http saas-poller"`
 	UserID                     string           `json:"userId" validate:"required,uuid4"`
 	AWSAccountID               string           `genericapi:"redact" json:"awsAccountId" validate:"omitempty,len=12,numeric"`
 	CWEEnabled                 *bool            `json:"cweEnabled"`
@@ -90,7 +102,12 @@ type PutIntegrationSettings struct {
 	KmsKey                     string           `json:"kmsKey" validate:"omitempty,kmsKeyArn"`
 	ManagedBucketNotifications bool             `json:"managedBucketNotifications"`
 
-	SqsConfig *SqsConfig `json:"sqsConfig,omitempty"`
+	SqsConfig    *SqsConfig    `json:"sqsConfig,omitempty"`
+	HTTPConfig   *HTTPConfig   `json:"httpConfig,omitempty"`
+	PollerConfig *PollerConfig `json:"pollerConfig,omitempty"`
+
+	Transforms []transform.Config `json:"transforms,omitempty" validate:"omitempty,dive"`
+	Sampling   []sampling.Config  `json:"sampling,omitempty" validate:"omitempty,dive"`
 }
 
 //
@@ -99,7 +116,7 @@ type PutIntegrationSettings struct {
 
 // ListIntegrationsInput allows filtering by the IntegrationType field
 type ListIntegrationsInput struct {
-	IntegrationType *string `json:"integrationType" validate:"omitempty,oneof=aws-scan aws-s3 aws-sqs"`
+	IntegrationType *string `json:"integrationType" validate:"omitempty,oneof=aws-scan aws-s3 aws-sqs http saas-poller"`
 }
 
 // UpdateIntegrationSettingsInput is used to update integration settings.
@@ -117,7 +134,14 @@ type UpdateIntegrationSettingsInput struct {
 	S3PrefixLogTypes        S3PrefixLogtypes `json:"s3PrefixLogTypes,omitempty" validate:"omitempty,min=1"`
 	KmsKey                  string           `json:"kmsKey" validate:"omitempty,kmsKeyArn"`
 
-	SqsConfig *SqsConfig `json:"sqsConfig,omitempty"`
+	SqsConfig    *SqsConfig    `json:"sqsConfig,omitempty"`
+	HTTPConfig   *HTTPConfig   `json:"httpConfig,omitempty"`
+	PollerConfig *PollerConfig `json:"pollerConfig,omitempty"`
+
+	// Transforms replace the transforms of the source, they are kept if nil. Use an empty list to remove them.
+	Transforms []transform.Config `json:"transforms" validate:"omitempty,dive"`
+	// Sampling replaces the sampling settings of the source, they are kept if nil. Use an empty list to remove them.
+	Sampling []sampling.Config `json:"sampling" validate:"omitempty,dive"`
 }
 
 // DeleteIntegrationInput is used to delete a specific item from the database.
@@ -187,6 +211,15 @@ type UpdateIntegrationLastScanEndInput struct {
 	LastScanErrorMessage string    `json:"lastScanErrorMessage"`
 }
 
+// UpdatePollerCheckpointInput is used to store the cursors of a SaaS poller source after each poll.
+type UpdatePollerCheckpointInput struct {
+	IntegrationID string `json:"integrationId" validate:"required,uuid4"`
+	// The position reached for each log type
+	Cursors       map[string]string `json:"cursors"`
+	LastPollTime  time.Time         `json:"lastPollTime" validate:"required"`
+	LastPollError string            `json:"lastPollError"`
+}
+
 // Updates the status of an integration
 // Sample request:
 // {
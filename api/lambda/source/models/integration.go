@@ -25,6 +25,9 @@ import (
 
 	"github.com/panther-labs/panther/internal/compliance/snapshotlogs"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/logstream"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/sampling"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/transform"
 	"github.com/panther-labs/panther/pkg/stringset"
 )
 
@@ -86,6 +89,16 @@ type SourceIntegrationMetadata struct {
 	StackName string `json:"stackName,omitempty"`
 
 	SqsConfig *SqsConfig `json:"sqsConfig,omitempty"`
+
+	HTTPConfig *HTTPConfig `json:"httpConfig,omitempty"`
+
+	PollerConfig *PollerConfig `json:"pollerConfig,omitempty"`
+
+	// Transforms filter and redact the events of the source before they are stored
+	Transforms []transform.Config `json:"transforms,omitempty"`
+
+	// Sampling limits the volume of the events of the source that are stored
+	Sampling []sampling.Config `json:"sampling,omitempty"`
 }
 
 type ManagedS3Resources struct {
@@ -102,6 +115,9 @@ type ManagedS3Resources struct {
 type S3PrefixLogtypesMapping struct {
 	S3Prefix string   `json:"prefix"`
 	LogTypes []string `json:"logTypes" validate:"required,min=1"`
+	// Framing reassembles log entries that span multiple lines.
+	// If not set, the framing required by the log types is used.
+	Framing *logstream.FramingConfig `json:"framing,omitempty"`
 }
 
 type S3PrefixLogtypes []S3PrefixLogtypesMapping
@@ -122,6 +138,19 @@ func (pl S3PrefixLogtypes) S3Prefixes() []string {
 	return prefixes
 }
 
+// ValidateFraming checks the framing configuration of all prefixes
+func (pl S3PrefixLogtypes) ValidateFraming() error {
+	for _, m := range pl {
+		if m.Framing == nil {
+			continue
+		}
+		if err := m.Framing.Validate(); err != nil {
+			return fmt.Errorf("invalid framing for prefix %q: %s", m.S3Prefix, err)
+		}
+	}
+	return nil
+}
+
 // Return the S3PrefixLogtypesMapping whose prefix is the longest one that matches the objectKey.
 func (pl S3PrefixLogtypes) LongestPrefixMatch(objectKey string) (bestMatch S3PrefixLogtypesMapping, matched bool) {
 	for _, m := range pl {
@@ -143,6 +172,10 @@ func (s *SourceIntegration) RequiredLogTypes() (logTypes []string) {
 		return s.S3PrefixLogTypes.LogTypes()
 	case IntegrationTypeSqs:
 		return s.SqsConfig.LogTypes
+	case IntegrationTypeHTTP:
+		return s.HTTPConfig.LogTypes
+	case IntegrationTypePoller:
+		return s.PollerConfig.LogTypes
 	default:
 		// should not be reached
 		panic(fmt.Sprintf("Could not determine logtypes for source {id:%s label:%s type:%s}",
@@ -152,10 +185,13 @@ func (s *SourceIntegration) RequiredLogTypes() (logTypes []string) {
 
 func (s *SourceIntegration) RequiredLogProcessingRole() string {
 	switch typ := s.IntegrationType; typ {
-	case IntegrationTypeAWS3, IntegrationTypeAWSScan:
+	case IntegrationTypeAWS3, IntegrationTypeAWSScan, IntegrationTypeHTTP:
 		return s.LogProcessingRole
 	case IntegrationTypeSqs:
 		return s.SqsConfig.LogProcessingRole
+	case IntegrationTypePoller:
+		// Poller sources have no S3 input data to read
+		return ""
 	default:
 		panic("Unknown type " + typ)
 	}
@@ -171,6 +207,12 @@ func (s *SourceIntegration) S3Info() (bucket string, prefixes []string) {
 		return s.S3Bucket, s.S3PrefixLogTypes.S3Prefixes()
 	case IntegrationTypeSqs:
 		return s.SqsConfig.S3Bucket, []string{"forwarder"}
+	case IntegrationTypeHTTP:
+		// The HTTP receiver forwards log entries to Firehose which partitions them by source id
+		return s.S3Bucket, []string{"http/" + s.IntegrationID}
+	case IntegrationTypePoller:
+		// Poller sources are processed by their own lambda, they have no input data in S3
+		return "", nil
 	default:
 		// should not be reached
 		panic(fmt.Sprintf("Could not determine s3 info for source {id:%s label:%s type:%s}",
@@ -193,6 +235,12 @@ type SourceIntegrationHealth struct {
 
 	// Checks for Sqs integrations
 	SqsStatus SourceIntegrationItemStatus `json:"sqsStatus"`
+
+	// Checks for HTTP integrations
+	HTTPStatus SourceIntegrationItemStatus `json:"httpStatus,omitempty"`
+
+	// Checks for SaaS poller integrations
+	PollerStatus SourceIntegrationItemStatus `json:"pollerStatus,omitempty"`
 }
 
 type SourceIntegrationItemStatus struct {
@@ -221,3 +269,69 @@ type SqsConfig struct {
 	// THe URL of the SQS queue
 	QueueURL string `json:"queueUrl"`
 }
+
+type HTTPConfig struct {
+	// The log types associated with the source. Needs to be set by UI.
+	LogTypes []string `json:"logTypes" validate:"required,min=1"`
+	// The method used to authenticate requests. Needs to be set by UI.
+	AuthMethod string `json:"authMethod" validate:"oneof=bearer hmac"`
+	// The bearer token or the HMAC secret key shared with the sender. Needs to be set by UI when creating the source.
+	// It is kept in Secrets Manager and never returned, leave it empty on updates to keep the current secret.
+	AuthSecret string `genericapi:"redact" json:"authSecret,omitempty" validate:"omitempty,min=16"`
+	// The request header holding the HMAC signature of the body. Defaults to HTTPDefaultSignatureHeader.
+	AuthHeader string `json:"authHeader,omitempty"`
+	// The hash function used for the HMAC signature (sha256 or sha1). Defaults to sha256.
+	AuthHashAlgorithm string `json:"authHashAlgorithm,omitempty" validate:"omitempty,oneof=sha256 sha1"`
+}
+
+type PollerConfig struct {
+	// The SaaS vendor to pull logs from. Needs to be set by UI.
+	Vendor string `json:"vendor" validate:"oneof=okta duo onelogin box gsuite slack"`
+	// The log types to pull, they need to be provided by the vendor. Needs to be set by UI.
+	LogTypes []string `json:"logTypes" validate:"required,min=1"`
+	// The base URL of the vendor API (ie https://example.okta.com). Defaults to the public API for Box, GSuite and Slack.
+	BaseURL string `json:"baseUrl,omitempty" validate:"omitempty,url,startswith=https://"`
+	// The client id, integration key or service account email used to authenticate. Not needed for token authentication.
+	ClientID string `json:"clientId,omitempty"`
+	// The API token, client secret or service account private key used to authenticate. Needs to be set by UI when creating the source.
+	// It is kept in Secrets Manager and never returned, leave it empty on updates to keep the current secret.
+	ClientSecret string `genericapi:"redact" json:"clientSecret,omitempty"`
+	// The user to impersonate (GSuite) or the enterprise id (Box) when required by the vendor.
+	Subject string `json:"subject,omitempty"`
+	// The minutes between polls. Defaults to PollerDefaultIntervalMins.
+	PollIntervalMins int `json:"pollIntervalMins,omitempty" validate:"omitempty,min=1,max=1440"`
+
+	// The position reached for each log type, set by the poller
+	Cursors map[string]string `json:"cursors,omitempty"`
+	// The time of the last poll, set by the poller
+	LastPollTime *time.Time `json:"lastPollTime,omitempty"`
+	// The error of the last poll, set by the poller
+	LastPollError string `json:"lastPollError,omitempty"`
+}
+
+// PollInterval returns the time between polls
+func (c *PollerConfig) PollInterval() time.Duration {
+	if c.PollIntervalMins <= 0 {
+		return PollerDefaultIntervalMins * time.Minute
+	}
+	return time.Duration(c.PollIntervalMins) * time.Minute
+}
+
+// PollDue checks if a poll should start
+func (c *PollerConfig) PollDue(now time.Time) bool {
+	return c.LastPollTime == nil || !now.Before(c.LastPollTime.Add(c.PollInterval()))
+}
+
+// ValidateLogTypes checks that the log types are provided by the vendor
+func (c *PollerConfig) ValidateLogTypes() error {
+	vendorLogTypes, ok := PollerVendorLogTypes[c.Vendor]
+	if !ok {
+		return fmt.Errorf("unsupported vendor %q", c.Vendor)
+	}
+	for _, logType := range c.LogTypes {
+		if !stringset.Contains(vendorLogTypes, logType) {
+			return fmt.Errorf("log type %q is not provided by %s", logType, c.Vendor)
+		}
+	}
+	return nil
+}
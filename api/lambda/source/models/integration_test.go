@@ -20,15 +20,18 @@ package models
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/logstream"
 )
 
 func TestS3PrefixLogtypes_LongestPrefixMatch(t *testing.T) {
 	pl := S3PrefixLogtypes{
-		{"prefixA/", []string{"Log.A"}},
-		{"prefixA/prefixB", []string{"Log.B"}},
-		{"", []string{"Log.C"}},
+		{S3Prefix: "prefixA/", LogTypes: []string{"Log.A"}},
+		{S3Prefix: "prefixA/prefixB", LogTypes: []string{"Log.B"}},
+		{S3Prefix: "", LogTypes: []string{"Log.C"}},
 	}
 
 	testcases := []struct {
@@ -50,8 +53,8 @@ func TestS3PrefixLogtypes_LongestPrefixMatch(t *testing.T) {
 
 func TestS3PrefixLogtypes_LongestPrefixMatch_ReturnNil(t *testing.T) {
 	pl := S3PrefixLogtypes{
-		{"prefixA/", []string{"Log.A"}},
-		{"prefixA/prefixB", []string{"Log.B"}},
+		{S3Prefix: "prefixA/", LogTypes: []string{"Log.A"}},
+		{S3Prefix: "prefixA/prefixB", LogTypes: []string{"Log.B"}},
 	}
 
 	_, matched := pl.LongestPrefixMatch("logs/log.json")
@@ -59,3 +62,41 @@ func TestS3PrefixLogtypes_LongestPrefixMatch_ReturnNil(t *testing.T) {
 	// No prefix matched
 	require.False(t, matched)
 }
+
+func TestS3PrefixLogtypes_ValidateFraming(t *testing.T) {
+	pl := S3PrefixLogtypes{
+		{S3Prefix: "prefixA/", LogTypes: []string{"Log.A"}},
+		{S3Prefix: "prefixB/", LogTypes: []string{"Log.B"}, Framing: &logstream.FramingConfig{ContinuationIndent: true}},
+	}
+	require.NoError(t, pl.ValidateFraming())
+	pl = append(pl, S3PrefixLogtypesMapping{
+		S3Prefix: "prefixC/",
+		LogTypes: []string{"Log.C"},
+		Framing:  &logstream.FramingConfig{StartRegex: "("},
+	})
+	require.Error(t, pl.ValidateFraming())
+}
+
+func TestPollerConfig_PollDue(t *testing.T) {
+	now := time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC)
+	config := PollerConfig{}
+	require.True(t, config.PollDue(now))
+	lastPoll := now.Add(-time.Minute)
+	config.LastPollTime = &lastPoll
+	require.False(t, config.PollDue(now))
+	require.True(t, config.PollDue(now.Add(4*time.Minute)))
+	config.PollIntervalMins = 10
+	require.False(t, config.PollDue(now.Add(4*time.Minute)))
+}
+
+func TestPollerConfig_ValidateLogTypes(t *testing.T) {
+	config := PollerConfig{
+		Vendor:   PollerVendorDuo,
+		LogTypes: []string{"Duo.Authentication", "Duo.Administrator"},
+	}
+	require.NoError(t, config.ValidateLogTypes())
+	config.LogTypes = append(config.LogTypes, "Okta.SystemLog")
+	require.Error(t, config.ValidateLogTypes())
+	config.Vendor = "foo"
+	require.Error(t, config.ValidateLogTypes())
+}
@@ -25,6 +25,33 @@ const (
 	IntegrationTypeAWS3 = "aws-s3"
 	// IntegrationTypeSqs is integration type for pulling data from an SQS queue.
 	IntegrationTypeSqs = "aws-sqs"
+	// IntegrationTypeHTTP is the integration type for data pushed to Panther over HTTP.
+	IntegrationTypeHTTP = "http"
+	// IntegrationTypePoller is the integration type for logs pulled by Panther from SaaS APIs.
+	IntegrationTypePoller = "saas-poller"
+
+	// HTTPAuthBearer authenticates HTTP sources with a bearer token in the Authorization header.
+	HTTPAuthBearer = "bearer"
+	// HTTPAuthHMAC authenticates HTTP sources with an HMAC signature of the request body.
+	HTTPAuthHMAC = "hmac"
+	// HTTPDefaultSignatureHeader is the default request header holding the HMAC signature.
+	HTTPDefaultSignatureHeader = "X-Panther-Signature"
+
+	// PollerVendorOkta pulls the Okta System Log.
+	PollerVendorOkta = "okta"
+	// PollerVendorDuo pulls the logs of the Duo Admin API.
+	PollerVendorDuo = "duo"
+	// PollerVendorOneLogin pulls the OneLogin events.
+	PollerVendorOneLogin = "onelogin"
+	// PollerVendorBox pulls the Box enterprise events.
+	PollerVendorBox = "box"
+	// PollerVendorGSuite pulls the G Suite activity reports.
+	PollerVendorGSuite = "gsuite"
+	// PollerVendorSlack pulls the Slack Enterprise Grid audit logs.
+	PollerVendorSlack = "slack"
+
+	// PollerDefaultIntervalMins is the default interval between polls of a SaaS API.
+	PollerDefaultIntervalMins = 5
 
 	// StatusError is the string set in the database when an error occurs in a scan.
 	StatusError = "error"
@@ -33,3 +60,13 @@ const (
 	// StatusScanning is the status set while a scan is underway.
 	StatusScanning = "scanning"
 )
+
+// PollerVendorLogTypes are the log types that can be pulled from each SaaS API
+var PollerVendorLogTypes = map[string][]string{
+	PollerVendorOkta:     {"Okta.SystemLog"},
+	PollerVendorDuo:      {"Duo.Authentication", "Duo.Administrator", "Duo.Telephony", "Duo.OfflineEnrollment"},
+	PollerVendorOneLogin: {"OneLogin.Events"},
+	PollerVendorBox:      {"Box.Event"},
+	PollerVendorGSuite:   {"GSuite.Reports"},
+	PollerVendorSlack:    {"Slack.AuditLogs", "Slack.AccessLogs", "Slack.IntegrationLogs"},
+}
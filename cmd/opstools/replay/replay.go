@@ -0,0 +1,256 @@
+package replay
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/destinations"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/quarantine"
+)
+
+// Replay processes quarantined log entries again using the current parsers.
+// Entries are classified using the log types that failed to parse them at the time they were quarantined.
+// Entries that fail to classify again are quarantined anew by the processor.
+type Replay struct {
+	S3     s3iface.S3API
+	Bucket string
+	// Prefix of the quarantine objects to replay, it must be under quarantine.Prefix
+	Prefix string
+	// LoadSource loads the source of the entries so that its transforms and sampling apply to the replayed entries
+	LoadSource func(id string) (*models.SourceIntegration, error)
+	// NewProcessor builds the processor for the replayed entries
+	NewProcessor processor.Factory
+	Destination  destinations.Destination
+	// Delete removes the quarantine objects once all entries are processed
+	Delete bool
+	DryRun bool
+	Logger *zap.SugaredLogger
+}
+
+// Stats are the stats of a replay
+type Stats struct {
+	NumObjects int
+	NumEntries int
+	// NumSkipped is the number of entries that had no candidate log types
+	NumSkipped int
+}
+
+// Run replays all quarantine objects under the prefix
+func (r *Replay) Run(ctx context.Context) (*Stats, error) {
+	if !strings.HasPrefix(r.Prefix, quarantine.Prefix) {
+		return nil, errors.Errorf("prefix %q is not under the quarantine prefix %q", r.Prefix, quarantine.Prefix)
+	}
+	keys, err := r.listObjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stats := Stats{}
+	streams := make(chan *common.DataStream)
+	// Streams are read while the objects are being processed, errors are collected after processing ends.
+	// Reading is canceled once processing ends so that the reader does not block if processing fails early.
+	readCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	readErr := make(chan error, 1)
+	go func() {
+		defer close(streams)
+		for _, key := range keys {
+			dataStreams, err := r.readObject(readCtx, key, &stats)
+			if err != nil {
+				readErr <- err
+				return
+			}
+			stats.NumObjects++
+			if r.DryRun {
+				continue
+			}
+			for _, s := range dataStreams {
+				select {
+				case streams <- s:
+				case <-readCtx.Done():
+					readErr <- readCtx.Err()
+					return
+				}
+			}
+		}
+		readErr <- nil
+	}()
+	var processErr error
+	if !r.DryRun {
+		processErr = processor.Process(ctx, streams, r.Destination, r.NewProcessor)
+		cancel()
+	}
+	err = <-readErr
+	// The reader error is the cancellation if processing failed
+	if processErr != nil {
+		return &stats, errors.Wrap(processErr, "failed to process quarantined entries")
+	}
+	if err != nil {
+		return &stats, err
+	}
+	if r.Delete && !r.DryRun {
+		if err := r.deleteObjects(ctx, keys); err != nil {
+			return &stats, err
+		}
+	}
+	return &stats, nil
+}
+
+func (r *Replay) listObjects(ctx context.Context) ([]string, error) {
+	var keys []string
+	input := s3.ListObjectsV2Input{
+		Bucket: aws.String(r.Bucket),
+		Prefix: aws.String(r.Prefix),
+	}
+	err := r.S3.ListObjectsV2PagesWithContext(ctx, &input, func(page *s3.ListObjectsV2Output, _ bool) bool {
+		for _, obj := range page.Contents {
+			keys = append(keys, aws.StringValue(obj.Key))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list quarantine objects in s3://%s/%s", r.Bucket, r.Prefix)
+	}
+	return keys, nil
+}
+
+// readObject reads the entries of a quarantine object grouping them in data streams by source, S3 object and log types
+func (r *Replay) readObject(ctx context.Context, key string, stats *Stats) ([]*common.DataStream, error) {
+	obj, err := r.S3.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(r.Bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get quarantine object s3://%s/%s", r.Bucket, key)
+	}
+	defer obj.Body.Close()
+
+	var (
+		streams []*common.DataStream
+		index   = map[string]*entryStream{}
+	)
+	err = quarantine.ReadEntries(obj.Body, func(entry *quarantine.Entry) error {
+		logTypes := entry.LogTypes()
+		if len(logTypes) == 0 {
+			stats.NumSkipped++
+			r.Logger.Warnf("skipping entry %d of %s in %s: no candidate log types", entry.LineNum, entry.S3ObjectKey, key)
+			return nil
+		}
+		stats.NumEntries++
+		groupKey := strings.Join(append([]string{entry.SourceID, entry.S3Bucket, entry.S3ObjectKey}, logTypes...), "\n")
+		if s, ok := index[groupKey]; ok {
+			s.entries = append(s.entries, entry.Log)
+			return nil
+		}
+		src, err := r.LoadSource(entry.SourceID)
+		if err != nil {
+			return errors.WithMessagef(err, "failed to load source of entry %d of %s", entry.LineNum, entry.S3ObjectKey)
+		}
+		s := &entryStream{
+			entries: []string{entry.Log},
+		}
+		index[groupKey] = s
+		streams = append(streams, &common.DataStream{
+			Stream:      s,
+			Source:      replaySource(src, entry, logTypes),
+			S3Bucket:    entry.S3Bucket,
+			S3ObjectKey: entry.S3ObjectKey,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to read s3://%s/%s", r.Bucket, key)
+	}
+	r.Logger.Debugf("read %d streams from %s", len(streams), key)
+	return streams, nil
+}
+
+// replaySource builds a source that classifies all entries with the candidate log types of the entry.
+// The settings of the source that apply to its events (ie transforms and sampling) are kept.
+func replaySource(src *models.SourceIntegration, entry *quarantine.Entry, logTypes []string) *models.SourceIntegration {
+	return &models.SourceIntegration{
+		SourceIntegrationMetadata: models.SourceIntegrationMetadata{
+			IntegrationID:    src.IntegrationID,
+			IntegrationLabel: src.IntegrationLabel,
+			IntegrationType:  models.IntegrationTypeAWS3,
+			S3Bucket:         entry.S3Bucket,
+			S3PrefixLogTypes: models.S3PrefixLogtypes{{S3Prefix: "", LogTypes: logTypes}},
+			Transforms:       src.Transforms,
+			Sampling:         src.Sampling,
+		},
+	}
+}
+
+func (r *Replay) deleteObjects(ctx context.Context, keys []string) error {
+	const maxDeleteBatchSize = 1000
+	for len(keys) > 0 {
+		batch := keys
+		if len(batch) > maxDeleteBatchSize {
+			batch = batch[:maxDeleteBatchSize]
+		}
+		keys = keys[len(batch):]
+		objects := make([]*s3.ObjectIdentifier, len(batch))
+		for i, key := range batch {
+			objects[i] = &s3.ObjectIdentifier{Key: aws.String(key)}
+		}
+		_, err := r.S3.DeleteObjectsWithContext(ctx, &s3.DeleteObjectsInput{
+			Bucket: aws.String(r.Bucket),
+			Delete: &s3.Delete{
+				Objects: objects,
+				Quiet:   aws.Bool(true),
+			},
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to delete replayed quarantine objects")
+		}
+	}
+	return nil
+}
+
+// entryStream is a logstream.Stream over quarantined log entries.
+// Entries are already framed so they are returned as is, even if they span multiple lines.
+type entryStream struct {
+	entries []string
+	next    int
+}
+
+// Next implements logstream.Stream interface
+func (s *entryStream) Next() []byte {
+	if s.next >= len(s.entries) {
+		return nil
+	}
+	entry := s.entries[s.next]
+	s.next++
+	return []byte(entry)
+}
+
+// Err implements logstream.Stream interface
+func (s *entryStream) Err() error {
+	return nil
+}
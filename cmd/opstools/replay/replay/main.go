@@ -0,0 +1,158 @@
+package main
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"flag"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+
+	"github.com/panther-labs/panther/cmd/opstools"
+	"github.com/panther-labs/panther/cmd/opstools/replay"
+	"github.com/panther-labs/panther/internal/compliance/snapshotlogs"
+	"github.com/panther-labs/panther/internal/core/logtypesapi"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/destinations"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	logmetrics "github.com/panther-labs/panther/internal/log_analysis/log_processor/metrics"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/quarantine"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/registry"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/sources"
+	"github.com/panther-labs/panther/pkg/awscfn"
+	"github.com/panther-labs/panther/tools/cfnstacks"
+)
+
+var (
+	version string // we expect this to be set by the build tool as `-X main.version=<some version>`
+)
+
+func main() {
+	opstools.SetUsage("replays quarantined log entries that failed to classify using the current parsers (Panther version %s)",
+		version)
+	opts := struct {
+		MasterStack    *string
+		SourceID       *string
+		Prefix         *string
+		Delete         *bool
+		DryRun         *bool
+		Debug          *bool
+		Region         *string
+		MaxConnections *int
+		MaxRetries     *int
+		MemorySize     *int
+	}{
+		MasterStack: flag.String("master-stack", "",
+			"if set, this is the name of the Panther master stack used to deploy, if not set the deployment is assumed from source"),
+		SourceID:       flag.String("source-id", "", "Replay only the entries of this source"),
+		Prefix:         flag.String("prefix", "", "Replay the quarantine objects under this prefix (overrides -source-id)"),
+		Delete:         flag.Bool("delete", false, "Delete the quarantine objects once they are replayed"),
+		DryRun:         flag.Bool("dry-run", false, "Read the quarantined entries without processing them"),
+		Debug:          flag.Bool("debug", false, "Enable additional logging"),
+		Region:         flag.String("region", "", "Set the AWS region to run on"),
+		MaxRetries:     flag.Int("max-retries", 12, "Max retries for AWS requests"),
+		MaxConnections: flag.Int("max-connections", 100, "Max number of connections to AWS"),
+		MemorySize:     flag.Int("memory", 2048, "Memory in MB available for buffering processed events"),
+	}
+	flag.Parse()
+
+	log := opstools.MustBuildLogger(*opts.Debug)
+	zap.ReplaceGlobals(log.Desugar())
+
+	prefix := *opts.Prefix
+	if prefix == "" {
+		prefix = quarantine.Prefix
+		if id := *opts.SourceID; id != "" {
+			prefix = quarantine.SourcePrefix(id)
+		}
+	}
+
+	sess, err := session.NewSession(&aws.Config{
+		Region:     opts.Region,
+		MaxRetries: opts.MaxRetries,
+		HTTPClient: opstools.NewHTTPClient(*opts.MaxConnections, 0),
+	})
+	if err != nil {
+		log.Fatalf("failed to start AWS session: %s", err)
+	}
+
+	opstools.ValidatePantherVersion(sess, log, *opts.MasterStack, version)
+
+	cfnClient := cloudformation.New(sess)
+	bootstrapStack, err := cfnstacks.GetBootstrapStack(cfnClient, *opts.MasterStack)
+	if err != nil {
+		log.Fatal(err)
+	}
+	outputs, err := awscfn.StackOutputs(cfnClient, bootstrapStack)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// The processor and the destination use the log processor globals
+	common.Config.ProcessedDataBucket = outputs["ProcessedDataBucket"]
+	common.Config.SnsTopicARN = outputs["ProcessedDataTopicArn"]
+	common.Config.AwsLambdaFunctionMemorySize = *opts.MemorySize
+	if common.Config.ProcessedDataBucket == "" || common.Config.SnsTopicARN == "" {
+		log.Fatalf("could not find processed data bucket and topic in %s outputs", bootstrapStack)
+	}
+	common.S3Client = s3.New(sess)
+	common.SnsClient = sns.New(sess)
+	common.LambdaClient = lambda.New(sess)
+	// Metrics are not synced, the counters are only needed by the processor
+	logmetrics.Setup()
+
+	resolver := logtypes.ChainResolvers(&logtypesapi.Resolver{
+		LogTypesAPI: &logtypesapi.LogTypesAPILambdaClient{
+			LambdaName: logtypesapi.LambdaName,
+			LambdaAPI:  common.LambdaClient,
+			Validate:   validator.New().Struct,
+		},
+		NativeLogTypes: logtypes.MustMerge("native", registry.NativeLogTypes(), snapshotlogs.LogTypes()),
+	}, snapshotlogs.Resolver())
+
+	r := replay.Replay{
+		S3:           common.S3Client,
+		Bucket:       common.Config.ProcessedDataBucket,
+		Prefix:       prefix,
+		LoadSource:   sources.LoadSource,
+		NewProcessor: processor.NewFactory(logtypes.ParserResolver(resolver)),
+		Destination:  destinations.CreateS3Destination(common.ConfigForDataLakeWriters()),
+		Delete:       *opts.Delete,
+		DryRun:       *opts.DryRun,
+		Logger:       log,
+	}
+	log.Infof("replaying quarantined entries in s3://%s/%s", r.Bucket, r.Prefix)
+	stats, err := r.Run(context.Background())
+	if stats != nil {
+		log.Infof("read %d entries from %d quarantine objects, skipped %d entries",
+			stats.NumEntries, stats.NumObjects, stats.NumSkipped)
+	}
+	if err != nil {
+		log.Fatalf("replay failed: %s", err)
+	}
+	log.Info("replay complete")
+}
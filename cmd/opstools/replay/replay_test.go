@@ -0,0 +1,141 @@
+package replay
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/quarantine"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/sampling"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/transform"
+	"github.com/panther-labs/panther/pkg/testutils"
+)
+
+func TestReplayProcessError(t *testing.T) {
+	assert := require.New(t)
+	keys := []string{"quarantine/source-id/one.json.gz", "quarantine/source-id/two.json.gz"}
+	s3Mock := &testutils.S3Mock{}
+	listOutput := &s3.ListObjectsV2Output{}
+	for _, key := range keys {
+		listOutput.Contents = append(listOutput.Contents, &s3.Object{Key: aws.String(key)})
+	}
+	s3Mock.On("ListObjectsV2PagesWithContext", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(listOutput, nil).Once()
+	for _, key := range keys {
+		s3Mock.On("GetObjectWithContext", mock.Anything, &s3.GetObjectInput{
+			Bucket: aws.String("bucket"),
+			Key:    aws.String(key),
+		}, mock.Anything).Return(&s3.GetObjectOutput{
+			Body: ioutil.NopCloser(bytes.NewReader(quarantineObject(t, &quarantine.Entry{
+				SourceID:    "source-id",
+				S3Bucket:    "logs",
+				S3ObjectKey: key,
+				Log:         `{"foo":"bar"}`,
+				Errors:      map[string]string{"Custom.Foo": "invalid"},
+			}))),
+		}, nil).Maybe()
+	}
+
+	src := &models.SourceIntegration{}
+	src.IntegrationID = "source-id"
+	src.IntegrationType = models.IntegrationTypeSqs
+	src.Transforms = []transform.Config{{LogType: "Custom.Foo", Mask: []string{"foo"}}}
+	src.Sampling = []sampling.Config{{LogType: "Custom.Foo", MaxEventsPerHour: 10}}
+	var inputs []*common.DataStream
+	r := Replay{
+		S3:     s3Mock,
+		Bucket: "bucket",
+		Prefix: "quarantine/source-id/",
+		LoadSource: func(_ string) (*models.SourceIntegration, error) {
+			return src, nil
+		},
+		NewProcessor: func(input *common.DataStream) (*processor.Processor, error) {
+			inputs = append(inputs, input)
+			return nil, errors.New("processor failed")
+		},
+		Destination: discardDestination{},
+		Logger:      zap.NewNop().Sugar(),
+	}
+	done := make(chan error)
+	go func() {
+		_, err := r.Run(context.Background())
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		// The processor fails on the first stream while the reader is blocked on the second one
+		assert.Error(err)
+		assert.Contains(err.Error(), "processor failed")
+	case <-time.After(10 * time.Second):
+		t.Fatal("replay did not return after processing failed")
+	}
+	s3Mock.AssertExpectations(t)
+
+	// The entries are processed with the transforms and sampling of their source
+	assert.Len(inputs, 1)
+	assert.Equal("source-id", inputs[0].Source.IntegrationID)
+	assert.Equal([]string{"Custom.Foo"}, inputs[0].Source.S3PrefixLogTypes.LogTypes())
+	assert.Equal(src.Transforms, inputs[0].Source.Transforms)
+	assert.Equal(src.Sampling, inputs[0].Source.Sampling)
+}
+
+func TestReplayInvalidPrefix(t *testing.T) {
+	r := Replay{
+		Prefix: "logs/",
+	}
+	_, err := r.Run(context.Background())
+	require.Error(t, err)
+}
+
+func quarantineObject(t *testing.T, entries ...*quarantine.Entry) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	for _, entry := range entries {
+		data, err := jsoniter.Marshal(entry)
+		require.NoError(t, err)
+		_, err = gz.Write(append(data, '\n'))
+		require.NoError(t, err)
+	}
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+type discardDestination struct{}
+
+func (discardDestination) SendEvents(results chan *parsers.Result, _ chan error) {
+	for range results {
+	}
+}
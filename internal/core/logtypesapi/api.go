@@ -22,6 +22,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
 	"github.com/pkg/errors"
 
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logschema"
@@ -43,6 +44,9 @@ type LogTypesAPI struct {
 	UpdateDataCatalog func(ctx context.Context, logType string, from, to []logschema.FieldSchema) error
 	LogTypesInUse     func(ctx context.Context) ([]string, error)
 	ManagedSchemas    managedschemas.ReleaseFeeder
+	// SourceS3Client returns a client to read sample log files from the bucket of a configured S3 source.
+	// It returns a nil client if the bucket does not belong to any S3 source.
+	SourceS3Client func(ctx context.Context, bucket string) (s3iface.S3API, error)
 }
 
 // SchemaDatabase handles the external actions required for LogTypesAPI to be implemented
@@ -53,6 +57,9 @@ type SchemaDatabase interface {
 	// GetSchema gets a single schema record
 	GetSchema(ctx context.Context, id string, revision int64) (*SchemaRecord, error)
 
+	// ListSchemaRevisions gets the records of all revisions of a schema ordered by revision
+	ListSchemaRevisions(ctx context.Context, id string) ([]*SchemaRecord, error)
+
 	// UpdateSchema updates a managed schema to the release version provided
 	UpdateUserSchema(ctx context.Context, id string, rev int64, upd SchemaUpdate) (*SchemaRecord, error)
 
@@ -67,6 +67,11 @@ func (l ListAvailableAPI) GetSchema(ctx context.Context, id string, revision int
 	panic("implement me")
 }
 
+// nolint:lll
+func (l ListAvailableAPI) ListSchemaRevisions(ctx context.Context, id string) ([]*logtypesapi.SchemaRecord, error) {
+	panic("implement me")
+}
+
 // nolint:lll
 func (l ListAvailableAPI) UpdateUserSchema(ctx context.Context, id string, rev int64, upd logtypesapi.SchemaUpdate) (*logtypesapi.SchemaRecord, error) {
 	panic("implement me")
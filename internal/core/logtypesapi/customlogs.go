@@ -21,6 +21,7 @@ package logtypesapi
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/pkg/errors"
 	"go.uber.org/multierr"
@@ -81,40 +82,51 @@ func (api *LogTypesAPI) PutCustomLog(ctx context.Context, input *PutCustomLogInp
 			Result: result,
 		}, nil
 	default:
-		current, err := api.Database.GetSchema(ctx, id, 0)
+		result, err := api.updateCustomLog(ctx, input.LogType, currentRevision, schema, input.SchemaUpdate)
 		if err != nil {
 			return nil, err
 		}
-		if current == nil {
-			return nil, NewAPIError(ErrNotFound, fmt.Sprintf("record %q was not found", id))
-		}
-		if !current.IsCustom() {
-			return nil, NewAPIError(ErrAlreadyExists, fmt.Sprintf("record %q is not user-defined", id))
-		}
-		if current.Revision != currentRevision {
-			return nil, NewAPIError(ErrRevisionConflict, fmt.Sprintf("record %q is not on revision %d", id, currentRevision))
-		}
-		currentSchema, err := buildSchema(current.Spec)
-		if err != nil {
-			return nil, err
-		}
-		if err := api.checkUpdate(currentSchema, schema); err != nil {
-			return nil, NewAPIError(ErrInvalidUpdate, fmt.Sprintf("schema update is not backwards compatible: %s", err))
-		}
-		result, err := api.Database.UpdateUserSchema(ctx, id, current.Revision, input.SchemaUpdate)
-		if err != nil {
-			return nil, err
-		}
-		if err := api.UpdateDataCatalog(ctx, input.LogType, currentSchema.Fields, schema.Fields); err != nil {
-			// The error will be shown to the user as a "ServerError"
-			return nil, errors.Wrapf(err, "could not queue event for %q database update", input.LogType)
-		}
 		return &PutCustomLogOutput{
 			Result: result,
 		}, nil
 	}
 }
 
+// updateCustomLog updates a custom log record at `currentRevision` to a new revision if the schema change is backwards compatible
+// nolint:lll
+func (api *LogTypesAPI) updateCustomLog(ctx context.Context, logType string, currentRevision int64, schema *logschema.Schema, upd SchemaUpdate) (*SchemaRecord, error) {
+	id := customlogs.LogType(logType)
+	current, err := api.Database.GetSchema(ctx, id, 0)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, NewAPIError(ErrNotFound, fmt.Sprintf("record %q was not found", id))
+	}
+	if !current.IsCustom() {
+		return nil, NewAPIError(ErrAlreadyExists, fmt.Sprintf("record %q is not user-defined", id))
+	}
+	if current.Revision != currentRevision {
+		return nil, NewAPIError(ErrRevisionConflict, fmt.Sprintf("record %q is not on revision %d", id, currentRevision))
+	}
+	currentSchema, err := buildSchema(current.Spec)
+	if err != nil {
+		return nil, err
+	}
+	if err := api.checkUpdate(currentSchema, schema); err != nil {
+		return nil, NewAPIError(ErrInvalidUpdate, fmt.Sprintf("schema update is not backwards compatible: %s", err))
+	}
+	result, err := api.Database.UpdateUserSchema(ctx, id, current.Revision+1, upd)
+	if err != nil {
+		return nil, err
+	}
+	if err := api.UpdateDataCatalog(ctx, logType, currentSchema.Fields, schema.Fields); err != nil {
+		// The error will be shown to the user as a "ServerError"
+		return nil, errors.Wrapf(err, "could not queue event for %q database update", logType)
+	}
+	return result, nil
+}
+
 func (api *LogTypesAPI) checkUpdate(a, b *logschema.Schema) error {
 	diff, err := logschema.Diff(a, b)
 	if err != nil {
@@ -234,3 +246,143 @@ type ListCustomLogsOutput struct {
 	Records []*SchemaRecord `json:"customLogs" description:"Custom log records stored"`
 	Error   *APIError       `json:"error,omitempty" description:"An error that occurred during the operation"`
 }
+
+// ListCustomLogRevisions lists all revisions of a custom log record along with the changes introduced by each revision
+// nolint:lll
+func (api *LogTypesAPI) ListCustomLogRevisions(ctx context.Context, input *ListCustomLogRevisionsInput) (*ListCustomLogRevisionsOutput, error) {
+	id := customlogs.LogType(input.LogType)
+	head, err := api.Database.GetSchema(ctx, id, 0)
+	if err != nil {
+		return nil, err
+	}
+	if head == nil || !head.IsCustom() || head.Disabled {
+		return nil, NewAPIError(ErrNotFound, fmt.Sprintf("custom log record %q not found", input.LogType))
+	}
+	records, err := api.Database.ListSchemaRevisions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	revisions := make([]*CustomLogRevision, 0, len(records))
+	var prev *logschema.Schema
+	for i, record := range records {
+		// A missing revision would make the changes of the next one wrong
+		if rev := int64(i + 1); record.Revision != rev {
+			return nil, NewAPIError(ErrNotFound, fmt.Sprintf("custom log record %q not found at revision %d", input.LogType, rev))
+		}
+		schema, err := buildSchema(record.Spec)
+		if err != nil {
+			return nil, err
+		}
+		revision := CustomLogRevision{
+			Record: record,
+		}
+		if prev != nil {
+			changes, err := logschema.Diff(prev, schema)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to diff %q revision %d", input.LogType, record.Revision)
+			}
+			revision.Changes = schemaChanges(changes)
+		}
+		revisions = append(revisions, &revision)
+		prev = schema
+	}
+	return &ListCustomLogRevisionsOutput{
+		Revisions: revisions,
+	}, nil
+}
+
+func schemaChanges(changes []logschema.Change) []SchemaChange {
+	if len(changes) == 0 {
+		return nil
+	}
+	out := make([]SchemaChange, len(changes))
+	for i := range changes {
+		c := &changes[i]
+		path := c.Path
+		// Paths of added/deleted fields point to the parent object
+		switch c.Type {
+		case logschema.AddField:
+			if field, ok := c.To.(*logschema.FieldSchema); ok {
+				path = append(path[:len(path):len(path)], field.Name)
+			}
+		case logschema.DeleteField:
+			if field, ok := c.From.(*logschema.FieldSchema); ok {
+				path = append(path[:len(path):len(path)], field.Name)
+			}
+		}
+		out[i] = SchemaChange{
+			Type: c.Type,
+			Path: strings.Join(path, "."),
+		}
+	}
+	return out
+}
+
+type ListCustomLogRevisionsInput struct {
+	LogType string `json:"logType" validate:"required,startswith=Custom." description:"The log type id"`
+}
+
+//nolint:lll
+type ListCustomLogRevisionsOutput struct {
+	Revisions []*CustomLogRevision `json:"revisions,omitempty" description:"The revisions of the custom log record from oldest to newest (field is omitted if an error occurred)"`
+	Error     *APIError            `json:"error,omitempty" description:"An error that occurred during the operation"`
+}
+
+// CustomLogRevision is a revision of a custom log record
+type CustomLogRevision struct {
+	Record  *SchemaRecord  `json:"record" description:"The custom log record at this revision"`
+	Changes []SchemaChange `json:"changes,omitempty" description:"The schema changes from the previous revision"`
+}
+
+// SchemaChange describes a change between two revisions of a schema
+type SchemaChange struct {
+	Type string `json:"type" description:"The type of change"`
+	Path string `json:"path" description:"The path of the changed value"`
+}
+
+// RevertCustomLog reverts a custom log record to a previous revision.
+// The contents of the previous revision are stored as a new revision if the change is backwards compatible.
+// nolint:lll
+func (api *LogTypesAPI) RevertCustomLog(ctx context.Context, input *RevertCustomLogInput) (*RevertCustomLogOutput, error) {
+	if input.TargetRevision >= input.Revision {
+		return nil, NewAPIError(ErrInvalidUpdate, fmt.Sprintf("cannot revert %q to revision %d from revision %d", input.LogType, input.TargetRevision, input.Revision))
+	}
+	id := customlogs.LogType(input.LogType)
+	target, err := api.Database.GetSchema(ctx, id, input.TargetRevision)
+	if err != nil {
+		return nil, err
+	}
+	if target == nil || !target.IsCustom() {
+		return nil, NewAPIError(ErrNotFound, fmt.Sprintf("custom log record %q not found at revision %d", input.LogType, input.TargetRevision))
+	}
+	id, schema, err := buildAndValidateUserSchema(&PutCustomLogInput{
+		LogType:      input.LogType,
+		SchemaUpdate: target.SchemaUpdate,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSchema(id, schema); err != nil {
+		return nil, err
+	}
+	result, err := api.updateCustomLog(ctx, input.LogType, input.Revision, schema, target.SchemaUpdate)
+	if err != nil {
+		return nil, err
+	}
+	return &RevertCustomLogOutput{
+		Result: result,
+	}, nil
+}
+
+// nolint:lll
+type RevertCustomLogInput struct {
+	LogType        string `json:"logType" validate:"required,startswith=Custom." description:"The log type id"`
+	Revision       int64  `json:"revision" validate:"required,min=1" description:"Current revision of the custom log record"`
+	TargetRevision int64  `json:"targetRevision" validate:"required,min=1" description:"The revision to revert the custom log record to"`
+}
+
+//nolint:lll
+type RevertCustomLogOutput struct {
+	Result *SchemaRecord `json:"record,omitempty" description:"The new revision of the record (field is omitted if an error occurred)"`
+	Error  *APIError     `json:"error,omitempty" description:"An error that occurred during the operation"`
+}
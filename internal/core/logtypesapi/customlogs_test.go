@@ -139,3 +139,153 @@ func TestAPI_PutCustomLog(t *testing.T) {
 		assert.Equal(logtypesapi.ErrInUse, logtypesapi.AsAPIError(err).Code)
 	}
 }
+
+func TestAPI_PutCustomLogUpdate(t *testing.T) {
+	numDataCatalogCalls := 0
+	api := logtypesapi.LogTypesAPI{
+		Database: logtypesapi.NewInMemory(),
+		UpdateDataCatalog: func(ctx context.Context, logType string, from, to []logschema.FieldSchema) error {
+			numDataCatalogCalls++
+			return nil
+		},
+	}
+	ctx := context.Background()
+	assert := require.New(t)
+	reply, err := api.PutCustomLog(ctx, &logtypesapi.PutCustomLogInput{
+		Revision: 0,
+		LogType:  "Custom.Event",
+		SchemaUpdate: logtypesapi.SchemaUpdate{
+			Spec: `{"version": 0, "fields": [{"name": "foo", "type": "string"}]}`,
+		},
+	})
+	assert.NoError(err)
+	assert.Equal(int64(1), reply.Result.Revision)
+
+	// A backwards compatible update is stored as the next revision
+	expect := logtypesapi.SchemaUpdate{
+		Spec: `{"version": 0, "fields": [{"name": "foo", "type": "string"}, {"name": "bar", "type": "string"}]}`,
+	}
+	reply, err = api.PutCustomLog(ctx, &logtypesapi.PutCustomLogInput{
+		Revision:     1,
+		LogType:      "Custom.Event",
+		SchemaUpdate: expect,
+	})
+	assert.NoError(err)
+	assert.Nil(reply.Error)
+	assert.Equal(int64(2), reply.Result.Revision)
+	assert.Equal(2, numDataCatalogCalls)
+
+	current, err := api.GetCustomLog(ctx, &logtypesapi.GetCustomLogInput{
+		LogType: "Custom.Event",
+	})
+	assert.NoError(err)
+	assert.Equal(int64(2), current.Result.Revision)
+	assert.Equal(expect.Spec, current.Result.Spec)
+}
+
+func TestAPI_CustomLogRevisions(t *testing.T) {
+	numDataCatalogCalls := 0
+	api := logtypesapi.LogTypesAPI{
+		Database: logtypesapi.NewInMemory(),
+		UpdateDataCatalog: func(ctx context.Context, logType string, from, to []logschema.FieldSchema) error {
+			numDataCatalogCalls++
+			return nil
+		},
+	}
+	ctx := context.Background()
+	assert := require.New(t)
+	specs := []string{
+		`{"version": 0, "fields": [{"name": "foo", "type": "string"}]}`,
+		`{"version": 0, "fields": [{"name": "foo", "type": "string"}, {"name": "bar", "type": "string"}]}`,
+		`{"version": 0, "fields": [{"name": "foo", "type": "string"}, {"name": "bar", "type": "string", "description": "Bar"}]}`,
+	}
+	for i, spec := range specs {
+		reply, err := api.PutCustomLog(ctx, &logtypesapi.PutCustomLogInput{
+			Revision: int64(i),
+			LogType:  "Custom.Event",
+			SchemaUpdate: logtypesapi.SchemaUpdate{
+				Description: "An example custom log type",
+				Spec:        spec,
+			},
+		})
+		assert.NoError(err)
+		assert.Equal(int64(i+1), reply.Result.Revision)
+	}
+	assert.Equal(3, numDataCatalogCalls)
+
+	revisions, err := api.ListCustomLogRevisions(ctx, &logtypesapi.ListCustomLogRevisionsInput{
+		LogType: "Custom.Event",
+	})
+	assert.NoError(err)
+	assert.Len(revisions.Revisions, 3)
+	for i, rev := range revisions.Revisions {
+		assert.Equal(int64(i+1), rev.Record.Revision)
+		assert.Equal(specs[i], rev.Record.Spec)
+	}
+	assert.Empty(revisions.Revisions[0].Changes)
+	assert.Equal([]logtypesapi.SchemaChange{
+		{Type: logschema.AddField, Path: "Fields.bar"},
+	}, revisions.Revisions[1].Changes)
+	assert.Equal([]logtypesapi.SchemaChange{
+		{Type: logschema.UpdateFieldMeta, Path: "Fields.bar.Description"},
+	}, revisions.Revisions[2].Changes)
+
+	// Reverting to revision 1 would delete field bar
+	_, err = api.RevertCustomLog(ctx, &logtypesapi.RevertCustomLogInput{
+		LogType:        "Custom.Event",
+		Revision:       3,
+		TargetRevision: 1,
+	})
+	assert.Error(err)
+	assert.Equal(logtypesapi.ErrInvalidUpdate, logtypesapi.AsAPIError(err).Code)
+
+	// Revert requires the current revision
+	_, err = api.RevertCustomLog(ctx, &logtypesapi.RevertCustomLogInput{
+		LogType:        "Custom.Event",
+		Revision:       2,
+		TargetRevision: 1,
+	})
+	assert.Error(err)
+	assert.Equal(logtypesapi.ErrRevisionConflict, logtypesapi.AsAPIError(err).Code)
+
+	// Cannot revert to a newer revision
+	_, err = api.RevertCustomLog(ctx, &logtypesapi.RevertCustomLogInput{
+		LogType:        "Custom.Event",
+		Revision:       3,
+		TargetRevision: 3,
+	})
+	assert.Error(err)
+	assert.Equal(logtypesapi.ErrInvalidUpdate, logtypesapi.AsAPIError(err).Code)
+
+	reverted, err := api.RevertCustomLog(ctx, &logtypesapi.RevertCustomLogInput{
+		LogType:        "Custom.Event",
+		Revision:       3,
+		TargetRevision: 2,
+	})
+	assert.NoError(err)
+	assert.Equal(int64(4), reverted.Result.Revision)
+	assert.Equal(specs[1], reverted.Result.Spec)
+	assert.Equal(4, numDataCatalogCalls)
+
+	current, err := api.GetCustomLog(ctx, &logtypesapi.GetCustomLogInput{
+		LogType: "Custom.Event",
+	})
+	assert.NoError(err)
+	assert.Equal(int64(4), current.Result.Revision)
+	assert.Equal(specs[1], current.Result.Spec)
+
+	revisions, err = api.ListCustomLogRevisions(ctx, &logtypesapi.ListCustomLogRevisionsInput{
+		LogType: "Custom.Event",
+	})
+	assert.NoError(err)
+	assert.Len(revisions.Revisions, 4)
+	assert.Equal([]logtypesapi.SchemaChange{
+		{Type: logschema.UpdateFieldMeta, Path: "Fields.bar.Description"},
+	}, revisions.Revisions[3].Changes)
+
+	_, err = api.ListCustomLogRevisions(ctx, &logtypesapi.ListCustomLogRevisionsInput{
+		LogType: "Custom.Missing",
+	})
+	assert.Error(err)
+	assert.Equal(logtypesapi.ErrNotFound, logtypesapi.AsAPIError(err).Code)
+}
@@ -21,6 +21,7 @@ package logtypesapi
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -44,6 +45,7 @@ const (
 	recordKindSchema = "custom"
 
 	attrRecordKind   = "RecordKind"
+	attrRecordID     = "RecordID"
 	attrDisabled     = "IsDeleted"
 	attrRevision     = "revision"
 	attrDescription  = "description"
@@ -121,6 +123,56 @@ func (d *DynamoDBSchemas) GetSchema(ctx context.Context, id string, revision int
 	return &record.SchemaRecord, nil
 }
 
+func (d *DynamoDBSchemas) ListSchemaRevisions(ctx context.Context, id string) ([]*SchemaRecord, error) {
+	input, err := buildSchemaRevisionsQuery(d.TableName, id)
+	if err != nil {
+		return nil, err
+	}
+	var records []*SchemaRecord
+	var itemErr error
+	queryErr := d.DB.QueryPagesWithContext(ctx, input, func(page *dynamodb.QueryOutput, isLast bool) bool {
+		for _, item := range page.Items {
+			record := ddbSchemaRecord{}
+			if itemErr = dynamodbattribute.UnmarshalMap(item, &record); itemErr != nil {
+				return false
+			}
+			// The prefix also matches the records of log types whose name starts with `<id>-`
+			if record.RecordID != schemaRecordID(id, record.Revision) {
+				continue
+			}
+			records = append(records, &record.SchemaRecord)
+		}
+		return true
+	})
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	if itemErr != nil {
+		return nil, itemErr
+	}
+	// Records are sorted by their id so revision 10 comes before revision 2
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Revision < records[j].Revision
+	})
+	return records, nil
+}
+
+// buildSchemaRevisionsQuery queries the revision records of a schema ('<ID>-<REV>') by the prefix of their key
+func buildSchemaRevisionsQuery(tableName, id string) (*dynamodb.QueryInput, error) {
+	keyCondition := expression.Key(attrRecordKind).Equal(expression.Value(recordKindSchema)).
+		And(expression.Key(attrRecordID).BeginsWith(schemaRecordID(id, 0) + "-"))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCondition).Build()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build schema revisions query")
+	}
+	return &dynamodb.QueryInput{
+		TableName:                 aws.String(tableName),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	}, nil
+}
+
 // nolint:lll
 func (d *DynamoDBSchemas) UpdateManagedSchema(ctx context.Context, id string, rev int64, release string, upd SchemaUpdate) (*SchemaRecord, error) {
 	now := time.Now().UTC()
@@ -357,7 +409,7 @@ func updateUserSchemaTX(tableName string, record SchemaRecord) transact.Transact
 		// Update the 'head' (rev 0) record
 		&transact.Update{
 			TableName: tableName,
-			Key:       schemaRecordID(record.Name, 0),
+			Key:       schemaRecordKey(record.Name, 0),
 			Set: map[string]interface{}{
 				attrRevision:     record.Revision,
 				attrDescription:  record.Description,
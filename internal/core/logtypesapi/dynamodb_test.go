@@ -0,0 +1,94 @@
+package logtypesapi
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/pkg/testutils"
+)
+
+func TestUpdateUserSchemaTX(t *testing.T) {
+	assert := require.New(t)
+	tx := updateUserSchemaTX("schemas", SchemaRecord{
+		Name:     "Custom.Event",
+		Revision: 2,
+	})
+	input, err := tx.Build()
+	assert.NoError(err)
+	assert.Len(input.TransactItems, 2)
+	// The head record is updated by its key
+	head := input.TransactItems[0].Update
+	assert.NotNil(head)
+	assert.Equal("CUSTOM.EVENT", aws.StringValue(head.Key["RecordID"].S))
+	assert.Equal(recordKindSchema, aws.StringValue(head.Key["RecordKind"].S))
+	rev := input.TransactItems[1].Put
+	assert.NotNil(rev)
+	assert.Equal("CUSTOM.EVENT-2", aws.StringValue(rev.Item["RecordID"].S))
+}
+
+func TestListSchemaRevisions(t *testing.T) {
+	assert := require.New(t)
+	input, err := buildSchemaRevisionsQuery("schemas", "Custom.Event")
+	assert.NoError(err)
+	assert.Equal("schemas", aws.StringValue(input.TableName))
+	values := make([]string, 0, len(input.ExpressionAttributeValues))
+	for _, v := range input.ExpressionAttributeValues {
+		values = append(values, aws.StringValue(v.S))
+	}
+	assert.ElementsMatch([]string{recordKindSchema, "CUSTOM.EVENT-"}, values)
+
+	item := func(id string, rev int64) map[string]*dynamodb.AttributeValue {
+		return mustMarshalMap(&ddbSchemaRecord{
+			recordKey: schemaRecordKey(id, rev),
+			SchemaRecord: SchemaRecord{
+				Name:     id,
+				Revision: rev,
+			},
+		})
+	}
+	db := &testutils.DynamoDBMock{}
+	db.On("QueryPagesWithContext", mock.Anything, input, mock.Anything).Return(&dynamodb.QueryOutput{
+		Items: []map[string]*dynamodb.AttributeValue{
+			item("Custom.Event", 1),
+			item("Custom.Event", 10),
+			item("Custom.Event-Foo", 1),
+			item("Custom.Event", 2),
+		},
+	}, nil).Once()
+	schemas := DynamoDBSchemas{
+		DB:        db,
+		TableName: "schemas",
+	}
+	records, err := schemas.ListSchemaRevisions(context.Background(), "Custom.Event")
+	assert.NoError(err)
+	db.AssertExpectations(t)
+	revisions := make([]int64, 0, len(records))
+	for _, r := range records {
+		assert.Equal("Custom.Event", r.Name)
+		revisions = append(revisions, r.Revision)
+	}
+	assert.Equal([]int64{1, 2, 10}, revisions)
+}
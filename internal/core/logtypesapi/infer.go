@@ -0,0 +1,216 @@
+package logtypesapi
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/customlogs"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logschema"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/sources"
+)
+
+const (
+	// defaultInferMaxObjects is the default number of S3 objects to sample
+	defaultInferMaxObjects = 10
+	// defaultInferMaxEvents is the default number of log entries to sample
+	defaultInferMaxEvents = 1000
+	// inferMaxKeys limits the number of S3 keys to list when choosing objects to sample
+	inferMaxKeys = 10000
+	// inferLogType is the log type used to validate inferred schemas
+	inferLogType = "Custom.Inferred"
+)
+
+// InferCustomLogSchema infers a candidate custom log schema from sample log entries.
+// The log entries are either provided in the input or read from objects sampled evenly across an S3 prefix.
+// nolint:lll
+func (api *LogTypesAPI) InferCustomLogSchema(ctx context.Context, input *InferCustomLogSchemaInput) (*InferCustomLogSchemaOutput, error) {
+	var parser *logschema.Parser
+	if input.Parser != "" {
+		parser = &logschema.Parser{}
+		if err := yaml.Unmarshal([]byte(input.Parser), parser); err != nil {
+			return nil, NewAPIError(ErrInvalidSyntax, fmt.Sprintf("invalid parser configuration: %s", err))
+		}
+	}
+	infer, err := customlogs.NewSchemaInference(parser)
+	if err != nil {
+		return nil, NewAPIError(ErrInvalidLogSchema, err.Error())
+	}
+	maxEvents := input.MaxEvents
+	if maxEvents == 0 {
+		maxEvents = defaultInferMaxEvents
+	}
+	numObjects := 0
+	if input.Sample != "" {
+		if _, err := infer.InferLogs(strings.NewReader(input.Sample), maxEvents); err != nil {
+			return nil, NewAPIError(ErrInvalidSyntax, fmt.Sprintf("failed to infer schema from sample: %s", err))
+		}
+	} else {
+		client, err := api.sourceS3Client(ctx, input.S3Bucket)
+		if err != nil {
+			return nil, err
+		}
+		keys, err := sampleObjectKeys(ctx, client, input)
+		if err != nil {
+			return nil, err
+		}
+		for i, key := range keys {
+			// Spread the remaining events over the remaining objects
+			limit := (maxEvents - infer.NumEvents()) / (len(keys) - i)
+			if limit < 1 {
+				break
+			}
+			if err := inferObject(ctx, client, infer, input.S3Bucket, key, limit); err != nil {
+				return nil, err
+			}
+			numObjects++
+		}
+	}
+	schema, err := infer.Schema()
+	if err != nil {
+		return nil, NewAPIError(ErrInvalidLogSchema, err.Error())
+	}
+	if err := checkSchema(inferLogType, schema); err != nil {
+		if apiErr := AsAPIError(err); apiErr != nil {
+			return nil, apiErr
+		}
+		return nil, NewAPIError(ErrInvalidLogSchema, fmt.Sprintf("inferred schema is not valid: %s", err))
+	}
+	spec, err := yaml.Marshal(schema)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode inferred schema")
+	}
+	return &InferCustomLogSchemaOutput{
+		Spec:       string(spec),
+		NumObjects: numObjects,
+		NumEvents:  infer.NumEvents(),
+	}, nil
+}
+
+// sourceS3Client returns a client for the bucket of an S3 source.
+// Sample log files can only be read from buckets that Panther is already configured to read from.
+func (api *LogTypesAPI) sourceS3Client(ctx context.Context, bucket string) (s3iface.S3API, error) {
+	if api.SourceS3Client == nil {
+		return nil, errors.New("S3 client is not configured")
+	}
+	client, err := api.SourceS3Client(ctx, bucket)
+	if err != nil {
+		return nil, err
+	}
+	if client == nil {
+		return nil, NewAPIError(ErrNotFound, fmt.Sprintf("bucket %q does not belong to a configured S3 source", bucket))
+	}
+	return client, nil
+}
+
+// sampleObjectKeys lists the objects under the input prefix and picks at most `MaxObjects` keys evenly spaced.
+func sampleObjectKeys(ctx context.Context, client s3iface.S3API, input *InferCustomLogSchemaInput) ([]string, error) {
+	var keys []string
+	listInput := s3.ListObjectsV2Input{
+		Bucket: aws.String(input.S3Bucket),
+		Prefix: aws.String(input.S3Prefix),
+	}
+	err := client.ListObjectsV2PagesWithContext(ctx, &listInput, func(page *s3.ListObjectsV2Output, _ bool) bool {
+		for _, obj := range page.Contents {
+			// Skip 'directory' placeholders and empty objects
+			if aws.Int64Value(obj.Size) == 0 || strings.HasSuffix(aws.StringValue(obj.Key), "/") {
+				continue
+			}
+			keys = append(keys, aws.StringValue(obj.Key))
+		}
+		return len(keys) < inferMaxKeys
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list objects in s3://%s/%s", input.S3Bucket, input.S3Prefix)
+	}
+	if len(keys) == 0 {
+		return nil, NewAPIError(ErrNotFound, fmt.Sprintf("no objects found in s3://%s/%s", input.S3Bucket, input.S3Prefix))
+	}
+	maxObjects := input.MaxObjects
+	if maxObjects == 0 {
+		maxObjects = defaultInferMaxObjects
+	}
+	if len(keys) <= maxObjects {
+		return keys, nil
+	}
+	sample := make([]string, maxObjects)
+	for i := range sample {
+		sample[i] = keys[i*len(keys)/maxObjects]
+	}
+	return sample, nil
+}
+
+// inferObject reads up to `maxEvents` log entries from an S3 object.
+// Objects are decoded like the log processor does, so all its compression, archive and record formats are supported.
+// nolint:lll
+func inferObject(ctx context.Context, client s3iface.S3API, infer *customlogs.SchemaInference, bucket, key string, maxEvents int) error {
+	reply, err := client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get s3://%s/%s", bucket, key)
+	}
+	streams, err := sources.OpenObject(reply.Body, key, aws.StringValue(reply.ContentEncoding), aws.StringValue(reply.ContentType))
+	if err != nil {
+		return NewAPIError(ErrInvalidSyntax, fmt.Sprintf("failed to read s3://%s/%s: %s", bucket, key, err))
+	}
+	defer func() {
+		for _, stream := range streams {
+			_ = stream.Close()
+		}
+	}()
+	for _, stream := range streams {
+		if maxEvents < 1 {
+			break
+		}
+		n, err := infer.InferStream(stream, maxEvents)
+		if err != nil {
+			return NewAPIError(ErrInvalidSyntax, fmt.Sprintf("failed to infer schema from s3://%s/%s: %s", bucket, stream.Key, err))
+		}
+		maxEvents -= n
+	}
+	return nil
+}
+
+// nolint:lll
+type InferCustomLogSchemaInput struct {
+	S3Bucket   string `json:"s3Bucket,omitempty" validate:"required_without=Sample" description:"The S3 bucket to sample log files from (must belong to an S3 source)"`
+	S3Prefix   string `json:"s3Prefix,omitempty" description:"The S3 prefix to sample log files from"`
+	MaxObjects int    `json:"maxObjects,omitempty" validate:"omitempty,min=1,max=100" description:"Max number of S3 objects to sample (defaults to 10)"`
+	MaxEvents  int    `json:"maxEvents,omitempty" validate:"omitempty,min=1,max=10000" description:"Max number of log entries to sample (defaults to 1000)"`
+	Sample     string `json:"sample,omitempty" validate:"required_without=S3Bucket" description:"Sample log entries to use instead of S3 objects"`
+	Parser     string `json:"parser,omitempty" description:"The parser configuration (csv, regex, fastmatch, ...) in YAML or JSON format (omit for JSON logs)"`
+}
+
+//nolint:lll
+type InferCustomLogSchemaOutput struct {
+	Spec       string    `json:"logSpec,omitempty" description:"The inferred schema spec in YAML format (field is omitted if an error occurred)"`
+	NumObjects int       `json:"numObjects,omitempty" description:"The number of S3 objects that were sampled"`
+	NumEvents  int       `json:"numEvents,omitempty" description:"The number of log entries that were sampled"`
+	Error      *APIError `json:"error,omitempty" description:"An error that occurred during the operation"`
+}
@@ -0,0 +1,195 @@
+package logtypesapi_test
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+
+	"github.com/panther-labs/panther/internal/core/logtypesapi"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logschema"
+	"github.com/panther-labs/panther/pkg/testutils"
+)
+
+func TestAPI_InferCustomLogSchema(t *testing.T) {
+	assert := require.New(t)
+	api := logtypesapi.LogTypesAPI{}
+	ctx := context.Background()
+	reply, err := api.InferCustomLogSchema(ctx, &logtypesapi.InferCustomLogSchemaInput{
+		Sample: "10.0.0.1,2020-01-02T10:11:12Z,GET\n10.0.0.2,2020-01-02T10:11:13Z,POST\n",
+		Parser: `
+csv:
+  delimiter: ','
+  columns: [remote_ip, time, method]
+`,
+	})
+	assert.NoError(err)
+	assert.Equal(2, reply.NumEvents)
+	assert.Equal(0, reply.NumObjects)
+	schema := logschema.Schema{}
+	assert.NoError(yaml.Unmarshal([]byte(reply.Spec), &schema))
+	assert.NotNil(schema.Parser)
+	assert.NotNil(schema.Parser.CSV)
+	assert.Len(schema.Fields, 3)
+	for _, f := range schema.Fields {
+		switch f.Name {
+		case "remote_ip":
+			assert.Equal([]string{"ip"}, f.Indicators)
+		case "time":
+			assert.Equal(logschema.TypeTimestamp, f.Type)
+			assert.Equal("rfc3339", f.TimeFormat)
+			assert.True(f.IsEventTime)
+		case "method":
+			assert.Equal(logschema.TypeString, f.Type)
+		default:
+			t.Errorf("unexpected field %q", f.Name)
+		}
+	}
+
+	_, err = api.InferCustomLogSchema(ctx, &logtypesapi.InferCustomLogSchemaInput{
+		Sample: "not json",
+	})
+	assert.Error(err)
+	assert.Equal(logtypesapi.ErrInvalidSyntax, logtypesapi.AsAPIError(err).Code)
+
+	_, err = api.InferCustomLogSchema(ctx, &logtypesapi.InferCustomLogSchemaInput{
+		Sample: `{"foo":"bar"}`,
+		Parser: "csv: [",
+	})
+	assert.Error(err)
+	assert.Equal(logtypesapi.ErrInvalidSyntax, logtypesapi.AsAPIError(err).Code)
+}
+
+func TestAPI_InferCustomLogSchemaS3(t *testing.T) {
+	assert := require.New(t)
+	s3Mock := &testutils.S3Mock{}
+	api := logtypesapi.LogTypesAPI{
+		SourceS3Client: func(_ context.Context, bucket string) (s3iface.S3API, error) {
+			if bucket == "bucket" {
+				return s3Mock, nil
+			}
+			return nil, nil
+		},
+	}
+	ctx := context.Background()
+	var gz bytes.Buffer
+	w := gzip.NewWriter(&gz)
+	_, _ = w.Write([]byte(`{"ts":"2020-01-02T10:11:12Z","url":"https://example.com"}` + "\n"))
+	assert.NoError(w.Close())
+	listOutput := &s3.ListObjectsV2Output{
+		Contents: []*s3.Object{
+			{Key: aws.String("logs/"), Size: aws.Int64(0)},
+			{Key: aws.String("logs/a.json"), Size: aws.Int64(10)},
+			{Key: aws.String("logs/b.json.gz"), Size: aws.Int64(10)},
+		},
+	}
+	s3Mock.On("ListObjectsV2PagesWithContext", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(listOutput, nil).Once()
+	s3Mock.On("GetObjectWithContext", mock.Anything, &s3.GetObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("logs/a.json"),
+	}, mock.Anything).Return(&s3.GetObjectOutput{
+		Body: ioutil.NopCloser(strings.NewReader(`{"ts":"2020-01-02T10:11:12Z","bytes":42}`)),
+	}, nil).Once()
+	s3Mock.On("GetObjectWithContext", mock.Anything, &s3.GetObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("logs/b.json.gz"),
+	}, mock.Anything).Return(&s3.GetObjectOutput{
+		Body: ioutil.NopCloser(&gz),
+	}, nil).Once()
+
+	reply, err := api.InferCustomLogSchema(ctx, &logtypesapi.InferCustomLogSchemaInput{
+		S3Bucket: "bucket",
+		S3Prefix: "logs/",
+	})
+	assert.NoError(err)
+	s3Mock.AssertExpectations(t)
+	assert.Equal(2, reply.NumObjects)
+	assert.Equal(2, reply.NumEvents)
+	schema := logschema.Schema{}
+	assert.NoError(yaml.Unmarshal([]byte(reply.Spec), &schema))
+	assert.Nil(schema.Parser)
+	assert.Len(schema.Fields, 3)
+
+	// Buckets of other sources cannot be read
+	_, err = api.InferCustomLogSchema(ctx, &logtypesapi.InferCustomLogSchemaInput{
+		S3Bucket: "other",
+	})
+	assert.Error(err)
+	assert.Equal(logtypesapi.ErrNotFound, logtypesapi.AsAPIError(err).Code)
+}
+
+func TestAPI_InferCustomLogSchemaS3Zip(t *testing.T) {
+	assert := require.New(t)
+	s3Mock := &testutils.S3Mock{}
+	api := logtypesapi.LogTypesAPI{
+		SourceS3Client: func(_ context.Context, _ string) (s3iface.S3API, error) {
+			return s3Mock, nil
+		},
+	}
+	// A zip archive with a plain and a zstd compressed log file
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	f, err := archive.Create("a.json")
+	assert.NoError(err)
+	_, _ = f.Write([]byte(`{"ts":"2020-01-02T10:11:12Z","bytes":42}` + "\n"))
+	f, err = archive.Create("b.json.zst")
+	assert.NoError(err)
+	enc, err := zstd.NewWriter(f)
+	assert.NoError(err)
+	_, _ = enc.Write([]byte(`{"ts":"2020-01-02T10:11:12Z","url":"https://example.com"}` + "\n"))
+	assert.NoError(enc.Close())
+	assert.NoError(archive.Close())
+	listOutput := &s3.ListObjectsV2Output{
+		Contents: []*s3.Object{
+			{Key: aws.String("logs/bundle.zip"), Size: aws.Int64(int64(buf.Len()))},
+		},
+	}
+	s3Mock.On("ListObjectsV2PagesWithContext", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(listOutput, nil).Once()
+	s3Mock.On("GetObjectWithContext", mock.Anything, &s3.GetObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("logs/bundle.zip"),
+	}, mock.Anything).Return(&s3.GetObjectOutput{
+		Body: ioutil.NopCloser(&buf),
+	}, nil).Once()
+
+	reply, err := api.InferCustomLogSchema(context.Background(), &logtypesapi.InferCustomLogSchemaInput{
+		S3Bucket: "bucket",
+		S3Prefix: "logs/",
+	})
+	assert.NoError(err)
+	s3Mock.AssertExpectations(t)
+	assert.Equal(1, reply.NumObjects)
+	assert.Equal(2, reply.NumEvents)
+	schema := logschema.Schema{}
+	assert.NoError(yaml.Unmarshal([]byte(reply.Spec), &schema))
+	assert.Len(schema.Fields, 3)
+}
@@ -20,6 +20,7 @@ package logtypesapi
 
 import (
 	"context"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -60,6 +61,22 @@ func (db *InMemDB) GetSchema(_ context.Context, id string, revision int64) (*Sch
 	return result, nil
 }
 
+func (db *InMemDB) ListSchemaRevisions(_ context.Context, id string) ([]*SchemaRecord, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	id = strings.ToUpper(id)
+	var records []*SchemaRecord
+	for key, record := range db.records {
+		if key.LogType == id && key.Revision > 0 {
+			records = append(records, record)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Revision < records[j].Revision
+	})
+	return records, nil
+}
+
 func (db *InMemDB) CreateUserSchema(ctx context.Context, name string, upd SchemaUpdate) (*SchemaRecord, error) {
 	now := time.Now()
 	db.mu.Lock()
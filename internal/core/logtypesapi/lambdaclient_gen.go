@@ -45,6 +45,10 @@ type LogTypesAPIPayload struct {
 	PutCustomLog             *PutCustomLogInput             `json:"PutCustomLog,omitempty"`
 	DelCustomLog             *DelCustomLogInput             `json:"DelCustomLog,omitempty"`
 	ListCustomLogs           *struct{}                      `json:"ListCustomLogs,omitempty"`
+	ListCustomLogRevisions   *ListCustomLogRevisionsInput   `json:"ListCustomLogRevisions,omitempty"`
+	RevertCustomLog          *RevertCustomLogInput          `json:"RevertCustomLog,omitempty"`
+	InferCustomLogSchema     *InferCustomLogSchemaInput     `json:"InferCustomLogSchema,omitempty"`
+	TestSchema               *TestSchemaInput               `json:"TestSchema,omitempty"`
 	ListManagedSchemaUpdates *ListManagedSchemaUpdatesInput `json:"ListManagedSchemaUpdates,omitempty"`
 	UpdateManagedSchemas     *UpdateManagedSchemasInput     `json:"UpdateManagedSchemas,omitempty"`
 	GetSchema                *GetSchemaInput                `json:"GetSchema,omitempty"`
@@ -125,6 +129,62 @@ func (c *LogTypesAPILambdaClient) ListCustomLogs(ctx context.Context) (*ListCust
 	return &reply, nil
 }
 
+func (c *LogTypesAPILambdaClient) ListCustomLogRevisions(ctx context.Context, input *ListCustomLogRevisionsInput) (*ListCustomLogRevisionsOutput, error) {
+	if input == nil {
+		input = &ListCustomLogRevisionsInput{}
+	}
+	payload := LogTypesAPIPayload{
+		ListCustomLogRevisions: input,
+	}
+	reply := ListCustomLogRevisionsOutput{}
+	if err := c.invoke(ctx, &payload, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+func (c *LogTypesAPILambdaClient) RevertCustomLog(ctx context.Context, input *RevertCustomLogInput) (*RevertCustomLogOutput, error) {
+	if input == nil {
+		input = &RevertCustomLogInput{}
+	}
+	payload := LogTypesAPIPayload{
+		RevertCustomLog: input,
+	}
+	reply := RevertCustomLogOutput{}
+	if err := c.invoke(ctx, &payload, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+func (c *LogTypesAPILambdaClient) InferCustomLogSchema(ctx context.Context, input *InferCustomLogSchemaInput) (*InferCustomLogSchemaOutput, error) {
+	if input == nil {
+		input = &InferCustomLogSchemaInput{}
+	}
+	payload := LogTypesAPIPayload{
+		InferCustomLogSchema: input,
+	}
+	reply := InferCustomLogSchemaOutput{}
+	if err := c.invoke(ctx, &payload, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+func (c *LogTypesAPILambdaClient) TestSchema(ctx context.Context, input *TestSchemaInput) (*TestSchemaOutput, error) {
+	if input == nil {
+		input = &TestSchemaInput{}
+	}
+	payload := LogTypesAPIPayload{
+		TestSchema: input,
+	}
+	reply := TestSchemaOutput{}
+	if err := c.invoke(ctx, &payload, &reply); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
 func (c *LogTypesAPILambdaClient) ListManagedSchemaUpdates(ctx context.Context, input *ListManagedSchemaUpdatesInput) (*ListManagedSchemaUpdatesOutput, error) {
 	if input == nil {
 		input = &ListManagedSchemaUpdatesInput{}
@@ -23,9 +23,14 @@ import (
 	"net/http"
 
 	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	lambdaclient "github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/aws/aws-sdk-go/service/sqs"
 	"github.com/google/go-github/github"
 	jsoniter "github.com/json-iterator/go"
@@ -64,6 +69,17 @@ func main() {
 
 	session := session.Must(session.NewSession())
 	lambdaClient := lambdaclient.New(session)
+	listIntegrations := func() ([]*models.SourceIntegration, error) {
+		input := &models.LambdaInput{
+			ListIntegrations: &models.ListIntegrationsInput{},
+		}
+		var integrations []*models.SourceIntegration
+		const sourcesAPILambda = "panther-source-api"
+		if err := genericapi.Invoke(lambdaClient, sourcesAPILambda, input, &integrations); err != nil {
+			return nil, errors.Wrap(err, "failed to retrieve existing integrations")
+		}
+		return integrations, nil
+	}
 	api := &logtypesapi.LogTypesAPI{
 		Database: &logtypesapi.DynamoDBSchemas{
 			DB:        dynamodb.New(session),
@@ -80,13 +96,9 @@ func main() {
 			return client.SendUpdateTableForLogType(ctx, logType)
 		},
 		LogTypesInUse: func(ctx context.Context) ([]string, error) {
-			input := &models.LambdaInput{
-				ListIntegrations: &models.ListIntegrationsInput{},
-			}
-			var integrations []*models.SourceIntegration
-			const sourcesAPILambda = "panther-source-api"
-			if err := genericapi.Invoke(lambdaClient, sourcesAPILambda, input, &integrations); err != nil {
-				return nil, errors.Wrap(err, "failed to retrieve existing integrations")
+			integrations, err := listIntegrations()
+			if err != nil {
+				return nil, err
 			}
 			var logTypes []string
 			for _, output := range integrations {
@@ -99,6 +111,30 @@ func main() {
 			Owner:  "panther-labs",
 			Client: github.NewClient(&http.Client{}),
 		},
+		SourceS3Client: func(ctx context.Context, bucket string) (s3iface.S3API, error) {
+			integrations, err := listIntegrations()
+			if err != nil {
+				return nil, err
+			}
+			for _, src := range integrations {
+				if src.IntegrationType != models.IntegrationTypeAWS3 || src.S3Bucket != bucket {
+					continue
+				}
+				// Read the bucket with the same role the log processor uses for the source
+				creds := stscreds.NewCredentials(session, src.LogProcessingRole)
+				region, err := s3manager.GetBucketRegionWithClient(ctx, s3.New(session, &aws.Config{
+					Credentials: creds,
+				}), bucket)
+				if err != nil {
+					return nil, errors.Wrapf(err, "failed to find the region of bucket %q", bucket)
+				}
+				return s3.New(session, &aws.Config{
+					Credentials: creds,
+					Region:      aws.String(region),
+				}), nil
+			}
+			return nil, nil
+		},
 	}
 
 	validate := validator.New()
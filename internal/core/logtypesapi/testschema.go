@@ -0,0 +1,166 @@
+package logtypesapi
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/classification"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/customlogs"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/logstream"
+)
+
+const (
+	// testSchemaMaxEntries limits the number of log entries tested in a single request
+	testSchemaMaxEntries = 1000
+	// testSchemaLogType is the log type used when no log type is provided
+	testSchemaLogType = "Custom.Test"
+)
+
+// TestSchema runs the parser of an unsaved custom log schema over sample log entries.
+// It reports the parsed events or the parser error for each log entry along with the aggregate parser stats.
+func (api *LogTypesAPI) TestSchema(_ context.Context, input *TestSchemaInput) (*TestSchemaOutput, error) {
+	name := testSchemaLogType
+	if input.LogType != "" {
+		name = customlogs.LogType(input.LogType)
+	}
+	schema, err := buildSchema(input.Spec)
+	if err != nil {
+		return nil, err
+	}
+	schema.Schema = name
+	if p := schema.Parser; p != nil && p.Native != nil {
+		return nil, NewAPIError(ErrInvalidLogSchema, "schemas using native parsers cannot be tested")
+	}
+	entry, err := customlogs.Build(name, schema)
+	if err != nil {
+		return nil, NewAPIError(ErrInvalidLogSchema, err.Error())
+	}
+	parser, err := entry.NewParser(nil)
+	if err != nil {
+		return nil, NewAPIError(ErrInvalidLogSchema, err.Error())
+	}
+
+	var stream logstream.Stream = logstream.NewLineStream(strings.NewReader(input.Sample), logstream.DefaultBufferSize)
+	if schema.Parser != nil && schema.Parser.Framing != nil {
+		framed, err := logstream.NewFramedStream(stream, schema.Parser.Framing)
+		if err != nil {
+			return nil, NewAPIError(ErrInvalidLogSchema, err.Error())
+		}
+		stream = framed
+	}
+
+	classifier := classification.NewClassifier(map[string]parsers.Interface{
+		name: parser,
+	})
+	jsonAPI := pantherlog.ConfigJSON()
+	var results []TestSchemaResult
+	// Blank log entries are skipped but still count towards the position of the following entries
+	line := 0
+	for len(results) < testSchemaMaxEntries {
+		log := stream.Next()
+		if log == nil {
+			break
+		}
+		line++
+		if strings.TrimSpace(string(log)) == "" {
+			continue
+		}
+		result := TestSchemaResult{
+			Line: line,
+			Log:  string(log),
+		}
+		classified, err := classifier.Classify(string(log))
+		if err != nil {
+			if parseErr := classified.ParserErrors[name]; parseErr != nil {
+				err = parseErr
+			}
+			result.Error = err.Error()
+			results = append(results, result)
+			continue
+		}
+		for _, event := range classified.Events {
+			data, err := jsonAPI.Marshal(event)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to encode event of log entry %d", result.Line)
+			}
+			result.Events = append(result.Events, string(data))
+		}
+		results = append(results, result)
+	}
+	if err := stream.Err(); err != nil {
+		return nil, NewAPIError(ErrInvalidSyntax, fmt.Sprintf("failed to read sample log entries: %s", err))
+	}
+
+	stats := TestSchemaStats{
+		LogType:             name,
+		NumFailed:           classifier.Stats().ClassificationFailureCount,
+		BytesProcessedCount: classifier.Stats().BytesProcessedCount,
+		LogLineCount:        classifier.Stats().LogLineCount,
+	}
+	if s := classifier.ParserStats()[name]; s != nil {
+		stats.ParserTimeMicroseconds = s.ParserTimeMicroseconds
+		stats.EventCount = s.EventCount
+		stats.EventFilteredCount = s.EventFilteredCount
+	}
+	return &TestSchemaOutput{
+		Results: results,
+		Stats:   stats,
+	}, nil
+}
+
+// nolint:lll
+type TestSchemaInput struct {
+	LogType string `json:"logType,omitempty" validate:"omitempty,startswith=Custom." description:"The log type id to use for the parsed events (defaults to Custom.Test)"`
+	Spec    string `json:"logSpec" validate:"required" description:"The schema spec in YAML or JSON format"`
+	Sample  string `json:"sample" validate:"required" description:"Sample log entries to parse (up to 1000 entries are tested)"`
+}
+
+//nolint:lll
+type TestSchemaOutput struct {
+	Results []TestSchemaResult `json:"results,omitempty" description:"The result of parsing each log entry (field is omitted if an error occurred)"`
+	Stats   TestSchemaStats    `json:"stats" description:"Aggregate parser stats for all log entries"`
+	Error   *APIError          `json:"error,omitempty" description:"An error that occurred during the operation"`
+}
+
+//nolint:lll
+type TestSchemaResult struct {
+	Line   int      `json:"line" description:"The position of the log entry in the sample (starting from 1)"`
+	Log    string   `json:"log" description:"The log entry"`
+	Events []string `json:"events,omitempty" description:"The parsed events in JSON format including p_* fields"`
+	Error  string   `json:"error,omitempty" description:"The decode or validation error if the log entry could not be parsed"`
+}
+
+// TestSchemaStats has the same aggregate stats as classification.ParserStats including failed log entries
+// nolint:lll
+type TestSchemaStats struct {
+	LogType                string `json:"logType" description:"The log type used to parse the log entries"`
+	ParserTimeMicroseconds uint64 `json:"parserTimeMicroseconds" description:"Total time spent parsing log entries"`
+	BytesProcessedCount    uint64 `json:"bytesProcessedCount" description:"Total size of the log entries"`
+	LogLineCount           uint64 `json:"logLineCount" description:"The number of log entries"`
+	EventCount             uint64 `json:"eventCount" description:"The number of parsed events"`
+	EventFilteredCount     uint64 `json:"eventFilteredCount" description:"The number of events dropped by event transforms"`
+	NumFailed              uint64 `json:"numFailed" description:"The number of log entries that failed to parse"`
+}
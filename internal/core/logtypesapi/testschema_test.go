@@ -0,0 +1,114 @@
+package logtypesapi_test
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/core/logtypesapi"
+)
+
+func TestAPI_TestSchema(t *testing.T) {
+	assert := require.New(t)
+	api := logtypesapi.LogTypesAPI{}
+	ctx := context.Background()
+	const spec = `
+fields:
+- name: time
+  type: timestamp
+  timeFormat: rfc3339
+  isEventTime: true
+  required: true
+- name: remote_ip
+  type: string
+  indicators: [ip]
+`
+	reply, err := api.TestSchema(ctx, &logtypesapi.TestSchemaInput{
+		LogType: "Custom.Sample",
+		Spec:    spec,
+		Sample: `{"time":"2020-01-02T10:11:12Z","remote_ip":"10.0.0.1"}
+
+{"remote_ip":"10.0.0.2"}
+{"time":"2020-01-02T10:11:13Z","remote_ip":"10.0.0.3"}
+not json
+`,
+	})
+	assert.NoError(err)
+	assert.Len(reply.Results, 4)
+
+	assert.Equal(1, reply.Results[0].Line)
+	assert.Empty(reply.Results[0].Error)
+	assert.Len(reply.Results[0].Events, 1)
+	assert.JSONEq(`{
+		"time":"2020-01-02T10:11:12Z",
+		"remote_ip":"10.0.0.1",
+		"p_log_type":"Custom.Sample",
+		"p_event_time":"2020-01-02T10:11:12Z",
+		"p_any_ip_addresses":["10.0.0.1"]
+	}`, withoutRandomFields(t, reply.Results[0].Events[0]))
+
+	// The blank line counts towards the line numbers
+	assert.Equal(3, reply.Results[1].Line)
+	assert.Equal(`{"remote_ip":"10.0.0.2"}`, reply.Results[1].Log)
+	assert.Empty(reply.Results[1].Events)
+	assert.Contains(reply.Results[1].Error, "validate failed")
+
+	assert.Equal(4, reply.Results[2].Line)
+	assert.Empty(reply.Results[2].Error)
+	assert.Len(reply.Results[2].Events, 1)
+
+	assert.Equal(5, reply.Results[3].Line)
+	assert.Contains(reply.Results[3].Error, "parse failed")
+
+	assert.Equal("Custom.Sample", reply.Stats.LogType)
+	assert.Equal(uint64(4), reply.Stats.LogLineCount)
+	assert.Equal(uint64(2), reply.Stats.EventCount)
+	assert.Equal(uint64(2), reply.Stats.NumFailed)
+
+	_, err = api.TestSchema(ctx, &logtypesapi.TestSchemaInput{
+		Spec:   "fields: [",
+		Sample: `{}`,
+	})
+	assert.Error(err)
+	assert.Equal(logtypesapi.ErrInvalidSyntax, logtypesapi.AsAPIError(err).Code)
+
+	_, err = api.TestSchema(ctx, &logtypesapi.TestSchemaInput{
+		Spec:   "fields: []",
+		Sample: `{}`,
+	})
+	assert.Error(err)
+	assert.Equal(logtypesapi.ErrInvalidLogSchema, logtypesapi.AsAPIError(err).Code)
+}
+
+func withoutRandomFields(t *testing.T, event string) string {
+	t.Helper()
+	values := map[string]interface{}{}
+	require.NoError(t, jsoniter.UnmarshalFromString(event, &values))
+	for _, name := range []string{"p_parse_time", "p_row_id"} {
+		require.Contains(t, values, name)
+		delete(values, name)
+	}
+	data, err := jsoniter.MarshalToString(values)
+	require.NoError(t, err)
+	return data
+}
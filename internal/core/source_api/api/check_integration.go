@@ -57,6 +57,10 @@ func (api *API) CheckIntegration(input *models.CheckIntegrationInput) (*models.S
 		return api.checkAwsS3Integration(input), nil
 	case models.IntegrationTypeSqs:
 		return api.checkSqsQueueHealth(input), nil
+	case models.IntegrationTypeHTTP:
+		return checkHTTPConfig(input), nil
+	case models.IntegrationTypePoller:
+		return checkPollerConfig(input), nil
 	default:
 		return nil, checkIntegrationInternalError
 	}
@@ -228,6 +232,10 @@ func (api *API) evaluateIntegration(integration *models.CheckIntegrationInput) (
 			return status.SqsStatus.Message, false, nil
 		}
 		return status.SqsStatus.Message, true, nil
+	case models.IntegrationTypeHTTP:
+		return status.HTTPStatus.Message, status.HTTPStatus.Healthy, nil
+	case models.IntegrationTypePoller:
+		return status.PollerStatus.Message, status.PollerStatus.Healthy, nil
 
 	default:
 		return "", false, errors.New("invalid integration type")
@@ -265,3 +273,36 @@ func (api *API) checkSqsQueueHealth(input *models.CheckIntegrationInput) *models
 	health.SqsStatus.Message = "We were able to call sqs:GetQueueAttributes on the specified SQS queue."
 	return health
 }
+
+// Check the authentication settings of an HTTP source
+func checkHTTPConfig(input *models.CheckIntegrationInput) *models.SourceIntegrationHealth {
+	health := &models.SourceIntegrationHealth{
+		IntegrationType: input.IntegrationType,
+	}
+	if input.HTTPConfig == nil {
+		health.HTTPStatus.Message = "HTTP source settings are missing."
+		return health
+	}
+	health.HTTPStatus.Healthy = true
+	health.HTTPStatus.Message = "HTTP source authentication is configured."
+	return health
+}
+
+// Check the settings of a SaaS poller source
+func checkPollerConfig(input *models.CheckIntegrationInput) *models.SourceIntegrationHealth {
+	health := &models.SourceIntegrationHealth{
+		IntegrationType: input.IntegrationType,
+	}
+	if input.PollerConfig == nil {
+		health.PollerStatus.Message = "SaaS poller source settings are missing."
+		return health
+	}
+	if err := input.PollerConfig.ValidateLogTypes(); err != nil {
+		health.PollerStatus.Message = "The selected log types are not available for this vendor."
+		health.PollerStatus.ErrorMessage = err.Error()
+		return health
+	}
+	health.PollerStatus.Healthy = true
+	health.PollerStatus.Message = "SaaS poller source is configured."
+	return health
+}
@@ -19,11 +19,14 @@ package api
  */
 
 import (
+	"context"
+
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
 	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/core/source_api/secrets"
 	"github.com/panther-labs/panther/pkg/genericapi"
 )
 
@@ -99,6 +102,13 @@ func (api *API) DeleteIntegration(input *models.DeleteIntegrationInput) error {
 				zap.Error(err))
 			return deleteIntegrationInternalError
 		}
+	case models.IntegrationTypeHTTP, models.IntegrationTypePoller:
+		if err := secrets.Delete(context.TODO(), api.SecretsClient, input.IntegrationID); err != nil {
+			zap.L().Error("failed to delete source secret",
+				zap.String("integrationId", input.IntegrationID),
+				zap.Error(err))
+			return deleteIntegrationInternalError
+		}
 	}
 
 	err = api.DdbClient.DeleteItem(input.IntegrationID)
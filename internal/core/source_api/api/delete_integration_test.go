@@ -26,6 +26,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
 	"github.com/aws/aws-sdk-go/service/sqs"
 	jsoniter "github.com/json-iterator/go"
 	"github.com/stretchr/testify/assert"
@@ -52,6 +53,25 @@ func TestDeleteIntegrationItem(t *testing.T) {
 	apiTest.AssertExpectations(t)
 }
 
+func TestDeleteHTTPIntegration(t *testing.T) {
+	t.Parallel()
+	apiTest := NewAPITest()
+
+	apiTest.mockDdb.On("DeleteItem", mock.Anything).Return(&dynamodb.DeleteItemOutput{}, nil)
+	apiTest.mockDdb.On("GetItem", mock.Anything).Return(generateGetItemOutput(models.IntegrationTypeHTTP), nil)
+	apiTest.mockSecrets.On("DeleteSecretWithContext", mock.Anything, &secretsmanager.DeleteSecretInput{
+		SecretId:                   aws.String("panther/sources/" + testIntegrationID),
+		ForceDeleteWithoutRecovery: aws.Bool(true),
+	}, mock.Anything).Return(&secretsmanager.DeleteSecretOutput{}, nil)
+
+	result := apiTest.DeleteIntegration(&models.DeleteIntegrationInput{
+		IntegrationID: testIntegrationID,
+	})
+
+	assert.NoError(t, result)
+	apiTest.AssertExpectations(t)
+}
+
 func TestDeleteLogIntegration(t *testing.T) {
 	t.Parallel()
 	apiTest := NewAPITest()
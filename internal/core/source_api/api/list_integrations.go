@@ -55,6 +55,11 @@ func (api *API) ListIntegrations(
 				integ.LogProcessingRole = api.Config.InputDataRoleArn
 			}
 		}
+		// HTTP sources created before the receiver forwarded log entries to the input data bucket
+		if integ.IntegrationType == models.IntegrationTypeHTTP && integ.S3Bucket == "" {
+			integ.S3Bucket = api.Config.InputDataBucketName
+			integ.LogProcessingRole = api.Config.InputDataRoleArn
+		}
 		result = append(result, integ)
 	}
 	return result, nil
@@ -34,7 +34,10 @@ import (
 	"github.com/panther-labs/panther/api/lambda/source/models"
 	pollermodels "github.com/panther-labs/panther/internal/compliance/snapshot_poller/models/poller"
 	awspoller "github.com/panther-labs/panther/internal/compliance/snapshot_poller/pollers/aws"
+	"github.com/panther-labs/panther/internal/core/source_api/secrets"
 	"github.com/panther-labs/panther/internal/log_analysis/datacatalog_updater/datacatalog"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/sampling"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/transform"
 	"github.com/panther-labs/panther/pkg/awsbatch/sqsbatch"
 	"github.com/panther-labs/panther/pkg/genericapi"
 	"github.com/panther-labs/panther/pkg/stringset"
@@ -73,6 +76,11 @@ func (api *API) PutIntegration(input *models.PutIntegrationInput) (newIntegratio
 		return nil, putIntegrationInternalError
 	}
 
+	if err = api.putSourceSecret(newIntegration.IntegrationID, input.IntegrationType, input.HTTPConfig, input.PollerConfig); err != nil {
+		zap.L().Error("failed to store source secret", zap.Error(err))
+		return nil, putIntegrationInternalError
+	}
+
 	if newIntegration.ManagedBucketNotifications {
 		api.handleManagedBucketNotifications(newIntegration)
 	}
@@ -81,6 +89,12 @@ func (api *API) PutIntegration(input *models.PutIntegrationInput) (newIntegratio
 	item := integrationToItem(newIntegration)
 	if err = api.DdbClient.PutItem(item); err != nil {
 		zap.L().Error("failed to store source integration in DDB", zap.Error(err))
+		// Do not leave behind the secret of a source that does not exist
+		if err := api.deleteSourceSecret(newIntegration.IntegrationID, input.IntegrationType); err != nil {
+			zap.L().Error("failed to delete source secret",
+				zap.String("integrationId", newIntegration.IntegrationID),
+				zap.Error(err))
+		}
 		return nil, putIntegrationInternalError
 	}
 
@@ -120,6 +134,10 @@ func (api *API) setupExternalResources(integration *models.SourceIntegration) er
 		if err := api.AddSourceAsLambdaTrigger(integration.IntegrationID); err != nil {
 			return errors.Wrap(err, "failed to configure queue as lambda source")
 		}
+	case models.IntegrationTypeHTTP:
+		if err := api.AllowInputDataBucketSubscription(); err != nil {
+			return errors.Wrap(err, "failed to enable subscription for input bucket")
+		}
 	}
 	return nil
 }
@@ -133,6 +151,31 @@ func (api *API) validateIntegration(input *models.PutIntegrationInput) error {
 				Message: "Cannot have duplicate prefixes in an s3 source.",
 			}
 		}
+		if err := input.S3PrefixLogTypes.ValidateFraming(); err != nil {
+			return &genericapi.InvalidInputError{
+				Message: err.Error(),
+			}
+		}
+	}
+	if input.IntegrationType == models.IntegrationTypeHTTP && input.HTTPConfig != nil && input.HTTPConfig.AuthSecret == "" {
+		return &genericapi.InvalidInputError{
+			Message: "HTTP sources need an authentication secret.",
+		}
+	}
+	if input.IntegrationType == models.IntegrationTypePoller && input.PollerConfig != nil && input.PollerConfig.ClientSecret == "" {
+		return &genericapi.InvalidInputError{
+			Message: "SaaS poller sources need a client secret.",
+		}
+	}
+	if err := transform.Validate(input.Transforms); err != nil {
+		return &genericapi.InvalidInputError{
+			Message: err.Error(),
+		}
+	}
+	if err := sampling.Validate(input.Sampling); err != nil {
+		return &genericapi.InvalidInputError{
+			Message: err.Error(),
+		}
 	}
 
 	// Validate the new integration (healthcheck).
@@ -151,6 +194,8 @@ func (api *API) validateIntegration(input *models.PutIntegrationInput) error {
 		S3PrefixLogTypes:  input.S3PrefixLogTypes,
 		KmsKey:            input.KmsKey,
 		SqsConfig:         input.SqsConfig,
+		HTTPConfig:        input.HTTPConfig,
+		PollerConfig:      input.PollerConfig,
 	})
 	if err != nil {
 		return putIntegrationInternalError
@@ -210,9 +255,9 @@ func (api API) integrationAlreadyExists(input *models.PutIntegrationInput) error
 						}
 					}
 				}
-			case models.IntegrationTypeSqs:
+			case models.IntegrationTypeSqs, models.IntegrationTypeHTTP, models.IntegrationTypePoller:
 				if existingIntegration.IntegrationLabel == input.IntegrationLabel {
-					// Sqs sources need to have different labels
+					// Sqs, HTTP and poller sources need to have different labels
 					return &genericapi.InvalidInputError{
 						Message: fmt.Sprintf("Integration with label %s already exists", input.IntegrationLabel),
 					}
@@ -283,6 +328,8 @@ func (api *API) generateNewIntegration(input *models.PutIntegrationInput) *model
 		IntegrationID:    uuid.New().String(),
 		IntegrationLabel: input.IntegrationLabel,
 		IntegrationType:  input.IntegrationType,
+		Transforms:       input.Transforms,
+		Sampling:         input.Sampling,
 	}
 
 	switch input.IntegrationType {
@@ -316,6 +363,24 @@ func (api *API) generateNewIntegration(input *models.PutIntegrationInput) *model
 			LogTypes:             input.SqsConfig.LogTypes,
 			QueueURL:             api.SourceSqsQueueURL(metadata.IntegrationID),
 		}
+	case models.IntegrationTypeHTTP:
+		metadata.S3Bucket = api.Config.InputDataBucketName
+		metadata.LogProcessingRole = api.Config.InputDataRoleArn
+		metadata.HTTPConfig = &models.HTTPConfig{
+			LogTypes:          input.HTTPConfig.LogTypes,
+			AuthMethod:        input.HTTPConfig.AuthMethod,
+			AuthHeader:        input.HTTPConfig.AuthHeader,
+			AuthHashAlgorithm: input.HTTPConfig.AuthHashAlgorithm,
+		}
+	case models.IntegrationTypePoller:
+		metadata.PollerConfig = &models.PollerConfig{
+			Vendor:           input.PollerConfig.Vendor,
+			LogTypes:         input.PollerConfig.LogTypes,
+			BaseURL:          input.PollerConfig.BaseURL,
+			ClientID:         input.PollerConfig.ClientID,
+			Subject:          input.PollerConfig.Subject,
+			PollIntervalMins: input.PollerConfig.PollIntervalMins,
+		}
 	}
 	return &models.SourceIntegration{
 		SourceIntegrationMetadata: metadata,
@@ -334,3 +399,35 @@ func (api *API) createTables(integration *models.SourceIntegration) error {
 	}
 	return nil
 }
+
+// putSourceSecret stores the credentials of a source in Secrets Manager.
+// They are never stored with the source or returned by the API.
+func (api *API) putSourceSecret(integrationID, integrationType string,
+	httpConfig *models.HTTPConfig, pollerConfig *models.PollerConfig) error {
+
+	var secret string
+	switch integrationType {
+	case models.IntegrationTypeHTTP:
+		if httpConfig != nil {
+			secret = httpConfig.AuthSecret
+		}
+	case models.IntegrationTypePoller:
+		if pollerConfig != nil {
+			secret = pollerConfig.ClientSecret
+		}
+	}
+	if secret == "" {
+		return nil
+	}
+	return secrets.Put(context.TODO(), api.SecretsClient, integrationID, secret)
+}
+
+// deleteSourceSecret deletes the credentials stored by putSourceSecret
+func (api *API) deleteSourceSecret(integrationID, integrationType string) error {
+	switch integrationType {
+	case models.IntegrationTypeHTTP, models.IntegrationTypePoller:
+		return secrets.Delete(context.TODO(), api.SecretsClient, integrationID)
+	default:
+		return nil
+	}
+}
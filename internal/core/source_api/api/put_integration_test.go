@@ -24,8 +24,10 @@ import (
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
 	"github.com/aws/aws-sdk-go/service/sqs"
 	jsoniter "github.com/json-iterator/go"
 	"github.com/stretchr/testify/assert"
@@ -391,3 +393,145 @@ func TestPutSqsIntegration(t *testing.T) {
 	assert.JSONEq(t, expectedSqsQueuePolicy, *createQueueRequest.Attributes["Policy"])
 	apiTest.AssertExpectations(t)
 }
+
+func TestPutHTTPIntegration(t *testing.T) {
+	t.Parallel()
+	apiTest := NewAPITest()
+	apiTest.DdbClient = &ddb.DDB{Client: &modelstest.MockDDBClient{TestErr: false}, TableName: "test"}
+	apiTest.Config.InputDataBucketName = "input-data"
+	apiTest.Config.InputDataRoleArn = "role-arn"
+	apiTest.EvaluateIntegrationFunc = func(_ *models.CheckIntegrationInput) (string, bool, error) { return "", true, nil }
+	apiTest.mockSqs.On("SendMessageWithContext", mock.Anything, mock.Anything).Return(&sqs.SendMessageOutput{}, nil)
+	// Configuring the Log Processor SQS queue to receive notifications from the input data bucket
+	apiTest.mockSqs.On("GetQueueAttributes", mock.Anything).
+		Return(&sqs.GetQueueAttributesOutput{Attributes: generateQueueAttributeOutput(t, []string{})}, nil).Once()
+	apiTest.mockSqs.On("SetQueueAttributes", mock.Anything).Return(&sqs.SetQueueAttributesOutput{}, nil).Once()
+	apiTest.mockSecrets.On("PutSecretValueWithContext", mock.Anything, mock.Anything, mock.Anything).
+		Return(&secretsmanager.PutSecretValueOutput{}, awserr.New(secretsmanager.ErrCodeResourceNotFoundException, "", nil))
+	apiTest.mockSecrets.On("CreateSecretWithContext", mock.Anything, mock.Anything, mock.Anything).
+		Return(&secretsmanager.CreateSecretOutput{}, nil)
+
+	out, err := apiTest.PutIntegration(&models.PutIntegrationInput{
+		PutIntegrationSettings: models.PutIntegrationSettings{
+			IntegrationLabel: testIntegrationLabel,
+			IntegrationType:  models.IntegrationTypeHTTP,
+			HTTPConfig: &models.HTTPConfig{
+				LogTypes:   []string{"Okta.SystemLog"},
+				AuthMethod: models.HTTPAuthBearer,
+				AuthSecret: "0123456789abcdef",
+			},
+		},
+	})
+
+	// Verify returned values
+	require.NoError(t, err)
+	require.NotEmpty(t, out)
+	// Firehose writes the log entries of each source to its own prefix in the input data bucket
+	bucket, prefixes := out.S3Info()
+	assert.Equal(t, "input-data", bucket)
+	assert.Equal(t, []string{"http/" + out.IntegrationID}, prefixes)
+	assert.Equal(t, "role-arn", out.RequiredLogProcessingRole())
+	assert.Equal(t, []string{"Okta.SystemLog"}, out.RequiredLogTypes())
+	assert.Equal(t, models.HTTPAuthBearer, out.HTTPConfig.AuthMethod)
+	// The secret is only stored in Secrets Manager
+	assert.Empty(t, out.HTTPConfig.AuthSecret)
+	createSecret := apiTest.mockSecrets.Calls[1].Arguments.Get(1).(*secretsmanager.CreateSecretInput)
+	assert.Equal(t, "panther/sources/"+out.IntegrationID, *createSecret.Name)
+	assert.Equal(t, "0123456789abcdef", *createSecret.SecretString)
+	apiTest.AssertExpectations(t)
+}
+
+// putItemErrDDBClient fails to store sources
+type putItemErrDDBClient struct {
+	*modelstest.MockDDBClient
+}
+
+func (*putItemErrDDBClient) PutItem(_ *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+	return nil, errors.New("fake PutItem error")
+}
+
+func TestPutHTTPIntegrationDatabaseError(t *testing.T) {
+	t.Parallel()
+	apiTest := NewAPITest()
+	apiTest.DdbClient = &ddb.DDB{Client: &putItemErrDDBClient{&modelstest.MockDDBClient{}}, TableName: "test"}
+	apiTest.EvaluateIntegrationFunc = func(_ *models.CheckIntegrationInput) (string, bool, error) { return "", true, nil }
+	apiTest.mockSqs.On("SendMessageWithContext", mock.Anything, mock.Anything).Return(&sqs.SendMessageOutput{}, nil)
+	apiTest.mockSqs.On("GetQueueAttributes", mock.Anything).
+		Return(&sqs.GetQueueAttributesOutput{Attributes: generateQueueAttributeOutput(t, []string{})}, nil).Once()
+	apiTest.mockSqs.On("SetQueueAttributes", mock.Anything).Return(&sqs.SetQueueAttributesOutput{}, nil).Once()
+	apiTest.mockSecrets.On("PutSecretValueWithContext", mock.Anything, mock.Anything, mock.Anything).
+		Return(&secretsmanager.PutSecretValueOutput{}, awserr.New(secretsmanager.ErrCodeResourceNotFoundException, "", nil))
+	apiTest.mockSecrets.On("CreateSecretWithContext", mock.Anything, mock.Anything, mock.Anything).
+		Return(&secretsmanager.CreateSecretOutput{}, nil)
+	apiTest.mockSecrets.On("DeleteSecretWithContext", mock.Anything, mock.Anything, mock.Anything).
+		Return(&secretsmanager.DeleteSecretOutput{}, nil)
+
+	out, err := apiTest.PutIntegration(&models.PutIntegrationInput{
+		PutIntegrationSettings: models.PutIntegrationSettings{
+			IntegrationLabel: testIntegrationLabel,
+			IntegrationType:  models.IntegrationTypeHTTP,
+			HTTPConfig: &models.HTTPConfig{
+				LogTypes:   []string{"Okta.SystemLog"},
+				AuthMethod: models.HTTPAuthBearer,
+				AuthSecret: "0123456789abcdef",
+			},
+		},
+	})
+	require.Error(t, err)
+	assert.Nil(t, out)
+	// The secret of the source that failed to be stored is deleted
+	createSecret := apiTest.mockSecrets.Calls[1].Arguments.Get(1).(*secretsmanager.CreateSecretInput)
+	deleteSecret := apiTest.mockSecrets.Calls[2].Arguments.Get(1).(*secretsmanager.DeleteSecretInput)
+	assert.Equal(t, *createSecret.Name, *deleteSecret.SecretId)
+	apiTest.AssertExpectations(t)
+}
+
+func TestPutPollerIntegration(t *testing.T) {
+	t.Parallel()
+	apiTest := NewAPITest()
+	apiTest.DdbClient = &ddb.DDB{Client: &modelstest.MockDDBClient{TestErr: false}, TableName: "test"}
+	apiTest.EvaluateIntegrationFunc = apiTest.evaluateIntegration
+	apiTest.mockSqs.On("SendMessageWithContext", mock.Anything, mock.Anything).Return(&sqs.SendMessageOutput{}, nil)
+	apiTest.mockSecrets.On("PutSecretValueWithContext", mock.Anything, mock.Anything, mock.Anything).
+		Return(&secretsmanager.PutSecretValueOutput{}, awserr.New(secretsmanager.ErrCodeResourceNotFoundException, "", nil))
+	apiTest.mockSecrets.On("CreateSecretWithContext", mock.Anything, mock.Anything, mock.Anything).
+		Return(&secretsmanager.CreateSecretOutput{}, nil)
+
+	input := &models.PutIntegrationInput{
+		PutIntegrationSettings: models.PutIntegrationSettings{
+			IntegrationLabel: testIntegrationLabel,
+			IntegrationType:  models.IntegrationTypePoller,
+			PollerConfig: &models.PollerConfig{
+				Vendor:       models.PollerVendorSlack,
+				LogTypes:     []string{"Okta.SystemLog"},
+				ClientSecret: "token",
+			},
+		},
+	}
+	// The log types need to be provided by the vendor
+	_, err := apiTest.PutIntegration(input)
+	require.Error(t, err)
+
+	input.PollerConfig.LogTypes = []string{"Slack.AuditLogs", "Slack.AccessLogs"}
+	out, err := apiTest.PutIntegration(input)
+	require.NoError(t, err)
+	require.NotEmpty(t, out)
+	bucket, prefixes := out.S3Info()
+	assert.Empty(t, bucket)
+	assert.Empty(t, prefixes)
+	assert.Empty(t, out.RequiredLogProcessingRole())
+	assert.Equal(t, []string{"Slack.AuditLogs", "Slack.AccessLogs"}, out.RequiredLogTypes())
+	assert.Equal(t, models.PollerVendorSlack, out.PollerConfig.Vendor)
+	// The secret is only stored in Secrets Manager
+	assert.Empty(t, out.PollerConfig.ClientSecret)
+	createSecret := apiTest.mockSecrets.Calls[1].Arguments.Get(1).(*secretsmanager.CreateSecretInput)
+	assert.Equal(t, "panther/sources/"+out.IntegrationID, *createSecret.Name)
+	assert.Equal(t, "token", *createSecret.SecretString)
+
+	// A secret is required
+	input.IntegrationLabel = "other"
+	input.PollerConfig.ClientSecret = ""
+	_, err = apiTest.PutIntegration(input)
+	require.Error(t, err)
+	apiTest.AssertExpectations(t)
+}
@@ -23,12 +23,16 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
 	"github.com/panther-labs/panther/api/lambda/source/models"
 	"github.com/panther-labs/panther/internal/core/source_api/ddb"
 	"github.com/panther-labs/panther/internal/log_analysis/datacatalog_updater/datacatalog"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/sampling"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/transform"
 	"github.com/panther-labs/panther/pkg/genericapi"
 	"github.com/panther-labs/panther/pkg/stringset"
 )
@@ -68,6 +72,12 @@ func (api *API) UpdateIntegrationSettings(input *models.UpdateIntegrationSetting
 
 	updateIntegrationDBItem(existingItem, input)
 
+	// The current secret is kept if a new one is not provided
+	if err := api.putSourceSecret(existingItem.IntegrationID, existingItem.IntegrationType, input.HTTPConfig, input.PollerConfig); err != nil {
+		zap.L().Error("failed to update source secret", zap.Error(err))
+		return nil, updateIntegrationInternalError
+	}
+
 	if existingItem.IntegrationType == models.IntegrationTypeSqs {
 		err := api.UpdateSourceSqsQueue(
 			existingItem.IntegrationID, existingItem.SqsConfig.AllowedPrincipalArns, existingItem.SqsConfig.AllowedSourceArns)
@@ -108,6 +118,8 @@ func (api *API) checkSource(existingItem *ddb.Integration, input *models.UpdateI
 		S3PrefixLogTypes:  input.S3PrefixLogTypes,
 		KmsKey:            input.KmsKey,
 		SqsConfig:         input.SqsConfig,
+		HTTPConfig:        input.HTTPConfig,
+		PollerConfig:      input.PollerConfig,
 	})
 	if err != nil {
 		return err
@@ -134,6 +146,31 @@ func (api *API) validateUniqueConstraints(existingIntegrationItem *ddb.Integrati
 				Message: "Cannot have duplicate prefixes in an s3 source.",
 			}
 		}
+		if err := input.S3PrefixLogTypes.ValidateFraming(); err != nil {
+			return &genericapi.InvalidInputError{
+				Message: err.Error(),
+			}
+		}
+	}
+	if existingIntegrationItem.IntegrationType == models.IntegrationTypeHTTP && input.HTTPConfig == nil {
+		return &genericapi.InvalidInputError{
+			Message: "HTTP source settings are missing.",
+		}
+	}
+	if existingIntegrationItem.IntegrationType == models.IntegrationTypePoller && input.PollerConfig == nil {
+		return &genericapi.InvalidInputError{
+			Message: "SaaS poller source settings are missing.",
+		}
+	}
+	if err := transform.Validate(input.Transforms); err != nil {
+		return &genericapi.InvalidInputError{
+			Message: err.Error(),
+		}
+	}
+	if err := sampling.Validate(input.Sampling); err != nil {
+		return &genericapi.InvalidInputError{
+			Message: err.Error(),
+		}
 	}
 
 	existingIntegrations, err := api.ListIntegrations(&models.ListIntegrationsInput{})
@@ -168,9 +205,9 @@ func (api *API) validateUniqueConstraints(existingIntegrationItem *ddb.Integrati
 						}
 					}
 				}
-			case models.IntegrationTypeSqs:
+			case models.IntegrationTypeSqs, models.IntegrationTypeHTTP, models.IntegrationTypePoller:
 				if existingIntegration.IntegrationLabel == input.IntegrationLabel {
-					// Sqs sources need to have different labels
+					// Sqs, HTTP and poller sources need to have different labels
 					return &genericapi.InvalidInputError{
 						Message: fmt.Sprintf("Integration with label %s already exists", input.IntegrationLabel),
 					}
@@ -182,6 +219,13 @@ func (api *API) validateUniqueConstraints(existingIntegrationItem *ddb.Integrati
 }
 
 func updateIntegrationDBItem(item *ddb.Integration, input *models.UpdateIntegrationSettingsInput) {
+	// Clients that do not manage transforms or sampling (ie the web app) leave them out of the input
+	if input.Transforms != nil {
+		item.Transforms = input.Transforms
+	}
+	if input.Sampling != nil {
+		item.Sampling = input.Sampling
+	}
 	switch item.IntegrationType {
 	case models.IntegrationTypeAWSScan:
 		item.IntegrationLabel = input.IntegrationLabel
@@ -209,6 +253,29 @@ func updateIntegrationDBItem(item *ddb.Integration, input *models.UpdateIntegrat
 		item.SqsConfig.LogTypes = input.SqsConfig.LogTypes
 		item.SqsConfig.AllowedSourceArns = input.SqsConfig.AllowedSourceArns
 		item.SqsConfig.AllowedPrincipalArns = input.SqsConfig.AllowedPrincipalArns
+	case models.IntegrationTypeHTTP:
+		item.IntegrationLabel = input.IntegrationLabel
+		if input.HTTPConfig == nil {
+			break
+		}
+		if item.HTTPConfig == nil {
+			item.HTTPConfig = &ddb.HTTPConfig{}
+		}
+		item.HTTPConfig.LogTypes = input.HTTPConfig.LogTypes
+		item.HTTPConfig.AuthMethod = input.HTTPConfig.AuthMethod
+		item.HTTPConfig.AuthHeader = input.HTTPConfig.AuthHeader
+		item.HTTPConfig.AuthHashAlgorithm = input.HTTPConfig.AuthHashAlgorithm
+	case models.IntegrationTypePoller:
+		item.IntegrationLabel = input.IntegrationLabel
+		if input.PollerConfig == nil || item.PollerConfig == nil {
+			break
+		}
+		// The cursors are kept so that the poller resumes where it stopped
+		item.PollerConfig.LogTypes = input.PollerConfig.LogTypes
+		item.PollerConfig.BaseURL = input.PollerConfig.BaseURL
+		item.PollerConfig.ClientID = input.PollerConfig.ClientID
+		item.PollerConfig.Subject = input.PollerConfig.Subject
+		item.PollerConfig.PollIntervalMins = input.PollerConfig.PollIntervalMins
 	}
 }
 
@@ -245,6 +312,20 @@ func (api *API) UpdateIntegrationLastScanEnd(input *models.UpdateIntegrationLast
 	return nil
 }
 
+// UpdatePollerCheckpoint stores the position reached by the poller of a SaaS source.
+func (api *API) UpdatePollerCheckpoint(input *models.UpdatePollerCheckpointInput) error {
+	err := api.DdbClient.UpdatePollerCheckpoint(input.IntegrationID, input.Cursors, input.LastPollTime, input.LastPollError)
+	if err != nil {
+		var awsErr awserr.Error
+		if errors.As(err, &awsErr) && awsErr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+			return &genericapi.InvalidInputError{Message: "Integration is not a SaaS poller source"}
+		}
+		zap.L().Error("failed to update poller checkpoint", zap.Error(err))
+		return &genericapi.InternalError{Message: "Failed updating the poller checkpoint"}
+	}
+	return nil
+}
+
 func (api *API) getItem(integrationID string) (*ddb.Integration, error) {
 	item, err := api.DdbClient.GetItem(integrationID)
 	if err != nil {
@@ -267,6 +348,16 @@ func (api *API) updateTables(item *ddb.Integration, input *models.UpdateIntegrat
 	case models.IntegrationTypeSqs:
 		existingLogTypes = item.SqsConfig.LogTypes
 		newLogTypes = input.SqsConfig.LogTypes
+	case models.IntegrationTypeHTTP:
+		if item.HTTPConfig != nil {
+			existingLogTypes = item.HTTPConfig.LogTypes
+		}
+		newLogTypes = input.HTTPConfig.LogTypes
+	case models.IntegrationTypePoller:
+		if item.PollerConfig != nil {
+			existingLogTypes = item.PollerConfig.LogTypes
+		}
+		newLogTypes = input.PollerConfig.LogTypes
 	}
 
 	// If the user hasn't added new log types to the integration
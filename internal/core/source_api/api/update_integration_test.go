@@ -24,7 +24,9 @@ import (
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	"github.com/aws/aws-sdk-go/service/sqs"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -33,6 +35,9 @@ import (
 
 	"github.com/panther-labs/panther/api/lambda/source/models"
 	"github.com/panther-labs/panther/internal/core/source_api/ddb"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/sampling"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/transform"
+	"github.com/panther-labs/panther/pkg/genericapi"
 )
 
 func TestUpdateIntegrationSettingsAwsScanType(t *testing.T) {
@@ -119,6 +124,88 @@ func TestUpdateIntegrationSettingsAwsS3Type(t *testing.T) {
 	apiTest.AssertExpectations(t)
 }
 
+func TestUpdateIntegrationKeepsTransforms(t *testing.T) {
+	t.Parallel()
+	apiTest := NewAPITest()
+
+	// Mocking health check
+	apiTest.EvaluateIntegrationFunc = func(_ *models.CheckIntegrationInput) (string, bool, error) {
+		return "", true, nil
+	}
+
+	transforms := []transform.Config{{LogType: "Log.TypeA", Mask: []string{"password"}}}
+	transformsAttr, err := dynamodbattribute.Marshal(transforms)
+	require.NoError(t, err)
+	getResponse := &dynamodb.GetItemOutput{Item: map[string]*dynamodb.AttributeValue{
+		"integrationId":   {S: aws.String(testIntegrationID)},
+		"integrationType": {S: aws.String(models.IntegrationTypeAWS3)},
+		"logTypes":        {SS: aws.StringSlice([]string{"Log.TypeA"})},
+		"transforms":      transformsAttr,
+	}}
+	apiTest.mockDdb.On("GetItem", mock.Anything).Return(getResponse, nil).Once()
+	apiTest.mockDdb.On("PutItem", mock.Anything).Return(&dynamodb.PutItemOutput{}, nil).Once()
+	apiTest.mockDdb.On("Scan", mock.Anything).Return(&dynamodb.ScanOutput{}, nil).Once()
+
+	// The input does not set transforms, like the updates of the web app
+	result, err := apiTest.UpdateIntegrationSettings(&models.UpdateIntegrationSettingsInput{
+		S3Bucket:         "test-bucket-1",
+		S3PrefixLogTypes: models.S3PrefixLogtypes{{S3Prefix: "prefix/", LogTypes: []string{"Log.TypeA"}}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, transforms, result.Transforms)
+
+	var stored ddb.Integration
+	for _, call := range apiTest.mockDdb.Calls {
+		if call.Method == "PutItem" {
+			putItem := call.Arguments.Get(0).(*dynamodb.PutItemInput)
+			require.NoError(t, dynamodbattribute.UnmarshalMap(putItem.Item, &stored))
+		}
+	}
+	assert.Equal(t, transforms, stored.Transforms)
+	apiTest.AssertExpectations(t)
+}
+
+func TestUpdateIntegrationKeepsSampling(t *testing.T) {
+	t.Parallel()
+	apiTest := NewAPITest()
+
+	// Mocking health check
+	apiTest.EvaluateIntegrationFunc = func(_ *models.CheckIntegrationInput) (string, bool, error) {
+		return "", true, nil
+	}
+
+	samplingConfig := []sampling.Config{{LogType: "Log.TypeA", SampleRate: 0.5}}
+	samplingAttr, err := dynamodbattribute.Marshal(samplingConfig)
+	require.NoError(t, err)
+	getResponse := &dynamodb.GetItemOutput{Item: map[string]*dynamodb.AttributeValue{
+		"integrationId":   {S: aws.String(testIntegrationID)},
+		"integrationType": {S: aws.String(models.IntegrationTypeAWS3)},
+		"logTypes":        {SS: aws.StringSlice([]string{"Log.TypeA"})},
+		"sampling":        samplingAttr,
+	}}
+	apiTest.mockDdb.On("GetItem", mock.Anything).Return(getResponse, nil).Once()
+	apiTest.mockDdb.On("PutItem", mock.Anything).Return(&dynamodb.PutItemOutput{}, nil).Once()
+	apiTest.mockDdb.On("Scan", mock.Anything).Return(&dynamodb.ScanOutput{}, nil).Once()
+
+	// The input does not set sampling, like the updates of the web app
+	result, err := apiTest.UpdateIntegrationSettings(&models.UpdateIntegrationSettingsInput{
+		S3Bucket:         "test-bucket-1",
+		S3PrefixLogTypes: models.S3PrefixLogtypes{{S3Prefix: "prefix/", LogTypes: []string{"Log.TypeA"}}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, samplingConfig, result.Sampling)
+
+	var stored ddb.Integration
+	for _, call := range apiTest.mockDdb.Calls {
+		if call.Method == "PutItem" {
+			putItem := call.Arguments.Get(0).(*dynamodb.PutItemInput)
+			require.NoError(t, dynamodbattribute.UnmarshalMap(putItem.Item, &stored))
+		}
+	}
+	assert.Equal(t, samplingConfig, stored.Sampling)
+	apiTest.AssertExpectations(t)
+}
+
 func TestUpdateIntegrationSameLogTypes(t *testing.T) {
 	t.Parallel()
 	apiTest := NewAPITest()
@@ -215,6 +302,55 @@ func TestUpdateIntegrationLastScanEnd(t *testing.T) {
 	apiTest.AssertExpectations(t)
 }
 
+func TestUpdatePollerCheckpoint(t *testing.T) {
+	t.Parallel()
+	apiTest := NewAPITest()
+
+	var update *dynamodb.UpdateItemInput
+	apiTest.mockDdb.On("UpdateItem", mock.Anything).Return(&dynamodb.UpdateItemOutput{}, nil).Once().Run(func(args mock.Arguments) {
+		update = args.Get(0).(*dynamodb.UpdateItemInput)
+	})
+
+	lastPollTime, err := time.Parse(time.RFC3339, "2009-11-10T23:00:00Z")
+	require.NoError(t, err)
+
+	err = apiTest.UpdatePollerCheckpoint(&models.UpdatePollerCheckpointInput{
+		IntegrationID: testIntegrationID,
+		Cursors:       map[string]string{"Okta.SystemLog": "https://example.okta.com/api/v1/logs?after=1"},
+		LastPollTime:  lastPollTime,
+		LastPollError: "something went wrong",
+	})
+
+	assert.NoError(t, err)
+	apiTest.AssertExpectations(t)
+	// Only the checkpoint attributes are updated
+	assert.Equal(t, testIntegrationID, *update.Key["integrationId"].S)
+	assert.Equal(t, "attribute_exists (#0)", *update.ConditionExpression)
+	assert.Equal(t, "pollerConfig", *update.ExpressionAttributeNames["#0"])
+	var checkpoint struct {
+		Cursors       map[string]string
+		LastPollTime  time.Time
+		LastPollError string
+	}
+	values := update.ExpressionAttributeValues
+	require.NoError(t, dynamodbattribute.Unmarshal(values[":0"], &checkpoint.Cursors))
+	require.NoError(t, dynamodbattribute.Unmarshal(values[":1"], &checkpoint.LastPollTime))
+	require.NoError(t, dynamodbattribute.Unmarshal(values[":2"], &checkpoint.LastPollError))
+	assert.Equal(t, map[string]string{"Okta.SystemLog": "https://example.okta.com/api/v1/logs?after=1"}, checkpoint.Cursors)
+	assert.Equal(t, lastPollTime, checkpoint.LastPollTime)
+	assert.Equal(t, "something went wrong", checkpoint.LastPollError)
+
+	// Sources that are not SaaS poller sources fail the condition
+	apiTest.mockDdb.On("UpdateItem", mock.Anything).Return(&dynamodb.UpdateItemOutput{},
+		awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "condition failed", nil)).Once()
+	err = apiTest.UpdatePollerCheckpoint(&models.UpdatePollerCheckpointInput{
+		IntegrationID: testIntegrationID,
+		LastPollTime:  lastPollTime,
+	})
+	assert.IsType(t, &genericapi.InvalidInputError{}, err)
+	apiTest.AssertExpectations(t)
+}
+
 func TestSlicesContainSameElements(t *testing.T) {
 	t.Parallel()
 	type testCase struct {
@@ -31,6 +31,8 @@ func integrationToItem(input *models.SourceIntegration) *ddb.Integration {
 		IntegrationID:    input.IntegrationID,
 		IntegrationLabel: input.IntegrationLabel,
 		IntegrationType:  input.IntegrationType,
+		Transforms:       input.Transforms,
+		Sampling:         input.Sampling,
 	}
 	item.LastEventReceived = input.LastEventReceived
 
@@ -70,6 +72,27 @@ func integrationToItem(input *models.SourceIntegration) *ddb.Integration {
 			AllowedPrincipalArns: input.SqsConfig.AllowedPrincipalArns,
 			AllowedSourceArns:    input.SqsConfig.AllowedSourceArns,
 		}
+	case models.IntegrationTypeHTTP:
+		item.S3Bucket = input.S3Bucket
+		item.LogProcessingRole = input.LogProcessingRole
+		item.HTTPConfig = &ddb.HTTPConfig{
+			LogTypes:          input.HTTPConfig.LogTypes,
+			AuthMethod:        input.HTTPConfig.AuthMethod,
+			AuthHeader:        input.HTTPConfig.AuthHeader,
+			AuthHashAlgorithm: input.HTTPConfig.AuthHashAlgorithm,
+		}
+	case models.IntegrationTypePoller:
+		item.PollerConfig = &ddb.PollerConfig{
+			Vendor:           input.PollerConfig.Vendor,
+			LogTypes:         input.PollerConfig.LogTypes,
+			BaseURL:          input.PollerConfig.BaseURL,
+			ClientID:         input.PollerConfig.ClientID,
+			Subject:          input.PollerConfig.Subject,
+			PollIntervalMins: input.PollerConfig.PollIntervalMins,
+			Cursors:          input.PollerConfig.Cursors,
+			LastPollTime:     input.PollerConfig.LastPollTime,
+			LastPollError:    input.PollerConfig.LastPollError,
+		}
 	}
 	return item
 }
@@ -83,6 +106,8 @@ func itemToIntegration(item *ddb.Integration) *models.SourceIntegration {
 	integration.CreatedAtTime = item.CreatedAtTime
 	integration.CreatedBy = item.CreatedBy
 	integration.LastEventReceived = item.LastEventReceived
+	integration.Transforms = item.Transforms
+	integration.Sampling = item.Sampling
 	switch item.IntegrationType {
 	case models.IntegrationTypeAWS3:
 		integration.AWSAccountID = item.AWSAccountID
@@ -124,6 +149,27 @@ func itemToIntegration(item *ddb.Integration) *models.SourceIntegration {
 			AllowedPrincipalArns: item.SqsConfig.AllowedPrincipalArns,
 			AllowedSourceArns:    item.SqsConfig.AllowedSourceArns,
 		}
+	case models.IntegrationTypeHTTP:
+		integration.S3Bucket = item.S3Bucket
+		integration.LogProcessingRole = item.LogProcessingRole
+		integration.HTTPConfig = &models.HTTPConfig{
+			LogTypes:          item.HTTPConfig.LogTypes,
+			AuthMethod:        item.HTTPConfig.AuthMethod,
+			AuthHeader:        item.HTTPConfig.AuthHeader,
+			AuthHashAlgorithm: item.HTTPConfig.AuthHashAlgorithm,
+		}
+	case models.IntegrationTypePoller:
+		integration.PollerConfig = &models.PollerConfig{
+			Vendor:           item.PollerConfig.Vendor,
+			LogTypes:         item.PollerConfig.LogTypes,
+			BaseURL:          item.PollerConfig.BaseURL,
+			ClientID:         item.PollerConfig.ClientID,
+			Subject:          item.PollerConfig.Subject,
+			PollIntervalMins: item.PollerConfig.PollIntervalMins,
+			Cursors:          item.PollerConfig.Cursors,
+			LastPollTime:     item.PollerConfig.LastPollTime,
+			LastPollError:    item.PollerConfig.LastPollError,
+		}
 	}
 	return integration
 }
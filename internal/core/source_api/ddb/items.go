@@ -22,6 +22,8 @@ import (
 	"time"
 
 	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/sampling"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/transform"
 )
 
 // Integration represents an integration item as it is stored in DynamoDB.
@@ -60,8 +62,13 @@ type Integration struct {
 	LogProcessingRole string   `json:"logProcessingRole,omitempty"`
 
 	SqsConfig                  *SqsConfig                `json:"sqsConfig,omitempty"`
+	HTTPConfig                 *HTTPConfig               `json:"httpConfig,omitempty"`
+	PollerConfig               *PollerConfig             `json:"pollerConfig,omitempty"`
 	ManagedBucketNotifications bool                      `json:"managedBucketNotifications,omitempty"`
 	ManagedS3Resources         models.ManagedS3Resources `json:"managedS3Resources,omitempty"`
+
+	Transforms []transform.Config `json:"transforms,omitempty"`
+	Sampling   []sampling.Config  `json:"sampling,omitempty"`
 }
 
 type IntegrationStatus struct {
@@ -78,3 +85,22 @@ type SqsConfig struct {
 	AllowedSourceArns    []string `json:"allowedSourceArns" dynamodbav:",stringset"`
 	QueueURL             string   `json:"queueUrl,omitempty"`
 }
+
+type HTTPConfig struct {
+	LogTypes          []string `json:"logTypes" dynamodbav:",stringset"`
+	AuthMethod        string   `json:"authMethod,omitempty"`
+	AuthHeader        string   `json:"authHeader,omitempty"`
+	AuthHashAlgorithm string   `json:"authHashAlgorithm,omitempty"`
+}
+
+type PollerConfig struct {
+	Vendor           string            `json:"vendor,omitempty"`
+	LogTypes         []string          `json:"logTypes" dynamodbav:",stringset"`
+	BaseURL          string            `json:"baseUrl,omitempty"`
+	ClientID         string            `json:"clientId,omitempty"`
+	Subject          string            `json:"subject,omitempty"`
+	PollIntervalMins int               `json:"pollIntervalMins,omitempty"`
+	Cursors          map[string]string `json:"cursors,omitempty"`
+	LastPollTime     *time.Time        `json:"lastPollTime,omitempty"`
+	LastPollError    string            `json:"lastPollError,omitempty"`
+}
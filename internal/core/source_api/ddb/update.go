@@ -19,6 +19,8 @@ package ddb
  */
 
 import (
+	"time"
+
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
 	"github.com/pkg/errors"
@@ -51,3 +53,40 @@ func (ddb *DDB) UpdateStatus(integrationID string, status IntegrationStatus) err
 	}
 	return nil
 }
+
+// UpdatePollerCheckpoint stores the position reached by the poller of a SaaS source.
+// Only the checkpoint attributes are updated so that concurrent changes to the source settings are kept.
+func (ddb *DDB) UpdatePollerCheckpoint(integrationID string, cursors map[string]string, lastPollTime time.Time, lastPollError string) error {
+	updateExpression := expression.
+		Set(expression.Name("pollerConfig.cursors"), expression.Value(cursors)).
+		Set(expression.Name("pollerConfig.lastPollTime"), expression.Value(lastPollTime))
+	if lastPollError != "" {
+		updateExpression = updateExpression.Set(expression.Name("pollerConfig.lastPollError"), expression.Value(lastPollError))
+	} else {
+		updateExpression = updateExpression.Remove(expression.Name("pollerConfig.lastPollError"))
+	}
+	cond := expression.AttributeExists(expression.Name("pollerConfig"))
+	expr, err := expression.NewBuilder().
+		WithCondition(cond).
+		WithUpdate(updateExpression).
+		Build()
+	if err != nil {
+		return errors.Wrap(err, "failed to generate update expression")
+	}
+	updateRequest := &dynamodb.UpdateItemInput{
+		TableName: &ddb.TableName,
+		Key: map[string]*dynamodb.AttributeValue{
+			hashKey: {S: &integrationID},
+		},
+		UpdateExpression:          expr.Update(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		ConditionExpression:       expr.Condition(),
+	}
+
+	_, err = ddb.Client.UpdateItem(updateRequest)
+	if err != nil {
+		return errors.Wrap(err, "failed to update item")
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package secrets
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
+	"github.com/pkg/errors"
+)
+
+const (
+	// Prefix of the secrets holding source credentials, the lambdas are only allowed to access secrets under it
+	namePrefix = "panther/sources/"
+
+	// DefaultCacheDuration is how long a Cache keeps a secret
+	DefaultCacheDuration = 2 * time.Minute
+)
+
+// Name returns the name of the secret holding the credentials of a source
+func Name(integrationID string) string {
+	return namePrefix + integrationID
+}
+
+// Put stores the credentials of a source, creating the secret if needed
+func Put(ctx context.Context, client secretsmanageriface.SecretsManagerAPI, integrationID, value string) error {
+	name := Name(integrationID)
+	_, err := client.PutSecretValueWithContext(ctx, &secretsmanager.PutSecretValueInput{
+		SecretId:     aws.String(name),
+		SecretString: aws.String(value),
+	})
+	if err == nil {
+		return nil
+	}
+	if awsErr, ok := err.(awserr.Error); !ok || awsErr.Code() != secretsmanager.ErrCodeResourceNotFoundException {
+		return errors.Wrapf(err, "failed to update secret %q", name)
+	}
+	_, err = client.CreateSecretWithContext(ctx, &secretsmanager.CreateSecretInput{
+		Name:         aws.String(name),
+		SecretString: aws.String(value),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to create secret %q", name)
+	}
+	return nil
+}
+
+// Get fetches the credentials of a source
+func Get(ctx context.Context, client secretsmanageriface.SecretsManagerAPI, integrationID string) (string, error) {
+	name := Name(integrationID)
+	out, err := client.GetSecretValueWithContext(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(name),
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get secret %q", name)
+	}
+	return aws.StringValue(out.SecretString), nil
+}
+
+// Delete deletes the credentials of a source.
+// It is not an error if the source has no secret.
+func Delete(ctx context.Context, client secretsmanageriface.SecretsManagerAPI, integrationID string) error {
+	name := Name(integrationID)
+	_, err := client.DeleteSecretWithContext(ctx, &secretsmanager.DeleteSecretInput{
+		SecretId:                   aws.String(name),
+		ForceDeleteWithoutRecovery: aws.Bool(true),
+	})
+	if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == secretsmanager.ErrCodeResourceNotFoundException {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete secret %q", name)
+	}
+	return nil
+}
+
+// Cache keeps the credentials of sources to avoid fetching them on every request.
+// It is not safe for concurrent use.
+type Cache struct {
+	Client secretsmanageriface.SecretsManagerAPI
+	// Duration is how long a secret is kept, defaults to DefaultCacheDuration
+	Duration time.Duration
+
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	value     string
+	expiresAt time.Time
+}
+
+// Get returns the credentials of a source, fetching them if they are not cached or have expired
+func (c *Cache) Get(ctx context.Context, integrationID string) (string, error) {
+	now := time.Now()
+	if entry, ok := c.entries[integrationID]; ok && now.Before(entry.expiresAt) {
+		return entry.value, nil
+	}
+	value, err := Get(ctx, c.Client, integrationID)
+	if err != nil {
+		return "", err
+	}
+	if c.entries == nil {
+		c.entries = map[string]cacheEntry{}
+	}
+	duration := c.Duration
+	if duration <= 0 {
+		duration = DefaultCacheDuration
+	}
+	c.entries[integrationID] = cacheEntry{
+		value:     value,
+		expiresAt: now.Add(duration),
+	}
+	return value, nil
+}
@@ -0,0 +1,90 @@
+package secrets
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/pkg/testutils"
+)
+
+var errNotFound = awserr.New(secretsmanager.ErrCodeResourceNotFoundException, "not found", nil)
+
+func TestPut(t *testing.T) {
+	assert := require.New(t)
+	client := &testutils.SecretsManagerMock{}
+	client.On("PutSecretValueWithContext", mock.Anything, &secretsmanager.PutSecretValueInput{
+		SecretId:     aws.String("panther/sources/source-id"),
+		SecretString: aws.String("secret"),
+	}, mock.Anything).Return(&secretsmanager.PutSecretValueOutput{}, errNotFound).Once()
+	client.On("CreateSecretWithContext", mock.Anything, &secretsmanager.CreateSecretInput{
+		Name:         aws.String("panther/sources/source-id"),
+		SecretString: aws.String("secret"),
+	}, mock.Anything).Return(&secretsmanager.CreateSecretOutput{}, nil).Once()
+	assert.NoError(Put(context.Background(), client, "source-id", "secret"))
+	client.AssertExpectations(t)
+
+	// Existing secrets are updated
+	client.On("PutSecretValueWithContext", mock.Anything, mock.Anything, mock.Anything).
+		Return(&secretsmanager.PutSecretValueOutput{}, nil).Once()
+	assert.NoError(Put(context.Background(), client, "source-id", "secret"))
+	client.AssertExpectations(t)
+}
+
+func TestDelete(t *testing.T) {
+	assert := require.New(t)
+	client := &testutils.SecretsManagerMock{}
+	client.On("DeleteSecretWithContext", mock.Anything, mock.Anything, mock.Anything).
+		Return(&secretsmanager.DeleteSecretOutput{}, errNotFound).Once()
+	assert.NoError(Delete(context.Background(), client, "source-id"))
+	client.On("DeleteSecretWithContext", mock.Anything, mock.Anything, mock.Anything).
+		Return(&secretsmanager.DeleteSecretOutput{}, awserr.New("AccessDenied", "denied", nil)).Once()
+	assert.Error(Delete(context.Background(), client, "source-id"))
+	client.AssertExpectations(t)
+}
+
+func TestCache(t *testing.T) {
+	assert := require.New(t)
+	client := &testutils.SecretsManagerMock{}
+	client.On("GetSecretValueWithContext", mock.Anything, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String("panther/sources/source-id"),
+	}, mock.Anything).Return(&secretsmanager.GetSecretValueOutput{
+		SecretString: aws.String("secret"),
+	}, nil).Once()
+	cache := Cache{Client: client}
+	for i := 0; i < 2; i++ {
+		secret, err := cache.Get(context.Background(), "source-id")
+		assert.NoError(err)
+		assert.Equal("secret", secret)
+	}
+	client.AssertExpectations(t)
+
+	client.On("GetSecretValueWithContext", mock.Anything, mock.Anything, mock.Anything).
+		Return(&secretsmanager.GetSecretValueOutput{}, errNotFound).Once()
+	_, err := cache.Get(context.Background(), "other-id")
+	assert.Error(err)
+	client.AssertExpectations(t)
+}
@@ -92,16 +92,16 @@ func TestGenerateViewAllLogs(t *testing.T) {
 
 	// nolint (lll)
 	expectedAllLogsSQL := `create or replace view panther_views.all_logs as
-select 'panther_logs' AS p_db_name,NULL AS p_any_aws_account_ids,NULL AS p_any_aws_arns,NULL AS p_any_aws_instance_ids,NULL AS p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_event_time,p_log_type,p_parse_time,p_row_id,p_source_id,p_source_label from panther_logs.table1
+select 'panther_logs' AS p_db_name,p_any_asns,NULL AS p_any_aws_account_ids,NULL AS p_any_aws_arns,NULL AS p_any_aws_instance_ids,NULL AS p_any_aws_tags,p_any_countries,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_enrichment_geo,p_event_time,p_log_group,p_log_stream,p_log_type,p_parse_time,p_row_id,p_source_id,p_source_label,p_threat_intel_matches from panther_logs.table1
 	union all
-select 'panther_logs' AS p_db_name,p_any_aws_account_ids,p_any_aws_arns,p_any_aws_instance_ids,p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_event_time,p_log_type,p_parse_time,p_row_id,p_source_id,p_source_label from panther_logs.table2
+select 'panther_logs' AS p_db_name,p_any_asns,p_any_aws_account_ids,p_any_aws_arns,p_any_aws_instance_ids,p_any_aws_tags,p_any_countries,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_enrichment_geo,p_event_time,p_log_group,p_log_stream,p_log_type,p_parse_time,p_row_id,p_source_id,p_source_label,p_threat_intel_matches from panther_logs.table2
 ;
 `
 	// nolint (lll)
 	expectedAllDatabasesSQL := `create or replace view panther_views.all_databases as
-select 'panther_logs' AS p_db_name,NULL AS p_any_aws_account_ids,NULL AS p_any_aws_arns,NULL AS p_any_aws_instance_ids,NULL AS p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_event_time,p_log_type,p_parse_time,p_row_id,p_source_id,p_source_label from panther_logs.table1
+select 'panther_logs' AS p_db_name,p_any_asns,NULL AS p_any_aws_account_ids,NULL AS p_any_aws_arns,NULL AS p_any_aws_instance_ids,NULL AS p_any_aws_tags,p_any_countries,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_enrichment_geo,p_event_time,p_log_group,p_log_stream,p_log_type,p_parse_time,p_row_id,p_source_id,p_source_label,p_threat_intel_matches from panther_logs.table1
 	union all
-select 'panther_logs' AS p_db_name,p_any_aws_account_ids,p_any_aws_arns,p_any_aws_instance_ids,p_any_aws_tags,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_event_time,p_log_type,p_parse_time,p_row_id,p_source_id,p_source_label from panther_logs.table2
+select 'panther_logs' AS p_db_name,p_any_asns,p_any_aws_account_ids,p_any_aws_arns,p_any_aws_instance_ids,p_any_aws_tags,p_any_countries,p_any_domain_names,p_any_ip_addresses,p_any_md5_hashes,p_any_sha1_hashes,p_any_sha256_hashes,p_enrichment_geo,p_event_time,p_log_group,p_log_stream,p_log_type,p_parse_time,p_row_id,p_source_id,p_source_label,p_threat_intel_matches from panther_logs.table2
 ;
 `
 	sqlStatements, err := NewViewMaker(&lister).GenerateLogViews(context.Background())
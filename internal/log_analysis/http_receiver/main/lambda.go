@@ -0,0 +1,91 @@
+package main
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/firehose"
+	awslambda "github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/kelseyhightower/envconfig"
+	"go.uber.org/zap"
+
+	"github.com/panther-labs/panther/internal/core/source_api/secrets"
+	"github.com/panther-labs/panther/internal/log_analysis/http_receiver/receiver"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/sources"
+	"github.com/panther-labs/panther/pkg/awsretry"
+	"github.com/panther-labs/panther/pkg/lambdalogger"
+)
+
+// EnvConfig is the configuration of the receiver.
+// The receiver forwards log entries to a Firehose delivery stream, they are processed by the log processor
+// so that enrichment, sampling and threat intel matching apply to HTTP sources as well.
+type EnvConfig struct {
+	StreamName string `required:"true" split_words:"true"`
+}
+
+var httpReceiver *receiver.Receiver
+
+func main() {
+	setup()
+	lambda.Start(handle)
+}
+
+func setup() {
+	var env EnvConfig
+	envconfig.MustProcess("", &env)
+
+	// Sources are loaded using the log processor globals
+	common.Session = session.Must(session.NewSession()) // use default retries for fetching creds, avoids hangs!
+	clientsSession := common.Session.Copy(request.WithRetryer(aws.NewConfig().WithMaxRetries(common.MaxRetries),
+		awsretry.NewConnectionErrRetryer(common.MaxRetries)))
+	common.LambdaClient = awslambda.New(clientsSession)
+
+	secretsCache := &secrets.Cache{
+		Client: secretsmanager.New(clientsSession),
+	}
+	httpReceiver = &receiver.Receiver{
+		LoadSource: sources.LoadSource,
+		LoadSecret: secretsCache.Get,
+		Firehose:   firehose.New(common.Session),
+		StreamName: env.StreamName,
+	}
+}
+
+func handle(ctx context.Context, req *events.APIGatewayV2HTTPRequest) (resp *events.APIGatewayV2HTTPResponse, err error) {
+	lc, _ := lambdalogger.ConfigureGlobal(ctx, nil)
+	operation := common.OpLogManager.Start(lc.InvokedFunctionArn, common.OpLogLambdaServiceDim).
+		WithMemUsed(lambdacontext.MemoryLimitInMB)
+	defer func() {
+		var statusCode int
+		if resp != nil {
+			statusCode = resp.StatusCode
+		}
+		operation.Stop().Log(err, zap.Int("statusCode", statusCode))
+	}()
+	return httpReceiver.Handle(ctx, req)
+}
@@ -0,0 +1,312 @@
+package receiver
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/hmac"
+	"crypto/sha1" // nolint: gosec
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/firehose"
+	"github.com/aws/aws-sdk-go/service/firehose/firehoseiface"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/logstream"
+	"github.com/panther-labs/panther/internal/log_analysis/message_forwarder/forwarder"
+	"github.com/panther-labs/panther/pkg/awsbatch/firehosebatch"
+)
+
+const (
+	// SourceIDParam is the path parameter holding the id of the HTTP source
+	SourceIDParam = "sourceId"
+
+	// MaxBodySize is the max size of a (decompressed) request body
+	MaxBodySize = 64 * 1024 * 1024
+
+	// MaxRecordSize is the max size of a log entry forwarded to Firehose (including the message envelope)
+	MaxRecordSize = 1000 * 1024
+
+	// Okta event hooks verify the endpoint by sending a challenge that needs to be echoed back
+	oktaVerificationHeader = "x-okta-verification-challenge"
+
+	// Requests time out after 30s so we cannot use the backoff of the log processor
+	firehoseMaxRetries = 3
+)
+
+// Receiver handles requests pushing logs to HTTP sources.
+// The body of each request is split to log entries that are forwarded to a Firehose delivery stream.
+// Firehose buffers the entries of all requests and writes them to S3 partitioned by source id
+// so that the log processor classifies them using the log types of the source, just like SQS sources.
+type Receiver struct {
+	// LoadSource loads the HTTP source configuration by id
+	LoadSource func(id string) (*models.SourceIntegration, error)
+	// LoadSecret loads the authentication secret of an HTTP source by id
+	LoadSecret func(ctx context.Context, id string) (string, error)
+	// Firehose is the client used to forward log entries
+	Firehose firehoseiface.FirehoseAPI
+	// StreamName is the name of the delivery stream that buffers log entries
+	StreamName string
+}
+
+// Handle handles an API Gateway HTTP API request
+func (r *Receiver) Handle(ctx context.Context, req *events.APIGatewayV2HTTPRequest) (*events.APIGatewayV2HTTPResponse, error) {
+	method := req.RequestContext.HTTP.Method
+	if method != http.MethodPost && method != http.MethodPut && method != http.MethodGet {
+		return reply(http.StatusMethodNotAllowed, "method not allowed"), nil
+	}
+	sourceID := req.PathParameters[SourceIDParam]
+	src, err := r.LoadSource(sourceID)
+	if err != nil || src == nil || src.IntegrationType != models.IntegrationTypeHTTP || src.HTTPConfig == nil {
+		// Do not reveal whether the source exists
+		zap.L().Debug("HTTP source not found", zap.String("sourceId", sourceID), zap.Error(err))
+		return reply(http.StatusNotFound, "not found"), nil
+	}
+	secret, err := r.LoadSecret(ctx, src.IntegrationID)
+	if err != nil {
+		// The sender should retry the request
+		zap.L().Error("failed to load HTTP source secret", zap.String("sourceId", sourceID), zap.Error(err))
+		return reply(http.StatusInternalServerError, "failed to process request"), nil
+	}
+	// Requests are authenticated before the body is decompressed
+	body, err := rawBody(req)
+	if err != nil {
+		zap.L().Warn("failed to read request body", zap.String("sourceId", sourceID), zap.Error(err))
+		return reply(http.StatusBadRequest, "invalid request body"), nil
+	}
+	if err := Authenticate(src.HTTPConfig, secret, req.Headers, body); err != nil {
+		zap.L().Warn("HTTP source request authentication failed", zap.String("sourceId", sourceID), zap.Error(err))
+		return reply(http.StatusUnauthorized, "unauthorized"), nil
+	}
+	if method == http.MethodGet {
+		return verify(req.Headers), nil
+	}
+	if body, err = decodeBody(req.Headers, body); err != nil {
+		zap.L().Warn("failed to decode request body", zap.String("sourceId", sourceID), zap.Error(err))
+		return reply(http.StatusBadRequest, "invalid request body"), nil
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return reply(http.StatusOK, "ok"), nil
+	}
+
+	records, err := buildRecords(src.IntegrationID, newBodyStream(body))
+	if err != nil {
+		zap.L().Warn("failed to read log entries", zap.String("sourceId", sourceID), zap.Error(err))
+		if errors.Is(err, errEntryTooLarge) {
+			return reply(http.StatusRequestEntityTooLarge, "log entry too large"), nil
+		}
+		return reply(http.StatusBadRequest, "invalid request body"), nil
+	}
+	// Records were checked to fit in a batch so there are no records that are too big to send
+	_, err = firehosebatch.BatchSend(ctx, r.Firehose, firehose.PutRecordBatchInput{
+		DeliveryStreamName: aws.String(r.StreamName),
+		Records:            records,
+	}, firehoseMaxRetries)
+	if err != nil {
+		// The sender should retry the request
+		zap.L().Error("failed to forward HTTP source request", zap.String("sourceId", sourceID), zap.Error(err))
+		return reply(http.StatusInternalServerError, "failed to process request"), nil
+	}
+	return reply(http.StatusOK, "ok"), nil
+}
+
+var errEntryTooLarge = errors.Errorf("log entry exceeds %d bytes", MaxRecordSize)
+
+// buildRecords wraps each log entry of a stream in a message of the source.
+// Messages are delimited by new lines, the log processor reads them like the messages of SQS sources.
+func buildRecords(sourceID string, stream logstream.Stream) ([]*firehose.Record, error) {
+	var records []*firehose.Record
+	for entry := stream.Next(); entry != nil; entry = stream.Next() {
+		message := forwarder.Message{
+			Payload:             string(entry),
+			SourceIntegrationID: sourceID,
+		}
+		data, err := jsoniter.Marshal(&message)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal log entry")
+		}
+		data = append(data, forwarder.RecordDelimiter)
+		if len(data) > MaxRecordSize {
+			return nil, errEntryTooLarge
+		}
+		records = append(records, &firehose.Record{Data: data})
+	}
+	if err := stream.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to read log entries")
+	}
+	return records, nil
+}
+
+// Authenticate checks that a request to an HTTP source is authorized.
+// HMAC signatures are computed over the body as it was sent, before it is decompressed.
+func Authenticate(config *models.HTTPConfig, secret string, headers map[string]string, body []byte) error {
+	switch config.AuthMethod {
+	case models.HTTPAuthBearer:
+		auth := header(headers, "Authorization")
+		// Allow senders that do not support the Bearer scheme to set the token as is (ie Okta event hooks)
+		token := strings.TrimSpace(strings.TrimPrefix(auth, "Bearer "))
+		if token == "" {
+			return errors.New("missing bearer token")
+		}
+		if subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+			return errors.New("invalid bearer token")
+		}
+		return nil
+	case models.HTTPAuthHMAC:
+		name := config.AuthHeader
+		if name == "" {
+			name = models.HTTPDefaultSignatureHeader
+		}
+		signature := header(headers, name)
+		if signature == "" {
+			return errors.Errorf("missing signature header %q", name)
+		}
+		algorithm := config.AuthHashAlgorithm
+		if algorithm == "" {
+			algorithm = "sha256"
+		}
+		var newHash func() hash.Hash
+		switch algorithm {
+		case "sha256":
+			newHash = sha256.New
+		case "sha1":
+			newHash = sha1.New
+		default:
+			return errors.Errorf("unsupported hash algorithm %q", algorithm)
+		}
+		// Signatures can be prefixed with the algorithm (ie GitHub's `sha256=<hex>`)
+		signature = strings.TrimPrefix(signature, algorithm+"=")
+		mac := hmac.New(newHash, []byte(secret))
+		_, _ = mac.Write(body)
+		expect := mac.Sum(nil)
+		if actual, err := hex.DecodeString(signature); err == nil && hmac.Equal(actual, expect) {
+			return nil
+		}
+		if actual, err := base64.StdEncoding.DecodeString(signature); err == nil && hmac.Equal(actual, expect) {
+			return nil
+		}
+		return errors.New("invalid signature")
+	default:
+		return errors.Errorf("unsupported authentication method %q", config.AuthMethod)
+	}
+}
+
+// header looks up a header value regardless of case
+func header(headers map[string]string, name string) string {
+	if v, ok := headers[name]; ok {
+		return v
+	}
+	for k, v := range headers {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return ""
+}
+
+// verify replies to the endpoint verification requests of known senders
+func verify(headers map[string]string) *events.APIGatewayV2HTTPResponse {
+	if challenge := header(headers, oktaVerificationHeader); challenge != "" {
+		body, _ := jsoniter.MarshalToString(map[string]string{"verification": challenge})
+		return &events.APIGatewayV2HTTPResponse{
+			StatusCode: http.StatusOK,
+			Headers:    map[string]string{"Content-Type": "application/json"},
+			Body:       body,
+		}
+	}
+	return reply(http.StatusOK, "ok")
+}
+
+// rawBody returns the body of a request as it was sent
+func rawBody(req *events.APIGatewayV2HTTPRequest) ([]byte, error) {
+	if !req.IsBase64Encoded {
+		return []byte(req.Body), nil
+	}
+	body, err := base64.StdEncoding.DecodeString(req.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid base64 body")
+	}
+	return body, nil
+}
+
+// decodeBody decompresses the body of a request
+func decodeBody(headers map[string]string, body []byte) ([]byte, error) {
+	if !strings.EqualFold(header(headers, "Content-Encoding"), "gzip") {
+		return body, nil
+	}
+	gz, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid gzip body")
+	}
+	defer gz.Close()
+	// Guard against decompression bombs
+	data, err := ioutil.ReadAll(io.LimitReader(gz, MaxBodySize+1))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid gzip body")
+	}
+	if len(data) > MaxBodySize {
+		return nil, errors.Errorf("body exceeds %d bytes", MaxBodySize)
+	}
+	return data, nil
+}
+
+// newBodyStream splits a request body to log entries.
+// JSON arrays produce an entry per element, a single JSON value is a single entry and
+// anything else (ie JSON lines or text logs) produces an entry per line.
+func newBodyStream(body []byte) logstream.Stream {
+	body = bytes.TrimSpace(body)
+	switch body[0] {
+	case '[':
+		if json.Valid(body) {
+			return logstream.NewJSONArrayStream(bytes.NewReader(body), logstream.DefaultBufferSize)
+		}
+	case '{':
+		// Webhook payloads are often pretty printed
+		compact := bytes.Buffer{}
+		if err := json.Compact(&compact, body); err == nil {
+			return logstream.NewLineStream(&compact, logstream.DefaultBufferSize)
+		}
+	}
+	return logstream.NewLineStream(bytes.NewReader(body), logstream.DefaultBufferSize)
+}
+
+func reply(code int, message string) *events.APIGatewayV2HTTPResponse {
+	body, _ := jsoniter.MarshalToString(map[string]string{"message": message})
+	return &events.APIGatewayV2HTTPResponse{
+		StatusCode: code,
+		Headers:    map[string]string{"Content-Type": "application/json"},
+		Body:       body,
+	}
+}
@@ -0,0 +1,321 @@
+package receiver
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/firehose"
+	"github.com/aws/aws-sdk-go/service/firehose/firehoseiface"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/logstream"
+	"github.com/panther-labs/panther/internal/log_analysis/message_forwarder/forwarder"
+)
+
+const testSecret = "0123456789abcdef"
+
+// testFirehose records the messages forwarded to the delivery stream
+type testFirehose struct {
+	firehoseiface.FirehoseAPI
+	streamName string
+	messages   []forwarder.Message
+	err        error
+}
+
+func (f *testFirehose) PutRecordBatchWithContext(
+	_ aws.Context,
+	input *firehose.PutRecordBatchInput,
+	_ ...request.Option) (*firehose.PutRecordBatchOutput, error) {
+
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.streamName = aws.StringValue(input.DeliveryStreamName)
+	for _, record := range input.Records {
+		if !strings.HasSuffix(string(record.Data), "\n") {
+			return nil, errors.New("missing record delimiter")
+		}
+		msg := forwarder.Message{}
+		if err := jsoniter.Unmarshal(record.Data, &msg); err != nil {
+			return nil, err
+		}
+		f.messages = append(f.messages, msg)
+	}
+	return &firehose.PutRecordBatchOutput{FailedPutCount: aws.Int64(0)}, nil
+}
+
+func testSource(authMethod string) *models.SourceIntegration {
+	return &models.SourceIntegration{
+		SourceIntegrationMetadata: models.SourceIntegrationMetadata{
+			IntegrationID:    "source-id",
+			IntegrationLabel: "webhook",
+			IntegrationType:  models.IntegrationTypeHTTP,
+			HTTPConfig: &models.HTTPConfig{
+				LogTypes:   []string{"Test.Event"},
+				AuthMethod: authMethod,
+			},
+		},
+	}
+}
+
+func testReceiver(src *models.SourceIntegration, fh *testFirehose) *Receiver {
+	return &Receiver{
+		LoadSource: func(id string) (*models.SourceIntegration, error) {
+			if id != src.IntegrationID {
+				return nil, errors.Errorf("source %q not found", id)
+			}
+			return src, nil
+		},
+		LoadSecret: func(_ context.Context, _ string) (string, error) {
+			return testSecret, nil
+		},
+		Firehose:   fh,
+		StreamName: "stream",
+	}
+}
+
+func testRequest(method, sourceID, body string, headers map[string]string) *events.APIGatewayV2HTTPRequest {
+	req := &events.APIGatewayV2HTTPRequest{
+		Headers:        headers,
+		PathParameters: map[string]string{SourceIDParam: sourceID},
+		Body:           body,
+	}
+	req.RequestContext.HTTP.Method = method
+	return req
+}
+
+func TestReceiver(t *testing.T) {
+	bearer := map[string]string{"authorization": "Bearer " + testSecret}
+	for _, tc := range []struct {
+		Name       string
+		Request    *events.APIGatewayV2HTTPRequest
+		Err        error
+		StatusCode int
+		Entries    []string
+	}{
+		{
+			Name:       "json object",
+			Request:    testRequest(http.MethodPost, "source-id", "{\n  \"name\": \"foo\"\n}", bearer),
+			StatusCode: http.StatusOK,
+			Entries:    []string{`{"name":"foo"}`},
+		},
+		{
+			Name:       "json array",
+			Request:    testRequest(http.MethodPost, "source-id", `[{"name":"foo"},{"name":"bar"}]`, bearer),
+			StatusCode: http.StatusOK,
+			Entries:    []string{`{"name":"foo"}`, `{"name":"bar"}`},
+		},
+		{
+			Name:       "json lines",
+			Request:    testRequest(http.MethodPost, "source-id", "{\"name\":\"foo\"}\n{\"name\":\"bar\"}\n", bearer),
+			StatusCode: http.StatusOK,
+			Entries:    []string{`{"name":"foo"}`, `{"name":"bar"}`},
+		},
+		{
+			Name:       "text lines",
+			Request:    testRequest(http.MethodPost, "source-id", "foo bar\nbaz\n", bearer),
+			StatusCode: http.StatusOK,
+			Entries:    []string{"foo bar", "baz"},
+		},
+		{
+			Name:       "entry too large",
+			Request:    testRequest(http.MethodPost, "source-id", strings.Repeat("a", MaxRecordSize), bearer),
+			StatusCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			Name:       "empty body",
+			Request:    testRequest(http.MethodPost, "source-id", "", bearer),
+			StatusCode: http.StatusOK,
+		},
+		{
+			Name:       "unknown source",
+			Request:    testRequest(http.MethodPost, "other-id", `{"name":"foo"}`, bearer),
+			StatusCode: http.StatusNotFound,
+		},
+		{
+			Name:       "invalid token",
+			Request:    testRequest(http.MethodPost, "source-id", `{"name":"foo"}`, map[string]string{"Authorization": "Bearer foo"}),
+			StatusCode: http.StatusUnauthorized,
+		},
+		{
+			Name:       "method not allowed",
+			Request:    testRequest(http.MethodDelete, "source-id", "", bearer),
+			StatusCode: http.StatusMethodNotAllowed,
+		},
+		{
+			Name:       "firehose error",
+			Request:    testRequest(http.MethodPost, "source-id", `{"name":"foo"}`, bearer),
+			Err:        errors.New("failed"),
+			StatusCode: http.StatusInternalServerError,
+		},
+	} {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			assert := require.New(t)
+			fh := &testFirehose{err: tc.Err}
+			r := testReceiver(testSource(models.HTTPAuthBearer), fh)
+			// Do not wait for retries
+			ctx, cancel := context.WithCancel(context.Background())
+			if tc.Err != nil {
+				cancel()
+			}
+			defer cancel()
+			resp, err := r.Handle(ctx, tc.Request)
+			assert.NoError(err)
+			assert.Equal(tc.StatusCode, resp.StatusCode, resp.Body)
+			var entries []string
+			for _, msg := range fh.messages {
+				assert.Equal("source-id", msg.SourceIntegrationID)
+				entries = append(entries, msg.Payload)
+			}
+			assert.Equal(tc.Entries, entries)
+			if len(entries) > 0 {
+				assert.Equal("stream", fh.streamName)
+			}
+		})
+	}
+}
+
+func TestReceiverGzip(t *testing.T) {
+	assert := require.New(t)
+	buf := bytes.Buffer{}
+	gz := gzip.NewWriter(&buf)
+	_, err := gz.Write([]byte(`{"name":"foo"}`))
+	assert.NoError(err)
+	assert.NoError(gz.Close())
+	req := testRequest(http.MethodPost, "source-id", base64.StdEncoding.EncodeToString(buf.Bytes()), map[string]string{
+		"Authorization":    "Bearer " + testSecret,
+		"Content-Encoding": "gzip",
+	})
+	req.IsBase64Encoded = true
+	fh := &testFirehose{}
+	resp, err := testReceiver(testSource(models.HTTPAuthBearer), fh).Handle(context.Background(), req)
+	assert.NoError(err)
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	assert.Len(fh.messages, 1)
+
+	// The signature is over the compressed body
+	mac := hmac.New(sha256.New, []byte(testSecret))
+	_, _ = mac.Write(buf.Bytes())
+	req.Headers = map[string]string{
+		models.HTTPDefaultSignatureHeader: hex.EncodeToString(mac.Sum(nil)),
+		"Content-Encoding":                "gzip",
+	}
+	fh = &testFirehose{}
+	resp, err = testReceiver(testSource(models.HTTPAuthHMAC), fh).Handle(context.Background(), req)
+	assert.NoError(err)
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	assert.Len(fh.messages, 1)
+}
+
+func TestReceiverAuthenticateBeforeDecoding(t *testing.T) {
+	assert := require.New(t)
+	req := testRequest(http.MethodPost, "source-id", "not gzip", map[string]string{
+		"Authorization":    "Bearer foo",
+		"Content-Encoding": "gzip",
+	})
+	resp, err := testReceiver(testSource(models.HTTPAuthBearer), &testFirehose{}).Handle(context.Background(), req)
+	assert.NoError(err)
+	assert.Equal(http.StatusUnauthorized, resp.StatusCode)
+
+	req.Headers["Authorization"] = "Bearer " + testSecret
+	resp, err = testReceiver(testSource(models.HTTPAuthBearer), &testFirehose{}).Handle(context.Background(), req)
+	assert.NoError(err)
+	assert.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestReceiverSecretError(t *testing.T) {
+	assert := require.New(t)
+	r := testReceiver(testSource(models.HTTPAuthBearer), &testFirehose{})
+	r.LoadSecret = func(_ context.Context, _ string) (string, error) {
+		return "", errors.New("failed")
+	}
+	req := testRequest(http.MethodPost, "source-id", `{"name":"foo"}`, map[string]string{"Authorization": "Bearer " + testSecret})
+	resp, err := r.Handle(context.Background(), req)
+	assert.NoError(err)
+	assert.Equal(http.StatusInternalServerError, resp.StatusCode)
+}
+
+func TestReceiverVerification(t *testing.T) {
+	assert := require.New(t)
+	req := testRequest(http.MethodGet, "source-id", "", map[string]string{
+		"authorization":                 testSecret,
+		"x-okta-verification-challenge": "challenge",
+	})
+	resp, err := testReceiver(testSource(models.HTTPAuthBearer), &testFirehose{}).Handle(context.Background(), req)
+	assert.NoError(err)
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	assert.JSONEq(`{"verification":"challenge"}`, resp.Body)
+}
+
+func TestAuthenticateHMAC(t *testing.T) {
+	assert := require.New(t)
+	body := []byte(`{"name":"foo"}`)
+	mac := hmac.New(sha256.New, []byte(testSecret))
+	_, _ = mac.Write(body)
+	sig := mac.Sum(nil)
+
+	config := testSource(models.HTTPAuthHMAC).HTTPConfig
+	assert.NoError(Authenticate(config, testSecret, map[string]string{
+		models.HTTPDefaultSignatureHeader: hex.EncodeToString(sig),
+	}, body))
+	assert.NoError(Authenticate(config, testSecret, map[string]string{
+		"x-panther-signature": base64.StdEncoding.EncodeToString(sig),
+	}, body))
+	assert.Error(Authenticate(config, testSecret, map[string]string{
+		models.HTTPDefaultSignatureHeader: hex.EncodeToString(sig),
+	}, []byte(`{"name":"bar"}`)))
+	assert.Error(Authenticate(config, testSecret, nil, body))
+
+	// GitHub style signatures
+	config.AuthHeader = "X-Hub-Signature-256"
+	assert.NoError(Authenticate(config, testSecret, map[string]string{
+		"X-Hub-Signature-256": "sha256=" + hex.EncodeToString(sig),
+	}, body))
+}
+
+func TestNewBodyStream(t *testing.T) {
+	assert := require.New(t)
+	readAll := func(s logstream.Stream) (entries []string) {
+		for entry := s.Next(); entry != nil; entry = s.Next() {
+			entries = append(entries, string(entry))
+		}
+		assert.NoError(s.Err())
+		return entries
+	}
+	assert.Equal([]string{`{"a":1}`}, readAll(newBodyStream([]byte(" {\n\"a\": 1\n}\n"))))
+	assert.Equal([]string{`{"a":1}`, `{"a":2}`}, readAll(newBodyStream([]byte(`[{"a":1},{"a":2}]`))))
+	assert.Equal([]string{"foo", "bar"}, readAll(newBodyStream([]byte("foo\nbar\n"))))
+}
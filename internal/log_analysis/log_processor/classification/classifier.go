@@ -20,12 +20,15 @@ package classification
 
 import (
 	"container/heap"
+	"sort"
 	"strings"
 	"time"
 
+	jsoniter "github.com/json-iterator/go"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
 )
 
@@ -49,25 +52,92 @@ type ClassifierResult struct {
 	Matched bool
 	// NumMiss counts the number for failed classification attempts
 	NumMiss int
+	// ParserErrors has the error of each parser that failed to parse the log entry keyed by log type.
+	// It is only set if the log entry could not be classified.
+	ParserErrors map[string]error
+	// Rejected has the records of a log entry that the matching parser rejected and no other parser could classify.
+	// The events of the other records are in Events.
+	Rejected []*RejectedRecord
+	// records rejected by the matching parser that need to be classified separately
+	partial []string
+}
+
+// RejectedRecord is a record of a log entry with many records that could not be classified
+type RejectedRecord struct {
+	Log string
+	// ParserErrors has the error of each parser that failed to parse the record keyed by log type.
+	ParserErrors map[string]error
+}
+
+// Router is implemented by parsers of JSON log types that share a discriminator field.
+// A router parser is only tried on log entries that have one of its values in the discriminator field,
+// so that many log types of the same JSON stream can be mapped to a source without trying every parser on every line.
+type Router interface {
+	// Route returns the name of the discriminator field and the values of the field that the parser accepts
+	Route() (field string, values []string)
 }
 
 // NewClassifier returns a new instance of a ClassifierAPI implementation
-func NewClassifier(parsers map[string]parsers.Interface) ClassifierAPI {
-	return &Classifier{
-		parsers:     NewParserPriorityQueue(parsers),
+func NewClassifier(index map[string]parsers.Interface) ClassifierAPI {
+	unrouted := make(map[string]parsers.Interface, len(index))
+	routed := make(map[string]map[string]map[string]parsers.Interface)
+	for logType, parser := range index {
+		router, ok := parser.(Router)
+		if !ok {
+			unrouted[logType] = parser
+			continue
+		}
+		field, values := router.Route()
+		byValue, ok := routed[field]
+		if !ok {
+			byValue = make(map[string]map[string]parsers.Interface)
+			routed[field] = byValue
+		}
+		for _, value := range values {
+			if byValue[value] == nil {
+				byValue[value] = make(map[string]parsers.Interface)
+			}
+			byValue[value][logType] = parser
+		}
+	}
+	c := &Classifier{
+		parsers:     NewParserPriorityQueue(unrouted),
 		parserStats: make(map[string]*ParserStats),
 	}
+	for field, byValue := range routed {
+		r := parserRoute{
+			field:   field,
+			parsers: make(map[string]*ParserPriorityQueue, len(byValue)),
+		}
+		for value, valueIndex := range byValue {
+			r.parsers[value] = NewParserPriorityQueue(valueIndex)
+		}
+		c.routes = append(c.routes, r)
+	}
+	// Fields are checked in a stable order
+	sort.Slice(c.routes, func(i, j int) bool {
+		return c.routes[i].field < c.routes[j].field
+	})
+	return c
 }
 
 // Classifier is the struct responsible for classifying logs
 type Classifier struct {
 	parsers *ParserPriorityQueue
+	// parsers routed by the value of a discriminator field
+	routes []parserRoute
 	// aggregate stats
 	stats ClassifierStats
 	// per-parser stats, map of LogType -> stats
 	parserStats map[string]*ParserStats
 }
 
+// parserRoute has the parsers of each value of a discriminator field
+type parserRoute struct {
+	field   string
+	parsers map[string]*ParserPriorityQueue
+}
+
 func (c *Classifier) Stats() *ClassifierStats {
 	return &c.stats
 }
@@ -94,8 +164,6 @@ func safeLogParse(logType string, parser parsers.Interface, log string) (results
 // Classify attempts to classify the provided log line
 func (c *Classifier) Classify(log string) (*ClassifierResult, error) {
 	startClassify := time.Now().UTC()
-	// Slice containing the popped queue items
-	var popped []interface{}
 	result := &ClassifierResult{}
 
 	if len(log) == 0 { // likely empty file, nothing to do
@@ -121,28 +189,86 @@ func (c *Classifier) Classify(log string) (*ClassifierResult, error) {
 		return result, nil
 	}
 
-	for c.parsers.Len() > 0 {
-		currentItem := c.parsers.Peek()
+	if !c.classify(log, result) {
+		return result, errors.New("failed to classify log line")
+	}
+	return result, nil
+}
+
+// classify tries the routed parsers and then the rest of the parsers until one of them parses the log entry.
+// If the parser rejects some of the records of the log entry, each of them is classified separately.
+func (c *Classifier) classify(log string, result *ClassifierResult) bool {
+	if !c.classifyRouted(log, result) && !c.classifyWith(c.parsers, log, result) {
+		return false
+	}
+	records := result.partial
+	result.partial = nil
+	for _, record := range records {
+		sub := ClassifierResult{}
+		if c.classify(strings.TrimSpace(record), &sub) {
+			result.Events = append(result.Events, sub.Events...)
+			result.Rejected = append(result.Rejected, sub.Rejected...)
+			continue
+		}
+		result.Rejected = append(result.Rejected, &RejectedRecord{
+			Log:          record,
+			ParserErrors: sub.ParserErrors,
+		})
+	}
+	return true
+}
+
+// classifyRouted tries the parsers routed by a discriminator field, only if the log entry has a matching value.
+func (c *Classifier) classifyRouted(log string, result *ClassifierResult) bool {
+	for _, r := range c.routes {
+		value := jsoniter.Get([]byte(log), r.field)
+		if value.ValueType() != jsoniter.StringValue {
+			continue
+		}
+		if q, ok := r.parsers[value.ToString()]; ok && c.classifyWith(q, log, result) {
+			return true
+		}
+	}
+	return false
+}
+
+// classifyWith tries the parsers of a queue in priority order until one of them parses the log entry
+func (c *Classifier) classifyWith(q *ParserPriorityQueue, log string, result *ClassifierResult) bool {
+	// Slice containing the popped queue items
+	var popped []interface{}
+	for q.Len() > 0 {
+		currentItem := q.Peek()
 
 		startParseTime := time.Now().UTC()
 		logType := currentItem.logType
 		parsedEvents, err := safeLogParse(logType, currentItem.parser, log)
 		endParseTime := time.Now().UTC()
+		// A parser that parsed only some of the records of the log entry matches it
+		var partial *pantherlog.RecordsError
+		if errors.As(err, &partial) {
+			parsedEvents, err = partial.Results, nil
+			result.partial = partial.Rejected
+		}
 
 		// Parser failed to parse event
 		if err != nil {
 			zap.L().Debug("failed to parse event", zap.String("expectedLogType", logType), zap.Error(err))
 			// Removing parser from queue
-			popped = append(popped, heap.Pop(c.parsers))
+			popped = append(popped, heap.Pop(q))
 			// Increasing penalty of the parser
 			// Due to increased penalty the parser will be lower priority in the queue
 			currentItem.penalty++
 			// Increment the number of misses in the result
 			result.NumMiss++
+			if result.ParserErrors == nil {
+				result.ParserErrors = make(map[string]error)
+			}
+			result.ParserErrors[logType] = err
 			// record failure
 			continue
 		}
 		result.Matched = true
+		result.ParserErrors = nil
 
 		// Since the parsing was successful, remove all penalty from the parser
 		// The parser will be higher priority in the queue
@@ -168,12 +294,9 @@ func (c *Classifier) Classify(log string) (*ClassifierResult, error) {
 
 	// Put back the popped items to the ParserPriorityQueue.
 	for _, item := range popped {
-		heap.Push(c.parsers, item)
+		heap.Push(q, item)
 	}
-	if !result.Matched {
-		return result, errors.New("failed to classify log line")
-	}
-	return result, nil
+	return result.Matched
 }
 
 // aggregate stats
@@ -201,6 +324,7 @@ type ParserStats struct {
 	BytesProcessedCount    uint64 // input bytes
 	LogLineCount           uint64 // input records
 	EventCount             uint64 // output records
+	EventFilteredCount     uint64 // output records dropped by event transforms
 	LogType                string
 }
 
@@ -208,6 +332,7 @@ func (s *ParserStats) Add(other *ParserStats) {
 	s.ParserTimeMicroseconds += other.ParserTimeMicroseconds
 	s.BytesProcessedCount += other.BytesProcessedCount
 	s.EventCount += other.EventCount
+	s.EventFilteredCount += other.EventFilteredCount
 	s.LogLineCount += other.LogLineCount
 }
 
@@ -134,7 +134,10 @@ func TestClassifyNoMatch(t *testing.T) {
 	expectedStats.ClassifyTimeMicroseconds = classifier.Stats().ClassifyTimeMicroseconds
 	require.Equal(t, expectedStats, classifier.Stats())
 
-	require.Equal(t, &ClassifierResult{NumMiss: 1}, result)
+	require.Equal(t, 1, result.NumMiss)
+	require.False(t, result.Matched)
+	require.Len(t, result.ParserErrors, 1)
+	require.EqualError(t, result.ParserErrors["failure"], "fail")
 	failingParser.AssertNumberOfCalls(t, "Parse", 1)
 	require.Nil(t, classifier.ParserStats()["failure"])
 }
@@ -171,7 +174,9 @@ func TestClassifyParserPanic(t *testing.T) {
 	expectedStats.ClassifyTimeMicroseconds = classifier.Stats().ClassifyTimeMicroseconds
 	require.Equal(t, expectedStats, classifier.Stats())
 
-	require.Equal(t, &ClassifierResult{NumMiss: 1}, result)
+	require.Equal(t, 1, result.NumMiss)
+	require.False(t, result.Matched)
+	require.EqualError(t, result.ParserErrors["panic"], `parser "panic" panic: test parser panic`)
 	panicParser.AssertNumberOfCalls(t, "Parse", 1)
 }
 
@@ -258,3 +263,94 @@ func testSkipClassify(logLine string, t *testing.T) {
 	require.Nil(t, classifier.ParserStats()["failure1"])
 	require.Nil(t, classifier.ParserStats()["failure2"])
 }
+
+type routedParser struct {
+	parsers.Interface
+	values []string
+}
+
+func (p *routedParser) Route() (string, []string) {
+	return "event_type", p.values
+}
+
+func TestClassifyRoutesByField(t *testing.T) {
+	logAlert := `{"event_type":"alert"}`
+	logFlow := `{"event_type":"flow"}`
+	logOther := `{"event_type":"other"}`
+	resultAlert := &parsers.Result{CoreFields: pantherlog.CoreFields{PantherLogType: "alert"}}
+	resultFlow := &parsers.Result{CoreFields: pantherlog.CoreFields{PantherLogType: "flow"}}
+	resultOther := &parsers.Result{CoreFields: pantherlog.CoreFields{PantherLogType: "other"}}
+	parserAlert := testutil.ParserConfig{
+		logAlert: resultAlert,
+	}.Parser()
+	parserFlow := testutil.ParserConfig{
+		logFlow: resultFlow,
+	}.Parser()
+	parserOther := testutil.ParserConfig{
+		logOther: resultOther,
+		"log":    errors.New("fail"),
+	}.Parser()
+	classifier := NewClassifier(map[string]parsers.Interface{
+		"alert": &routedParser{Interface: parserAlert, values: []string{"alert"}},
+		"flow":  &routedParser{Interface: parserFlow, values: []string{"flow"}},
+		"other": parserOther,
+	})
+
+	result, err := classifier.Classify(logAlert)
+	require.NoError(t, err)
+	require.Equal(t, []*parsers.Result{resultAlert}, result.Events)
+	require.Zero(t, result.NumMiss)
+	result, err = classifier.Classify(logFlow)
+	require.NoError(t, err)
+	require.Equal(t, []*parsers.Result{resultFlow}, result.Events)
+	require.Zero(t, result.NumMiss)
+	// Entries without a routed value fall back to the unrouted parsers
+	result, err = classifier.Classify(logOther)
+	require.NoError(t, err)
+	require.Equal(t, []*parsers.Result{resultOther}, result.Events)
+	require.Zero(t, result.NumMiss)
+	result, err = classifier.Classify("log")
+	require.Error(t, err)
+	require.Len(t, result.ParserErrors, 1)
+	require.Contains(t, result.ParserErrors, "other")
+
+	parserAlert.AssertNumberOfCalls(t, "Parse", 1)
+	parserFlow.AssertNumberOfCalls(t, "Parse", 1)
+	parserOther.AssertNumberOfCalls(t, "Parse", 2)
+	require.Equal(t, uint64(1), classifier.ParserStats()["alert"].LogLineCount)
+	require.Equal(t, uint64(1), classifier.ParserStats()["flow"].LogLineCount)
+}
+
+func TestClassifyRejectedRecords(t *testing.T) {
+	resultA := &parsers.Result{CoreFields: pantherlog.CoreFields{PantherLogType: "a"}}
+	resultB := &parsers.Result{CoreFields: pantherlog.CoreFields{PantherLogType: "b"}}
+	parserA := testutil.ParserConfig{
+		"batch": &pantherlog.RecordsError{
+			Results:  []*parsers.Result{resultA},
+			Rejected: []string{"b", "c"},
+			Err:      errors.New("not a"),
+		},
+		"b": errors.New("not a"),
+		"c": errors.New("not a"),
+	}.Parser()
+	parserB := testutil.ParserConfig{
+		"batch": errors.New("not b"),
+		"b":     resultB,
+		"c":     errors.New("not b"),
+	}.Parser()
+	classifier := NewClassifier(map[string]parsers.Interface{
+		"a": parserA,
+		"b": parserB,
+	})
+
+	result, err := classifier.Classify("batch")
+	require.NoError(t, err)
+	require.True(t, result.Matched)
+	// Rejected records are classified separately
+	require.Equal(t, []*parsers.Result{resultA, resultB}, result.Events)
+	require.Len(t, result.Rejected, 1)
+	require.Equal(t, "c", result.Rejected[0].Log)
+	require.Len(t, result.Rejected[0].ParserErrors, 2)
+	require.Equal(t, uint64(1), classifier.Stats().LogLineCount)
+	require.Equal(t, uint64(2), classifier.Stats().EventCount)
+}
@@ -19,11 +19,14 @@ package common
  */
 
 import (
+	"context"
 	"io"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
 	"github.com/aws/aws-sdk-go/service/lambda"
 	"github.com/aws/aws-sdk-go/service/lambda/lambdaiface"
 	"github.com/aws/aws-sdk-go/service/s3"
@@ -33,10 +36,15 @@ import (
 	"github.com/aws/aws-sdk-go/service/sqs"
 	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
 	"github.com/kelseyhightower/envconfig"
+	"go.uber.org/zap"
 
 	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/enrichment"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/metrics"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/logstream"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/sampling"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/threatintel"
 	"github.com/panther-labs/panther/pkg/awsretry"
 )
 
@@ -54,6 +62,11 @@ var (
 	SqsClient    sqsiface.SQSAPI
 	SnsClient    snsiface.SNSAPI
 
+	// GeoResolver adds the geolocation of IP addresses to events, it is nil if no GeoIP database is configured
+	GeoResolver pantherlog.GeoResolver
+	// ThreatIntel matches indicators against threat intel lookup tables, it is nil if no tables are configured
+	ThreatIntel *threatintel.Store
+
 	Config EnvConfig
 )
 
@@ -63,6 +76,14 @@ type EnvConfig struct {
 	SqsQueueURL                 string `required:"true" split_words:"true"`
 	SqsBatchSize                int64  `required:"true" split_words:"true"`
 	SnsTopicARN                 string `required:"true" split_words:"true"`
+	// Local paths or S3 URLs (s3://bucket/key) of MaxMind databases used to enrich events.
+	// The Lambda functions can only read S3 objects under the enrichment/ prefix of the processed data bucket.
+	GeoIPCityDatabase string `envconfig:"GEOIP_CITY_DATABASE"`
+	GeoIPASNDatabase  string `envconfig:"GEOIP_ASN_DATABASE"`
+	// Local directory or S3 prefix (s3://bucket/prefix/) of the threat intel lookup tables
+	ThreatIntelTables string `split_words:"true"`
+	// DynamoDB table of the hourly sampling counts shared by all log processor instances
+	SamplingCountersTable string `split_words:"true"`
 }
 
 func Setup() {
@@ -82,6 +103,65 @@ func Setup() {
 		panic(err)
 	}
 	metrics.Setup()
+	SetupSampling(dynamodb.New(clientsSession))
+	SetupGeoIP()
+	SetupThreatIntel()
+}
+
+// SetupSampling shares the hourly sampling counts of sources with the other log processor instances.
+// The sampling caps are enforced by each instance separately if there is no table configured.
+func SetupSampling(client dynamodbiface.DynamoDBAPI) {
+	if Config.SamplingCountersTable == "" {
+		return
+	}
+	sampling.DefaultCounters.Store = &sampling.DynamoDBStore{
+		Client:    client,
+		TableName: Config.SamplingCountersTable,
+	}
+}
+
+// SetupGeoIP loads the configured GeoIP databases.
+// Events are processed without geolocation if the databases fail to load.
+func SetupGeoIP() {
+	geoIP, err := enrichment.LoadGeoIP(context.Background(), S3Client, Config.GeoIPCityDatabase, Config.GeoIPASNDatabase)
+	if err != nil {
+		zap.L().Error("failed to load GeoIP databases", zap.Error(err))
+		return
+	}
+	if geoIP != nil {
+		GeoResolver = geoIP
+	}
+}
+
+// SetupThreatIntel loads the configured threat intel lookup tables.
+// Loading is retried on RefreshThreatIntel if it fails.
+func SetupThreatIntel() {
+	if Config.ThreatIntelTables == "" {
+		return
+	}
+	ThreatIntel = &threatintel.Store{
+		Location: Config.ThreatIntelTables,
+		S3:       S3Client,
+	}
+	RefreshThreatIntel(context.Background())
+}
+
+// RefreshThreatIntel reloads the threat intel lookup tables if they are stale
+func RefreshThreatIntel(ctx context.Context) {
+	if ThreatIntel == nil {
+		return
+	}
+	if err := ThreatIntel.Refresh(ctx); err != nil {
+		zap.L().Error("failed to refresh threat intel lookup tables", zap.Error(err))
+	}
+}
+
+// ThreatIntelMatcher returns the matcher to set on results
+func ThreatIntelMatcher() pantherlog.ThreatIntelMatcher {
+	if ThreatIntel == nil {
+		return nil
+	}
+	return ThreatIntel
 }
 
 // DataStream represents a data stream for an s3 object read by the processor
@@ -30,6 +30,7 @@ import (
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/preprocessors"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/logstream"
 )
 
 const LogTypePrefix = "Custom"
@@ -60,6 +61,9 @@ func Build(name string, schema *logschema.Schema) (logtypes.Entry, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := logschema.ResolveIndicators(name, valueSchema, schema.CustomIndicators); err != nil {
+		return nil, err
+	}
 
 	typ, err := valueSchema.GoType()
 	if err != nil {
@@ -74,6 +78,14 @@ func Build(name string, schema *logschema.Schema) (logtypes.Entry, error) {
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to build preprocessor")
 	}
+	framing, err := buildFraming(schema.Parser)
+	if err != nil {
+		return nil, err
+	}
+	variants, err := buildVariants(schema)
+	if err != nil {
+		return nil, err
+	}
 	entry, err := logtypes.Config{
 		Name:         name,
 		Description:  desc.Description,
@@ -83,6 +95,8 @@ func Build(name string, schema *logschema.Schema) (logtypes.Entry, error) {
 			LogType:      name,
 			EventSchema:  eventType,
 			PreProcessor: preProcessor,
+			Framing:      framing,
+			Variants:     variants,
 			API:          pantherlog.ConfigJSON(),
 			Builder:      pantherlog.ResultBuilder{},
 			Validate:     pantherlog.ValidateStruct,
@@ -104,7 +118,47 @@ func buildPreprocessor(parser *logschema.Parser) (preprocessors.Interface, error
 		return parser.CSV.BuildPreprocessor()
 	case parser.Regex != nil:
 		return parser.Regex.BuildPreprocessor()
+	case parser.KV != nil:
+		return parser.KV.BuildPreprocessor()
+	case parser.CEF != nil:
+		return parser.CEF.BuildPreprocessor()
+	case parser.LEEF != nil:
+		return parser.LEEF.BuildPreprocessor()
+	case parser.XML != nil:
+		return parser.XML.BuildPreprocessor()
 	default:
 		return preprocessors.Nop(), nil
 	}
 }
+
+func buildFraming(parser *logschema.Parser) (*logstream.FramingConfig, error) {
+	if parser == nil || parser.Framing == nil {
+		return nil, nil
+	}
+	if err := parser.Framing.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid parser framing")
+	}
+	return parser.Framing, nil
+}
+
+// buildVariants builds the event schemas for each tag value of a union schema
+func buildVariants(schema *logschema.Schema) (*customparser.Variants, error) {
+	values, err := logschema.ResolveVariants(schema)
+	if err != nil || values == nil {
+		return nil, err
+	}
+	schemas := make(map[string]reflect.Type)
+	for i, value := range values {
+		typ, err := value.GoType()
+		if err != nil {
+			return nil, err
+		}
+		for _, tag := range schema.Union.Variants[i].Values {
+			schemas[tag] = typ.Elem()
+		}
+	}
+	return &customparser.Variants{
+		Tag:     schema.Union.Tag,
+		Schemas: schemas,
+	}, nil
+}
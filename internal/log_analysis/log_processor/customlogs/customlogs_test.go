@@ -43,6 +43,7 @@ import (
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logschema"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes/logtesting"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/logstream"
 )
 
 func ExampleBuild() {
@@ -324,3 +325,379 @@ func TestNameCollisions(t *testing.T) {
 	assert.Error(err)
 	assert.Nil(entry)
 }
+
+func TestLogSchemaParserFraming(t *testing.T) {
+	assert := require.New(t)
+	logSchema := logschema.Schema{}
+	assert.NoError(yaml.Unmarshal([]byte(`
+version: 0
+parser:
+  fastmatch:
+    match:
+      - '%{ts} %{level} %{message}'
+  framing:
+    startMatch: '%{date}T%{time} '
+fields:
+  - name: ts
+    type: timestamp
+    timeFormat: rfc3339
+    isEventTime: true
+  - name: level
+    type: string
+  - name: message
+    type: string
+`), &logSchema))
+	assert.NoError(logschema.ValidateSchema(&logSchema))
+	entry, err := customlogs.Build("Custom.Framed", &logSchema)
+	assert.NoError(err)
+	parser, err := entry.NewParser(nil)
+	assert.NoError(err)
+	framer, ok := parser.(logstream.Framer)
+	assert.True(ok)
+	assert.Equal(&logstream.FramingConfig{StartMatch: "%{date}T%{time} "}, framer.Framing())
+	results, err := parser.ParseLog("2020-06-02T00:01:07Z ERROR foo\n  at bar")
+	assert.NoError(err)
+	assert.Len(results, 1)
+
+	logSchema.Parser.Framing = &logstream.FramingConfig{StartRegex: "("}
+	_, err = customlogs.Build("Custom.Framed", &logSchema)
+	assert.Error(err)
+}
+
+func TestLogSchemaParserCEF(t *testing.T) {
+	assert := require.New(t)
+	logSchema := logschema.Schema{}
+	assert.NoError(yaml.Unmarshal([]byte(`
+version: 0
+parser:
+  cef:
+    prefixField: header
+    expandFields:
+      ts: '%{header}'
+fields:
+  - name: ts
+    type: timestamp
+    timeFormat: '%b %d %H:%M:%S %Y'
+    isEventTime: true
+  - name: deviceVendor
+    type: string
+  - name: name
+    type: string
+  - name: severity
+    type: int
+  - name: src
+    type: string
+    indicators: [ip]
+  - name: suser
+    type: string
+`), &logSchema))
+	assert.NoError(logschema.ValidateSchema(&logSchema))
+	entry, err := customlogs.Build("Custom.CEF", &logSchema)
+	assert.NoError(err)
+	expectJSON := fmt.Sprintf(`{
+  "ts": "Jun 02 00:01:07 2020",
+  "deviceVendor": "Acme",
+  "name": "Blocked login",
+  "severity": 7,
+  "src": "10.0.0.1",
+  "suser": "John Doe",
+  "p_log_type": "%s",
+  "p_any_ip_addresses": ["10.0.0.1"],
+  "p_event_time": "2020-06-02T00:01:07Z"
+}`, entry.String())
+	logtesting.TestRegisteredParser(t, entry, entry.String(),
+		`Jun 02 00:01:07 2020 CEF:0|Acme|Firewall|1.0|100|Blocked login|7|src=10.0.0.1 suser=John Doe`, expectJSON)
+}
+
+func TestLogSchemaParserXML(t *testing.T) {
+	assert := require.New(t)
+	logSchema := logschema.Schema{}
+	assert.NoError(yaml.Unmarshal([]byte(`
+version: 0
+parser:
+  xml:
+    arrayElements: [Data]
+fields:
+  - name: System
+    type: object
+    fields:
+      - name: EventID
+        type: int
+      - name: TimeCreated
+        type: object
+        fields:
+          - name: '@SystemTime'
+            type: timestamp
+            timeFormat: rfc3339
+            isEventTime: true
+  - name: EventData
+    type: object
+    fields:
+      - name: Data
+        type: array
+        element:
+          type: object
+          fields:
+            - name: '@Name'
+              type: string
+            - name: text
+              type: string
+`), &logSchema))
+	assert.NoError(logschema.ValidateSchema(&logSchema))
+	entry, err := customlogs.Build("Custom.WindowsEvent", &logSchema)
+	assert.NoError(err)
+	expectJSON := fmt.Sprintf(`{
+  "System": {"EventID": 4624, "TimeCreated": {"@SystemTime": "2020-06-02T00:01:07Z"}},
+  "EventData": {"Data": [{"@Name": "TargetUserName", "text": "alice"}]},
+  "p_log_type": "%s",
+  "p_event_time": "2020-06-02T00:01:07Z"
+}`, entry.String())
+	logtesting.TestRegisteredParser(t, entry, entry.String(),
+		`<Event><System><EventID>4624</EventID><TimeCreated SystemTime="2020-06-02T00:01:07Z"/></System><EventData><Data Name="TargetUserName">alice</Data></EventData></Event>`,
+		expectJSON)
+}
+
+func TestLogSchemaCustomIndicators(t *testing.T) {
+	assert := require.New(t)
+	logSchema := logschema.Schema{}
+	assert.NoError(yaml.Unmarshal([]byte(`
+version: 0
+customIndicators:
+  - name: device_id
+    description: Device identifiers
+    pattern: 'DEV-[0-9]+'
+    normalize: upper
+  - name: mac
+    normalize: mac
+fields:
+  - name: ts
+    type: timestamp
+    timeFormat: rfc3339
+    isEventTime: true
+  - name: device
+    type: string
+    indicators: [device_id]
+  - name: hwaddr
+    type: string
+    indicators: [mac]
+  - name: src
+    type: string
+    indicators: [ip, device_id]
+`), &logSchema))
+	assert.NoError(logschema.ValidateSchema(&logSchema))
+	entry, err := customlogs.Build("Custom.Devices", &logSchema)
+	assert.NoError(err)
+	expectJSON := fmt.Sprintf(`{
+  "ts": "2020-06-02T00:01:07Z",
+  "device": "dev-42",
+  "hwaddr": "AA-BB-CC-DD-EE-FF",
+  "src": "10.0.0.1",
+  "p_log_type": "%s",
+  "p_any_ip_addresses": ["10.0.0.1"],
+  "p_any_device_id": ["DEV-42"],
+  "p_any_mac": ["aa:bb:cc:dd:ee:ff"],
+  "p_event_time": "2020-06-02T00:01:07Z"
+}`, entry.String())
+	logtesting.TestRegisteredParser(t, entry, entry.String(),
+		`{"ts":"2020-06-02T00:01:07Z","device":"dev-42","hwaddr":"AA-BB-CC-DD-EE-FF","src":"10.0.0.1"}`, expectJSON)
+
+	unknown := logschema.Schema{}
+	assert.NoError(yaml.Unmarshal([]byte(`
+version: 0
+fields:
+  - name: device
+    type: string
+    indicators: [serial_number]
+`), &unknown))
+	assert.NoError(logschema.ValidateSchema(&unknown))
+	_, err = customlogs.Build("Custom.Unknown", &unknown)
+	assert.Error(err)
+}
+
+func TestLogSchemaTimeFormats(t *testing.T) {
+	assert := require.New(t)
+	logSchema := logschema.Schema{}
+	assert.NoError(yaml.Unmarshal([]byte(`
+version: 0
+fields:
+  - name: ts
+    type: timestamp
+    timeFormat: '%Y-%m-%d %H:%M:%S'
+    timeZone: America/New_York
+    isEventTime: true
+  - name: created
+    type: timestamp
+    timeFormat: filetime
+  - name: ticks
+    type: timestamp
+    timeFormat: dotnet_ticks
+  - name: lastLogon
+    type: timestamp
+    timeFormat: ldap
+`), &logSchema))
+	assert.NoError(logschema.ValidateSchema(&logSchema))
+	entry, err := customlogs.Build("Custom.TimeFormats", &logSchema)
+	assert.NoError(err)
+	expectJSON := fmt.Sprintf(`{
+  "ts": "2020-10-16 08:30:45",
+  "created": 132473250450000000,
+  "ticks": 637384482450000000,
+  "lastLogon": "20201016123045Z",
+  "p_log_type": "%s",
+  "p_event_time": "2020-10-16T12:30:45Z"
+}`, entry.String())
+	logtesting.TestRegisteredParser(t, entry, entry.String(),
+		`{"ts":"2020-10-16 08:30:45","created":"132473250450000000","ticks":637384482450000000,"lastLogon":"20201016123045.0Z"}`,
+		expectJSON)
+}
+
+func TestLogSchemaUnion(t *testing.T) {
+	assert := require.New(t)
+	logSchema := logschema.Schema{}
+	assert.NoError(yaml.Unmarshal([]byte(`
+version: 0
+fields:
+  - name: ts
+    type: timestamp
+    timeFormat: rfc3339
+    isEventTime: true
+    required: true
+union:
+  tag: eventType
+  variants:
+    - values: [login, logout]
+      fields:
+        - name: user
+          type: string
+          required: true
+        - name: src
+          type: string
+          indicators: [ip]
+    - values: [download]
+      fields:
+        - name: user
+          type: string
+        - name: url
+          type: string
+          required: true
+          indicators: [url]
+`), &logSchema))
+	assert.NoError(logschema.ValidateSchema(&logSchema))
+	entry, err := customlogs.Build("Custom.Union", &logSchema)
+	assert.NoError(err)
+
+	expectLogin := fmt.Sprintf(`{
+  "eventType": "login",
+  "ts": "2020-06-02T00:01:07Z",
+  "user": "alice",
+  "src": "10.0.0.1",
+  "p_log_type": "%s",
+  "p_any_ip_addresses": ["10.0.0.1"],
+  "p_event_time": "2020-06-02T00:01:07Z"
+}`, entry.String())
+	logtesting.TestRegisteredParser(t, entry, entry.String(),
+		`{"eventType":"login","ts":"2020-06-02T00:01:07Z","user":"alice","src":"10.0.0.1"}`, expectLogin)
+
+	// Fields of other variants are not copied to the event
+	expectDownload := fmt.Sprintf(`{
+  "eventType": "download",
+  "ts": "2020-06-02T00:01:07Z",
+  "url": "https://example.com",
+  "p_log_type": "%s",
+  "p_any_domain_names": ["example.com"],
+  "p_event_time": "2020-06-02T00:01:07Z"
+}`, entry.String())
+	logtesting.TestRegisteredParser(t, entry, entry.String(),
+		`{"eventType":"download","ts":"2020-06-02T00:01:07Z","url":"https://example.com","src":"10.0.0.1"}`, expectDownload)
+
+	parser, err := entry.NewParser(nil)
+	assert.NoError(err)
+	// Each event is validated against its own variant
+	_, err = parser.ParseLog(`{"eventType":"download","ts":"2020-06-02T00:01:07Z","user":"alice"}`)
+	assert.Error(err)
+	assert.Contains(err.Error(), "validate failed")
+	_, err = parser.ParseLog(`{"eventType":"logout","ts":"2020-06-02T00:01:07Z"}`)
+	assert.Error(err)
+	_, err = parser.ParseLog(`{"eventType":"upload","ts":"2020-06-02T00:01:07Z","user":"alice"}`)
+	assert.Error(err)
+	assert.Contains(err.Error(), "unknown union tag")
+	_, err = parser.ParseLog(`{"ts":"2020-06-02T00:01:07Z","user":"alice"}`)
+	assert.Error(err)
+	assert.Contains(err.Error(), "missing union tag")
+}
+
+func TestLogSchemaConstraints(t *testing.T) {
+	assert := require.New(t)
+	logSchema := logschema.Schema{}
+	assert.NoError(yaml.Unmarshal([]byte(`
+version: 0
+fields:
+  - name: method
+    type: string
+    required: true
+    enum: [GET, POST]
+  - name: user
+    type: string
+    pattern: '[a-z][a-z0-9_]*'
+    maxLength: 8
+  - name: status
+    type: smallint
+    min: 100
+    max: 599
+  - name: level
+    type: int
+    enum: ["1", "2", "3"]
+  - name: ratio
+    type: float
+    min: 0
+    max: 1
+  - name: tags
+    type: array
+    element:
+      type: string
+      enum: [a, b]
+`), &logSchema))
+	assert.NoError(logschema.ValidateSchema(&logSchema))
+	entry, err := customlogs.Build("Custom.Constraints", &logSchema)
+	assert.NoError(err)
+	parser, err := entry.NewParser(nil)
+	assert.NoError(err)
+
+	for _, log := range []string{
+		`{"method":"GET"}`,
+		`{"method":"POST","user":"alice_01","status":200,"level":2,"ratio":0.5,"tags":["a","b",null]}`,
+		`{"method":"GET","status":100,"ratio":1}`,
+	} {
+		results, err := parser.ParseLog(log)
+		assert.NoError(err, log)
+		assert.Len(results, 1, log)
+	}
+	for _, log := range []string{
+		`{}`,
+		`{"method":"PUT"}`,
+		`{"method":"get"}`,
+		`{"method":"GET","user":"Alice"}`,
+		`{"method":"GET","user":"alice; drop table"}`,
+		`{"method":"GET","user":"alice_long_name"}`,
+		`{"method":"GET","status":99}`,
+		`{"method":"GET","status":600}`,
+		`{"method":"GET","level":4}`,
+		`{"method":"GET","ratio":1.5}`,
+		`{"method":"GET","tags":["a","c"]}`,
+	} {
+		_, err := parser.ParseLog(log)
+		assert.Error(err, log)
+		assert.Contains(err.Error(), "validate failed", log)
+	}
+
+	invalid := logschema.Schema{}
+	assert.NoError(yaml.Unmarshal([]byte(`
+version: 0
+fields:
+  - name: status
+    type: int
+    min: 1.5
+`), &invalid))
+	_, err = customlogs.Build("Custom.Invalid", &invalid)
+	assert.Error(err)
+}
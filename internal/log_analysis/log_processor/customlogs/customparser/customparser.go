@@ -28,6 +28,7 @@ import (
 
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/preprocessors"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/logstream"
 )
 
 // Factory implements parsers.Factory interface using reflection to parse json log entries to a single log event.
@@ -35,9 +36,20 @@ type Factory struct {
 	LogType      string
 	EventSchema  reflect.Type
 	PreProcessor preprocessors.Interface
-	API          jsoniter.API
-	Builder      pantherlog.ResultBuilder
-	Validate     func(interface{}) error
+	// Framing is the framing of multi-line log entries required by the parser
+	Framing *logstream.FramingConfig
+	// Variants are the event schemas of a union log type.
+	// Events are decoded and validated using the schema of their variant and then copied to EventSchema.
+	Variants *Variants
+	API      jsoniter.API
+	Builder  pantherlog.ResultBuilder
+	Validate func(interface{}) error
+}
+
+// Variants maps the values of a tag key to the event schema of each variant
+type Variants struct {
+	Tag     string
+	Schemas map[string]reflect.Type
 }
 
 // NewParser implements parsers.Factory interface.
@@ -45,12 +57,74 @@ type Factory struct {
 func (f *Factory) NewParser(_ interface{}) (pantherlog.LogParser, error) {
 	decoder := newEventDecoderJSON(f.API, f.EventSchema)
 	builder := f.Builder
-	return preprocessors.Wrap(&parser{
+	p := preprocessors.Wrap(&parser{
 		logType:       f.LogType,
 		eventDecoder:  decoder,
+		variants:      f.newVariantDecoders(),
 		validate:      f.Validate,
 		resultBuilder: &builder,
-	}, f.PreProcessor), nil
+	}, f.PreProcessor)
+	if f.Framing != nil {
+		return &framedParser{
+			LogParser: p,
+			framing:   f.Framing,
+		}, nil
+	}
+	return p, nil
+}
+
+func (f *Factory) newVariantDecoders() *variantDecoders {
+	if f.Variants == nil {
+		return nil
+	}
+	decoders := make(map[string]*eventDecoderJSON, len(f.Variants.Schemas))
+	// Variants with many tag values share a decoder
+	shared := make(map[reflect.Type]*eventDecoderJSON)
+	for tag, schema := range f.Variants.Schemas {
+		d, ok := shared[schema]
+		if !ok {
+			d = newEventDecoderJSON(f.API, schema)
+			shared[schema] = d
+		}
+		decoders[tag] = d
+	}
+	return &variantDecoders{
+		api:      f.API,
+		tag:      f.Variants.Tag,
+		decoders: decoders,
+	}
+}
+
+// framedParser exposes the framing required by a parser to the processor
+type framedParser struct {
+	pantherlog.LogParser
+	framing *logstream.FramingConfig
+}
+
+var _ logstream.Framer = (*framedParser)(nil)
+
+// Framing implements logstream.Framer interface
+func (p *framedParser) Framing() *logstream.FramingConfig {
+	return p.framing
+}
+
+// variantDecoders selects the event decoder of a variant by the value of the tag key in a log entry
+type variantDecoders struct {
+	api      jsoniter.API
+	tag      string
+	decoders map[string]*eventDecoderJSON
+}
+
+func (v *variantDecoders) decoder(log string) (*eventDecoderJSON, error) {
+	tag := v.api.Get([]byte(log), v.tag)
+	if tag.ValueType() != jsoniter.StringValue {
+		return nil, errors.Errorf("missing union tag %q", v.tag)
+	}
+	d, ok := v.decoders[tag.ToString()]
+	if !ok {
+		return nil, errors.Errorf("unknown union tag %q value %q", v.tag, tag.ToString())
+	}
+	return d, nil
 }
 
 type eventDecoderJSON struct {
@@ -88,6 +162,7 @@ func (d *eventDecoderJSON) DecodeEvent(log string) (event interface{}, err error
 type parser struct {
 	logType       string
 	eventDecoder  *eventDecoderJSON
+	variants      *variantDecoders
 	validate      func(interface{}) error
 	resultBuilder *pantherlog.ResultBuilder
 }
@@ -97,16 +172,45 @@ func (p *parser) ParseLog(log string) ([]*pantherlog.Result, error) {
 	if log == "" {
 		return nil, nil
 	}
-	event, err := p.eventDecoder.DecodeEvent(log)
+	event, err := p.decodeEvent(log)
+	if err != nil {
+		return nil, err
+	}
+	result, err := p.resultBuilder.BuildResult(p.logType, event)
+	if err != nil {
+		return nil, errors.Wrapf(err, "result failed")
+	}
+	return []*pantherlog.Result{result}, nil
+}
+
+// decodeEvent decodes and validates an event.
+// Events of union log types are validated against their own variant and then copied to the merged event schema.
+func (p *parser) decodeEvent(log string) (interface{}, error) {
+	decoder := p.eventDecoder
+	if p.variants != nil {
+		d, err := p.variants.decoder(log)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parse failed")
+		}
+		decoder = d
+	}
+	event, err := decoder.DecodeEvent(log)
 	if err != nil {
 		return nil, errors.Wrapf(err, "parse failed")
 	}
 	if err := p.validate(event); err != nil {
 		return nil, errors.Wrapf(err, "validate failed")
 	}
-	result, err := p.resultBuilder.BuildResult(p.logType, event)
+	if p.variants == nil {
+		return event, nil
+	}
+	data, err := p.variants.api.MarshalToString(event)
 	if err != nil {
-		return nil, errors.Wrapf(err, "result failed")
+		return nil, errors.Wrapf(err, "parse failed")
 	}
-	return []*pantherlog.Result{result}, nil
+	event, err = p.eventDecoder.DecodeEvent(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse failed")
+	}
+	return event, nil
 }
@@ -0,0 +1,202 @@
+package customlogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"io"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logschema"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/preprocessors"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/logstream"
+)
+
+// SchemaInference infers a candidate schema from sample log entries.
+// Log entries are first passed through the preprocessor of the parser (i.e. CSV or regex) so the inferred fields
+// match the JSON objects the parser will decode.
+type SchemaInference struct {
+	parser    *logschema.Parser
+	pre       preprocessors.Interface
+	framing   *logstream.FramingConfig
+	value     *logschema.ValueSchema
+	numEvents int
+}
+
+var inferJSON = jsoniter.Config{
+	UseNumber: true,
+}.Froze()
+
+// NewSchemaInference creates a new schema inference for log entries handled by `parser`.
+// If parser is nil the log entries are expected to be JSON objects.
+func NewSchemaInference(parser *logschema.Parser) (*SchemaInference, error) {
+	if parser != nil && parser.Native != nil {
+		return nil, errors.New("cannot infer schemas for native parsers")
+	}
+	pre, err := buildPreprocessor(parser)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build preprocessor")
+	}
+	framing, err := buildFraming(parser)
+	if err != nil {
+		return nil, err
+	}
+	return &SchemaInference{
+		parser:  parser,
+		pre:     pre,
+		framing: framing,
+	}, nil
+}
+
+// NumEvents returns the number of log entries that were used to infer the schema
+func (s *SchemaInference) NumEvents() int {
+	return s.numEvents
+}
+
+// InferLog merges the values of a single log entry into the inferred schema.
+func (s *SchemaInference) InferLog(log string) error {
+	log, err := s.pre.PreProcessLog(log)
+	if err != nil {
+		return err
+	}
+	log = strings.TrimSpace(log)
+	if log == "" {
+		return nil
+	}
+	var data map[string]interface{}
+	if err := inferJSON.UnmarshalFromString(log, &data); err != nil {
+		return errors.Wrap(err, "log entry is not a JSON object")
+	}
+	value := logschema.InferJSONValueSchema(data)
+	if value == nil || value.Type != logschema.TypeObject {
+		return errors.New("invalid log entry")
+	}
+	s.value = logschema.Merge(s.value, value)
+	s.numEvents++
+	return nil
+}
+
+// InferLogs merges the values of up to `maxEvents` log entries read from `r` into the inferred schema.
+// If `maxEvents` is less than 1 all log entries are read.
+// It returns the number of log entries that were used to infer the schema.
+func (s *SchemaInference) InferLogs(r io.Reader, maxEvents int) (int, error) {
+	return s.InferStream(logstream.NewLineStream(r, logstream.DefaultBufferSize), maxEvents)
+}
+
+// InferStream merges the values of up to `maxEvents` log entries read from a log stream into the inferred schema.
+// If `maxEvents` is less than 1 all log entries are read.
+// It returns the number of log entries that were used to infer the schema.
+func (s *SchemaInference) InferStream(stream logstream.Stream, maxEvents int) (int, error) {
+	if s.framing != nil {
+		framed, err := logstream.NewFramedStream(stream, s.framing)
+		if err != nil {
+			return 0, err
+		}
+		stream = framed
+	}
+	numEvents, numEntries := 0, 0
+	for maxEvents < 1 || numEvents < maxEvents {
+		entry := stream.Next()
+		if entry == nil {
+			break
+		}
+		numEntries++
+		n := s.numEvents
+		if err := s.InferLog(string(entry)); err != nil {
+			return numEvents, errors.WithMessagef(err, "failed to infer log entry %d", numEntries)
+		}
+		numEvents += s.numEvents - n
+	}
+	return numEvents, stream.Err()
+}
+
+// Schema returns the inferred schema.
+// A top level timestamp field is marked as the event time using common field names as hints.
+func (s *SchemaInference) Schema() (*logschema.Schema, error) {
+	value := s.value.NonEmpty()
+	if value == nil {
+		return nil, errors.New("no fields found in log entries")
+	}
+	markEventTime(value.Fields)
+	return &logschema.Schema{
+		Version: 0,
+		Parser:  s.parser,
+		Fields:  value.Fields,
+	}, nil
+}
+
+// eventTimeHints are common names of event time fields in order of preference (lowercase without punctuation)
+var eventTimeHints = []string{
+	"timestamp",
+	"eventtime",
+	"time",
+	"ts",
+	"datetime",
+	"eventtimestamp",
+	"date",
+	"logtime",
+	"createdat",
+}
+
+// markEventTime sets `isEventTime` on the timestamp field that most likely is the event time.
+// Fields that are present in all log entries are preferred.
+func markEventTime(fields []logschema.FieldSchema) {
+	best, bestScore := -1, -1
+	for i := range fields {
+		field := &fields[i]
+		if field.Type != logschema.TypeTimestamp {
+			continue
+		}
+		if field.IsEventTime {
+			return
+		}
+		score := 0
+		if field.Required {
+			score += 2 * len(eventTimeHints)
+		}
+		name := normalizeFieldName(field.Name)
+		for j, hint := range eventTimeHints {
+			if name == hint {
+				score += len(eventTimeHints) - j
+				break
+			}
+		}
+		if score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	if best != -1 {
+		fields[best].IsEventTime = true
+	}
+}
+
+func normalizeFieldName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case 'a' <= r && r <= 'z', '0' <= r && r <= '9':
+			return r
+		case 'A' <= r && r <= 'Z':
+			return r + 'a' - 'A'
+		default:
+			return -1
+		}
+	}, name)
+}
@@ -0,0 +1,139 @@
+package customlogs_test
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/customlogs"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logschema"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/preprocessors"
+)
+
+func TestSchemaInferenceJSON(t *testing.T) {
+	assert := require.New(t)
+	infer, err := customlogs.NewSchemaInference(nil)
+	assert.NoError(err)
+	const input = `
+{"ts":"2020-01-02T10:11:12Z","remote_ip":"10.0.0.1","bytes":42,"created":"2020-01-02 10:11:12"}
+
+{"ts":"2020-01-02T10:11:13Z","remote_ip":"10.0.0.2","bytes":1.5,"user":"alice"}
+`
+	n, err := infer.InferLogs(strings.NewReader(input), 0)
+	assert.NoError(err)
+	assert.Equal(2, n)
+	assert.Equal(2, infer.NumEvents())
+	schema, err := infer.Schema()
+	assert.NoError(err)
+	assert.ElementsMatch([]logschema.FieldSchema{
+		{
+			Name:        "bytes",
+			Required:    true,
+			ValueSchema: logschema.ValueSchema{Type: logschema.TypeFloat},
+		},
+		{
+			Name:        "created",
+			ValueSchema: logschema.ValueSchema{Type: logschema.TypeTimestamp, TimeFormat: "%Y-%m-%d %H:%M:%S"},
+		},
+		{
+			Name:        "remote_ip",
+			Required:    true,
+			ValueSchema: logschema.ValueSchema{Type: logschema.TypeString, Indicators: []string{"ip"}},
+		},
+		{
+			Name:        "ts",
+			Required:    true,
+			ValueSchema: logschema.ValueSchema{Type: logschema.TypeTimestamp, TimeFormat: "rfc3339", IsEventTime: true},
+		},
+		{
+			Name:        "user",
+			ValueSchema: logschema.ValueSchema{Type: logschema.TypeString},
+		},
+	}, schema.Fields)
+	_, err = customlogs.Build("Custom.Inferred", schema)
+	assert.NoError(err)
+}
+
+func TestSchemaInferenceCSV(t *testing.T) {
+	assert := require.New(t)
+	parser := &logschema.Parser{
+		CSV: &preprocessors.CSVMatchConfig{
+			Delimiter: " ",
+			Columns:   []string{"time", "src_ip", "port", "action"},
+		},
+	}
+	infer, err := customlogs.NewSchemaInference(parser)
+	assert.NoError(err)
+	const input = `2020-01-02T10:11:12Z 10.0.0.1 443 ACCEPT
+2020-01-02T10:11:13Z 10.0.0.2 80 REJECT
+2020-01-02T10:11:14Z 10.0.0.3 22 ACCEPT
+`
+	n, err := infer.InferLogs(strings.NewReader(input), 2)
+	assert.NoError(err)
+	assert.Equal(2, n)
+	schema, err := infer.Schema()
+	assert.NoError(err)
+	assert.Equal(parser, schema.Parser)
+	assert.ElementsMatch([]logschema.FieldSchema{
+		{
+			Name:        "action",
+			Required:    true,
+			ValueSchema: logschema.ValueSchema{Type: logschema.TypeString},
+		},
+		{
+			Name:        "port",
+			Required:    true,
+			ValueSchema: logschema.ValueSchema{Type: logschema.TypeBigInt},
+		},
+		{
+			Name:        "src_ip",
+			Required:    true,
+			ValueSchema: logschema.ValueSchema{Type: logschema.TypeString, Indicators: []string{"ip"}},
+		},
+		{
+			Name:        "time",
+			Required:    true,
+			ValueSchema: logschema.ValueSchema{Type: logschema.TypeTimestamp, TimeFormat: "rfc3339", IsEventTime: true},
+		},
+	}, schema.Fields)
+	entry, err := customlogs.Build("Custom.Inferred", schema)
+	assert.NoError(err)
+	p, err := entry.NewParser(nil)
+	assert.NoError(err)
+	results, err := p.ParseLog("2020-01-02T10:11:14Z 10.0.0.3 22 ACCEPT")
+	assert.NoError(err)
+	assert.Len(results, 1)
+}
+
+func TestSchemaInferenceErrors(t *testing.T) {
+	assert := require.New(t)
+	infer, err := customlogs.NewSchemaInference(nil)
+	assert.NoError(err)
+	_, err = infer.Schema()
+	assert.Error(err)
+	assert.Error(infer.InferLog(`["foo"]`))
+	assert.Error(infer.InferLog(`not json`))
+	_, err = customlogs.NewSchemaInference(&logschema.Parser{
+		Native: &logschema.NativeParser{Name: "AWS.CloudTrail"},
+	})
+	assert.Error(err)
+}
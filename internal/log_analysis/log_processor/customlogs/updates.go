@@ -46,6 +46,19 @@ func CheckSchemaChange(d *logschema.Change) error {
 			return errors.Errorf("cannot change value type from %q to %q on field %q at %q", from.Type, to.Type, target, path)
 		}
 		return nil
+	case logschema.UpdateCustomIndicators:
+		// Removing an indicator would drop its p_any_ column from existing tables
+		from, to := d.From.([]logschema.CustomIndicator), d.To.([]logschema.CustomIndicator)
+	next:
+		for i := range from {
+			for j := range to {
+				if from[i].Name == to[j].Name {
+					continue next
+				}
+			}
+			return errors.Errorf("cannot delete custom indicator %q", from[i].Name)
+		}
+		return nil
 	default:
 		return nil
 	}
@@ -79,4 +79,16 @@ func TestCheckSchemaUpdates(t *testing.T) {
 			},
 		},
 	}))
+	assert.NoError(CheckSchemaChange(&logschema.Change{
+		Type: logschema.UpdateCustomIndicators,
+		Path: []string{"CustomIndicators"},
+		From: []logschema.CustomIndicator{{Name: "foo"}},
+		To:   []logschema.CustomIndicator{{Name: "foo", Normalize: "lower"}, {Name: "bar"}},
+	}))
+	assert.Error(CheckSchemaChange(&logschema.Change{
+		Type: logschema.UpdateCustomIndicators,
+		Path: []string{"CustomIndicators"},
+		From: []logschema.CustomIndicator{{Name: "foo"}, {Name: "bar"}},
+		To:   []logschema.CustomIndicator{{Name: "bar"}},
+	}))
 }
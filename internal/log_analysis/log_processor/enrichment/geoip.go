@@ -0,0 +1,218 @@
+// Package enrichment adds context to log events by looking up their indicators in local databases.
+package enrichment
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/oschwald/maxminddb-golang"
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// geoCacheSize is the number of IP addresses to keep resolved.
+// Logs tend to repeat the same addresses so this saves most of the lookups.
+const geoCacheSize = 10000
+
+// GeoIP resolves the geolocation of IP addresses using MaxMind City and ASN databases.
+type GeoIP struct {
+	// City is a GeoIP2/GeoLite2 City or Country database
+	City *maxminddb.Reader
+	// ASN is an optional GeoLite2 ASN database
+	ASN   *maxminddb.Reader
+	cache *lru.Cache
+}
+
+var _ pantherlog.GeoResolver = (*GeoIP)(nil)
+
+// NewGeoIP creates a GeoIP resolver. Any of the databases can be nil.
+func NewGeoIP(city, asn *maxminddb.Reader) *GeoIP {
+	cache, _ := lru.New(geoCacheSize) // only errors for non-positive size
+	return &GeoIP{
+		City:  city,
+		ASN:   asn,
+		cache: cache,
+	}
+}
+
+// LoadGeoIP loads the City and ASN databases from local paths or S3 URLs (s3://bucket/key).
+// It returns nil if no database location is set.
+func LoadGeoIP(ctx context.Context, s3API s3iface.S3API, cityLocation, asnLocation string) (*GeoIP, error) {
+	if cityLocation == "" && asnLocation == "" {
+		return nil, nil
+	}
+	var city, asn *maxminddb.Reader
+	if cityLocation != "" {
+		db, err := LoadDB(ctx, s3API, cityLocation)
+		if err != nil {
+			return nil, err
+		}
+		city = db
+	}
+	if asnLocation != "" {
+		db, err := LoadDB(ctx, s3API, asnLocation)
+		if err != nil {
+			return nil, err
+		}
+		asn = db
+	}
+	return NewGeoIP(city, asn), nil
+}
+
+// LoadDB loads an mmdb database from a local path or an S3 URL (s3://bucket/key)
+func LoadDB(ctx context.Context, s3API s3iface.S3API, location string) (*maxminddb.Reader, error) {
+	data, err := readLocation(ctx, s3API, location)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read mmdb database %q", location)
+	}
+	db, err := maxminddb.FromBytes(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open mmdb database %q", location)
+	}
+	return db, nil
+}
+
+func readLocation(ctx context.Context, s3API s3iface.S3API, location string) ([]byte, error) {
+	if !strings.HasPrefix(location, "s3://") {
+		return ioutil.ReadFile(location)
+	}
+	path := strings.TrimPrefix(location, "s3://")
+	pos := strings.IndexByte(path, '/')
+	if pos <= 0 || pos == len(path)-1 {
+		return nil, errors.Errorf("invalid S3 URL %q", location)
+	}
+	output, err := s3API.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(path[:pos]),
+		Key:    aws.String(path[pos+1:]),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer output.Body.Close()
+	return ioutil.ReadAll(output.Body)
+}
+
+// ResolveGeo implements pantherlog.GeoResolver
+func (g *GeoIP) ResolveGeo(ip string) *pantherlog.GeoInfo {
+	if cached, ok := g.cache.Get(ip); ok {
+		return cached.(*pantherlog.GeoInfo)
+	}
+	info := g.lookup(ip)
+	g.cache.Add(ip, info)
+	return info
+}
+
+func (g *GeoIP) lookup(ip string) *pantherlog.GeoInfo {
+	addr := net.ParseIP(ip)
+	if addr == nil || addr.IsLoopback() || addr.IsUnspecified() || addr.IsLinkLocalUnicast() || isPrivate(addr) {
+		return nil
+	}
+	record := geoRecord{}
+	for _, db := range []*maxminddb.Reader{g.City, g.ASN} {
+		if db == nil {
+			continue
+		}
+		// Lookup errors are for addresses not covered by the database (ie IPv6 on an IPv4 database)
+		_ = db.Lookup(addr, &record)
+	}
+	info := record.GeoInfo()
+	if info == (pantherlog.GeoInfo{}) {
+		return nil
+	}
+	return &info
+}
+
+// geoRecord holds the fields of GeoIP2 City, Country and ASN records
+type geoRecord struct {
+	City              geoNames   `maxminddb:"city"`
+	Country           geoCountry `maxminddb:"country"`
+	RegisteredCountry geoCountry `maxminddb:"registered_country"`
+	Subdivisions      []geoNames `maxminddb:"subdivisions"`
+	Location          struct {
+		Latitude  float64 `maxminddb:"latitude"`
+		Longitude float64 `maxminddb:"longitude"`
+	} `maxminddb:"location"`
+	ASN            uint32 `maxminddb:"autonomous_system_number"`
+	ASOrganization string `maxminddb:"autonomous_system_organization"`
+}
+
+type geoNames struct {
+	Names map[string]string `maxminddb:"names"`
+}
+
+type geoCountry struct {
+	ISOCode string            `maxminddb:"iso_code"`
+	Names   map[string]string `maxminddb:"names"`
+}
+
+func (r *geoRecord) GeoInfo() pantherlog.GeoInfo {
+	country := r.Country
+	if country.ISOCode == "" {
+		// Anonymous proxies and satellite providers only have a registered country
+		country = r.RegisteredCountry
+	}
+	info := pantherlog.GeoInfo{
+		Country:        country.ISOCode,
+		CountryName:    country.Names["en"],
+		City:           r.City.Names["en"],
+		Latitude:       r.Location.Latitude,
+		Longitude:      r.Location.Longitude,
+		ASN:            r.ASN,
+		ASOrganization: r.ASOrganization,
+	}
+	if len(r.Subdivisions) > 0 {
+		info.Region = r.Subdivisions[0].Names["en"]
+	}
+	return info
+}
+
+var privateNetworks = []*net.IPNet{
+	mustParseCIDR("10.0.0.0/8"),
+	mustParseCIDR("172.16.0.0/12"),
+	mustParseCIDR("192.168.0.0/16"),
+	mustParseCIDR("100.64.0.0/10"),
+	mustParseCIDR("fc00::/7"),
+}
+
+// isPrivate checks if an address is in a private network that has no geolocation
+func isPrivate(ip net.IP) bool {
+	for _, network := range privateNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func mustParseCIDR(cidr string) *net.IPNet {
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		panic(err)
+	}
+	return network
+}
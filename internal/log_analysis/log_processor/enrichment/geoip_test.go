@@ -0,0 +1,129 @@
+package enrichment
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/oschwald/maxminddb-golang"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+	"github.com/panther-labs/panther/pkg/testutils"
+)
+
+func testCityDB() []byte {
+	names := func(name string) map[string]interface{} {
+		return map[string]interface{}{"names": map[string]interface{}{"en": name, "de": "-"}}
+	}
+	w := newTestWriter()
+	w.Insert("8.8.8.0/24", map[string]interface{}{
+		"city": names("Mountain View"),
+		"country": map[string]interface{}{
+			"iso_code": "US",
+			"names":    map[string]interface{}{"en": "United States"},
+		},
+		"subdivisions": []interface{}{names("California")},
+		"location": map[string]interface{}{
+			"latitude":  37.386,
+			"longitude": -122.0838,
+		},
+	})
+	w.Insert("2a01:4f8::/32", map[string]interface{}{
+		"registered_country": map[string]interface{}{
+			"iso_code": "DE",
+			"names":    map[string]interface{}{"en": "Germany"},
+		},
+	})
+	return w.Bytes()
+}
+
+func testASNDB() []byte {
+	w := newTestWriter()
+	w.Insert("8.8.8.0/24", map[string]interface{}{
+		"autonomous_system_number":       uint32(15169),
+		"autonomous_system_organization": "GOOGLE",
+	})
+	return w.Bytes()
+}
+
+func TestGeoIP(t *testing.T) {
+	assert := require.New(t)
+	city, err := maxminddb.FromBytes(testCityDB())
+	assert.NoError(err)
+	asn, err := maxminddb.FromBytes(testASNDB())
+	assert.NoError(err)
+	g := NewGeoIP(city, asn)
+
+	expect := &pantherlog.GeoInfo{
+		Country:        "US",
+		CountryName:    "United States",
+		Region:         "California",
+		City:           "Mountain View",
+		Latitude:       37.386,
+		Longitude:      -122.0838,
+		ASN:            15169,
+		ASOrganization: "GOOGLE",
+	}
+	assert.Equal(expect, g.ResolveGeo("8.8.8.8"))
+	// Cached
+	assert.Same(g.ResolveGeo("8.8.8.8"), g.ResolveGeo("8.8.8.8"))
+	assert.Equal(&pantherlog.GeoInfo{
+		Country:     "DE",
+		CountryName: "Germany",
+	}, g.ResolveGeo("2a01:4f8::1"))
+	assert.Nil(g.ResolveGeo("9.9.9.9"))
+	assert.Nil(g.ResolveGeo("10.0.0.1"))
+	assert.Nil(g.ResolveGeo("127.0.0.1"))
+	assert.Nil(g.ResolveGeo("foo"))
+
+	// ASN only
+	g = NewGeoIP(nil, asn)
+	assert.Equal(&pantherlog.GeoInfo{ASN: 15169, ASOrganization: "GOOGLE"}, g.ResolveGeo("8.8.8.8"))
+}
+
+func TestLoadGeoIP(t *testing.T) {
+	assert := require.New(t)
+	s3Mock := &testutils.S3Mock{}
+	s3Mock.On("GetObjectWithContext", mock.Anything, &s3.GetObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("geoip/city.mmdb"),
+	}, mock.Anything).Return(&s3.GetObjectOutput{
+		Body: ioutil.NopCloser(bytes.NewReader(testCityDB())),
+	}, nil).Once()
+	g, err := LoadGeoIP(context.Background(), s3Mock, "s3://bucket/geoip/city.mmdb", "")
+	assert.NoError(err)
+	assert.Equal("US", g.ResolveGeo("8.8.8.8").Country)
+	s3Mock.AssertExpectations(t)
+
+	g, err = LoadGeoIP(context.Background(), s3Mock, "", "")
+	assert.NoError(err)
+	assert.Nil(g)
+
+	_, err = LoadGeoIP(context.Background(), s3Mock, "s3://bucket", "")
+	assert.Error(err)
+	_, err = LoadGeoIP(context.Background(), s3Mock, "/nonexistent/city.mmdb", "")
+	assert.Error(err)
+}
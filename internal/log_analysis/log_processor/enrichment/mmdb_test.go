@@ -0,0 +1,213 @@
+package enrichment
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"encoding/binary"
+	"math"
+	"net"
+	"sort"
+	"testing"
+
+	"github.com/oschwald/maxminddb-golang"
+	"github.com/stretchr/testify/require"
+)
+
+// The metadata of an mmdb file follows the last occurrence of this marker
+var metadataMarker = []byte("\xAB\xCD\xEFMaxMind.com")
+
+// dataSectionSeparator is the size of the zero bytes between the search tree and the data section
+const dataSectionSeparator = 16
+
+// mmdb data types, see https://maxmind.github.io/MaxMind-DB/
+const (
+	typeString = 2
+	typeDouble = 3
+	typeUint16 = 5
+	typeUint32 = 6
+	typeMap    = 7
+	typeArray  = 11
+	typeBool   = 14
+)
+
+// testWriter builds IPv6 mmdb databases with 24 bit records
+type testWriter struct {
+	nodes [][2]testRecord
+	data  []byte
+}
+
+type testRecord struct {
+	node int
+	data int
+	set  bool
+}
+
+func newTestWriter() *testWriter {
+	return &testWriter{nodes: make([][2]testRecord, 1)}
+}
+
+// Insert adds a network to the search tree pointing to the encoded value
+func (w *testWriter) Insert(cidr string, value interface{}) {
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		panic(err)
+	}
+	ip := network.IP.To16()
+	ones, bits := network.Mask.Size()
+	if bits == 32 {
+		// IPv4 networks go in the ::/96 subnet
+		ip = make(net.IP, net.IPv6len)
+		copy(ip[12:], network.IP.To4())
+		ones += 96
+	}
+	offset := len(w.data)
+	w.data = encodeValue(w.data, value)
+	node := 0
+	for i := 0; i < ones; i++ {
+		bit := (ip[i>>3] >> (7 - uint(i&7))) & 1
+		if i == ones-1 {
+			w.nodes[node][bit] = testRecord{data: offset, set: true}
+			return
+		}
+		r := w.nodes[node][bit]
+		if !r.set || r.node == 0 {
+			w.nodes = append(w.nodes, [2]testRecord{})
+			r = testRecord{node: len(w.nodes) - 1, set: true}
+			w.nodes[node][bit] = r
+		}
+		node = r.node
+	}
+}
+
+func (w *testWriter) Bytes() []byte {
+	nodeCount := len(w.nodes)
+	var buf []byte
+	for _, node := range w.nodes {
+		for _, r := range node {
+			v := nodeCount
+			switch {
+			case r.set && r.node != 0:
+				v = r.node
+			case r.set:
+				v = nodeCount + dataSectionSeparator + r.data
+			}
+			buf = append(buf, byte(v>>16), byte(v>>8), byte(v))
+		}
+	}
+	buf = append(buf, make([]byte, dataSectionSeparator)...)
+	buf = append(buf, w.data...)
+	buf = append(buf, metadataMarker...)
+	return encodeValue(buf, map[string]interface{}{
+		"binary_format_major_version": uint16(2),
+		"binary_format_minor_version": uint16(0),
+		"build_epoch":                 uint32(0),
+		"node_count":                  uint32(nodeCount),
+		"record_size":                 uint16(24),
+		"ip_version":                  uint16(6),
+		"database_type":               "Test-City",
+		"description":                 map[string]interface{}{"en": "Test database"},
+		"languages":                   []interface{}{"en"},
+	})
+}
+
+func encodeHeader(buf []byte, typ, size int) []byte {
+	var ctrl byte
+	var ext []byte
+	if typ > 7 {
+		ext = []byte{byte(typ - 7)}
+	} else {
+		ctrl = byte(typ << 5)
+	}
+	switch {
+	case size < 29:
+		ctrl |= byte(size)
+	case size < 285:
+		ctrl |= 29
+		ext = append(ext, byte(size-29))
+	default:
+		ctrl |= 30
+		ext = append(ext, byte((size-285)>>8), byte(size-285))
+	}
+	return append(append(buf, ctrl), ext...)
+}
+
+func encodeValue(buf []byte, value interface{}) []byte {
+	switch v := value.(type) {
+	case string:
+		return append(encodeHeader(buf, typeString, len(v)), v...)
+	case float64:
+		b := make([]byte, 8)
+		binary.BigEndian.PutUint64(b, math.Float64bits(v))
+		return append(encodeHeader(buf, typeDouble, 8), b...)
+	case uint16:
+		return append(encodeHeader(buf, typeUint16, 2), byte(v>>8), byte(v))
+	case uint32:
+		return append(encodeHeader(buf, typeUint32, 4), byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+	case bool:
+		n := 0
+		if v {
+			n = 1
+		}
+		return encodeHeader(buf, typeBool, n)
+	case []interface{}:
+		buf = encodeHeader(buf, typeArray, len(v))
+		for _, el := range v {
+			buf = encodeValue(buf, el)
+		}
+		return buf
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		buf = encodeHeader(buf, typeMap, len(v))
+		for _, key := range keys {
+			buf = encodeValue(buf, key)
+			buf = encodeValue(buf, v[key])
+		}
+		return buf
+	default:
+		panic("unsupported value")
+	}
+}
+
+func TestTestWriter(t *testing.T) {
+	assert := require.New(t)
+	w := newTestWriter()
+	w.Insert("1.2.3.0/24", map[string]interface{}{"name": "foo"})
+	w.Insert("2001:db8::/32", map[string]interface{}{"name": "bar", "tags": []interface{}{"a", true}})
+	db, err := maxminddb.FromBytes(w.Bytes())
+	assert.NoError(err)
+	assert.NoError(db.Verify())
+	assert.Equal(uint(len(w.nodes)), db.Metadata.NodeCount)
+	assert.Equal("Test-City", db.Metadata.DatabaseType)
+
+	var value interface{}
+	assert.NoError(db.Lookup(net.ParseIP("1.2.3.4"), &value))
+	assert.Equal(map[string]interface{}{"name": "foo"}, value)
+	value = nil
+	assert.NoError(db.Lookup(net.ParseIP("2001:db8::1"), &value))
+	assert.Equal(map[string]interface{}{"name": "bar", "tags": []interface{}{"a", true}}, value)
+	value = nil
+	assert.NoError(db.Lookup(net.ParseIP("1.2.4.1"), &value))
+	assert.Nil(value)
+	assert.NoError(db.Lookup(net.ParseIP("2001:db9::1"), &value))
+	assert.Nil(value)
+}
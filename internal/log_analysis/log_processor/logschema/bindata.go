@@ -95,7 +95,7 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
-var _schemaJson = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xec\x5a\x7b\x6f\x1b\x37\x12\xff\xdf\x9f\x82\x60\x7c\xc0\x5d\x22\x45\xce\xf9\x72\x45\x0c\x14\x45\xe2\x26\x4d\x80\x3c\x8c\xb8\x49\xd0\x58\xb2\x41\xef\xce\x4a\x4c\xb9\xe4\x96\xe4\xfa\x91\x40\xdf\xbd\xd8\xf7\x92\x4b\xae\x56\x96\x95\xa2\x69\xfe\x68\xb3\x22\x67\x86\x33\xc3\xdf\x0c\x87\x43\x7f\xd9\x41\x08\xef\xaa\x60\x01\x31\xc1\x07\x08\x2f\xb4\x4e\x0e\x26\x93\x4f\x4a\xf0\x71\x31\x7a\x5f\xc8\xf9\x24\x94\x24\xd2\xe3\xbd\x1f\x26\xc5\xd8\x1d\x3c\xca\xf8\x34\xd5\x0c\x32\xae\x23\xc2\xf5\x02\x24\x62\x62\x8e\x4a\x59\x39\xc1\x2e\x0d\x2b\xa1\xea\x60\x32\x91\x29\x4f\x0a\xca\xfb\x54\x94\xa2\xd4\x84\x89\xb9\x4a\x20\x98\x5c\xec\x95\x4c\x12\xa2\x8c\xeb\xce\x24\x84\x88\x72\xaa\xa9\xe0\xaa\xa4\x3e\x4e\x20\x28\xa8\x5a\x73\xf8\x00\x65\x66\x20\x84\x5b\x44\xd5\x58\xa6\xe6\x75\x92\x6b\x29\xce\x3f\x41\xa0\x73\xf6\x7c\x3c\x91\x22\x01\xa9\x29\xa8\x16\x35\x42\xf8\x02\xa4\xa2\x82\x1b\x83\x08\xe1\x40\x70\xa5\xf1\x01\xda\xab\x07\x97\xa3\x86\xa9\x76\xa1\xc1\x53\x2d\xad\xb4\xa4\x7c\x8e\x47\xed\xb9\x98\xf2\x97\xc0\xe7\x7a\x81\x0f\xd0\xbe\x31\x93\x10\xad\x41\x66\x0a\xe0\xd3\x93\xc7\xe3\x8f\xb3\xec\x7f\x64\xfc\x79\x6f\xfc\x68\x76\xef\xdf\xd3\xe9\xfd\xce\xe0\x7f\x7e\xda\xc5\x4e\xb5\x42\x50\x81\xa4\x89\x76\xd8\x63\xe9\xe6\x64\x97\x10\x81\x04\x1e\xc0\xbb\xb7\x2f\xd7\xb1\x2d\x12\x32\x26\x99\xb3\x70\x2a\xa9\x5b\x74\x42\xa4\x02\xe9\x13\x6a\xed\x55\xe1\x30\x72\x75\xd4\xde\xb2\x07\xb6\x3b\x7b\x66\x3d\x7b\x5d\x6c\xac\xba\xe8\x0c\x22\x84\x05\x87\x37\x19\x10\x4f\xac\x09\xd4\x21\xcd\xc9\xdd\xb0\x2d\xac\x3c\x3c\x7e\xff\x81\xea\xc5\x73\x20\x21\x48\xdc\xe1\x5e\x8e\x6e\x6d\x09\x91\x6a\xef\x2a\xd6\xc8\x6c\xa7\x47\x07\x1c\x11\xa5\x63\xa2\x83\x85\xcb\x35\x7d\x8a\x3c\x23\x4a\xbf\xca\x19\x7b\xe5\x4b\x98\xc3\xd5\xba\xb2\xdf\x66\x4c\x03\x84\x73\xa2\xe9\x05\xac\x2b\xfd\x75\xc1\xb5\xe3\x73\xda\xd2\x89\xe3\x88\x02\x0b\x6d\x54\x79\xd6\x29\x50\xfd\xac\xe0\xf0\xc4\x6b\x37\xad\x95\x53\x7d\xa1\x51\x66\x8c\xa3\x1e\x94\x77\x33\xc9\xee\x70\x07\x5d\x10\x96\x42\x9e\x57\xfd\xde\x31\x14\x22\x61\x98\xb3\x12\x66\xe8\x14\x11\xa6\x60\xc7\x66\xaf\x59\xb1\x84\x3f\x52\x2a\x21\x3b\x35\x4e\xea\x3c\x3c\xaa\x9d\x5c\x40\xb6\x24\xc7\x86\x37\x1d\xf9\x9e\x48\x49\xae\x9b\x74\x1f\x53\xfe\x42\x43\x6c\xe4\x06\x4c\xcb\x91\x56\xfa\x77\x7b\x20\xd7\xa0\xed\x81\xa5\xa1\x4b\x33\xdd\x52\x84\x30\x66\x65\x90\xe1\x1b\xda\xb3\x93\x9c\xc4\x4e\x6c\xfb\x12\x7a\x67\x77\x4c\x47\x7b\xe5\x9c\x0b\xc1\x80\xf0\x7e\x41\x25\xf1\x40\x1c\x65\xd4\xc7\x41\x07\x46\x96\x4c\xff\xa1\xb5\xda\x4e\x2f\x22\x0d\x68\xe5\x2e\x1c\x95\xa2\x66\xae\x48\x1c\x10\xcd\x8e\xa0\xa8\x96\x37\x81\xda\x10\xb6\xc0\xd1\x3d\x5e\x06\x2c\x59\x98\x6c\xad\xb9\x96\xd2\x05\xd8\x36\x91\x90\x87\xd5\x26\x02\x54\x40\x18\x91\x9b\x48\xd0\x34\xb6\x1d\xbf\x16\xbf\x84\x68\xc8\xbe\xd5\x68\x75\x24\x17\xab\xea\xc1\xc0\xd3\xd8\xd8\xcd\x6e\x5d\xd4\x0d\x74\x2b\x45\x21\x84\xb3\xc2\xbb\xfd\x9b\x72\x83\x3e\x62\x82\x18\x03\x2a\x26\x8c\x59\x44\xe7\x74\x6e\x8f\x94\x91\xdc\x1a\xca\x5c\xa8\x34\x89\x13\x6c\x96\x7b\xd8\xe9\x89\x16\x6a\x36\x28\xac\x1d\xb9\xa2\xae\xaa\x2b\x21\xdb\x3a\x63\xfb\x8f\x9a\x5c\x33\xdf\x39\xd3\x00\x7e\x5b\xb6\x17\x30\x70\x9a\x0e\x0c\x62\xe0\x7a\x98\xed\x3d\x19\x69\x85\xe1\xd5\x32\xa6\xe5\xad\x48\xbd\x65\xd3\xfb\x2e\x0f\x55\x28\x15\xe0\xaf\x41\xdf\x00\xbb\x0d\xfb\x2a\x64\x1a\x90\xcf\xd6\xb2\xdd\x32\xb8\xc9\xaf\xdb\xda\xeb\xbe\xeb\x16\xe5\x21\x0d\x88\x16\xd2\x5b\xfc\xd9\x09\xc3\x59\xc2\xf4\x20\xa4\x5e\xa1\x7d\x6c\x2e\x37\xf0\x58\x23\xf0\x46\x49\x92\x1a\xf9\x27\x14\x31\xa1\x46\x9a\x5a\x08\xa5\x8b\xc3\xba\x19\x4b\x25\x6b\xff\x8c\xc3\x87\x46\x46\x5c\x90\x07\xd6\xef\xff\x3e\xfc\xbf\x91\x74\x2f\xd5\x19\x91\xbc\x33\x14\x04\x22\xe5\xfa\x8c\x86\xf6\x0c\xe5\x4a\x13\x1e\x80\x63\x4a\x13\x23\xc3\x6b\x49\x3a\x64\xa9\x02\x69\x9b\x00\x31\xa1\x86\x11\x1c\xf4\x19\x09\x43\xe9\x4e\xbf\xf5\x79\xb7\x2d\x50\x36\xa7\x81\x1b\x97\xea\xe9\x05\x70\xfd\x2b\xed\x14\x9e\xfe\x62\x71\x69\x1d\x36\xcf\xaa\x96\x80\xc1\xee\xbe\x64\xaf\x2a\xf9\xec\xfb\x72\xd3\x89\x7a\x92\x52\xa6\xc7\x94\xa3\xda\x22\x54\xf6\x22\x3a\x3c\x66\x95\x89\x0f\x45\x1c\x8b\x2e\x9f\xea\x32\xd6\xf9\x49\x46\xc1\xfe\xfe\xfe\xa3\x2c\xf9\xa4\x9c\x5e\x55\xff\x9e\xc5\xaa\xfe\x4c\x9b\x4f\x9e\x7f\x06\x4c\xa4\x61\xc4\x88\x6c\xd7\x9d\xa8\x5b\x04\xdf\xdc\x05\x87\xa9\xd2\x22\x5e\xdf\x01\x8f\x51\xd0\x70\x96\x4c\x88\x72\xa4\xb4\x8c\xf2\x21\x2e\x34\xc9\x89\x3b\x92\x5a\x0d\xab\x7f\x9d\x90\xc7\xe7\x4f\x82\xc3\x30\x7a\xfe\xe2\x53\xfc\x2a\x39\x7e\x77\xf9\xe1\xea\xfa\xb7\xcf\x1f\x67\xbd\x35\xff\xcd\xcd\xfd\x45\x20\x46\xae\x45\xaa\x6f\xcf\xe2\x79\x2d\x72\x90\xc9\xa7\x05\xf1\x8f\xfe\x9b\xc8\x7a\x47\xd2\xc8\x08\x18\x33\x13\x54\x95\xeb\xb6\x12\x41\xab\x02\xb4\x42\x98\xc8\x39\x74\xc2\x77\x68\x7f\xf3\xc1\x60\x07\x14\xcb\x98\x57\xb3\xca\x78\x57\x33\x6d\x80\x23\x8c\x05\x16\x44\x95\x9c\xb3\x95\x9e\x6a\x68\x3d\xee\xd2\x32\x05\xa7\xb7\x42\x60\x34\xa6\xda\xdf\xdf\xec\xad\x7b\x46\x99\xfd\xd3\xdc\x0b\xa8\x51\xb3\x14\x1c\x91\x94\xe5\x5b\x35\x72\x6f\x54\x20\x58\x1a\xfb\xbb\x47\x8e\x02\xc2\xd5\x18\x41\x3d\x95\x45\x6f\x68\x7a\xb6\xdd\xd7\x3d\x53\xbf\xd3\xe4\x48\x42\x44\xed\x5e\xe0\x4d\xa0\x65\x1c\xb0\x89\xbe\x7e\x9f\x95\xc3\x5f\xd1\x13\x2b\xad\xd5\x92\xc6\xc7\x09\x09\x6e\x76\x8a\xc2\x55\x42\x78\xd8\xe9\x77\xa1\x9e\x7b\x32\x5c\xe9\xa3\x3c\x68\x9e\xb6\x79\xbb\xc1\xe8\x0f\xb3\xa6\xa1\xbc\x6e\xa4\x55\x40\x5c\x1d\x67\x7f\x61\xb4\xac\x0c\x71\xab\x63\xf9\x3d\xd0\xbe\x07\xda\x2d\x07\x5a\xf3\x60\xb2\x6e\x84\x15\xef\x33\xab\xe3\xcb\xf5\x8e\x73\x9b\xbb\xe3\xe9\x98\x57\x2f\x48\x47\x65\xa9\xf4\xcf\xc2\xe8\x46\xd1\xfa\x77\xc2\xef\x6b\xfb\xb1\xcd\xd1\x6a\x5f\x8d\x51\xc7\xa3\x86\xff\x85\xba\x47\x9b\xd6\x13\xe1\xb6\xc2\xa9\x2c\xfd\x7f\xce\xfc\xb7\xdd\x97\xba\xbd\xf1\xa3\xb3\xd9\x5d\xe7\x3b\xdd\xaa\xfb\x91\x0f\x6f\x9b\x3f\xda\x8d\xbe\x5e\x62\x59\x37\xed\x7f\x53\x09\xe4\x1b\x49\x12\x1e\xae\x01\xc1\xe9\x81\x63\xf7\x2a\x6b\x79\xcc\x7a\x99\xb5\x8f\xa1\xf5\x1f\x68\x57\x81\xe6\x7f\x2e\x07\x0f\x96\xd4\xfa\x2b\xaa\x5c\x41\x74\x49\xf5\x02\x25\x8c\x04\xb0\x10\x2c\x2b\x4d\x0d\xf2\xdd\x40\xc4\xe5\x8b\x00\x7e\x95\x2a\x8d\x02\xc1\x35\xa1\x1c\x11\x8d\x18\x10\xa5\x91\xe0\xe0\x67\x6f\xb7\x6a\xbe\x4c\xa7\xea\xee\xc9\xe9\x72\x76\x2f\xfb\x98\x4e\x97\xab\xdf\xd3\xd6\x36\x24\xef\xa2\xc0\x25\xa3\x1c\x94\xdf\x90\x37\x9c\x5d\x23\xc2\x98\xb8\xac\x88\x33\x73\xf4\x02\x10\xf0\xd0\x6b\xc0\xe9\xc9\xe9\x74\xca\x33\xed\xb9\xf1\xf7\x4f\xe5\x57\xd9\x3c\xd8\xc9\xfe\x5b\xee\xfc\x19\x00\x00\xff\xff\xd4\x9d\x9e\xb7\xea\x26\x00\x00")
+var _schemaJson = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xec\x5c\x79\x6f\xdc\x36\x16\xff\x5f\x9f\x82\x50\x5d\xa0\xc7\x38\x76\x9b\xb6\xbb\x35\x50\xec\xba\xae\xbd\xcd\x6e\xec\x06\x71\x9a\x6e\x63\x8f\x0d\x5a\xe2\x78\x18\x4b\xa4\x42\x52\x3e\x31\xdf\x7d\x41\x5d\x3c\x44\xea\x98\x19\xb7\xdd\xc0\xc1\x20\x99\x91\xf8\xee\xdf\x7b\x7c\xa4\xa8\x3c\x04\x00\x84\x1b\x3c\x9a\xa3\x14\x86\x3b\x20\x9c\x0b\x91\xed\x6c\x6d\xbd\xe7\x94\x6c\x96\x57\x9f\x51\x76\xb9\x15\x33\x38\x13\x9b\xdb\x7f\xdb\x2a\xaf\x7d\x12\x4e\x24\x9d\xc0\x22\x41\x92\xea\x15\x24\x62\x8e\x18\x48\xe8\x25\xa8\x78\x15\x03\x36\x70\x5c\x33\xe5\x3b\x5b\x5b\x2c\x27\x59\x39\xf2\x19\xa6\x15\x2b\xbe\x95\xd0\x4b\x9e\xa1\x68\xeb\x7a\xbb\xe4\xba\xc1\xd0\x4c\x52\x7d\xb2\x15\xa3\x19\x26\x58\x60\x4a\x78\x35\xfa\x38\x43\x51\x39\x4a\xbb\x17\xee\x00\x69\x06\x00\xa1\x36\xa8\xbe\x26\xd5\xbc\xcb\x0a\x2d\xe9\xc5\x7b\x14\x89\x82\xbc\xb8\x9e\x31\x9a\x21\x26\x30\x52\x1c\xe4\x27\xbc\x46\x8c\x63\x4a\x8c\x8b\x00\x84\x11\x25\x5c\x84\x3b\x60\xbb\xb9\xb8\xa8\x59\x35\xa2\x6d\x9a\x5a\x34\x17\x0c\x93\xcb\x46\xb4\xfc\x84\x29\x26\x2f\x11\xb9\x14\xf3\x70\x07\x3c\x37\xee\x64\x50\x08\xc4\xa4\x02\xe1\xd9\xc9\xee\xe6\xbb\xa9\xfc\x0b\x6e\xde\x6f\x6f\x7e\x3f\xfd\xf2\xb3\xd3\xd3\x67\xad\x8b\x9f\xff\x63\x23\x74\xaa\x15\x23\x1e\x31\x9c\x09\x87\x3d\x96\x6e\x4e\x72\x86\x66\x88\x21\x12\xa1\x5f\x5f\xbf\x1c\x63\xdb\x8c\xb2\x14\x4a\x67\x85\x39\xc3\x6e\xcd\x32\xc8\x38\x62\x3e\xa6\x56\xac\xe4\x27\xa4\x04\xfd\x22\x91\x71\xa2\x5d\x04\xe0\x01\x84\x0c\x7d\xc8\x31\x43\x12\x6b\x27\x61\xc4\xaf\xc3\xa9\x2e\xc9\x35\x68\x06\xb9\x48\xa1\x88\xe6\xfd\x43\x19\xba\x44\xb7\xfd\xc3\xae\x06\x48\x8d\xd0\xac\x7f\x50\x82\x86\x8c\xba\x4d\x93\xfe\x41\x04\x0a\x7c\x8d\x1c\xe3\x8c\x5f\xc0\xa2\x9a\x31\x98\x4a\xb0\x4e\x4d\x22\x00\x42\x42\x85\x15\xaf\xea\x06\x24\x77\x8e\xc8\x8c\x88\xcf\xe8\x28\x8d\x8a\xd5\xf0\x88\x8d\x88\xdb\x98\xe8\x8d\x88\x61\x5f\x24\x5b\x83\xa7\xd6\x95\x45\xe0\xfb\x65\x04\xd4\x57\xfd\xe4\xa7\xc8\xa1\x76\xa0\x3d\x09\xe8\x42\x14\x00\xde\x42\x5e\xe6\xfd\xde\xf1\xdb\xdf\xb0\x98\xff\x8c\x60\x8c\x58\x18\x58\xa4\x2e\xa7\x2c\x2b\x82\xe6\xc2\x2b\x25\xe8\x72\xa5\xa5\x83\x86\x46\x87\x6b\xba\x14\x39\x80\x5c\x1c\x16\x84\x9d\xfc\x4b\xf0\x8e\xe4\xfd\x5a\x12\x0d\x60\x7e\x75\x3d\x96\xf3\x7f\xde\x76\x73\x94\x49\x31\x92\xe5\xde\xfe\x41\x37\xcf\x04\x8d\x67\xfa\x72\xbf\x8f\xab\xcc\xb3\x91\x4c\xff\x7b\xf8\xb2\x9b\x67\x95\x90\x23\xd9\x1e\x95\x54\x9d\x9c\xeb\xf2\x3b\x92\xf5\x41\x45\xe6\xcd\x7e\x43\x4e\x08\xe3\xb8\x20\x87\xc9\x2b\xbd\x0e\xcc\x60\xc2\x51\xe0\x20\x09\x67\x18\x25\x31\xf7\x4c\xd7\x27\x21\x64\x0c\xde\x85\x13\x10\x92\x3c\x49\xcc\xa9\x23\xc4\x02\xa5\x8e\x22\xe3\xb6\xa6\x90\x53\x74\x70\x81\xcb\x0e\x5d\xa5\x9c\x38\xba\x1a\x37\xd7\x72\xa8\x93\x8b\x36\xcc\x63\x9d\xb3\x19\xa9\x7a\x34\xc3\x7b\x3a\x31\x70\xf5\x6e\x1b\xad\x41\x5e\x8d\xaf\x61\x92\x23\xdb\x0f\x6b\x8d\x68\x94\x73\x41\xd3\x17\x24\xc6\x11\x14\x94\x79\xad\xaf\x42\xbb\x74\x44\x2d\x39\x61\xe0\xb2\x66\x11\x58\x0a\x9a\xf3\x5f\xdd\x92\x4f\x1a\x20\x36\x10\x0b\xb1\x59\x32\xea\x2e\xc5\x9a\x41\x4b\x04\x4c\x1d\x82\xc4\x1c\x99\x28\xf2\x4f\x8d\xee\x2c\xf0\x5a\x5e\xe2\xe6\xa0\xa4\xd1\x08\x16\x81\xfd\x6d\x11\x68\x3a\xb5\x80\xed\x03\xa2\x4f\xd1\x50\xc0\x4b\x5f\x34\xfb\x56\x22\x5f\x35\x37\x1a\x0f\xc9\x25\x11\x64\x18\x12\xc1\x7d\x5c\x1d\x18\x49\x31\x79\x51\xc1\xe4\xab\xe5\xc1\x53\xb8\xe2\x6d\x29\x7d\x29\xe4\x48\x57\x4c\x34\x03\x14\x6e\x7a\x13\xa6\xe2\x18\x1a\x3a\x28\xbd\x47\x47\xa5\x48\xe9\xc7\x74\x61\x47\x8c\xbd\x51\x36\x5c\xa9\x28\x56\x5d\x38\x3a\x13\xa5\xcb\xd6\xb5\x4e\x12\x81\xa5\x90\x09\x89\x2a\x0e\x4b\x20\xc1\x2e\x65\x4a\xdd\xd1\x60\x20\x30\xb5\xdb\x87\x61\xf5\xf3\x48\x12\x36\x54\x6b\x0c\x99\xda\x75\xe8\x22\xf5\x2c\xf3\xcb\xe6\x75\x12\xf4\xe2\x4d\x97\x48\x24\x79\x82\xef\xd1\x18\x99\x88\xe4\x69\x91\xd9\x09\xbd\x41\x4c\xb6\x1c\x79\x96\x95\x5f\x52\x18\x85\xd3\xc0\xc6\x83\x07\x05\x45\x00\x56\xc7\xc0\x91\x19\x47\x9f\xea\x9a\x77\xc3\xb3\x13\xb8\x79\x3f\x95\x7f\x6d\x6f\x7e\x7f\x3e\xfd\x62\x43\x8d\x4a\xe1\x6d\xe3\xb3\xef\xbe\x31\xe4\x1a\x93\x89\x43\xa0\x99\x53\xce\xda\xe1\x48\xb2\xa1\x09\x66\x4e\x50\xea\xb6\xa6\x08\x4c\x12\x6b\x75\xa8\xc4\xf8\x13\xa4\x2b\x49\x7c\x89\xe2\x72\xb4\x71\x7b\x31\x31\x7e\xea\x81\xf7\xf2\xb9\xa0\x34\x41\x90\x74\x33\xaa\x06\xb7\x98\xb8\xbd\x28\x47\x1f\x47\x28\xea\xe6\xe9\x4f\xdb\x25\xec\x1c\xe8\x2d\x93\xae\x63\x72\x68\xcb\x88\x68\x76\xf7\xd8\x12\x48\x04\x5d\x3b\x4d\x1e\xb0\x0a\x06\x09\x97\x3b\x8e\x7b\x25\x61\x27\x73\x9e\x25\x78\x19\xde\xc7\x05\x5d\x27\xeb\x62\x3d\xfa\xef\xe3\x5f\x8e\xba\xdc\x33\x08\x66\x29\xe4\x57\x4b\xe8\x78\x28\xc9\x3a\x19\xc7\x68\x06\xf3\x44\x74\x29\x58\xc5\xcf\xb8\xbd\x08\x3c\x2c\x1d\x25\x75\x52\xb1\x6a\x4a\xab\xde\x94\x07\x83\xb6\x0d\xed\xde\xbd\x82\xf5\xa4\x02\x9f\x63\xcf\xac\x8b\xa2\xc0\xc4\x00\x9a\x82\xf7\x28\x8a\x12\x4b\x13\x3d\xf2\xf6\x0e\xdd\x34\x70\x79\x51\xe3\xfc\x10\xf4\xc6\xd7\xb1\x14\xac\x59\x4d\x8d\xb2\x6c\x27\x82\x72\xb8\xaf\xfa\x7a\xdb\x93\xd1\xfd\x9b\x36\xdf\x7c\x3d\xa4\xb1\xb3\xe0\x36\x09\x06\x15\x0b\xa7\x0b\x43\x8e\x32\xc8\xac\x86\xcc\x25\x24\xb0\xd9\x34\x4c\xcc\xb8\x56\xc6\x2f\xd1\x1c\x34\x11\x38\xb6\xca\xcc\xe8\x00\x0c\xb5\x69\x12\xf4\xba\xad\xb1\x52\x46\x83\xc4\xe8\xd6\xc7\x12\x13\x81\x2e\x11\x1b\xec\x27\xa5\xe3\x2a\xae\x3a\x34\x8b\xdd\x68\x4f\xcd\x21\x9f\xfb\x2c\x6a\x55\xdb\xc6\x12\xb9\x27\x8a\x50\xf6\x8a\xa1\x19\xee\x75\x88\x22\x2a\x9d\x8c\xd3\x3c\xf5\x3e\x09\xbc\x42\x28\x3b\xce\x67\x6b\x61\x1b\x58\xec\x87\xbb\x57\x15\x0d\xa5\x84\x63\x03\x7f\x40\xf9\x29\x33\xd4\xaa\x3f\x93\x31\x1c\xca\xd6\x75\x15\x0e\x45\xc1\x59\x85\x01\x8f\x60\x02\xd9\x2a\x1c\x04\x4e\xd1\x2a\xf4\x0c\xcd\x2c\x72\x77\x0d\xaf\x7b\x46\x2d\x6c\x9e\x94\x6f\x96\x42\xd5\x6f\xe0\x28\x0a\x76\x16\x81\x76\xf1\x0e\xe5\xc3\x7e\xfd\x37\x26\xc6\xf8\x59\x42\xa1\x71\x81\xa7\x30\x49\xac\x41\x17\xf8\xd2\xbe\x52\xa5\x9e\x76\x49\xba\x90\x0b\x98\x66\xfa\x38\xe9\x2c\xa7\x27\x34\xd4\xac\x50\x1d\xaa\xf1\xce\x27\xf9\x35\x93\xe6\xde\x62\xd2\x37\x03\x8e\xd9\xe8\xeb\xa9\x9e\x85\x66\xda\x86\xa6\x61\xbc\x02\xfc\x63\xd9\x5e\x48\x70\x9b\x8e\x12\x94\x22\x22\x86\xd9\xde\xd1\x9d\xf4\x18\x5e\x8b\x31\xc3\xae\x65\xea\x9a\x4d\xf7\xa4\x91\xb9\xab\x50\xa0\xb8\x01\xbd\x02\xb6\x0e\xfb\x3a\x65\x14\xc8\xb5\x5d\x87\x49\x60\x33\x1d\xe0\xc4\xe2\x6c\x09\x83\x98\x88\x7d\x49\xd3\x10\x34\x1e\x2c\xe7\x1d\x9f\x45\x24\x4f\x2f\x10\xf3\x90\xc1\xdb\xc1\x64\x81\x45\xee\x88\x9b\x19\x2c\x4b\x6f\x25\xc7\xd3\x2a\xba\xb7\x25\x72\x82\x3f\xe4\xa8\xbe\x2e\x58\x8e\x26\x81\xb7\x7b\xb4\xc3\xe8\xdc\xa0\xd0\xe6\xac\x35\x83\xa8\xc9\x1f\x53\x7c\xab\xcb\x5a\xdb\x43\x15\xef\x22\xc9\xfa\xed\xc5\x56\xa3\x8c\xd2\xb5\xa5\xf1\x48\x96\x9d\x5b\x92\x15\xf3\x71\xcb\xa0\x35\xe7\xca\x1f\xbf\x97\xa9\xef\xdc\x2d\xd3\xf4\x69\x5c\x03\x8b\x7b\x6f\x16\xaa\x08\x2b\xd9\x3e\x5b\x1d\x4d\x03\x36\xe6\xe3\x98\xa6\x10\x1b\xd3\xf6\x9c\x72\x51\x2e\xa7\xd5\xb5\x9c\x25\xfa\xcf\x34\xfe\x56\xff\xc9\xe7\xf0\x2b\xeb\xf7\xd7\xdf\x7e\xa7\x5f\x81\x37\xfc\x1c\x32\x43\x4c\x71\x29\x8a\x68\x4e\xc4\x39\x8e\xed\x3b\x98\x70\x01\x49\x84\x1c\xb7\x8a\x47\x3c\xea\x92\x60\xb0\x35\x2c\xe7\x88\xd9\x26\xa0\x14\x62\xc3\x08\x82\xc4\x39\x8c\x63\x3d\x44\xe1\xd5\xdf\xf9\xb9\x55\x2e\xa4\xd4\xfb\x9c\xa1\x73\x81\x08\x2c\x75\xad\xee\x98\x51\x69\x1a\xc6\xc7\xaa\x40\xaa\x9d\x6a\x6e\x57\xb2\xe5\x27\xc4\x7c\xff\x1a\x11\xf1\x06\xb7\x76\x04\x1b\x35\xea\xe9\xcb\x49\x2f\xd9\x1f\xd4\x49\xf1\x10\xf4\x9e\x03\xd2\x87\x74\x21\xb0\xfe\xa3\x8e\x8f\xfe\x98\xe3\x44\x6c\x62\x02\x1a\x8b\x40\x95\x8d\x2d\x1a\x73\xb3\x34\xdc\xa3\x69\x4a\xdb\x74\xbc\x2d\xac\x86\x7d\xc8\x66\xd1\xf3\xe7\xcf\xbf\x97\xb3\x78\x4e\xf0\x6d\xfd\xef\x79\xca\x9b\xaf\xb9\xfa\x4a\x8a\xaf\x33\x9c\x20\x29\x43\x7e\x8f\xa9\x90\x40\x11\x38\xba\x2a\xee\x25\x31\xcc\xe4\xbf\x51\x42\xf3\x78\x96\x40\x56\x67\xa7\xc3\xa7\xab\xb9\x69\xaf\xa8\xba\xe3\x9d\xb4\x0b\x22\x45\x59\x11\x01\x4c\x00\x17\x6c\x26\x99\x01\x42\x05\x2c\x06\xb7\x38\x69\x4f\x2d\x3e\x3d\x81\xbb\x17\x3f\x46\x7b\xf1\xec\xe7\x17\xef\xd3\xc3\xec\xf8\xd7\x9b\xdf\x6e\xef\x7e\xbf\x7f\x37\x0d\x1f\xc7\xdc\x7f\x51\x90\xc0\x3b\x9a\x8b\xf5\x59\x7c\xd9\xb0\x1c\x64\xf2\x59\x39\xf8\x07\xcb\xc0\xc0\x35\xaf\x69\x66\x87\x52\xdf\x77\x94\x78\xd3\xce\x61\xba\x32\xfb\xa7\x72\x8b\xb6\x54\xfd\x5e\x72\x99\x04\x7e\x4b\xdf\xcc\x11\x78\xb1\x7b\xb4\xab\x86\x03\x41\x41\xce\x0b\xab\x95\xe3\x38\xb8\x29\x8f\xc2\x69\xe3\x30\x29\x1d\x83\x29\x01\x9f\xe1\x67\xe8\x19\xd8\x4d\x11\xc3\x11\xdc\x3a\x42\x37\xe7\xbf\x53\x76\xf5\xf9\xa0\x49\x30\xb0\x1c\xe0\x98\xae\x26\x46\x35\x31\xcb\x64\xbd\x2e\x56\xbe\x5a\x6f\x95\xd4\xd6\x97\x9a\x92\x92\x0a\xb2\x4b\xd4\xaa\x6d\x5d\x31\x5a\xce\x01\xa5\x98\x66\x5b\xdc\x30\xde\x75\x18\x72\x80\x23\x0c\x01\x73\xc8\x2b\xca\x69\xaf\xa7\xd4\x58\x8f\xbb\x64\xf3\xdd\x5c\x6f\x2c\x2a\x70\x97\xe0\x14\x0b\xc4\xc6\x38\xac\x29\xba\x13\x59\x21\x4f\x0b\x2f\x00\xeb\x74\x98\x7a\x24\x11\x4e\xdc\x81\x8a\x68\x92\xa7\x64\x4c\x2b\xed\x5a\x65\x74\xf5\xd8\x1d\x36\x78\xc3\xae\x02\x6f\x6a\xcb\xaf\x70\xcf\xae\xe2\x08\x68\x69\x7c\x51\x9a\x89\xbb\xb7\x7f\xf0\x11\x92\x5e\x6b\x05\xc3\xe9\x71\x06\xa3\xe5\x5a\x0c\x74\x9b\x41\x12\xb7\x9e\x69\x77\x34\xff\x02\xdd\x8a\x57\x45\xd2\xec\xeb\xb4\x81\xad\xe5\xc2\x9f\x66\xea\x40\xb0\x92\x38\x2c\xd3\x6a\x20\xf6\xe7\xd9\x9f\x98\x2d\xbd\x29\xae\xf6\x8a\x9f\x12\xed\x29\xd1\x1e\x23\xd1\xd4\x81\x77\x25\x6a\x58\x86\x55\x6f\x7b\xf4\xe6\x97\xeb\x1c\xfe\x3a\xa3\xe3\xf6\x49\xf3\x06\xc0\xab\xaa\x47\xec\x8d\xda\x47\x85\x51\x9d\xc4\xab\xe5\x47\x81\xdf\xd6\x11\x7c\x2f\x7a\x1d\x4f\xd4\x2c\x48\x73\x01\x99\x78\xdd\x7e\x31\xa9\x75\x98\x40\x8e\x3b\x6c\xbf\xed\x64\x8f\x8b\x28\x11\x98\xe4\x45\xdf\xfe\x82\xc4\xc5\x06\x7a\xd7\x78\x1a\x09\x24\xf6\xe4\xae\x0a\x8a\xbb\x47\xde\x23\x74\xf5\xe6\xf8\xad\x76\x86\xa1\x7f\xa2\xd3\xac\xf3\xc4\x75\x39\x80\x6b\xde\x58\x2b\x5f\x87\xf7\x3c\xd3\xa4\xb7\x13\x36\x1c\x3a\x96\xb8\xf6\xf1\x58\xba\x14\xde\xbe\xc4\x04\xf1\x47\xd8\x5d\x1c\xfc\x48\xb9\x79\x2f\x48\xe9\xe0\xcd\x8b\xb5\x36\x46\x46\x58\x7d\xed\x10\x70\x57\x8e\xf2\xf1\xd4\xca\x87\x29\x7c\x52\x7f\x70\x4b\xfd\x90\x53\x81\xd6\x23\x4c\xdf\x58\xf6\xaa\x71\x1a\xba\xf5\x90\xf3\xcf\x2a\xb8\xd9\xfe\xf8\x67\x35\xed\xe6\xc2\xa9\xd5\x5f\x76\xe6\x1a\x7f\x28\xa4\x5a\x07\xed\x1f\x68\x9a\x8c\x4e\xe1\xac\x58\xdc\x16\x5a\xf9\x3c\xb2\x5c\xf8\x9f\x60\xf5\x7f\x0d\xab\xe2\x45\x4c\xa5\xca\x68\x5c\x3d\xde\xd4\x70\xea\x39\xe2\xf1\x84\xe4\x27\x24\xbb\x90\x2c\xdf\xfe\x55\x9a\x8c\x06\x32\x14\x82\xe1\x8b\x5c\xa0\x15\x21\xe0\x83\xf3\x3f\xdd\x8e\x94\x09\xbe\x1a\x96\x7d\x02\x25\x67\xb7\xcc\x02\x6d\xfb\xe5\x39\x1e\xee\x93\xbb\x7e\x48\xea\x24\xb6\x11\xbd\x70\x5d\x6b\x72\x06\x16\xff\xb1\x50\xab\xde\x08\x57\x8a\x74\xbe\xa4\xe4\x43\x9c\xe3\xad\x0f\xdb\x98\xc0\x76\x8a\x6b\xe1\xab\xfd\x6f\x02\x8a\x9b\x17\xff\x4b\xed\xdc\x54\x8f\xd7\x7e\x92\xf9\xfc\xb8\x2f\x5d\xd7\x2f\x57\xd9\x43\xda\xbe\xd1\x65\x74\x02\x4a\x79\x4f\xf3\xdd\xd0\xc0\x5b\x24\x95\xc7\x3c\xd6\xff\x09\x65\xfc\x69\xd2\xfa\x4b\x4c\x5a\x46\x5a\x7a\xa8\x94\x44\x5f\xba\xf8\xe0\xf8\x10\xb4\x0c\x35\x3d\x66\x96\x85\xd6\x8e\xe7\xf8\xf7\xfd\x2a\xf6\x5e\xd0\x7c\xe3\x02\xcd\xf8\xc7\xe6\x95\x82\xc5\x73\x6e\x90\x25\x30\x42\x73\x9a\xc4\xf6\xca\x76\x23\xa2\x69\x75\xb4\x35\x3c\xcc\xb9\x00\x72\x3f\x08\x62\x02\xa0\x00\x09\x82\x5c\x00\x4a\x90\x9f\xbc\xaa\x3f\x92\xfa\xd3\x87\xd3\x53\xfe\xc5\xc9\xd9\x62\xfa\xa5\xfc\x72\x7a\xba\xd0\x62\xb9\x2e\x43\xe4\x03\x7b\x82\x6e\x92\x62\x05\xef\x35\xe4\x17\x92\xdc\x01\x98\x24\xf4\xa6\x1e\x2c\xcd\x11\x73\x04\x10\x89\xbd\x06\x9c\x9d\x9c\x9d\x9e\x12\xa9\x3d\x31\xfe\xf3\x30\xf3\x2c\x53\x00\xc0\x22\x58\x04\xff\x1b\x00\xdc\x9d\x7a\x87\x27\x4e\x00\x00")
 
 func schemaJsonBytes() ([]byte, error) {
 	return bindataRead(
@@ -0,0 +1,175 @@
+package logschema
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// Constraints restrict the valid values of string and number fields.
+// Events with values that do not satisfy the constraints fail validation.
+// nolint:lll
+type Constraints struct {
+	Enum      []string `json:"enum,omitempty" yaml:"enum,omitempty" description:"The allowed values"`
+	Pattern   string   `json:"pattern,omitempty" yaml:"pattern,omitempty" description:"A regular expression that the whole string value must match"`
+	Min       *float64 `json:"min,omitempty" yaml:"min,omitempty" description:"The minimum number value"`
+	Max       *float64 `json:"max,omitempty" yaml:"max,omitempty" description:"The maximum number value"`
+	MaxLength int      `json:"maxLength,omitempty" yaml:"maxLength,omitempty" description:"The maximum number of characters of a string value"`
+}
+
+// IsEmpty checks if no constraints are defined.
+// It is not named IsZero to avoid omitting ValueSchema values embedding Constraints in YAML (yaml.IsZeroer).
+func (c *Constraints) IsEmpty() bool {
+	return reflect.DeepEqual(c, &Constraints{})
+}
+
+// Clone returns a deep copy of the constraints
+func (c *Constraints) Clone() Constraints {
+	out := *c
+	out.Enum = append([]string(nil), c.Enum...)
+	if c.Min != nil {
+		min := *c.Min
+		out.Min = &min
+	}
+	if c.Max != nil {
+		max := *c.Max
+		out.Max = &max
+	}
+	return out
+}
+
+// Validate checks that the constraints can be applied to a value
+func (c *Constraints) Validate(v *ValueSchema) error {
+	if c.IsEmpty() {
+		return nil
+	}
+	isString := v.Type == TypeString
+	isInt := v.Type == TypeInt || v.Type == TypeBigInt || v.Type == TypeSmallInt
+	if !isString && !isInt && v.Type != TypeFloat {
+		return errors.Errorf("constraints cannot be used on %s values", v.Type)
+	}
+	if !isString && (c.Pattern != "" || c.MaxLength != 0) {
+		return errors.New("pattern and maxLength can only be used on string values")
+	}
+	if isString && (c.Min != nil || c.Max != nil) {
+		return errors.New("min and max can only be used on number values")
+	}
+	if c.MaxLength < 0 {
+		return errors.New("invalid maxLength")
+	}
+	if c.Pattern != "" {
+		if _, err := regexp.Compile(c.Pattern); err != nil {
+			return errors.Wrap(err, "invalid pattern")
+		}
+	}
+	if c.Min != nil && c.Max != nil && *c.Min > *c.Max {
+		return errors.New("min is greater than max")
+	}
+	if isInt {
+		for _, n := range []*float64{c.Min, c.Max} {
+			if n != nil && math.Trunc(*n) != *n {
+				return errors.Errorf("%s values cannot have fractional limits", v.Type)
+			}
+		}
+	}
+	for _, value := range c.Enum {
+		switch {
+		case isInt:
+			if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+				return errors.Errorf("invalid %s enum value %q", v.Type, value)
+			}
+		case v.Type == TypeFloat:
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				return errors.Errorf("invalid %s enum value %q", v.Type, value)
+			}
+		}
+	}
+	return nil
+}
+
+// validateRules returns the rules of a `validate` struct tag that enforce the constraints
+func (c *Constraints) validateRules() []string {
+	var rules []string
+	if len(c.Enum) > 0 {
+		rules = append(rules, pantherlog.ValidateEnum+"="+pantherlog.EnumParam(c.Enum...))
+	}
+	if c.Pattern != "" {
+		// The whole value must match the pattern
+		rules = append(rules, pantherlog.ValidatePattern+"="+pantherlog.PatternParam(`^(?:`+c.Pattern+`)$`))
+	}
+	if c.MaxLength > 0 {
+		rules = append(rules, "max="+strconv.Itoa(c.MaxLength))
+	}
+	if c.Min != nil {
+		rules = append(rules, "min="+strconv.FormatFloat(*c.Min, 'f', -1, 64))
+	}
+	if c.Max != nil {
+		rules = append(rules, "max="+strconv.FormatFloat(*c.Max, 'f', -1, 64))
+	}
+	return rules
+}
+
+// buildValidateTag builds the `validate` struct tag of a field.
+// Constraints of array elements are applied to each element.
+func buildValidateTag(field *FieldSchema) string {
+	rule := "omitempty"
+	if field.Required {
+		rule = "required"
+	}
+	rules := []string{rule}
+	value := &field.ValueSchema
+	for value.Type == TypeArray && hasConstraints(value.Element) {
+		// Null elements are skipped
+		rules = append(rules, "dive", "omitempty")
+		value = value.Element
+	}
+	rules = append(rules, value.Constraints.validateRules()...)
+	if len(rules) == 1 && !field.Required {
+		return ""
+	}
+	return `validate:` + quoteStructTag(strings.Join(rules, ","))
+}
+
+// validateConstraints validates the constraints of a value and its array elements
+func validateConstraints(v *ValueSchema) error {
+	for ; v.Type == TypeArray && v.Element != nil; v = v.Element {
+		if !v.Constraints.IsEmpty() {
+			return errors.New("array values cannot have constraints, use constraints on the element")
+		}
+	}
+	return v.Constraints.Validate(v)
+}
+
+func hasConstraints(v *ValueSchema) bool {
+	if v == nil {
+		return false
+	}
+	if v.Type == TypeArray {
+		return hasConstraints(v.Element)
+	}
+	return !v.Constraints.IsEmpty()
+}
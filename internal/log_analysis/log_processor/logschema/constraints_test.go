@@ -0,0 +1,99 @@
+package logschema
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConstraintsValidate(t *testing.T) {
+	min, max := 1.0, 0.5
+	for _, tc := range []struct {
+		Name  string
+		Value ValueSchema
+		Fail  bool
+	}{
+		{"string enum", ValueSchema{Type: TypeString, Constraints: Constraints{Enum: []string{"a", "b"}}}, false},
+		{"string pattern", ValueSchema{Type: TypeString, Constraints: Constraints{Pattern: `[a-z]+`, MaxLength: 10}}, false},
+		{"int enum", ValueSchema{Type: TypeInt, Constraints: Constraints{Enum: []string{"1", "2"}}}, false},
+		{"int range", ValueSchema{Type: TypeBigInt, Constraints: Constraints{Min: &max}}, true},
+		{"float range", ValueSchema{Type: TypeFloat, Constraints: Constraints{Min: &max, Max: &min}}, false},
+		{"invalid range", ValueSchema{Type: TypeFloat, Constraints: Constraints{Min: &min, Max: &max}}, true},
+		{"invalid int enum", ValueSchema{Type: TypeInt, Constraints: Constraints{Enum: []string{"a"}}}, true},
+		{"invalid pattern", ValueSchema{Type: TypeString, Constraints: Constraints{Pattern: `[a-z`}}, true},
+		{"string range", ValueSchema{Type: TypeString, Constraints: Constraints{Max: &min}}, true},
+		{"number pattern", ValueSchema{Type: TypeInt, Constraints: Constraints{Pattern: `\d+`}}, true},
+		{"boolean enum", ValueSchema{Type: TypeBoolean, Constraints: Constraints{Enum: []string{"true"}}}, true},
+		{"array constraints", ValueSchema{Type: TypeArray, Element: &ValueSchema{Type: TypeString}, Constraints: Constraints{MaxLength: 1}}, true},
+	} {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			err := validateConstraints(&tc.Value)
+			if tc.Fail {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestConstraintsTags(t *testing.T) {
+	assert := require.New(t)
+	min, max := 0.0, 65535.0
+	goFields, err := objectFields([]FieldSchema{
+		{
+			Name:        "method",
+			Required:    true,
+			ValueSchema: ValueSchema{Type: TypeString, Constraints: Constraints{Enum: []string{"GET", "POST", "TWO WORDS"}}},
+		},
+		{
+			Name:        "port",
+			ValueSchema: ValueSchema{Type: TypeInt, Constraints: Constraints{Min: &min, Max: &max}},
+		},
+		{
+			Name: "tags",
+			ValueSchema: ValueSchema{
+				Type:    TypeArray,
+				Element: &ValueSchema{Type: TypeString, Constraints: Constraints{Pattern: `\w+,\w+`, MaxLength: 8}},
+			},
+		},
+		{
+			Name:        "name",
+			ValueSchema: ValueSchema{Type: TypeString},
+		},
+	})
+	assert.NoError(err)
+	assert.Len(goFields, 4)
+	assert.Equal(`json:"method,omitempty" validate:"required,enum=GET POST TWO+WORDS" description:"method"`, string(goFields[0].Tag))
+	assert.Equal(`json:"port,omitempty" validate:"omitempty,min=0,max=65535" description:"port"`, string(goFields[1].Tag))
+	assert.Equal(`json:"tags,omitempty" validate:"omitempty,dive,omitempty,pattern=%5E%28%3F%3A%5Cw%2B%2C%5Cw%2B%29%24,max=8" description:"tags"`,
+		string(goFields[2].Tag))
+	assert.Equal(`json:"name,omitempty" description:"name"`, string(goFields[3].Tag))
+
+	_, err = objectFields([]FieldSchema{
+		{
+			Name:        "port",
+			ValueSchema: ValueSchema{Type: TypeString, Constraints: Constraints{Min: &min}},
+		},
+	})
+	assert.Error(err)
+}
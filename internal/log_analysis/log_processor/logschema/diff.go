@@ -43,10 +43,16 @@ const (
 	UpdateFieldMeta = "UpdateFieldMeta"
 	// UpdateValue is the type of change when a field's value type has changed.
 	UpdateValue = "UpdateValue"
-	// UpdateValueMeta is the type of change when metadata about a field's value type has changed (i.e. TimeFormat, IsEventTime, Indicators).
+	// UpdateValueMeta is the type of change when metadata about a field's value type has changed (i.e. TimeFormat, TimeZone, IsEventTime, Indicators).
 	UpdateValueMeta = "UpdateValueMeta"
+	// UpdateTransforms is the type of change when a field's transforms have changed (i.e. Rename, Split, Default).
+	UpdateTransforms = "UpdateTransforms"
 	// UpdateParser is the type of change when a schema's Parser has changed.
 	UpdateParser = "UpdateParser"
+	// UpdateCustomIndicators is the type of change when a schema's custom indicators have changed.
+	UpdateCustomIndicators = "UpdateCustomIndicators"
+	// UpdateUnion is the type of change when a schema's union tag or variant tag values have changed.
+	UpdateUnion = "UpdateUnion"
 	// UpdateMeta is the type of change when a schema's metadata has changed (i.e. Schema, Description, ReferenceURL).
 	UpdateMeta = "UpdateMeta"
 )
@@ -75,6 +81,13 @@ func Diff(from, to *Schema) ([]Change, error) {
 	if !reflect.DeepEqual(from.Parser, to.Parser) {
 		c.add(UpdateParser, from.Parser, to.Parser, "Parser")
 	}
+	if !reflect.DeepEqual(from.CustomIndicators, to.CustomIndicators) {
+		c.add(UpdateCustomIndicators, from.CustomIndicators, to.CustomIndicators, "CustomIndicators")
+	}
+	// Changes to the fields of union variants are found by walking the merged values
+	if !from.Union.equalTags(to.Union) {
+		c.add(UpdateUnion, from.Union, to.Union, "Union")
+	}
 	DiffWalk(valueFrom, valueTo, func(ch Change) bool {
 		c.changes = append(c.changes, ch)
 		return true
@@ -131,6 +144,17 @@ func diffWalk(from, to *ValueSchema, walk func(c Change) bool, path []string) bo
 				return false
 			}
 		}
+		if from.TimeZone != to.TimeZone {
+			ch := Change{
+				Path: append(path, "TimeZone"),
+				Type: UpdateValueMeta,
+				From: from,
+				To:   to,
+			}
+			if !walk(ch) {
+				return false
+			}
+		}
 		return true
 	case TypeString:
 		if from, to, changed := diffIndicators(from.Indicators, to.Indicators); changed {
@@ -140,12 +164,27 @@ func diffWalk(from, to *ValueSchema, walk func(c Change) bool, path []string) bo
 				From: from,
 				To:   to,
 			}
-			return walk(ch)
+			if !walk(ch) {
+				return false
+			}
 		}
-		return true
+		return diffConstraints(from, to, walk, path)
 	default:
+		return diffConstraints(from, to, walk, path)
+	}
+}
+
+func diffConstraints(from, to *ValueSchema, walk func(c Change) bool, path []string) bool {
+	if reflect.DeepEqual(from.Constraints, to.Constraints) {
 		return true
 	}
+	ch := Change{
+		Type: UpdateValueMeta,
+		Path: append(path, "Constraints"),
+		From: from.Constraints,
+		To:   to.Constraints,
+	}
+	return walk(ch)
 }
 
 func walkObject(from, to []FieldSchema, walk func(c Change) bool, path []string) bool {
@@ -156,6 +195,17 @@ func walkObject(from, to []FieldSchema, walk func(c Change) bool, path []string)
 			if !diffWalk(&A.ValueSchema, &B.ValueSchema, walk, append(path, A.Name)) {
 				return false
 			}
+			if !reflect.DeepEqual(A.Transforms, B.Transforms) {
+				ch := Change{
+					Type: UpdateTransforms,
+					Path: append(path, A.Name, "Transforms"),
+					From: A.Transforms,
+					To:   B.Transforms,
+				}
+				if !walk(ch) {
+					return false
+				}
+			}
 			if A.Required != B.Required {
 				ch := Change{
 					Type: UpdateFieldMeta,
@@ -0,0 +1,146 @@
+package logschema
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// CustomIndicator defines an indicator field `p_any_<name>` for the values of string fields with `indicators: [<name>]`.
+// Values are trimmed and normalized before they are checked against the pattern.
+// nolint:lll
+type CustomIndicator struct {
+	Name        string `json:"name" yaml:"name" description:"The name of the indicator, values are stored in the p_any_<name> field"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty" description:"The description of the indicator values"`
+	Pattern     string `json:"pattern,omitempty" yaml:"pattern,omitempty" description:"A regular expression that values must match"`
+	Normalize   string `json:"normalize,omitempty" yaml:"normalize,omitempty" description:"Normalize values before matching (lower|upper|mac)"`
+}
+
+var indicatorNormalizers = map[string]func(string) (string, bool){
+	"lower": func(s string) (string, bool) {
+		return strings.ToLower(s), true
+	},
+	"upper": func(s string) (string, bool) {
+		return strings.ToUpper(s), true
+	},
+	// Use lowercase colon separated hex digits for MAC addresses
+	"mac": func(s string) (string, bool) {
+		addr, err := net.ParseMAC(s)
+		if err != nil {
+			return "", false
+		}
+		return addr.String(), true
+	},
+}
+
+// Scanner builds a value scanner for the indicator field
+func (c *CustomIndicator) Scanner(id pantherlog.FieldID) (pantherlog.ValueScanner, error) {
+	var pattern *regexp.Regexp
+	if c.Pattern != "" {
+		re, err := regexp.Compile(`^(?:` + c.Pattern + `)$`)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid pattern for indicator %q", c.Name)
+		}
+		pattern = re
+	}
+	var normalize func(string) (string, bool)
+	if c.Normalize != "" {
+		fn, ok := indicatorNormalizers[c.Normalize]
+		if !ok {
+			return nil, errors.Errorf("invalid normalizer %q for indicator %q", c.Normalize, c.Name)
+		}
+		normalize = fn
+	}
+	return pantherlog.ValueScannerFunc(func(w pantherlog.ValueWriter, input string) {
+		value := strings.TrimSpace(input)
+		if value == "" {
+			return
+		}
+		if normalize != nil {
+			var ok bool
+			if value, ok = normalize(value); !ok {
+				return
+			}
+		}
+		if pattern != nil && !pattern.MatchString(value) {
+			return
+		}
+		w.WriteValues(id, value)
+	}), nil
+}
+
+// ResolveIndicators registers the custom indicators of a log type and updates the indicators of a resolved value to use them.
+// The registrations of a previous build of the log type are replaced.
+// It fails if the value uses an indicator that is neither built-in nor defined in `indicators`.
+func ResolveIndicators(logType string, value *ValueSchema, indicators []CustomIndicator) error {
+	scanners := make(map[string]string, len(indicators))
+	for i := range indicators {
+		c := &indicators[i]
+		if scanner, _ := pantherlog.LookupScanner(c.Name); scanner != nil {
+			return errors.Errorf("custom indicator %q conflicts with a built-in indicator", c.Name)
+		}
+		if _, duplicate := scanners[c.Name]; duplicate {
+			return errors.Errorf("duplicate custom indicator %q", c.Name)
+		}
+		id, err := pantherlog.RegisterCustomIndicator(logType, c.Name, c.Description)
+		if err != nil {
+			return err
+		}
+		scanner, err := c.Scanner(id)
+		if err != nil {
+			return err
+		}
+		name := pantherlog.CustomScannerName(logType, c.Name)
+		if err := pantherlog.RegisterCustomScanner(name, scanner, id); err != nil {
+			return err
+		}
+		scanners[c.Name] = name
+	}
+	return resolveIndicators(value, scanners)
+}
+
+func resolveIndicators(value *ValueSchema, scanners map[string]string) error {
+	switch value.Type {
+	case TypeObject:
+		for i := range value.Fields {
+			if err := resolveIndicators(&value.Fields[i].ValueSchema, scanners); err != nil {
+				return errors.WithMessagef(err, "field %q", value.Fields[i].Name)
+			}
+		}
+	case TypeArray:
+		return resolveIndicators(value.Element, scanners)
+	case TypeString:
+		for i, name := range value.Indicators {
+			if scanner, ok := scanners[name]; ok {
+				value.Indicators[i] = scanner
+				continue
+			}
+			if scanner, _ := pantherlog.LookupScanner(name); scanner == nil {
+				return errors.Errorf("unknown indicator %q", name)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package logschema
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+func TestResolveIndicators(t *testing.T) {
+	assert := require.New(t)
+	indicators := []CustomIndicator{
+		{
+			Name:      "ticket_id",
+			Pattern:   `[A-Z]+-[0-9]+`,
+			Normalize: "upper",
+		},
+	}
+	value := &ValueSchema{
+		Type: TypeObject,
+		Fields: []FieldSchema{
+			{
+				Name: "ticket",
+				ValueSchema: ValueSchema{
+					Type:       TypeString,
+					Indicators: []string{"ticket_id", "url"},
+				},
+			},
+			{
+				Name: "links",
+				ValueSchema: ValueSchema{
+					Type: TypeArray,
+					Element: &ValueSchema{
+						Type:       TypeString,
+						Indicators: []string{"ticket_id"},
+					},
+				},
+			},
+		},
+	}
+	assert.NoError(ResolveIndicators("Custom.Tickets", value, indicators))
+	name := pantherlog.CustomScannerName("Custom.Tickets", "ticket_id")
+	assert.Equal([]string{name, "url"}, value.Fields[0].Indicators)
+	assert.Equal([]string{name}, value.Fields[1].Element.Indicators)
+
+	scanner, fields := pantherlog.LookupScanner(name)
+	assert.NotNil(scanner)
+	assert.Len(fields, 1)
+	assert.Equal("p_any_ticket_id", pantherlog.FieldNameJSON(fields[0]))
+	values := pantherlog.ValueBuffer{}
+	scanner.ScanValues(&values, " sec-123 ")
+	scanner.ScanValues(&values, "not a ticket")
+	assert.Equal([]string{"SEC-123"}, values.Get(fields[0]))
+
+	// Built-in names cannot be redefined
+	assert.Error(ResolveIndicators("Custom.Tickets", &ValueSchema{Type: TypeString}, []CustomIndicator{{Name: "ip"}}))
+	// Unknown indicators are rejected
+	assert.Error(ResolveIndicators("Custom.Tickets", &ValueSchema{Type: TypeString, Indicators: []string{"unknown_kind"}}, nil))
+	// Invalid patterns are rejected
+	assert.Error(ResolveIndicators("Custom.Tickets", &ValueSchema{Type: TypeString}, []CustomIndicator{{Name: "bad_pattern", Pattern: "("}}))
+}
+
+func TestDiffCustomIndicators(t *testing.T) {
+	assert := require.New(t)
+	from := &Schema{
+		Fields: []FieldSchema{
+			{
+				Name:        "foo",
+				ValueSchema: ValueSchema{Type: TypeString},
+			},
+		},
+	}
+	to := from.Clone()
+	to.CustomIndicators = []CustomIndicator{{Name: "foo_id"}}
+	changes, err := Diff(from, to)
+	assert.NoError(err)
+	assert.Len(changes, 1)
+	assert.Equal(UpdateCustomIndicators, changes[0].Type)
+	assert.Equal([]string{"CustomIndicators"}, changes[0].Path)
+}
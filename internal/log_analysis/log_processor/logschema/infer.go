@@ -29,8 +29,10 @@ import (
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/itchyny/timefmt-go"
 	"github.com/pkg/errors"
 
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog/tcodec"
 	"github.com/panther-labs/panther/pkg/x/structfields"
 )
 
@@ -110,10 +112,10 @@ func inferString(s string) *ValueSchema {
 			Type: TypeBoolean,
 		}
 	}
-	if _, err := time.Parse(time.RFC3339, s); err == nil {
+	if timeFormat := inferTimeFormat(s); timeFormat != "" {
 		return &ValueSchema{
 			Type:       TypeTimestamp,
-			TimeFormat: "rfc3339",
+			TimeFormat: timeFormat,
 		}
 	}
 	return &ValueSchema{
@@ -122,6 +124,43 @@ func inferString(s string) *ValueSchema {
 	}
 }
 
+// inferTimeFormats are the strftime formats to detect timestamps in string values.
+// Each format is paired with an equivalent Go layout because strftime parsing is not strict about the number of digits.
+var inferTimeFormats = []struct {
+	Format string
+	Layout string
+}{
+	{"%Y-%m-%d %H:%M:%S", "2006-01-02 15:04:05"},
+	{"%Y-%m-%d %H:%M:%S.%f", "2006-01-02 15:04:05.999999"},
+	{"%Y-%m-%dT%H:%M:%S", "2006-01-02T15:04:05"},
+	{"%Y-%m-%dT%H:%M:%S.%f", "2006-01-02T15:04:05.999999"},
+	{"%Y/%m/%d %H:%M:%S", "2006/01/02 15:04:05"},
+	{"%d/%b/%Y:%H:%M:%S %z", "02/Jan/2006:15:04:05 -0700"},
+	{"%a, %d %b %Y %H:%M:%S %z", time.RFC1123Z},
+	{"%Y-%m-%d", "2006-01-02"},
+}
+
+func inferTimeFormat(s string) string {
+	if _, err := time.Parse(time.RFC3339, s); err == nil {
+		return "rfc3339"
+	}
+	for _, f := range inferTimeFormats {
+		if _, err := time.Parse(f.Layout, s); err != nil {
+			continue
+		}
+		if _, err := timefmt.Parse(s, f.Format); err == nil {
+			return f.Format
+		}
+	}
+	// Only detect LDAP timestamps with explicit UTC time zone to avoid matching numeric strings
+	if strings.HasSuffix(s, "Z") {
+		if _, err := tcodec.ParseGeneralizedTime(s, time.UTC); err == nil {
+			return "ldap"
+		}
+	}
+	return ""
+}
+
 func inferIndicators(s string) []string {
 	if ip := net.ParseIP(s); ip != nil {
 		return []string{"ip"}
@@ -252,6 +291,7 @@ func inferFieldSchema(s *FieldSchema, field reflect.StructField) error {
 		if format, ok := field.Tag.Lookup("tcodec"); ok {
 			value.TimeFormat = format
 		}
+		value.TimeZone = field.Tag.Get(tcodec.LocationTagName)
 	}
 	if pantherTag := field.Tag.Get("panther"); pantherTag != "" {
 		scanners := strings.Split(pantherTag, ",")
@@ -40,3 +40,41 @@ func TestExport(t *testing.T) {
 		println(string(data))
 	}
 }
+
+func TestInferTimeFormats(t *testing.T) {
+	assert := require.New(t)
+	for input, expect := range map[string]string{
+		"2020-10-16T12:30:45Z":            "rfc3339",
+		"2020-10-16T12:30:45.123+02:00":   "rfc3339",
+		"2020-10-16 12:30:45":             "%Y-%m-%d %H:%M:%S",
+		"2020-10-16 12:30:45.123456":      "%Y-%m-%d %H:%M:%S.%f",
+		"2020-10-16T12:30:45":             "%Y-%m-%dT%H:%M:%S",
+		"2020/10/16 12:30:45":             "%Y/%m/%d %H:%M:%S",
+		"16/Oct/2020:12:30:45 -0700":      "%d/%b/%Y:%H:%M:%S %z",
+		"Fri, 16 Oct 2020 12:30:45 +0000": "%a, %d %b %Y %H:%M:%S %z",
+		"2020-10-16":                      "%Y-%m-%d",
+		"20201016123045.0Z":               "ldap",
+	} {
+		value := logschema.InferJSONValueSchema(input)
+		assert.Equal(logschema.TypeTimestamp, value.Type, input)
+		assert.Equal(expect, value.TimeFormat, input)
+	}
+	for _, input := range []string{
+		"2020-10-16 12:30:45.123456789",
+		"2020-13-16",
+		"20201016123045",
+		"16/10/2020",
+	} {
+		value := logschema.InferJSONValueSchema(input)
+		assert.NotEqual(logschema.TypeTimestamp, value.Type, input)
+	}
+	// Numeric timestamps keep their format when merged with numbers
+	merged := logschema.Merge(&logschema.ValueSchema{
+		Type:       logschema.TypeTimestamp,
+		TimeFormat: "filetime",
+	}, &logschema.ValueSchema{
+		Type: logschema.TypeBigInt,
+	})
+	assert.Equal(logschema.TypeTimestamp, merged.Type)
+	assert.Equal("filetime", merged.TimeFormat)
+}
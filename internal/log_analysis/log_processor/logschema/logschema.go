@@ -26,6 +26,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/preprocessors"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/logstream"
 	"github.com/panther-labs/panther/pkg/stringset"
 
 	// Force dependency on go-bindata to avoid fetching during mage gen
@@ -66,6 +67,10 @@ type Schema struct {
 	Version      int                     `json:"version" yaml:"version"`
 	Definitions  map[string]*ValueSchema `json:"definitions,omitempty" yaml:"definitions,omitempty"`
 	Fields       []FieldSchema           `json:"fields" yaml:"fields"`
+	// Union defines log events of different shapes that extend the common `fields`
+	Union *Union `json:"union,omitempty" yaml:"union,omitempty"`
+	// CustomIndicators defines additional `p_any_<name>` fields for the indicators of the schema
+	CustomIndicators []CustomIndicator `json:"customIndicators,omitempty" yaml:"customIndicators,omitempty"`
 }
 
 func (s *Schema) Clone() *Schema {
@@ -89,7 +94,13 @@ type Parser struct {
 	CSV       *preprocessors.CSVMatchConfig  `json:"csv,omitempty" yaml:"csv,omitempty"`
 	FastMatch *preprocessors.FastMatchConfig `json:"fastmatch,omitempty" yaml:"fastmatch,omitempty"`
 	Regex     *preprocessors.RegexConfig     `json:"regex,omitempty" yaml:"regex,omitempty"`
+	KV        *preprocessors.KVConfig        `json:"kv,omitempty" yaml:"kv,omitempty"`
+	CEF       *preprocessors.CEFConfig       `json:"cef,omitempty" yaml:"cef,omitempty"`
+	LEEF      *preprocessors.LEEFConfig      `json:"leef,omitempty" yaml:"leef,omitempty"`
+	XML       *preprocessors.XMLConfig       `json:"xml,omitempty" yaml:"xml,omitempty"`
 	Native    *NativeParser                  `json:"native,omitempty" taml:"native,omitempty"`
+	// Framing reassembles log entries that span multiple lines before they are parsed
+	Framing *logstream.FramingConfig `json:"framing,omitempty" yaml:"framing,omitempty"`
 }
 
 type NativeParser struct {
@@ -103,13 +114,27 @@ type ValueSchema struct {
 	Target      string        `json:"target,omitempty" yaml:"target,omitempty"`
 	Indicators  []string      `json:"indicators,omitempty" yaml:"indicators,omitempty"`
 	TimeFormat  string        `json:"timeFormat,omitempty" yaml:"timeFormat,omitempty"`
+	TimeZone    string        `json:"timeZone,omitempty" yaml:"timeZone,omitempty"`
 	IsEventTime bool          `json:"isEventTime,omitempty" yaml:"isEventTime,omitempty"`
+	// Transforms apply to the value of object fields
+	Transforms `yaml:",inline"`
+	// Constraints restrict the valid values of strings and numbers
+	Constraints `yaml:",inline"`
 }
 
 func (v *ValueSchema) Clone() *ValueSchema {
 	if v == nil {
 		return nil
 	}
+	out := v.cloneValue()
+	if out != nil {
+		out.Transforms = v.Transforms.Clone()
+		out.Constraints = v.Constraints.Clone()
+	}
+	return out
+}
+
+func (v *ValueSchema) cloneValue() *ValueSchema {
 	switch v.Type {
 	case TypeObject:
 		var fields []FieldSchema
@@ -131,6 +156,7 @@ func (v *ValueSchema) Clone() *ValueSchema {
 		return &ValueSchema{
 			Type:        TypeTimestamp,
 			TimeFormat:  v.TimeFormat,
+			TimeZone:    v.TimeZone,
 			IsEventTime: v.IsEventTime,
 		}
 	case TypeString:
@@ -57,12 +57,13 @@ func Merge(a, b *ValueSchema) *ValueSchema {
 			}
 			return &ValueSchema{Type: TypeString}
 		case TypeTimestamp:
-			if a.TimeFormat != b.TimeFormat {
+			if a.TimeFormat != b.TimeFormat || a.TimeZone != b.TimeZone {
 				return &ValueSchema{Type: TypeString}
 			}
 			return &ValueSchema{
 				Type:        TypeTimestamp,
 				TimeFormat:  a.TimeFormat,
+				TimeZone:    a.TimeZone,
 				IsEventTime: a.IsEventTime || b.IsEventTime, // event time should be 'sticky'
 			}
 		default:
@@ -131,7 +132,7 @@ func castTimestamp(typ ValueType, timeFormat string, isEventTime bool) *ValueSch
 	switch typ {
 	case TypeBigInt:
 		switch timeFormat {
-		case "unix", "unix_ms", "unix_us", "unix_ns":
+		case "unix", "unix_ms", "unix_us", "unix_ns", "filetime", "dotnet_ticks", "ldap":
 			// Preserve time format as this is something we cannot infer
 			return &ValueSchema{
 				Type:        TypeTimestamp,
@@ -26,10 +26,12 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog/tcodec"
 )
 
 // This file provides utils conversion from logschema.ValueSchema to go reflect.Type
@@ -91,6 +93,17 @@ func objectFields(schema []FieldSchema) ([]reflect.StructField, error) {
 		if err != nil {
 			return nil, err
 		}
+		if err := field.Transforms.Validate(&field.ValueSchema); err != nil {
+			return nil, errors.WithMessagef(err, "invalid transforms for field %q", field.Name)
+		}
+		if err := validateConstraints(&field.ValueSchema); err != nil {
+			return nil, errors.WithMessagef(err, "invalid constraints for field %q", field.Name)
+		}
+		if tz := field.TimeZone; tz != "" && field.Type == TypeTimestamp {
+			if _, err := time.LoadLocation(tz); err != nil {
+				return nil, errors.Wrapf(err, "invalid time zone for field %q", field.Name)
+			}
+		}
 		fields = append(fields, reflect.StructField{
 			Name:  "Field_" + strconv.Itoa(i) + "_" + fieldNameGo(field.Name),
 			Type:  typ,
@@ -120,10 +133,13 @@ func buildStructTag(schema *FieldSchema) reflect.StructTag {
 		name = "-"
 	}
 	tag := fmt.Sprintf(`json:"%s,omitempty"`, name)
-	if schema.Required {
-		tag += ` validate:"required"`
+	if validate := buildValidateTag(schema); validate != "" {
+		tag += " " + validate
 	}
 	tag = extendStructTag(&schema.ValueSchema, tag)
+	if !schema.Transforms.IsEmpty() {
+		tag += " " + schema.Transforms.structTag()
+	}
 	desc := normalizeSpace(schema.Description)
 	if desc == "" {
 		desc = schema.Name
@@ -158,23 +174,34 @@ func extendStructTag(schema *ValueSchema, tag string) string {
 		if schema.IsEventTime {
 			tag = tag + ` event_time:"true"`
 		}
-		var codec string
-		switch timeFormat := schema.TimeFormat; timeFormat {
-		case "rfc3339", "unix", "unix_ms", "unix_us", "unix_ns":
-			codec = timeFormat
-		case "":
-			// Use rfc3339 as the default codec.
-			// Keep this in case we decide to make `timeFormat`/`customTimeFormat` optional.
-			codec = "rfc3339"
-		default:
-			codec = "strftime=" + timeFormat
+		codec := timeCodecTag(schema.TimeFormat)
+		tag = tag + fmt.Sprintf(` tcodec:"%s"`, codec)
+		if schema.TimeZone != "" {
+			tag = tag + fmt.Sprintf(` %s:"%s"`, tcodec.LocationTagName, schema.TimeZone)
 		}
-		return tag + fmt.Sprintf(` tcodec:"%s"`, codec)
+		return tag
 	default:
 		return tag
 	}
 }
 
+// timeCodecTag maps a `timeFormat` to a `tcodec` tag value.
+// Registered codec names and `layout=`/`strftime=` values are used as is, anything else is a strftime format.
+func timeCodecTag(timeFormat string) string {
+	switch {
+	case timeFormat == "":
+		// Use rfc3339 as the default codec.
+		// Keep this in case we decide to make `timeFormat`/`customTimeFormat` optional.
+		return "rfc3339"
+	case strings.HasPrefix(timeFormat, "layout="), strings.HasPrefix(timeFormat, "strftime="):
+		return timeFormat
+	case tcodec.Lookup(timeFormat) != nil:
+		return timeFormat
+	default:
+		return "strftime=" + timeFormat
+	}
+}
+
 func fieldNameJSON(schema *FieldSchema) string {
 	data, _ := json.Marshal(schema.Name)
 	return string(unquoteJSON(data))
@@ -60,3 +60,40 @@ func TestArrayIndicators(t *testing.T) {
 	assert.Equal(reflect.TypeOf([]null.String{}), goFields[0].Type)
 	assert.Equal(`json:"remote_ips,omitempty" panther:"ip" description:"remote ip addresses"`, string(goFields[0].Tag))
 }
+
+func TestTimestampTags(t *testing.T) {
+	assert := require.New(t)
+	for timeFormat, expect := range map[string]string{
+		"":                  `tcodec:"rfc3339"`,
+		"unix_ms":           `tcodec:"unix_ms"`,
+		"filetime":          `tcodec:"filetime"`,
+		"dotnet_ticks":      `tcodec:"dotnet_ticks"`,
+		"ldap":              `tcodec:"ldap"`,
+		"layout=2006-01-02": `tcodec:"layout=2006-01-02"`,
+		"%Y-%m-%d %H:%M:%S": `tcodec:"strftime=%Y-%m-%d %H:%M:%S"`,
+	} {
+		tag := extendStructTag(&ValueSchema{
+			Type:       TypeTimestamp,
+			TimeFormat: timeFormat,
+		}, "")
+		assert.Equal(" "+expect, tag, timeFormat)
+	}
+	tag := extendStructTag(&ValueSchema{
+		Type:       TypeTimestamp,
+		TimeFormat: "%Y-%m-%d %H:%M:%S",
+		TimeZone:   "Europe/Athens",
+	}, "")
+	assert.Equal(` tcodec:"strftime=%Y-%m-%d %H:%M:%S" tz:"Europe/Athens"`, tag)
+
+	_, err := objectFields([]FieldSchema{
+		{
+			Name: "ts",
+			ValueSchema: ValueSchema{
+				Type:       TypeTimestamp,
+				TimeFormat: "%Y-%m-%d %H:%M:%S",
+				TimeZone:   "Nowhere/Land",
+			},
+		},
+	})
+	assert.Error(err)
+}
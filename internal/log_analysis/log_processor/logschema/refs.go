@@ -37,13 +37,25 @@ const MaxDepth = 64
 
 // Resolve returns a copy of a ValueSchema with all references resolved from a manifest.
 // It fails if a references cannot be resolved, if the nesting level exceeds MaxDepth or if there is a cyclic reference.
+// The values of all variants of a union schema are merged to a single value.
 func Resolve(schema *Schema) (*ValueSchema, error) {
+	if schema.Union != nil {
+		variants, err := ResolveVariants(schema)
+		if err != nil {
+			return nil, err
+		}
+		return mergeVariants(variants), nil
+	}
+	return resolveFields(schema.Fields, schema.Definitions)
+}
+
+func resolveFields(fields []FieldSchema, manifest map[string]*ValueSchema) (*ValueSchema, error) {
 	path := make([]string, 0, MaxDepth)
 	visited := make([]string, 0, MaxDepth)
 	resolved, err := safeBuild(&ValueSchema{
 		Type:   TypeObject,
-		Fields: schema.Fields,
-	}, schema.Definitions, path, visited)
+		Fields: fields,
+	}, manifest, path, visited)
 	if err != nil {
 		// Add stack here so we don't get huge recursive stack from safeBuild
 		return nil, errors.WithStack(err)
@@ -63,6 +75,8 @@ func safeBuild(input *ValueSchema, manifest map[string]*ValueSchema, path, visit
 			if err != nil {
 				return nil, err
 			}
+			// Transforms apply to the field so they are kept when resolving references
+			value.Transforms = field.Transforms.Clone()
 			field.ValueSchema = *value
 			out[i] = field
 		}
@@ -74,6 +88,9 @@ func safeBuild(input *ValueSchema, manifest map[string]*ValueSchema, path, visit
 		if len(path) == cap(path) {
 			return nil, fmt.Errorf("max nesting level (%d) exceeded", MaxDepth)
 		}
+		if input.Element != nil && !input.Element.Transforms.IsEmpty() {
+			return nil, fmt.Errorf("array elements cannot have transforms %v, use transforms on the array field", path)
+		}
 		item, err := safeBuild(input.Element, manifest, append(path, `[]`), visited)
 		if err != nil {
 			return nil, err
@@ -98,20 +115,28 @@ func safeBuild(input *ValueSchema, manifest map[string]*ValueSchema, path, visit
 		if !ok {
 			return nil, fmt.Errorf("unresolved type reference %q", target)
 		}
+		if !ref.Transforms.IsEmpty() {
+			return nil, fmt.Errorf("type definition %q cannot have transforms, use transforms on the fields referencing it", target)
+		}
 		return safeBuild(ref, manifest, path, append(visited, target))
 	case TypeString:
 		return &ValueSchema{
-			Type:       TypeString,
-			Indicators: append([]string(nil), input.Indicators...),
+			Type:        TypeString,
+			Indicators:  append([]string(nil), input.Indicators...),
+			Constraints: input.Constraints.Clone(),
 		}, nil
 	case TypeTimestamp:
 		return &ValueSchema{
 			Type:        TypeTimestamp,
 			TimeFormat:  input.TimeFormat,
+			TimeZone:    input.TimeZone,
 			IsEventTime: input.IsEventTime,
 		}, nil
 	default:
-		return &ValueSchema{Type: input.Type}, nil
+		return &ValueSchema{
+			Type:        input.Type,
+			Constraints: input.Constraints.Clone(),
+		}, nil
 	}
 }
 
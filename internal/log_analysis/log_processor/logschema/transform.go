@@ -0,0 +1,412 @@
+package logschema
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"unsafe"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/modern-go/reflect2"
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/transform"
+)
+
+// TagNameTransform is the struct tag holding the JSON encoded field transforms
+const TagNameTransform = "transform"
+
+func init() {
+	// Since the transform extension only affects structs with `transform` tags we register it globally
+	jsoniter.RegisterExtension(&transformExt{})
+}
+
+// Transforms are declarative fixes applied to the value of an object field before it is decoded.
+//
+// The value of a field is resolved in the following order:
+//   - The value is read from the `rename` or `copy` key, or built by `concat`, or else read from the field name.
+//   - String values are split by `split` or decoded from JSON by `parseJSON`
+//   - String values are masked by `mask`
+//   - Missing, null or empty values are replaced by `default`
+//
+// A renamed key is removed from the object whereas a copied key is kept.
+// nolint:lll
+type Transforms struct {
+	Rename    string           `json:"rename,omitempty" yaml:"rename,omitempty" description:"Read the value of the field from this key"`
+	Copy      string           `json:"copy,omitempty" yaml:"copy,omitempty" description:"Copy the value of the field from this key"`
+	Concat    *ConcatTransform `json:"concat,omitempty" yaml:"concat,omitempty" description:"Build the value of the field by joining the values of other keys"`
+	Split     *SplitTransform  `json:"split,omitempty" yaml:"split,omitempty" description:"Split a string value"`
+	ParseJSON bool             `json:"parseJSON,omitempty" yaml:"parseJSON,omitempty" description:"Decode the value of the field from a JSON encoded string"`
+	Mask      *MaskTransform   `json:"mask,omitempty" yaml:"mask,omitempty" description:"Hide sensitive string values"`
+	Default   string           `json:"default,omitempty" yaml:"default,omitempty" description:"Default value for missing or empty values"`
+}
+
+// ConcatTransform builds a string value by joining the values of other keys.
+// Missing or empty values are skipped.
+type ConcatTransform struct {
+	Fields    []string `json:"fields" yaml:"fields"`
+	Separator string   `json:"separator,omitempty" yaml:"separator,omitempty"`
+}
+
+// SplitTransform splits a string value by a separator.
+// Array fields use all parts, other fields use the part at `index` (negative indexes count from the end).
+type SplitTransform struct {
+	Separator string `json:"separator" yaml:"separator"`
+	Index     int    `json:"index,omitempty" yaml:"index,omitempty"`
+}
+
+// MaskTransform hides sensitive string values.
+// Values are replaced by transform.MaskValue, keeping `keepPrefix` and `keepSuffix` characters unmasked.
+// If `hash` is set the value is replaced by its hex encoded SHA256 hash.
+type MaskTransform struct {
+	Hash       bool `json:"hash,omitempty" yaml:"hash,omitempty"`
+	KeepPrefix int  `json:"keepPrefix,omitempty" yaml:"keepPrefix,omitempty"`
+	KeepSuffix int  `json:"keepSuffix,omitempty" yaml:"keepSuffix,omitempty"`
+}
+
+// IsEmpty checks if no transforms are defined.
+// It is not named IsZero to avoid omitting ValueSchema values embedding Transforms in YAML (yaml.IsZeroer).
+func (t *Transforms) IsEmpty() bool {
+	return reflect.DeepEqual(t, &Transforms{})
+}
+
+// Clone returns a deep copy of the transforms
+func (t *Transforms) Clone() Transforms {
+	out := *t
+	if t.Concat != nil {
+		out.Concat = &ConcatTransform{
+			Fields:    append([]string(nil), t.Concat.Fields...),
+			Separator: t.Concat.Separator,
+		}
+	}
+	if t.Split != nil {
+		split := *t.Split
+		out.Split = &split
+	}
+	if t.Mask != nil {
+		mask := *t.Mask
+		out.Mask = &mask
+	}
+	return out
+}
+
+// Validate checks that the transforms can be applied to a value
+func (t *Transforms) Validate(v *ValueSchema) error {
+	sources := 0
+	if t.Rename != "" {
+		sources++
+	}
+	if t.Copy != "" {
+		sources++
+	}
+	if t.Concat != nil {
+		if len(t.Concat.Fields) < 2 {
+			return errors.New("concat requires at least 2 fields")
+		}
+		sources++
+	}
+	if sources > 1 {
+		return errors.New("only one of rename, copy or concat can be used")
+	}
+	if t.Split != nil {
+		if t.ParseJSON {
+			return errors.New("split cannot be combined with parseJSON")
+		}
+		if t.Split.Separator == "" {
+			return errors.New("empty split separator")
+		}
+		if v.Type == TypeArray && v.Element.Type != TypeString {
+			return errors.New("split array values must have string elements")
+		}
+	}
+	if t.Mask != nil {
+		if v.Type != TypeString || t.ParseJSON {
+			return errors.New("mask can only be used on string values")
+		}
+		if t.Mask.KeepPrefix < 0 || t.Mask.KeepSuffix < 0 {
+			return errors.New("invalid mask length")
+		}
+	}
+	if t.Default != "" && (v.Type == TypeObject || v.Type == TypeArray) {
+		return errors.New("default cannot be used on object or array values")
+	}
+	return nil
+}
+
+// structTag returns the struct tag holding the transforms
+func (t *Transforms) structTag() string {
+	data, err := json.Marshal(t)
+	if err != nil {
+		// this should never happen
+		panic("failed to serialize transforms: " + err.Error())
+	}
+	return TagNameTransform + `:` + quoteStructTag(string(data))
+}
+
+func quoteStructTag(s string) string {
+	// strconv.Quote would escape unicode which reflect.StructTag does not need
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+type transformExt struct {
+	jsoniter.DummyExtension
+}
+
+// DecorateDecoder implements jsoniter.Extension interface
+func (*transformExt) DecorateDecoder(typ reflect2.Type, decoder jsoniter.ValDecoder) jsoniter.ValDecoder {
+	if typ.Kind() != reflect.Struct {
+		return decoder
+	}
+	var fields []*fieldTransforms
+	styp := typ.(reflect2.StructType)
+	for i := 0; i < styp.NumField(); i++ {
+		field := styp.Field(i)
+		tag, ok := field.Tag().Lookup(TagNameTransform)
+		if !ok {
+			continue
+		}
+		f := fieldTransforms{
+			name:  strings.Split(field.Tag().Get("json"), ",")[0],
+			array: field.Type().Kind() == reflect.Slice,
+		}
+		if err := json.Unmarshal([]byte(tag), &f.Transforms); err != nil || f.name == "" || f.name == "-" {
+			continue
+		}
+		fields = append(fields, &f)
+	}
+	if fields == nil {
+		return decoder
+	}
+	return &transformDecoder{
+		decoder: decoder,
+		fields:  fields,
+	}
+}
+
+type transformDecoder struct {
+	decoder jsoniter.ValDecoder
+	fields  []*fieldTransforms
+}
+
+// Decode implements jsoniter.ValDecoder interface
+func (d *transformDecoder) Decode(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	if iter.WhatIsNext() != jsoniter.ObjectValue {
+		d.decoder.Decode(ptr, iter)
+		return
+	}
+	var obj rawObject
+	iter.ReadMapCB(func(iter *jsoniter.Iterator, key string) bool {
+		// Copy the value since the iterator buffer gets reused
+		value := append([]byte(nil), bytes.TrimSpace(iter.SkipAndReturnBytes())...)
+		obj = append(obj, rawField{key: key, value: value})
+		return true
+	})
+	if iter.Error != nil {
+		return
+	}
+	for _, f := range d.fields {
+		obj = f.apply(obj)
+	}
+	// Writing raw JSON does not depend on the API configuration
+	stream := jsoniter.ConfigDefault.BorrowStream(nil)
+	defer jsoniter.ConfigDefault.ReturnStream(stream)
+	obj.writeJSON(stream)
+	sub := iter.Pool().BorrowIterator(stream.Buffer())
+	defer iter.Pool().ReturnIterator(sub)
+	d.decoder.Decode(ptr, sub)
+	if sub.Error != nil {
+		iter.ReportError("transform", sub.Error.Error())
+	}
+}
+
+type rawField struct {
+	key   string
+	value []byte
+}
+
+// rawObject is a JSON object with raw values that preserves the order of keys
+type rawObject []rawField
+
+func (obj rawObject) index(key string) int {
+	for i := range obj {
+		if obj[i].key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func (obj rawObject) get(key string) []byte {
+	if i := obj.index(key); i != -1 {
+		return obj[i].value
+	}
+	return nil
+}
+
+func (obj rawObject) set(key string, value []byte) rawObject {
+	if i := obj.index(key); i != -1 {
+		obj[i].value = value
+		return obj
+	}
+	return append(obj, rawField{key: key, value: value})
+}
+
+func (obj rawObject) remove(key string) rawObject {
+	if i := obj.index(key); i != -1 {
+		return append(obj[:i], obj[i+1:]...)
+	}
+	return obj
+}
+
+func (obj rawObject) writeJSON(stream *jsoniter.Stream) {
+	stream.WriteObjectStart()
+	for i := range obj {
+		if i > 0 {
+			stream.WriteMore()
+		}
+		stream.WriteObjectField(obj[i].key)
+		stream.WriteRaw(string(obj[i].value))
+	}
+	stream.WriteObjectEnd()
+}
+
+type fieldTransforms struct {
+	Transforms
+	name  string
+	array bool
+}
+
+func (f *fieldTransforms) apply(obj rawObject) rawObject {
+	var value []byte
+	switch {
+	case f.Rename != "":
+		if value = obj.get(f.Rename); value != nil {
+			obj = obj.remove(f.Rename)
+		} else {
+			value = obj.get(f.name)
+		}
+	case f.Copy != "":
+		value = obj.get(f.Copy)
+	case f.Concat != nil:
+		value = f.concat(obj)
+	default:
+		value = obj.get(f.name)
+	}
+	if s, ok := readRawString(value); ok {
+		switch {
+		case f.Split != nil:
+			value = f.split(s)
+		case f.ParseJSON:
+			value = nil
+			if data := []byte(s); jsoniter.Valid(data) {
+				value = data
+			}
+		}
+	}
+	if f.Mask != nil {
+		if s, ok := readRawString(value); ok && s != "" {
+			value = writeRawString(f.mask(s))
+		}
+	}
+	if f.Default != "" && isEmptyRawValue(value) {
+		value = writeRawString(f.Default)
+	}
+	if value == nil {
+		return obj.remove(f.name)
+	}
+	return obj.set(f.name, value)
+}
+
+func (f *fieldTransforms) concat(obj rawObject) []byte {
+	var parts []string
+	for _, key := range f.Concat.Fields {
+		value := obj.get(key)
+		s, ok := readRawString(value)
+		if !ok && !isEmptyRawValue(value) {
+			// Use non-string scalar values as is
+			s, ok = string(value), value[0] != '{' && value[0] != '['
+		}
+		if ok && s != "" {
+			parts = append(parts, s)
+		}
+	}
+	if parts == nil {
+		return nil
+	}
+	return writeRawString(strings.Join(parts, f.Concat.Separator))
+}
+
+func (f *fieldTransforms) split(s string) []byte {
+	parts := strings.Split(s, f.Split.Separator)
+	if f.array {
+		data, _ := jsoniter.Marshal(parts)
+		return data
+	}
+	i := f.Split.Index
+	if i < 0 {
+		i += len(parts)
+	}
+	if 0 <= i && i < len(parts) {
+		return writeRawString(parts[i])
+	}
+	return nil
+}
+
+func (f *fieldTransforms) mask(s string) string {
+	if f.Mask.Hash {
+		h := sha256.Sum256([]byte(s))
+		return hex.EncodeToString(h[:])
+	}
+	runes := []rune(s)
+	prefix, suffix := f.Mask.KeepPrefix, f.Mask.KeepSuffix
+	if prefix+suffix >= len(runes) {
+		return transform.MaskValue
+	}
+	return string(runes[:prefix]) + transform.MaskValue + string(runes[len(runes)-suffix:])
+}
+
+func readRawString(value []byte) (string, bool) {
+	if len(value) == 0 || value[0] != '"' {
+		return "", false
+	}
+	var s string
+	if err := jsoniter.Unmarshal(value, &s); err != nil {
+		return "", false
+	}
+	return s, true
+}
+
+func writeRawString(s string) []byte {
+	data, _ := jsoniter.Marshal(s)
+	return data
+}
+
+func isEmptyRawValue(value []byte) bool {
+	switch string(value) {
+	case "", "null", `""`:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,210 @@
+package logschema
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+const transformsSchema = `
+version: 0
+fields:
+  - name: user
+    type: string
+    copy: email
+    split:
+      separator: '@'
+  - name: domain
+    type: string
+    copy: email
+    split:
+      separator: '@'
+      index: -1
+  - name: email
+    type: string
+    mask:
+      keepSuffix: 4
+  - name: tags
+    type: array
+    element:
+      type: string
+    split:
+      separator: ','
+  - name: request
+    type: object
+    parseJSON: true
+    fields:
+      - name: path
+        type: string
+        rename: Request Path
+      - name: secret
+        type: string
+        mask:
+          hash: true
+  - name: src
+    type: string
+    concat:
+      fields: [ip, port]
+      separator: ':'
+  - name: enabled
+    type: boolean
+    default: 'false'
+  - name: status
+    type: string
+    rename: Status Code
+    default: unknown
+`
+
+func TestTransforms(t *testing.T) {
+	assert := require.New(t)
+	schema := Schema{}
+	assert.NoError(yaml.Unmarshal([]byte(transformsSchema), &schema))
+	assert.NoError(ValidateSchema(&schema))
+	value, err := Resolve(&schema)
+	assert.NoError(err)
+	typ, err := value.GoType()
+	assert.NoError(err)
+
+	input := `{
+		"email": "alice@example.com",
+		"tags": "a,b,c",
+		"request": "{\"Request Path\":\"/api\",\"secret\":\"foo\"}",
+		"ip": "10.0.0.1",
+		"port": 22,
+		"enabled": "",
+		"Status Code": "200"
+	}`
+	event := reflect.New(typ.Elem()).Interface()
+	assert.NoError(pantherlog.ConfigJSON().UnmarshalFromString(input, event))
+	actual, err := pantherlog.ConfigJSON().MarshalToString(event)
+	assert.NoError(err)
+	expect := `{
+		"user": "alice",
+		"domain": "example.com",
+		"email": "****.com",
+		"tags": ["a", "b", "c"],
+		"request": {
+			"path": "/api",
+			"secret": "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"
+		},
+		"src": "10.0.0.1:22",
+		"enabled": false,
+		"status": "200"
+	}`
+	assert.JSONEq(expect, actual)
+
+	event = reflect.New(typ.Elem()).Interface()
+	assert.NoError(pantherlog.ConfigJSON().UnmarshalFromString(`{"user":"bob","request":"not json"}`, event))
+	actual, err = pantherlog.ConfigJSON().MarshalToString(event)
+	assert.NoError(err)
+	assert.JSONEq(`{"enabled": false, "status": "unknown"}`, actual)
+}
+
+func TestTransformsValidate(t *testing.T) {
+	for _, tc := range []struct {
+		Name       string
+		Transforms Transforms
+		Value      ValueSchema
+	}{
+		{"rename and copy", Transforms{Rename: "a", Copy: "b"}, ValueSchema{Type: TypeString}},
+		{"concat one field", Transforms{Concat: &ConcatTransform{Fields: []string{"a"}}}, ValueSchema{Type: TypeString}},
+		{"split and parseJSON", Transforms{Split: &SplitTransform{Separator: ","}, ParseJSON: true}, ValueSchema{Type: TypeString}},
+		{"split int array", Transforms{Split: &SplitTransform{Separator: ","}}, ValueSchema{Type: TypeArray, Element: &ValueSchema{Type: TypeInt}}},
+		{"mask int", Transforms{Mask: &MaskTransform{}}, ValueSchema{Type: TypeInt}},
+		{"default object", Transforms{Default: "{}"}, ValueSchema{Type: TypeObject}},
+	} {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			require.Error(t, tc.Transforms.Validate(&tc.Value))
+			_, err := objectFields([]FieldSchema{{Name: "foo", ValueSchema: func() ValueSchema {
+				v := tc.Value
+				v.Transforms = tc.Transforms
+				return v
+			}()}})
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestTransformsNonField(t *testing.T) {
+	for name, src := range map[string]string{
+		"array element": `
+version: 0
+fields:
+  - name: tags
+    type: array
+    element:
+      type: string
+      default: none
+`,
+		"definition": `
+version: 0
+definitions:
+  Email:
+    type: string
+    mask:
+      hash: true
+fields:
+  - name: email
+    type: ref
+    target: Email
+`,
+	} {
+		src := src
+		t.Run(name, func(t *testing.T) {
+			schema := Schema{}
+			require.NoError(t, yaml.Unmarshal([]byte(src), &schema))
+			_, err := Resolve(&schema)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestTransformsDiff(t *testing.T) {
+	assert := require.New(t)
+	from := Schema{}
+	assert.NoError(yaml.Unmarshal([]byte(transformsSchema), &from))
+	to := *from.Clone()
+	assert.Equal(from.Fields, to.Fields)
+	to.Fields[0].Split.Index = 1
+	changes, err := Diff(&from, &to)
+	assert.NoError(err)
+	assert.Len(changes, 1)
+	assert.Equal(UpdateTransforms, changes[0].Type)
+	assert.Equal([]string{"Fields", "user", "Transforms"}, changes[0].Path)
+}
+
+func TestTransformsYAMLOmitEmpty(t *testing.T) {
+	assert := require.New(t)
+	value := ValueSchema{
+		Type: TypeArray,
+		Element: &ValueSchema{
+			Type: TypeString,
+		},
+	}
+	data, err := yaml.Marshal(&value)
+	assert.NoError(err)
+	assert.YAMLEq("type: array\nelement:\n  type: string\n", string(data))
+}
@@ -0,0 +1,179 @@
+package logschema
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"reflect"
+
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/pkg/stringset"
+)
+
+// Union describes log events of different shapes that are distinguished by the value of a tag field.
+//
+// Each variant extends the common fields of the schema with its own fields.
+// The tag field is a required string field in all variants unless it is already defined as a field.
+// nolint:lll
+type Union struct {
+	Tag      string    `json:"tag" yaml:"tag" description:"The key of the log entry with the name of the variant"`
+	Variants []Variant `json:"variants" yaml:"variants" description:"The variants of the log events"`
+}
+
+// Variant is the shape of the log events whose tag is one of `values`.
+// nolint:lll
+type Variant struct {
+	Values      []string      `json:"values" yaml:"values" description:"The values of the tag field for this variant"`
+	Description string        `json:"description,omitempty" yaml:"description,omitempty" description:"Variant description"`
+	Fields      []FieldSchema `json:"fields,omitempty" yaml:"fields,omitempty" description:"Variant fields in addition to the common fields"`
+}
+
+// equalTags checks if two unions have the same tag and variant tag values
+func (u *Union) equalTags(other *Union) bool {
+	if u == nil || other == nil {
+		return u == other
+	}
+	if u.Tag != other.Tag || len(u.Variants) != len(other.Variants) {
+		return false
+	}
+	for i := range u.Variants {
+		if !reflect.DeepEqual(u.Variants[i].Values, other.Variants[i].Values) {
+			return false
+		}
+	}
+	return true
+}
+
+// ResolveVariants resolves the value of each variant of a union schema.
+// Each value has the common fields of the schema, the tag field and the fields of the variant.
+// It returns nil if the schema is not a union.
+func ResolveVariants(schema *Schema) ([]*ValueSchema, error) {
+	u := schema.Union
+	if u == nil {
+		return nil, nil
+	}
+	if u.Tag == "" {
+		return nil, errors.New("empty union tag")
+	}
+	if len(u.Variants) == 0 {
+		return nil, errors.New("union has no variants")
+	}
+	tags := make(map[string]struct{})
+	values := make([]*ValueSchema, 0, len(u.Variants))
+	for i := range u.Variants {
+		v := &u.Variants[i]
+		if len(v.Values) == 0 {
+			return nil, errors.Errorf("union variant %d has no tag values", i)
+		}
+		for _, tag := range v.Values {
+			if _, duplicate := tags[tag]; duplicate {
+				return nil, errors.Errorf("duplicate union tag value %q", tag)
+			}
+			tags[tag] = struct{}{}
+		}
+		fields, err := variantFields(u.Tag, schema.Fields, v.Fields)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "invalid union variant %q", v.Values[0])
+		}
+		value, err := resolveFields(fields, schema.Definitions)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to resolve union variant %q", v.Values[0])
+		}
+		if tag := findField(u.Tag, value.Fields); tag.Type != TypeString {
+			return nil, errors.Errorf("union tag field %q must be a string", u.Tag)
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// variantFields appends the fields of a variant to the common fields, adding the tag field if needed.
+func variantFields(tag string, common, variant []FieldSchema) ([]FieldSchema, error) {
+	fields := make([]FieldSchema, 0, len(common)+len(variant)+1)
+	fields = append(fields, common...)
+	for _, f := range variant {
+		if findField(f.Name, common) != nil {
+			return nil, errors.Errorf("field %q is already a common field", f.Name)
+		}
+		fields = append(fields, f)
+	}
+	if findField(tag, fields) == nil {
+		fields = append([]FieldSchema{{
+			Name:        tag,
+			Required:    true,
+			Description: "The name of the event variant",
+			ValueSchema: ValueSchema{Type: TypeString},
+		}}, fields...)
+	}
+	return fields, nil
+}
+
+// mergeVariants merges the resolved values of all variants to a value that can hold any of the variants.
+// Since events are validated against their own variant before being copied to the merged value,
+// the merged value keeps the indicators and descriptions of all variants but has no transforms or constraints.
+func mergeVariants(variants []*ValueSchema) *ValueSchema {
+	var merged *ValueSchema
+	for _, v := range variants {
+		merged = Merge(merged, v)
+	}
+	for _, v := range variants {
+		mergeValueMeta(merged, v)
+	}
+	stripTransforms(merged)
+	return merged
+}
+
+func mergeValueMeta(dst, src *ValueSchema) {
+	switch {
+	case dst == nil || src == nil || dst.Type != src.Type:
+		return
+	case dst.Type == TypeObject:
+		for i := range dst.Fields {
+			d := &dst.Fields[i]
+			s := findField(d.Name, src.Fields)
+			if s == nil {
+				continue
+			}
+			if d.Description == "" {
+				d.Description = s.Description
+			}
+			mergeValueMeta(&d.ValueSchema, &s.ValueSchema)
+		}
+	case dst.Type == TypeArray:
+		mergeValueMeta(dst.Element, src.Element)
+	case dst.Type == TypeString:
+		dst.Indicators = stringset.Append(dst.Indicators, src.Indicators...)
+	}
+}
+
+func stripTransforms(v *ValueSchema) {
+	if v == nil {
+		return
+	}
+	v.Transforms = Transforms{}
+	v.Constraints = Constraints{}
+	switch v.Type {
+	case TypeObject:
+		for i := range v.Fields {
+			stripTransforms(&v.Fields[i].ValueSchema)
+		}
+	case TypeArray:
+		stripTransforms(v.Element)
+	}
+}
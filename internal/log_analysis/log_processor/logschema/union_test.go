@@ -0,0 +1,164 @@
+package logschema
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestResolveUnion(t *testing.T) {
+	assert := require.New(t)
+	schema := Schema{}
+	assert.NoError(yaml.Unmarshal([]byte(`
+version: 0
+fields:
+  - name: ts
+    type: timestamp
+    timeFormat: rfc3339
+    required: true
+union:
+  tag: kind
+  variants:
+    - values: [a]
+      fields:
+        - name: user
+          type: string
+          required: true
+          description: The user name
+        - name: src
+          type: string
+          indicators: [ip]
+          rename: source
+    - values: [b, c]
+      fields:
+        - name: user
+          type: string
+          required: true
+        - name: src
+          type: string
+          indicators: [domain]
+        - name: size
+          type: int
+`), &schema))
+	assert.NoError(ValidateSchema(&schema))
+
+	variants, err := ResolveVariants(&schema)
+	assert.NoError(err)
+	assert.Len(variants, 2)
+	assert.Equal(&ValueSchema{Type: TypeString}, &findField("kind", variants[0].Fields).ValueSchema)
+	assert.True(findField("kind", variants[0].Fields).Required)
+	assert.Equal("source", findField("src", variants[0].Fields).Rename)
+	assert.Nil(findField("size", variants[0].Fields))
+	assert.NotNil(findField("size", variants[1].Fields))
+
+	merged, err := Resolve(&schema)
+	assert.NoError(err)
+	assert.Len(merged.Fields, 5)
+	for _, name := range []string{"kind", "ts", "user"} {
+		assert.True(findField(name, merged.Fields).Required, name)
+	}
+	user := findField("user", merged.Fields)
+	assert.Equal("The user name", user.Description)
+	src := findField("src", merged.Fields)
+	assert.False(src.Required)
+	assert.Equal([]string{"ip", "domain"}, src.Indicators)
+	assert.True(src.Transforms.IsEmpty())
+	assert.False(findField("size", merged.Fields).Required)
+
+	// Only union schemas can omit the common fields
+	schema.Fields = nil
+	assert.NoError(ValidateSchema(&schema))
+	schema.Union = nil
+	assert.Error(ValidateSchema(&schema))
+}
+
+func TestResolveUnionErrors(t *testing.T) {
+	for _, spec := range []string{
+		// duplicate tag value
+		`
+union:
+  tag: kind
+  variants:
+    - values: [a]
+    - values: [a]
+`,
+		// variant field conflicts with common field
+		`
+fields:
+  - name: ts
+    type: timestamp
+    timeFormat: rfc3339
+union:
+  tag: kind
+  variants:
+    - values: [a]
+      fields:
+        - name: ts
+          type: string
+`,
+		// tag is not a string
+		`
+fields:
+  - name: kind
+    type: int
+union:
+  tag: kind
+  variants:
+    - values: [a]
+`,
+	} {
+		schema := Schema{}
+		require.NoError(t, yaml.Unmarshal([]byte(spec), &schema))
+		_, err := Resolve(&schema)
+		require.Error(t, err, spec)
+	}
+}
+
+func TestDiffUnion(t *testing.T) {
+	assert := require.New(t)
+	from := Schema{
+		Union: &Union{
+			Tag: "kind",
+			Variants: []Variant{
+				{Values: []string{"a"}},
+			},
+		},
+	}
+	to := from.Clone()
+	to.Union.Variants[0].Description = "Variant A"
+	changes, err := Diff(&from, to)
+	assert.NoError(err)
+	assert.Empty(changes)
+
+	to.Union.Variants[0].Values = []string{"a", "b"}
+	to.Union.Variants = append(to.Union.Variants, Variant{
+		Values: []string{"c"},
+		Fields: []FieldSchema{
+			{Name: "size", ValueSchema: ValueSchema{Type: TypeInt}},
+		},
+	})
+	changes, err = Diff(&from, to)
+	assert.NoError(err)
+	assert.Len(changes, 2)
+	assert.Equal(UpdateUnion, changes[0].Type)
+	assert.Equal(AddField, changes[1].Type)
+}
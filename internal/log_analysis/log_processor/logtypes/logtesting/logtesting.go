@@ -192,6 +192,8 @@ func unmarshalResultJSON(data []byte, r *pantherlog.Result, indicators pantherlo
 		RowID       string      `json:"p_row_id"`
 		SourceID    string      `json:"p_source_id"`
 		SourceLabel string      `json:"p_source_label"`
+		LogGroup    string      `json:"p_log_group"`
+		LogStream   string      `json:"p_log_stream"`
 	}{}
 	if err := jsoniter.Unmarshal(data, &tmp); err != nil {
 		return err
@@ -217,6 +219,8 @@ func unmarshalResultJSON(data []byte, r *pantherlog.Result, indicators pantherlo
 			PantherParseTime:   tmp.ParseTime,
 			PantherSourceID:    tmp.SourceID,
 			PantherSourceLabel: tmp.SourceLabel,
+			PantherLogGroup:    tmp.LogGroup,
+			PantherLogStream:   tmp.LogStream,
 		},
 	}
 	values.WriteValuesTo(r)
@@ -61,6 +61,9 @@ func process(ctx context.Context, scalingDecisionInterval time.Duration) (err er
 	// runs in the background, periodically polling the queue to make scaling decisions
 	go processor.RunScalingDecisions(scalingCtx, common.SqsClient, common.LambdaClient, scalingDecisionInterval)
 
+	// Pick up changes to the threat intel lookup tables
+	common.RefreshThreatIntel(ctx)
+
 	var sqsMessageCount int
 	defer func() {
 		cancelScaling()
@@ -25,11 +25,16 @@ import (
 )
 
 const (
-	SubsystemLogProcessor             = "LogProcessor"
-	MetricLogProcessorGetObject       = "GetObject"
-	MetricLogProcessorBytesProcessed  = "BytesProcessed"
-	MetricLogProcessorEventsProcessed = "EventsProcessed"
-	MetricLogProcessorEventLatency    = "EventLatency"
+	SubsystemLogProcessor               = "LogProcessor"
+	MetricLogProcessorGetObject         = "GetObject"
+	MetricLogProcessorBytesProcessed    = "BytesProcessed"
+	MetricLogProcessorEventsProcessed   = "EventsProcessed"
+	MetricLogProcessorEventLatency      = "EventLatency"
+	MetricLogProcessorEventsQuarantined = "EventsQuarantined"
+	MetricLogProcessorEventsFiltered    = "EventsFiltered"
+	MetricLogProcessorEventsSampled     = "EventsSampled"
+	MetricLogProcessorEventsDropped     = "EventsDropped"
+	MetricLogProcessorEventsDiverted    = "EventsDiverted"
 
 	// StatusDimension indicating that a subsystem operation is well
 	StatusOK = "OK"
@@ -45,6 +50,11 @@ var (
 	BytesProcessed      metrics.Counter
 	EventsProcessed     metrics.Counter
 	EventLatencySeconds metrics.Counter
+	EventsQuarantined   metrics.Counter
+	EventsFiltered      metrics.Counter
+	EventsSampled       metrics.Counter
+	EventsDropped       metrics.Counter
+	EventsDiverted      metrics.Counter
 )
 
 func Setup() {
@@ -57,4 +67,14 @@ func Setup() {
 	BytesProcessed = CWManager.NewCounter(MetricLogProcessorBytesProcessed, metrics.UnitBytes)
 	EventsProcessed = CWManager.NewCounter(MetricLogProcessorEventsProcessed, metrics.UnitCount)
 	EventLatencySeconds = CWManager.NewCounter(MetricLogProcessorEventLatency, metrics.UnitSeconds)
+	EventsQuarantined = CWManager.NewCounter(MetricLogProcessorEventsQuarantined, metrics.UnitCount).
+		With(metrics.SubsystemDimension, SubsystemLogProcessor)
+	EventsFiltered = CWManager.NewCounter(MetricLogProcessorEventsFiltered, metrics.UnitCount).
+		With(metrics.SubsystemDimension, SubsystemLogProcessor)
+	EventsSampled = CWManager.NewCounter(MetricLogProcessorEventsSampled, metrics.UnitCount).
+		With(metrics.SubsystemDimension, SubsystemLogProcessor)
+	EventsDropped = CWManager.NewCounter(MetricLogProcessorEventsDropped, metrics.UnitCount).
+		With(metrics.SubsystemDimension, SubsystemLogProcessor)
+	EventsDiverted = CWManager.NewCounter(MetricLogProcessorEventsDiverted, metrics.UnitCount).
+		With(metrics.SubsystemDimension, SubsystemLogProcessor)
 }
@@ -0,0 +1,119 @@
+package pantherlog
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/go-playground/validator.v9"
+)
+
+const (
+	// ValidateEnum is the validation tag for values that must be one of a set of values.
+	// Use EnumParam to encode the tag parameter.
+	ValidateEnum = "enum"
+	// ValidatePattern is the validation tag for string values that must match a regular expression.
+	// Use PatternParam to encode the tag parameter.
+	ValidatePattern = "pattern"
+)
+
+// EnumParam encodes the values of an `enum` validation tag.
+// Values are escaped so that they can contain spaces, commas or pipes that are special in validate tags.
+func EnumParam(values ...string) string {
+	params := make([]string, len(values))
+	for i, v := range values {
+		params[i] = url.QueryEscape(v)
+	}
+	return strings.Join(params, " ")
+}
+
+// PatternParam encodes the regular expression of a `pattern` validation tag.
+func PatternParam(expr string) string {
+	return url.QueryEscape(expr)
+}
+
+// registerConstraintValidators registers the validations for value constraints
+func registerConstraintValidators(v *validator.Validate) {
+	// Errors can only occur if the tags are already registered
+	_ = v.RegisterValidation(ValidateEnum, validateEnum)
+	_ = v.RegisterValidation(ValidatePattern, validatePattern)
+}
+
+var constraintParams sync.Map
+
+func validateEnum(fl validator.FieldLevel) bool {
+	param := fl.Param()
+	values, ok := constraintParams.Load(ValidateEnum + param)
+	if !ok {
+		var enum []string
+		for _, p := range strings.Fields(param) {
+			value, _ := url.QueryUnescape(p)
+			enum = append(enum, value)
+		}
+		values, _ = constraintParams.LoadOrStore(ValidateEnum+param, enum)
+	}
+	field := fl.Field()
+	for _, value := range values.([]string) {
+		if enumValueEqual(field, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func enumValueEqual(field reflect.Value, value string) bool {
+	switch field.Kind() {
+	case reflect.String:
+		return field.String() == value
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		return err == nil && field.Int() == n
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, 64)
+		return err == nil && field.Uint() == n
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, 64)
+		return err == nil && field.Float() == f
+	default:
+		return false
+	}
+}
+
+func validatePattern(fl validator.FieldLevel) bool {
+	param := fl.Param()
+	re, ok := constraintParams.Load(ValidatePattern + param)
+	if !ok {
+		expr, err := url.QueryUnescape(param)
+		if err != nil {
+			return false
+		}
+		compiled, err := regexp.Compile(expr)
+		if err != nil {
+			return false
+		}
+		re, _ = constraintParams.LoadOrStore(ValidatePattern+param, compiled)
+	}
+	field := fl.Field()
+	return field.Kind() == reflect.String && re.(*regexp.Regexp).MatchString(field.String())
+}
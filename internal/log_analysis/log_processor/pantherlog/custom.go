@@ -0,0 +1,178 @@
+package pantherlog
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// FieldPrefixAnyJSON is the prefix for the JSON names of custom indicator fields
+const FieldPrefixAnyJSON = FieldPrefixJSON + "any_"
+
+// Custom indicator field ids start high enough so they do not collide with ids registered by modules at init()
+const customFieldIDStart FieldID = 1 << 16
+
+var reCustomIndicatorName = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// customIndicators holds indicator fields and scanners registered at runtime (ie by custom log schemas).
+// Unlike the registrations done at init(), these can happen concurrently with the processing of events.
+// Registrations are scoped to a log type and replaced when the log type registers them again,
+// so rebuilding a schema does not grow the registry.
+var customIndicators = struct {
+	sync.RWMutex
+	ids      map[customIndicatorKey]FieldID
+	fields   map[FieldID]reflect.StructField
+	names    map[FieldID]string
+	scanners map[string]*scannerEntry
+	next     FieldID
+}{
+	ids:      map[customIndicatorKey]FieldID{},
+	fields:   map[FieldID]reflect.StructField{},
+	names:    map[FieldID]string{},
+	scanners: map[string]*scannerEntry{},
+	next:     customFieldIDStart,
+}
+
+type customIndicatorKey struct {
+	logType string
+	name    string
+}
+
+// RegisterCustomIndicator registers a `p_any_<name>` indicator field of a log type at runtime.
+// It is safe to use concurrently and returns the same field id each time a log type registers a name.
+// Registering a name again updates the description of the field.
+func RegisterCustomIndicator(logType, name, description string) (FieldID, error) {
+	if !reCustomIndicatorName.MatchString(name) {
+		return FieldNone, errors.Errorf("invalid custom indicator name %q", name)
+	}
+	meta := FieldMeta{
+		Name:        FieldPrefix + "Any" + customFieldNameGo(name),
+		NameJSON:    FieldPrefixAnyJSON + name,
+		Description: customIndicatorDescription(name, description),
+	}
+	// Custom indicators cannot override fields registered at init()
+	if _, duplicate := fieldsByName[meta.Name]; duplicate {
+		return FieldNone, errors.Errorf("duplicate field name %q", meta.Name)
+	}
+	if _, duplicate := fieldsByName[meta.NameJSON]; duplicate {
+		return FieldNone, errors.Errorf("duplicate JSON field name %q", meta.NameJSON)
+	}
+	customIndicators.Lock()
+	defer customIndicators.Unlock()
+	key := customIndicatorKey{logType: logType, name: name}
+	id, ok := customIndicators.ids[key]
+	if !ok {
+		id = customIndicators.next
+		customIndicators.next++
+		customIndicators.ids[key] = id
+	}
+	customIndicators.fields[id] = meta.StructField()
+	customIndicators.names[id] = meta.NameJSON
+	return id, nil
+}
+
+// RegisterCustomScanner registers a value scanner for custom indicator fields at runtime.
+// It is safe to use concurrently.
+// Registering a name again replaces its scanner, so names should be scoped to the log type (see CustomScannerName).
+func RegisterCustomScanner(name string, scanner ValueScanner, fields ...FieldID) error {
+	if name == "" {
+		return errors.New("anonymous scanner")
+	}
+	if scanner == nil {
+		return errors.New("nil scanner")
+	}
+	if _, duplicate := registeredScanners[name]; duplicate {
+		return errors.Errorf("duplicate scanner %q", name)
+	}
+	if len(fields) == 0 {
+		return errors.New("no value fields")
+	}
+	customIndicators.Lock()
+	defer customIndicators.Unlock()
+	for _, id := range fields {
+		if _, ok := customIndicators.fields[id]; !ok {
+			return errors.Errorf("unregistered custom field id %d", id)
+		}
+	}
+	customIndicators.scanners[name] = &scannerEntry{
+		Scanner: scanner,
+		Fields:  append([]FieldID(nil), fields...),
+	}
+	return nil
+}
+
+// CustomScannerName is the name of the scanner of a custom indicator of a log type
+func CustomScannerName(logType, name string) string {
+	return logType + ":" + name
+}
+
+func lookupCustomScanner(name string) (*scannerEntry, bool) {
+	customIndicators.RLock()
+	defer customIndicators.RUnlock()
+	entry, ok := customIndicators.scanners[name]
+	return entry, ok
+}
+
+// lookupField finds the struct field of a field id
+func lookupField(id FieldID) (reflect.StructField, bool) {
+	if field, ok := registeredFields[id]; ok {
+		return field, true
+	}
+	customIndicators.RLock()
+	defer customIndicators.RUnlock()
+	field, ok := customIndicators.fields[id]
+	return field, ok
+}
+
+// lookupFieldNameJSON finds the JSON field name of a field id
+func lookupFieldNameJSON(id FieldID) (string, bool) {
+	if name, ok := registeredFieldNamesJSON[id]; ok {
+		return name, true
+	}
+	if id < customFieldIDStart {
+		return "", false
+	}
+	customIndicators.RLock()
+	defer customIndicators.RUnlock()
+	name, ok := customIndicators.names[id]
+	return name, ok
+}
+
+func customFieldNameGo(name string) string {
+	parts := strings.Split(name, "_")
+	for i, part := range parts {
+		parts[i] = strings.Title(part)
+	}
+	return strings.Join(parts, "")
+}
+
+func customIndicatorDescription(name, description string) string {
+	if description == "" {
+		description = strings.ReplaceAll(name, "_", " ")
+	}
+	// Descriptions end up in struct tags so we need to avoid quotes
+	description = strings.ReplaceAll(description, `"`, `'`)
+	return fmt.Sprintf("Panther added field with collection of %s values associated with the row", description)
+}
@@ -0,0 +1,99 @@
+package pantherlog
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomIndicators(t *testing.T) {
+	assert := require.New(t)
+	id, err := RegisterCustomIndicator("Custom.Foo", "device_id", "")
+	assert.NoError(err)
+	assert.True(id >= customFieldIDStart)
+	assert.Equal("p_any_device_id", FieldNameJSON(id))
+	again, err := RegisterCustomIndicator("Custom.Foo", "device_id", "Device serial numbers")
+	assert.NoError(err)
+	assert.Equal(id, again)
+	field, _ := lookupField(id)
+	assert.Contains(string(field.Tag), "Device serial numbers", "description is updated")
+	other, err := RegisterCustomIndicator("Custom.Bar", "device_id", "")
+	assert.NoError(err)
+	assert.NotEqual(id, other, "registrations are scoped to the log type")
+	// Restore the default description
+	_, err = RegisterCustomIndicator("Custom.Foo", "device_id", "")
+	assert.NoError(err)
+
+	_, err = RegisterCustomIndicator("Custom.Foo", "ip_addresses", "")
+	assert.Error(err, "duplicate of a builtin field")
+	_, err = RegisterCustomIndicator("Custom.Foo", "foo", "")
+	assert.Error(err, "duplicate of a field registered at init()")
+	_, err = RegisterCustomIndicator("Custom.Foo", "Device-ID", "")
+	assert.Error(err)
+
+	scanner := ValueScannerFunc(func(w ValueWriter, input string) {
+		w.WriteValues(id, strings.ToUpper(input))
+	})
+	assert.NoError(RegisterCustomScanner("device_id:test", scanner, id))
+	assert.NoError(RegisterCustomScanner("device_id:test", ValueScannerFunc(func(ValueWriter, string) {}), other))
+	_, fields := LookupScanner("device_id:test")
+	assert.Equal([]FieldID{other}, fields, "registration replaces the scanner")
+	assert.NoError(RegisterCustomScanner("device_id:test", scanner, id))
+	assert.Error(RegisterCustomScanner("ip", scanner, id), "duplicate of a builtin scanner")
+	assert.Error(RegisterCustomScanner("device_id:other", scanner, FieldIPAddress), "not a custom field")
+	_, fields = LookupScanner("device_id:test")
+	assert.Equal([]FieldID{id}, fields)
+
+	type T struct {
+		Device string `json:"device" panther:"device_id:test"`
+	}
+	typ, err := BuildEventTypeSchema(reflect.TypeOf(T{}))
+	assert.NoError(err)
+	field, ok := typ.FieldByName("PantherAnyDeviceId")
+	assert.True(ok)
+	assert.Equal(`json:"p_any_device_id,omitempty" description:"Panther added field with collection of device id values associated with the row"`, string(field.Tag))
+
+	now := time.Now().UTC()
+	result := Result{
+		CoreFields: CoreFields{
+			PantherLogType:   "Foo.Bar",
+			PantherRowID:     "id",
+			PantherParseTime: now,
+		},
+		Event: &T{Device: "abc"},
+	}
+	actual, err := jsoniter.MarshalToString(&result)
+	assert.NoError(err)
+	expect := fmt.Sprintf(`{
+		"device": "abc",
+		"p_row_id": "id",
+		"p_event_time": "%s",
+		"p_parse_time": "%s",
+		"p_any_device_id": ["ABC"],
+		"p_log_type": "Foo.Bar"
+	}`, now.Format(time.RFC3339Nano), now.Format(time.RFC3339Nano))
+	assert.JSONEq(expect, actual)
+}
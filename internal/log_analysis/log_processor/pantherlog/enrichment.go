@@ -0,0 +1,166 @@
+package pantherlog
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+const (
+	// FieldEnrichmentGeoJSON is the field holding the geolocation of the IP addresses of an event
+	FieldEnrichmentGeoJSON = FieldPrefixJSON + "enrichment_geo"
+	fieldEnrichmentGeo     = FieldPrefix + "EnrichmentGeo"
+
+	// FieldThreatIntelMatchesJSON is the field holding the indicator values of an event found in threat intel lookup tables
+	FieldThreatIntelMatchesJSON = FieldPrefixJSON + "threat_intel_matches"
+	fieldThreatIntelMatches     = FieldPrefix + "ThreatIntelMatches"
+)
+
+// GeoInfo is the geolocation of an IP address
+type GeoInfo struct {
+	Country        string  `json:"country,omitempty" description:"The ISO 3166-1 code of the country"`
+	CountryName    string  `json:"country_name,omitempty" description:"The English name of the country"`
+	Region         string  `json:"region,omitempty" description:"The English name of the region (first level subdivision)"`
+	City           string  `json:"city,omitempty" description:"The English name of the city"`
+	Latitude       float64 `json:"latitude,omitempty" description:"The approximate latitude"`
+	Longitude      float64 `json:"longitude,omitempty" description:"The approximate longitude"`
+	ASN            uint32  `json:"asn,omitempty" description:"The autonomous system number"`
+	ASOrganization string  `json:"as_organization,omitempty" description:"The organization of the autonomous system"`
+}
+
+// GeoResolver resolves the geolocation of IP addresses
+type GeoResolver interface {
+	// ResolveGeo returns the geolocation of an IP address or nil if it is not known
+	ResolveGeo(ip string) *GeoInfo
+}
+
+// GeoEnricher is implemented by events that add the geolocation of their IP addresses themselves.
+type GeoEnricher interface {
+	EnrichGeo(r GeoResolver)
+}
+
+// ThreatIntelMatcher matches indicator values against lookup tables of known-bad indicators
+type ThreatIntelMatcher interface {
+	// MatchThreatIntel returns the names of the lookup tables that contain an indicator value
+	MatchThreatIntel(id FieldID, value string) []string
+}
+
+// ThreatIntelEnricher is implemented by events that match their indicator values themselves.
+type ThreatIntelEnricher interface {
+	EnrichThreatIntel(m ThreatIntelMatcher)
+}
+
+// geoEnrichmentField is the struct field added to the schema of events with IP address indicators
+var geoEnrichmentField = reflect.StructField{
+	Name: fieldEnrichmentGeo,
+	Type: reflect.TypeOf(map[string]GeoInfo(nil)),
+	Tag:  `json:"` + FieldEnrichmentGeoJSON + `,omitempty" description:"Panther added field with the geolocation of the ip addresses associated with the row"`,
+}
+
+// threatIntelMatchesField is the struct field added to the schema of events with indicators.
+// It maps the name of each lookup table to the indicator values found in it.
+var threatIntelMatchesField = reflect.StructField{
+	Name: fieldThreatIntelMatches,
+	Type: reflect.TypeOf(map[string][]string(nil)),
+	Tag:  `json:"` + FieldThreatIntelMatchesJSON + `,omitempty" description:"Panther added field with the indicator values associated with the row found in each threat intel lookup table"`,
+}
+
+// enrichmentIndicators returns the indicator fields that are filled by enrichment
+func enrichmentIndicators(indicators FieldSet) FieldSet {
+	for _, id := range indicators {
+		if id == FieldIPAddress {
+			return FieldSet{FieldCountry, FieldASN}
+		}
+	}
+	return nil
+}
+
+// enrichGeo resolves the geolocation of the IP addresses collected from the event.
+// It writes the countries and the autonomous system numbers to the indicator values of the result.
+func (r *Result) enrichGeo() map[string]GeoInfo {
+	if r.GeoResolver == nil {
+		return nil
+	}
+	var geo map[string]GeoInfo
+	for _, ip := range r.values.Get(FieldIPAddress) {
+		info := r.GeoResolver.ResolveGeo(ip)
+		if info == nil {
+			continue
+		}
+		if geo == nil {
+			geo = make(map[string]GeoInfo)
+		}
+		geo[ip] = *info
+		r.values.WriteValues(FieldCountry, info.Country)
+		if info.ASN != 0 {
+			r.values.WriteValues(FieldASN, strconv.FormatUint(uint64(info.ASN), 10))
+		}
+	}
+	return geo
+}
+
+// matchThreatIntel looks up the indicator values collected from the event in the threat intel lookup tables.
+func (r *Result) matchThreatIntel() map[string][]string {
+	if r.ThreatIntel == nil {
+		return nil
+	}
+	var matches map[string][]string
+	for _, id := range r.values.Fields() {
+		if id.IsCore() {
+			continue
+		}
+		for _, value := range r.values.Get(id) {
+			matches = appendThreatIntelMatches(matches, r.ThreatIntel.MatchThreatIntel(id, value), value)
+		}
+	}
+	// Values of different fields can end up in the same table
+	for _, values := range matches {
+		sort.Strings(values)
+	}
+	return matches
+}
+
+// AppendThreatIntelMatches adds an indicator value to the matches of each table
+func AppendThreatIntelMatches(matches map[string][]string, id FieldID, value string, m ThreatIntelMatcher) map[string][]string {
+	return appendThreatIntelMatches(matches, m.MatchThreatIntel(id, value), value)
+}
+
+func appendThreatIntelMatches(matches map[string][]string, tables []string, value string) map[string][]string {
+	for _, table := range tables {
+		if matches == nil {
+			matches = make(map[string][]string)
+		}
+		values := matches[table]
+		if !containsString(values, value) {
+			matches[table] = append(values, value)
+		}
+	}
+	return matches
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -78,6 +78,12 @@ func (e *resultEncoder) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
 	// Hack around events with embedded parsers.PantherLog.
 	// TODO: Remove this once all parsers are ported to not use parsers.PantherLog
 	if result.EventIncludesPantherFields {
+		if e, ok := result.Event.(GeoEnricher); ok && result.GeoResolver != nil {
+			e.EnrichGeo(result.GeoResolver)
+		}
+		if e, ok := result.Event.(ThreatIntelEnricher); ok && result.ThreatIntel != nil {
+			e.EnrichThreatIntel(result.ThreatIntel)
+		}
 		stream.WriteVal(result.Event)
 		return
 	}
@@ -141,11 +147,27 @@ func (*resultEncoder) writePantherFields(r *Result, stream *jsoniter.Stream) {
 		stream.WriteVal(r.PantherSourceLabel)
 	}
 
+	if r.PantherLogGroup != "" {
+		stream.WriteMore()
+		stream.WriteObjectField(FieldLogGroupJSON)
+		stream.WriteVal(r.PantherLogGroup)
+	}
+
+	if r.PantherLogStream != "" {
+		stream.WriteMore()
+		stream.WriteObjectField(FieldLogStreamJSON)
+		stream.WriteVal(r.PantherLogStream)
+	}
+
+	// Enrichment adds values to the indicator fields so it needs to happen before writing them
+	geo := r.enrichGeo()
+	matches := r.matchThreatIntel()
+
 	for id, values := range r.values.index {
 		if len(values) == 0 || id.IsCore() {
 			continue
 		}
-		fieldName, ok := registeredFieldNamesJSON[id]
+		fieldName, ok := lookupFieldNameJSON(id)
 		if !ok {
 			continue
 		}
@@ -162,6 +184,17 @@ func (*resultEncoder) writePantherFields(r *Result, stream *jsoniter.Stream) {
 		stream.WriteArrayEnd()
 	}
 
+	if len(geo) > 0 {
+		stream.WriteMore()
+		stream.WriteObjectField(FieldEnrichmentGeoJSON)
+		stream.WriteVal(geo)
+	}
+	if len(matches) > 0 {
+		stream.WriteMore()
+		stream.WriteObjectField(FieldThreatIntelMatchesJSON)
+		stream.WriteVal(matches)
+	}
+
 	stream.WriteObjectEnd()
 }
 
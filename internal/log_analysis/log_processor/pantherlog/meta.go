@@ -44,6 +44,8 @@ const (
 	CoreFieldRowID
 	CoreFieldSourceID
 	CoreFieldSourceLabel
+	CoreFieldLogGroup
+	CoreFieldLogStream
 )
 
 func coreField(id FieldID) reflect.StructField {
@@ -72,6 +74,11 @@ const (
 	FieldAWSTag
 	FieldEmail
 	FieldUsername
+	FieldKubernetesObject
+	FieldAzureTenantID
+	// Enrichment fields are not collected by scanners, they are looked up using the values of other fields
+	FieldCountry
+	FieldASN
 )
 
 // ScanValues implements ValueScanner interface
@@ -88,6 +95,8 @@ type CoreFields struct {
 	PantherRowID       string    `json:"p_row_id" validate:"required" description:"Panther added field with unique id (within table)"`
 	PantherSourceID    string    `json:"p_source_id,omitempty" description:"Panther added field with the source id"`
 	PantherSourceLabel string    `json:"p_source_label,omitempty" description:"Panther added field with the source label"`
+	PantherLogGroup    string    `json:"p_log_group,omitempty" description:"Panther added field with the CloudWatch Logs log group of events delivered by a subscription"`
+	PantherLogStream   string    `json:"p_log_stream,omitempty" description:"Panther added field with the CloudWatch Logs log stream of events delivered by a subscription"`
 }
 
 const (
@@ -100,6 +109,8 @@ const (
 	FieldParseTimeJSON   = FieldPrefixJSON + "parse_time"
 	FieldSourceIDJSON    = FieldPrefixJSON + "source_id"
 	FieldSourceLabelJSON = FieldPrefixJSON + "source_label"
+	FieldLogGroupJSON    = FieldPrefixJSON + "log_group"
+	FieldLogStreamJSON   = FieldPrefixJSON + "log_stream"
 )
 
 var (
@@ -114,6 +125,8 @@ var (
 		CoreFieldLogType:     coreField(CoreFieldLogType),
 		CoreFieldSourceID:    coreField(CoreFieldSourceID),
 		CoreFieldSourceLabel: coreField(CoreFieldSourceLabel),
+		CoreFieldLogGroup:    coreField(CoreFieldLogGroup),
+		CoreFieldLogStream:   coreField(CoreFieldLogStream),
 	}
 	// registeredFieldNamesJSON stores the JSON field names of registered field ids.
 	registeredFieldNamesJSON = map[FieldID]string{}
@@ -130,12 +143,18 @@ var (
 		"PantherLogType":   FieldNone,
 		FieldRowIDJSON:     FieldNone,
 		"PantherRowID":     FieldNone,
+		// Reserve field names for enrichment fields
+		FieldEnrichmentGeoJSON:      FieldNone,
+		fieldEnrichmentGeo:          FieldNone,
+		FieldThreatIntelMatchesJSON: FieldNone,
+		fieldThreatIntelMatches:     FieldNone,
 	}
 )
 
 // FieldNameJSON returns the JSON field name of a field id.
 func FieldNameJSON(kind FieldID) string {
-	return registeredFieldNamesJSON[kind]
+	name, _ := lookupFieldNameJSON(kind)
+	return name
 }
 
 // RegisteredFieldNamesJSON returns the JSON field names for registered indicator fields
@@ -210,6 +229,26 @@ func init() {
 		NameJSON:    "p_any_usernames",
 		Description: "Panther added field with collection of usernames associated with the row",
 	})
+	MustRegisterIndicator(FieldKubernetesObject, FieldMeta{
+		Name:        "PantherAnyKubernetesObjects",
+		NameJSON:    "p_any_kubernetes_objects",
+		Description: "Panther added field with collection of Kubernetes object references associated with the row",
+	})
+	MustRegisterIndicator(FieldAzureTenantID, FieldMeta{
+		Name:        "PantherAnyAzureTenantIDs",
+		NameJSON:    "p_any_azure_tenant_ids",
+		Description: "Panther added field with collection of Azure tenant ids associated with the row",
+	})
+	MustRegisterIndicator(FieldCountry, FieldMeta{
+		Name:        "PantherAnyCountries",
+		NameJSON:    "p_any_countries",
+		Description: "Panther added field with collection of the countries of the ip addresses associated with the row",
+	})
+	MustRegisterIndicator(FieldASN, FieldMeta{
+		Name:        "PantherAnyASNs",
+		NameJSON:    "p_any_asns",
+		Description: "Panther added field with collection of the autonomous system numbers of the ip addresses associated with the row",
+	})
 	MustRegisterScannerFunc("ip", ScanIPAddress, FieldIPAddress)
 	MustRegisterScannerFunc("domain", ScanDomainName, FieldDomainName)
 	MustRegisterScannerFunc("md5", ScanMD5Hash, FieldMD5Hash)
@@ -229,6 +268,8 @@ func init() {
 	MustRegisterScannerFunc("aws_tag", ScanAWSTag, FieldAWSTag)
 	MustRegisterScannerFunc("email", ScanEmail, FieldEmail)
 	MustRegisterScanner("username", FieldUsername, FieldUsername)
+	MustRegisterScanner("k8s_object", FieldKubernetesObject, FieldKubernetesObject)
+	MustRegisterScanner("azure_tenant_id", FieldAzureTenantID, FieldAzureTenantID)
 }
 
 // MustRegisterIndicator allows modules to define their own indicator fields.
@@ -340,6 +381,9 @@ func BuildEventTypeSchema(eventType reflect.Type, indicators ...FieldID) (reflec
 	// Auto-detect required field ids
 	indicators = append(indicators, FieldSetFromType(eventType)...)
 	indicators = FieldSet(indicators).Indicators()
+	// Events with IP addresses are enriched with their geolocation
+	enrichment := enrichmentIndicators(indicators)
+	indicators = FieldSet(indicators).Extend(enrichment...)
 	// Sort field set to make sure struct fields have strict order
 	sort.Sort(FieldSet(indicators))
 
@@ -351,7 +395,7 @@ func BuildEventTypeSchema(eventType reflect.Type, indicators ...FieldID) (reflec
 			return nil, errors.New(`invalid field id`)
 		}
 
-		field, ok := registeredFields[id]
+		field, ok := lookupField(id)
 		if !ok {
 			continue
 		}
@@ -362,6 +406,17 @@ func BuildEventTypeSchema(eventType reflect.Type, indicators ...FieldID) (reflec
 		field.Index = []int{len(fields)}
 		fields = append(fields, field)
 	}
+	if len(enrichment) > 0 {
+		field := geoEnrichmentField
+		field.Index = []int{len(fields)}
+		fields = append(fields, field)
+	}
+	if len(distinct) > 0 {
+		// Events with indicators are matched against threat intel lookup tables
+		field := threatIntelMatchesField
+		field.Index = []int{len(fields)}
+		fields = append(fields, field)
+	}
 
 	if err := checkDistinctNames(fields); err != nil {
 		return nil, err
@@ -39,17 +39,31 @@ func TestMetaEventStruct(t *testing.T) {
 	require.NoError(t, err)
 	// nolint:lll
 	expectMappings := map[string]string{
-		"addr":               "addr",
-		"foo":                "foo",
-		"p_any_domain_names": "p_any_domain_names",
-		"p_any_ip_addresses": "p_any_ip_addresses",
-		"p_event_time":       "p_event_time",
-		"p_log_type":         "p_log_type",
-		"p_parse_time":       "p_parse_time",
-		"p_row_id":           "p_row_id",
-		"p_source_id":        "p_source_id",
-		"p_source_label":     "p_source_label",
-		"ts":                 "ts",
+		"addr":                   "addr",
+		"foo":                    "foo",
+		"p_any_domain_names":     "p_any_domain_names",
+		"p_any_ip_addresses":     "p_any_ip_addresses",
+		"p_any_countries":        "p_any_countries",
+		"p_any_asns":             "p_any_asns",
+		"p_enrichment_geo":       "p_enrichment_geo",
+		"p_threat_intel_matches": "p_threat_intel_matches",
+		"country":                "country",
+		"country_name":           "country_name",
+		"region":                 "region",
+		"city":                   "city",
+		"latitude":               "latitude",
+		"longitude":              "longitude",
+		"asn":                    "asn",
+		"as_organization":        "as_organization",
+		"p_event_time":           "p_event_time",
+		"p_log_type":             "p_log_type",
+		"p_parse_time":           "p_parse_time",
+		"p_row_id":               "p_row_id",
+		"p_source_id":            "p_source_id",
+		"p_source_label":         "p_source_label",
+		"p_log_group":            "p_log_group",
+		"p_log_stream":           "p_log_stream",
+		"ts":                     "ts",
 	}
 	require.Equal(t, expectMappings, mappings)
 	// nolint: lll,govet
@@ -63,8 +77,14 @@ func TestMetaEventStruct(t *testing.T) {
 		{"p_row_id", "string", "Panther added field with unique id (within table)", true},
 		{"p_source_id", "string", "Panther added field with the source id", false},
 		{"p_source_label", "string", "Panther added field with the source label", false},
+		{"p_log_group", "string", "Panther added field with the CloudWatch Logs log group of events delivered by a subscription", false},
+		{"p_log_stream", "string", "Panther added field with the CloudWatch Logs log stream of events delivered by a subscription", false},
 		{"p_any_ip_addresses", "array<string>", "Panther added field with collection of ip addresses associated with the row", false},
 		{"p_any_domain_names", "array<string>", "Panther added field with collection of domain names associated with the row", false},
+		{"p_any_countries", "array<string>", "Panther added field with collection of the countries of the ip addresses associated with the row", false},
+		{"p_any_asns", "array<string>", "Panther added field with collection of the autonomous system numbers of the ip addresses associated with the row", false},
+		{"p_enrichment_geo", "map<string,struct<country:string,country_name:string,region:string,city:string,latitude:double,longitude:double,asn:bigint,as_organization:string>>", "Panther added field with the geolocation of the ip addresses associated with the row", false},
+		{"p_threat_intel_matches", "map<string,array<string>>", "Panther added field with the indicator values associated with the row found in each threat intel lookup table", false},
 	}, columns)
 }
 
@@ -52,5 +52,6 @@ func ValidateStruct(x interface{}) error {
 var validate = func() *validator.Validate {
 	v := validator.New()
 	null.RegisterValidators(v)
+	registerConstraintValidators(v)
 	return v
 }()
@@ -20,6 +20,7 @@ package pantherlog
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"strings"
 	"time"
@@ -117,3 +118,70 @@ func (p *simpleJSONParser) ParseLog(log string) ([]*Result, error) {
 	}
 	return []*Result{result}, nil
 }
+
+// RecordsError is returned by parsers of log entries with many records when only some of the records are valid events.
+// The rejected records should be classified as separate log entries.
+type RecordsError struct {
+	// Results has the results of the valid records
+	Results []*Result
+	// Rejected has the records that are not valid events
+	Rejected []string
+	// Err is the error of the first rejected record
+	Err error
+}
+
+// Error implements error interface
+func (e *RecordsError) Error() string {
+	return fmt.Sprintf("%d of %d records rejected: %s", len(e.Rejected), len(e.Rejected)+len(e.Results), e.Err)
+}
+
+// Unwrap returns the error of the first rejected record
+func (e *RecordsError) Unwrap() error {
+	return e.Err
+}
+
+// ParseRecords parses each record of a log entry as a JSON event of a log type.
+// If only some of the records are valid events it returns a *RecordsError with the results of the valid ones.
+// If none of the records is a valid event it returns the error of the first one.
+func (b *ResultBuilder) ParseRecords(logType string, records []string, newEvent func() interface{}) ([]*Result, error) {
+	results := make([]*Result, 0, len(records))
+	var rejected []string
+	var firstErr error
+	for _, record := range records {
+		result, err := b.parseRecord(logType, record, newEvent())
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			rejected = append(rejected, record)
+			continue
+		}
+		results = append(results, result)
+	}
+	switch {
+	case len(rejected) == 0:
+		return results, nil
+	case len(results) == 0:
+		return nil, firstErr
+	default:
+		return nil, &RecordsError{
+			Results:  results,
+			Rejected: rejected,
+			Err:      firstErr,
+		}
+	}
+}
+
+func (b *ResultBuilder) parseRecord(logType, record string, event interface{}) (*Result, error) {
+	if err := ConfigJSON().UnmarshalFromString(record, event); err != nil {
+		return nil, errors.Wrapf(err, "failed to read %q JSON event", logType)
+	}
+	if err := ValidateStruct(event); err != nil {
+		return nil, errors.Wrapf(err, "log event %q validation failed", logType)
+	}
+	result, err := b.BuildResult(logType, event)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to build %q log event", logType)
+	}
+	return result, nil
+}
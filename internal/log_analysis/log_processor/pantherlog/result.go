@@ -35,6 +35,12 @@ type Result struct {
 	// to avoid duplicate panther fields in resulting JSON.
 	// FIXME: Remove this field once all parsers are ported to the new method.
 	EventIncludesPantherFields bool
+	// GeoResolver resolves the geolocation of the IP addresses of the event when the result is serialized.
+	// Geolocation enrichment is disabled if nil.
+	GeoResolver GeoResolver
+	// ThreatIntel matches the indicator values of the event against lookup tables when the result is serialized.
+	// Threat intel matching is disabled if nil.
+	ThreatIntel ThreatIntelMatcher
 	// Collected indicator values for this result.
 	// This field is normally nil throughout the lifetime of results.
 	// It is populated temporarily by the custom jsoniter encoder for *Result to collect all indicator field values.
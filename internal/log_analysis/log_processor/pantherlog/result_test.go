@@ -19,6 +19,7 @@ package pantherlog_test
  */
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 	"time"
@@ -113,6 +114,50 @@ func TestNewResultBuilder(t *testing.T) {
 	require.NoError(t, err)
 	require.JSONEq(t, expect, string(actual))
 }
+
+type testGeoResolver map[string]*pantherlog.GeoInfo
+
+func (r testGeoResolver) ResolveGeo(ip string) *pantherlog.GeoInfo {
+	return r[ip]
+}
+
+func TestResultGeoEnrichment(t *testing.T) {
+	now := time.Now().UTC()
+	event := testEvent{
+		Name: "event",
+		IP:   "1.1.1.1",
+		Host: null.FromString("10.0.0.1"),
+	}
+	result, err := newBuilder("id", now).BuildResult("TestEvent", &event)
+	require.NoError(t, err)
+	result.GeoResolver = testGeoResolver{
+		"1.1.1.1": {
+			Country:        "AU",
+			CountryName:    "Australia",
+			ASN:            13335,
+			ASOrganization: "CLOUDFLARENET",
+		},
+	}
+	expect := fmt.Sprintf(`{
+		"p_row_id": "id",
+		"p_log_type": "TestEvent",
+		"p_event_time": "%[1]s",
+		"p_parse_time": "%[1]s",
+		"@name": "event",
+		"ip": "1.1.1.1",
+		"hostname": "10.0.0.1",
+		"p_any_ip_addresses": ["1.1.1.1","10.0.0.1"],
+		"p_any_countries": ["AU"],
+		"p_any_asns": ["13335"],
+		"p_enrichment_geo": {
+			"1.1.1.1": {"country": "AU", "country_name": "Australia", "asn": 13335, "as_organization": "CLOUDFLARENET"}
+		}
+	}`, now.Format(time.RFC3339Nano))
+	actual, err := buildAPI().Marshal(result)
+	require.NoError(t, err)
+	require.JSONEq(t, expect, string(actual))
+}
+
 func TestOldResults(t *testing.T) {
 	rowID := "id"
 	now := time.Now().UTC()
@@ -306,3 +351,31 @@ func TestSourceFields(t *testing.T) {
 	require.NoError(t, err)
 	require.JSONEq(t, expect, string(actual))
 }
+
+func TestParseRecords(t *testing.T) {
+	assert := require.New(t)
+	type event struct {
+		Kind string `json:"kind" validate:"eq=foo"`
+	}
+	newEvent := func() interface{} { return &event{} }
+	b := pantherlog.ResultBuilder{}
+	results, err := b.ParseRecords("Foo", []string{`{"kind":"foo"}`, `{"kind":"foo"}`}, newEvent)
+	assert.NoError(err)
+	assert.Len(results, 2)
+	assert.Equal("Foo", results[0].PantherLogType)
+
+	// Invalid records are rejected
+	results, err = b.ParseRecords("Foo", []string{`{"kind":"foo"}`, `{"kind":"bar"}`, `{`}, newEvent)
+	assert.Nil(results)
+	partial := &pantherlog.RecordsError{}
+	assert.True(errors.As(err, &partial))
+	assert.Len(partial.Results, 1)
+	assert.Equal([]string{`{"kind":"bar"}`, `{`}, partial.Rejected)
+	assert.Contains(err.Error(), "2 of 3 records rejected")
+
+	// The log entry does not match if all records are rejected
+	results, err = b.ParseRecords("Foo", []string{`{"kind":"bar"}`}, newEvent)
+	assert.Nil(results)
+	assert.Error(err)
+	assert.False(errors.As(err, &partial))
+}
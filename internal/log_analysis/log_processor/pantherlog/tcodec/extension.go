@@ -23,6 +23,8 @@ import (
 	"reflect"
 	"strings"
 	"time"
+	// Embed the time zone database so that `tz` tags work regardless of the host
+	_ "time/tzdata"
 	"unsafe"
 
 	jsoniter "github.com/json-iterator/go"
@@ -45,6 +47,14 @@ import (
 // }
 // ```
 //
+// To decode timestamps without time zone information in a specific time zone use the `tz` tag.
+//
+// ```
+// type Foo struct {
+//   LocalTimestamp time.Time `json:"ts_local" tcodec:"strftime=%Y-%m-%d %H:%M:%S" tz:"America/New_York"`
+// }
+// ```
+//
 type Extension struct {
 	jsoniter.DummyExtension
 
@@ -59,6 +69,9 @@ type Extension struct {
 // DefaultTagName is the struct tag name used for defining time decoders for a time.Time field.
 const DefaultTagName = "tcodec"
 
+// LocationTagName is the struct tag name used for defining the default time zone for a time.Time field.
+const LocationTagName = "tz"
+
 var (
 	typTime    = reflect.TypeOf(time.Time{})
 	typTimePtr = reflect.PtrTo(typTime)
@@ -83,6 +96,12 @@ func (ext *Extension) UpdateStructDescriptor(desc *jsoniter.StructDescriptor) {
 		}
 		// convert tag to TimeCodec
 		codec, err := ext.resolveCodec(tag)
+		if tz, ok := field.Tag().Lookup(LocationTagName); ok && err == nil {
+			var loc *time.Location
+			if loc, err = time.LoadLocation(tz); err == nil {
+				codec = DefaultLocation(loc, codec)
+			}
+		}
 		if err != nil {
 			// Report failed lookup error on decode/encode
 			jsonCodec := &errCodec{
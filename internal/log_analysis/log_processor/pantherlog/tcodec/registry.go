@@ -26,11 +26,14 @@ import (
 var (
 	defaultRegistry = &Registry{
 		codecs: map[string]TimeCodec{
-			"unix":    UnixSecondsCodec(),
-			"unix_ms": UnixMillisecondsCodec(),
-			"unix_us": UnixMicrosecondsCodec(),
-			"unix_ns": UnixNanosecondsCodec(),
-			"rfc3339": Join(LayoutCodec(time.RFC3339), LayoutCodec(time.RFC3339Nano)),
+			"unix":         UnixSecondsCodec(),
+			"unix_ms":      UnixMillisecondsCodec(),
+			"unix_us":      UnixMicrosecondsCodec(),
+			"unix_ns":      UnixNanosecondsCodec(),
+			"rfc3339":      Join(LayoutCodec(time.RFC3339), LayoutCodec(time.RFC3339Nano)),
+			"filetime":     FileTimeCodec(),
+			"dotnet_ticks": DotNetTicksCodec(),
+			"ldap":         LDAPCodec(),
 		},
 	}
 )
@@ -123,13 +123,29 @@ func (layout layoutCodec) EncodeTime(tm time.Time, stream *jsoniter.Stream) {
 }
 
 func (layout layoutCodec) DecodeTime(iter *jsoniter.Iterator) time.Time {
+	return decodeString(iter, func(s string) (time.Time, error) {
+		return time.Parse(string(layout), s)
+	})
+}
+
+func (layout layoutCodec) decodeIn(loc *time.Location) TimeDecoder {
+	return TimeDecoderFunc(func(iter *jsoniter.Iterator) time.Time {
+		return decodeString(iter, func(s string) (time.Time, error) {
+			return time.ParseInLocation(string(layout), s, loc)
+		})
+	})
+}
+
+// decodeString decodes a timestamp from a JSON string using `parse`.
+// Empty strings and `null` values decode to the zero time.
+func decodeString(iter *jsoniter.Iterator, parse func(s string) (time.Time, error)) time.Time {
 	switch iter.WhatIsNext() {
 	case jsoniter.StringValue:
 		s := iter.ReadString()
 		if s == "" {
 			return time.Time{}
 		}
-		tm, err := time.Parse(string(layout), s)
+		tm, err := parse(s)
 		if err != nil {
 			iter.ReportError(`DecodeTime`, err.Error())
 		}
@@ -158,24 +174,74 @@ func (format strftimeCodec) EncodeTime(tm time.Time, stream *jsoniter.Stream) {
 }
 
 func (format strftimeCodec) DecodeTime(iter *jsoniter.Iterator) time.Time {
-	switch iter.WhatIsNext() {
-	case jsoniter.StringValue:
-		s := iter.ReadString()
-		if s == "" {
-			return time.Time{}
+	return decodeString(iter, func(s string) (time.Time, error) {
+		return timefmt.Parse(s, string(format))
+	})
+}
+
+func (format strftimeCodec) decodeIn(loc *time.Location) TimeDecoder {
+	if strftimeHasZone(string(format)) {
+		return format
+	}
+	return TimeDecoderFunc(func(iter *jsoniter.Iterator) time.Time {
+		return decodeString(iter, func(s string) (time.Time, error) {
+			tm, err := timefmt.Parse(s, string(format))
+			if err != nil {
+				return time.Time{}, err
+			}
+			return wallClockIn(tm, loc), nil
+		})
+	})
+}
+
+// strftimeHasZone checks if a strftime format reads time zone information
+func strftimeHasZone(format string) bool {
+	for i := 0; i < len(format)-1; i++ {
+		if format[i] != '%' {
+			continue
 		}
-		tm, err := timefmt.Parse(s, string(format))
-		if err != nil {
-			iter.ReportError(`DecodeTime`, err.Error())
+		i++
+		switch format[i] {
+		case 'z', 'Z', 's', '+':
+			return true
+		}
+	}
+	return false
+}
+
+// wallClockIn returns the time with the same wall clock as `tm` in `loc`
+func wallClockIn(tm time.Time, loc *time.Location) time.Time {
+	year, month, day := tm.Date()
+	hour, min, sec := tm.Clock()
+	return time.Date(year, month, day, hour, min, sec, tm.Nanosecond(), loc)
+}
+
+// DefaultLocation uses `loc` as the time zone of decoded timestamps that have no time zone information.
+// Decoders that always read an absolute time (i.e. unix timestamps) are not affected.
+func DefaultLocation(loc *time.Location, codec TimeCodec) TimeCodec {
+	dec, enc := Split(codec)
+	return Join(decodeIn(loc, dec), enc)
+}
+
+// locationDecoder is implemented by decoders that read timestamps that might not have time zone information.
+type locationDecoder interface {
+	decodeIn(loc *time.Location) TimeDecoder
+}
+
+func decodeIn(loc *time.Location, dec TimeDecoder) TimeDecoder {
+	switch d := dec.(type) {
+	case locationDecoder:
+		return d.decodeIn(loc)
+	case *tryDecoder:
+		decoders := make([]TimeDecoder, len(d.decoders))
+		for i, dec := range d.decoders {
+			decoders[i] = decodeIn(loc, dec)
+		}
+		return &tryDecoder{
+			decoders: decoders,
 		}
-		return tm
-	case jsoniter.NilValue:
-		iter.ReadNil()
-		return time.Time{}
 	default:
-		iter.Skip()
-		iter.ReportError(`DecodeTime`, `invalid JSON value`)
-		return time.Time{}
+		return dec
 	}
 }
 
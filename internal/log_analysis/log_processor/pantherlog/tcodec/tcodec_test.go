@@ -240,3 +240,42 @@ func TestTryDecoder_DecodeTime(t *testing.T) {
 		require.Error(t, iter.Error)
 	}
 }
+
+func TestDefaultLocation(t *testing.T) {
+	type T struct {
+		Strftime time.Time `json:"strftime" tcodec:"strftime=%Y-%m-%d %H:%M:%S" tz:"Europe/Athens"`
+		Zone     time.Time `json:"zone" tcodec:"strftime=%Y-%m-%d %H:%M:%S %z" tz:"Europe/Athens"`
+		Layout   time.Time `json:"layout" tcodec:"layout=2006-01-02 15:04" tz:"America/New_York"`
+		Unix     time.Time `json:"unix" tcodec:"unix" tz:"America/New_York"`
+		LDAP     time.Time `json:"ldap" tcodec:"ldap" tz:"Europe/Athens"`
+	}
+	api := jsoniter.Config{}.Froze()
+	api.RegisterExtension(&Extension{})
+	v := T{}
+	input := `{
+"strftime": "2020-10-16 15:30:45",
+"zone": "2020-10-16 15:30:45 +0000",
+"layout": "2020-10-16 08:30",
+"unix": 1602851445,
+"ldap": "20201016153045"
+}`
+	require.NoError(t, api.UnmarshalFromString(input, &v))
+	require.Equal(t, time.Date(2020, 10, 16, 12, 30, 45, 0, time.UTC), v.Strftime.UTC())
+	require.Equal(t, time.Date(2020, 10, 16, 15, 30, 45, 0, time.UTC), v.Zone.UTC())
+	require.Equal(t, time.Date(2020, 10, 16, 12, 30, 0, 0, time.UTC), v.Layout.UTC())
+	require.Equal(t, time.Date(2020, 10, 16, 12, 30, 45, 0, time.UTC), v.Unix.UTC())
+	require.Equal(t, time.Date(2020, 10, 16, 12, 30, 45, 0, time.UTC), v.LDAP.UTC())
+
+	type Invalid struct {
+		Time time.Time `json:"time" tcodec:"rfc3339" tz:"Nowhere/Land"`
+	}
+	require.Error(t, api.UnmarshalFromString(`{"time":"2020-10-16T12:30:45Z"}`, &Invalid{}))
+
+	athens, err := time.LoadLocation("Europe/Athens")
+	require.NoError(t, err)
+	codec := DefaultLocation(athens, Join(TryDecoders(StrftimeCodec("%Y/%m/%d %H:%M"), LayoutCodec("2006.01.02 15:04")), StdCodec()))
+	iter := jsoniter.ParseString(jsoniter.ConfigDefault, `"2020.10.16 15:30"`)
+	tm := codec.DecodeTime(iter)
+	require.NoError(t, iter.Error)
+	require.Equal(t, time.Date(2020, 10, 16, 12, 30, 0, 0, time.UTC), tm.UTC())
+}
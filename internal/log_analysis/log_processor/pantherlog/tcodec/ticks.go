@@ -0,0 +1,255 @@
+package tcodec
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+const (
+	ticksPerSecond = int64(time.Second / 100)
+	// Number of 100ns intervals between 1601-01-01 and 1970-01-01
+	fileTimeUnixEpoch = 116444736000000000
+	// Number of 100ns intervals between 0001-01-01 and 1970-01-01
+	dotNetTicksUnixEpoch = 621355968000000000
+)
+
+// FileTime reads a timestamp from a Windows FILETIME value (100ns intervals since 1601-01-01 UTC).
+// Active Directory uses 0 and math.MaxInt64 to mark 'never', these values convert to the zero time.
+func FileTime(n int64) time.Time {
+	if n == 0 || n == math.MaxInt64 {
+		return time.Time{}
+	}
+	return ticksTime(n, fileTimeUnixEpoch)
+}
+
+// DotNetTicks reads a timestamp from .NET DateTime ticks (100ns intervals since 0001-01-01 UTC).
+func DotNetTicks(n int64) time.Time {
+	if n == 0 {
+		return time.Time{}
+	}
+	return ticksTime(n, dotNetTicksUnixEpoch)
+}
+
+func ticksTime(n, epoch int64) time.Time {
+	// Splitting to seconds avoids overflowing int64 nanoseconds for dates after 2262
+	n -= epoch
+	sec, ticks := n/ticksPerSecond, n%ticksPerSecond
+	return time.Unix(sec, ticks*100).UTC()
+}
+
+func timeTicks(tm time.Time, epoch int64) int64 {
+	return (tm.Unix()*ticksPerSecond + epoch) + int64(tm.Nanosecond()/100)
+}
+
+// FileTimeCodec decodes/encodes timestamps as Windows FILETIME values.
+// It decodes both string and number JSON values and encodes always to number.
+// Hex strings (i.e. `0x01D6A3B4C5D6E7F8`) are also supported.
+func FileTimeCodec() TimeCodec {
+	return &ticksCodec{
+		epoch: fileTimeUnixEpoch,
+		read:  FileTime,
+	}
+}
+
+// DotNetTicksCodec decodes/encodes timestamps as .NET DateTime ticks.
+// It decodes both string and number JSON values and encodes always to number.
+func DotNetTicksCodec() TimeCodec {
+	return &ticksCodec{
+		epoch: dotNetTicksUnixEpoch,
+		read:  DotNetTicks,
+	}
+}
+
+type ticksCodec struct {
+	epoch int64
+	read  func(n int64) time.Time
+}
+
+func (c *ticksCodec) EncodeTime(tm time.Time, stream *jsoniter.Stream) {
+	if tm.IsZero() {
+		stream.WriteNil()
+		return
+	}
+	stream.WriteInt64(timeTicks(tm, c.epoch))
+}
+
+func (c *ticksCodec) DecodeTime(iter *jsoniter.Iterator) (tm time.Time) {
+	switch iter.WhatIsNext() {
+	case jsoniter.NumberValue:
+		return c.read(iter.ReadInt64())
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		return
+	case jsoniter.StringValue:
+		s := iter.ReadString()
+		if s == "" {
+			return
+		}
+		n, err := parseTicks(s)
+		if err != nil {
+			iter.ReportError("ReadTicks", err.Error())
+			return
+		}
+		return c.read(n)
+	default:
+		iter.Skip()
+		iter.ReportError("ReadTicks", `invalid JSON value`)
+		return
+	}
+}
+
+func parseTicks(s string) (int64, error) {
+	if hex := strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X"); len(hex) < len(s) {
+		n, err := strconv.ParseUint(hex, 16, 64)
+		if err != nil {
+			return 0, err
+		}
+		if n > math.MaxInt64 {
+			return 0, errors.New("ticks value out of range")
+		}
+		return int64(n), nil
+	}
+	return strconv.ParseInt(s, 10, 64)
+}
+
+// LDAPCodec decodes/encodes LDAP/Active Directory timestamps.
+//
+// String values are decoded as LDAP GeneralizedTime (i.e. `20201016123045.0Z` or `20201016123045-0700`),
+// using UTC when no time zone is specified.
+// Number values and numeric strings of more than 14 digits are decoded as Windows FILETIME values used by
+// Active Directory (i.e. `lastLogonTimestamp`).
+// Timestamps are always encoded as GeneralizedTime strings in UTC.
+func LDAPCodec() TimeCodec {
+	return &ldapCodec{
+		loc: time.UTC,
+	}
+}
+
+type ldapCodec struct {
+	loc *time.Location
+}
+
+const layoutGeneralizedTime = "20060102150405.999999999Z0700"
+
+func (*ldapCodec) EncodeTime(tm time.Time, stream *jsoniter.Stream) {
+	if tm.IsZero() {
+		stream.WriteNil()
+		return
+	}
+	stream.WriteString(tm.UTC().Format(layoutGeneralizedTime))
+}
+
+func (c *ldapCodec) DecodeTime(iter *jsoniter.Iterator) (tm time.Time) {
+	switch iter.WhatIsNext() {
+	case jsoniter.NumberValue:
+		return FileTime(iter.ReadInt64())
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		return
+	case jsoniter.StringValue:
+		s := iter.ReadString()
+		if s == "" {
+			return
+		}
+		t, err := ParseGeneralizedTime(s, c.loc)
+		if err != nil {
+			iter.ReportError("ReadLDAPTime", err.Error())
+			return
+		}
+		return t
+	default:
+		iter.Skip()
+		iter.ReportError("ReadLDAPTime", `invalid JSON value`)
+		return
+	}
+}
+
+func (c *ldapCodec) decodeIn(loc *time.Location) TimeDecoder {
+	return &ldapCodec{
+		loc: loc,
+	}
+}
+
+var errInvalidGeneralizedTime = errors.New("invalid LDAP generalized time")
+
+// ParseGeneralizedTime parses an LDAP GeneralizedTime string (YYYYMMDDHHMMSS[.fraction][Z|±hhmm]).
+// Minutes and seconds are optional as per RFC4517, fractions are only supported for seconds.
+// Numeric strings longer than 14 digits are parsed as Windows FILETIME values.
+// Timestamps without time zone information use `loc`.
+func ParseGeneralizedTime(s string, loc *time.Location) (time.Time, error) {
+	digits := 0
+	for digits < len(s) && '0' <= s[digits] && s[digits] <= '9' {
+		digits++
+	}
+	if digits == len(s) && digits > 14 {
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return FileTime(n), nil
+	}
+	var layout string
+	switch digits {
+	case 10:
+		layout = "2006010215"
+	case 12:
+		layout = "200601021504"
+	case 14:
+		layout = "20060102150405"
+	default:
+		return time.Time{}, errInvalidGeneralizedTime
+	}
+	tail := s[digits:]
+	// Fraction can use either ',' or '.'
+	if digits == 14 && tail != "" && (tail[0] == '.' || tail[0] == ',') {
+		n := 1
+		for n < len(tail) && '0' <= tail[n] && tail[n] <= '9' {
+			n++
+		}
+		if n == 1 {
+			return time.Time{}, errInvalidGeneralizedTime
+		}
+		layout += "." + strings.Repeat("9", n-1)
+		s = s[:digits] + "." + s[digits+1:]
+		tail = tail[n:]
+	}
+	switch {
+	case tail == "":
+		if loc == nil {
+			loc = time.UTC
+		}
+		return time.ParseInLocation(layout, s, loc)
+	case tail == "Z":
+		layout += "Z"
+	case len(tail) == 5:
+		layout += "-0700"
+	case len(tail) == 3:
+		layout += "-07"
+	default:
+		return time.Time{}, errInvalidGeneralizedTime
+	}
+	return time.Parse(layout, s)
+}
@@ -0,0 +1,110 @@
+package tcodec
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileTime(t *testing.T) {
+	expect := time.Date(2020, 10, 16, 12, 30, 45, 123456700, time.UTC)
+	require.Equal(t, expect, FileTime(132473250451234567))
+	require.True(t, FileTime(0).IsZero())
+	require.True(t, FileTime(math.MaxInt64).IsZero())
+	// Dates before 1970
+	require.Equal(t, time.Date(1601, 1, 1, 0, 0, 0, 100, time.UTC), FileTime(1))
+}
+
+func TestDotNetTicks(t *testing.T) {
+	expect := time.Date(2020, 10, 16, 12, 30, 45, 0, time.UTC)
+	require.Equal(t, expect, DotNetTicks(637384482450000000))
+	require.Equal(t, time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC), DotNetTicks(0).UTC())
+}
+
+func TestTicksCodecs(t *testing.T) {
+	type T struct {
+		FileTime time.Time `json:"filetime,omitempty" tcodec:"filetime"`
+		Ticks    time.Time `json:"ticks,omitempty" tcodec:"dotnet_ticks"`
+		LDAP     time.Time `json:"ldap,omitempty" tcodec:"ldap"`
+	}
+	api := jsoniter.Config{}.Froze()
+	api.RegisterExtension(&Extension{})
+	expect := time.Date(2020, 10, 16, 12, 30, 45, 0, time.UTC)
+
+	for _, input := range []string{
+		`{"filetime":132473250450000000,"ticks":637384482450000000,"ldap":"20201016123045.0Z"}`,
+		`{"filetime":"132473250450000000","ticks":"637384482450000000","ldap":"20201016143045+0200"}`,
+		`{"filetime":"0x1d6a3b82b504880","ticks":637384482450000000,"ldap":132473250450000000}`,
+		`{"filetime":132473250450000000,"ticks":637384482450000000,"ldap":"132473250450000000"}`,
+	} {
+		v := T{}
+		require.NoError(t, api.UnmarshalFromString(input, &v), input)
+		require.Equal(t, expect, v.FileTime.UTC(), input)
+		require.Equal(t, expect, v.Ticks.UTC(), input)
+		require.Equal(t, expect, v.LDAP.UTC(), input)
+	}
+
+	v := T{
+		FileTime: expect,
+		Ticks:    expect,
+		LDAP:     expect.Add(time.Millisecond),
+	}
+	actual, err := api.MarshalToString(&v)
+	require.NoError(t, err)
+	require.Equal(t, `{"filetime":132473250450000000,"ticks":637384482450000000,"ldap":"20201016123045.001Z"}`, actual)
+
+	require.Error(t, api.UnmarshalFromString(`{"filetime":"foo"}`, &v))
+	require.Error(t, api.UnmarshalFromString(`{"ticks":true}`, &v))
+	require.Error(t, api.UnmarshalFromString(`{"ldap":"2020-10-16"}`, &v))
+}
+
+func TestParseGeneralizedTime(t *testing.T) {
+	loc := time.FixedZone("EET", 2*60*60)
+	for input, expect := range map[string]time.Time{
+		"20201016123045Z":        time.Date(2020, 10, 16, 12, 30, 45, 0, time.UTC),
+		"20201016123045.123Z":    time.Date(2020, 10, 16, 12, 30, 45, 123000000, time.UTC),
+		"20201016123045,5Z":      time.Date(2020, 10, 16, 12, 30, 45, 500000000, time.UTC),
+		"202010161230Z":          time.Date(2020, 10, 16, 12, 30, 0, 0, time.UTC),
+		"2020101612-07":          time.Date(2020, 10, 16, 19, 0, 0, 0, time.UTC),
+		"20201016123045":         time.Date(2020, 10, 16, 10, 30, 45, 0, time.UTC),
+		"20201016123045.5-0130":  time.Date(2020, 10, 16, 14, 0, 45, 500000000, time.UTC),
+		"132473250450000000":     time.Date(2020, 10, 16, 12, 30, 45, 0, time.UTC),
+		"20201016123045.999999Z": time.Date(2020, 10, 16, 12, 30, 45, 999999000, time.UTC),
+	} {
+		actual, err := ParseGeneralizedTime(input, loc)
+		require.NoError(t, err, input)
+		require.Equal(t, expect, actual.UTC(), input)
+	}
+	for _, input := range []string{
+		"",
+		"2020",
+		"2020101612.5Z",
+		"20201016123045.Z",
+		"20201016123045+02:00",
+		"2020-10-16T12:30:45Z",
+	} {
+		_, err := ParseGeneralizedTime(input, loc)
+		require.Error(t, err, input)
+	}
+}
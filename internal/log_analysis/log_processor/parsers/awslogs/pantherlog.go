@@ -21,6 +21,7 @@ package awslogs
 import (
 	"regexp"
 
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
 )
 
@@ -91,3 +92,15 @@ func (pl *AWSPantherLog) AppendAnyAWSTagPtrs(values ...*string) {
 func (pl *AWSPantherLog) AppendAnyAWSTags(values ...string) {
 	parsers.AppendAnyString(&pl.PantherAnyAWSTags, values...)
 }
+
+// EnrichThreatIntel implements pantherlog.ThreatIntelEnricher interface.
+// It extends the matches of the embedded parsers.PantherLog with the AWS indicators.
+func (pl *AWSPantherLog) EnrichThreatIntel(m pantherlog.ThreatIntelMatcher) {
+	pl.PantherLog.EnrichThreatIntel(m)
+	for _, value := range pl.PantherAnyAWSAccountIds {
+		pl.PantherThreatIntelMatches = pantherlog.AppendThreatIntelMatches(pl.PantherThreatIntelMatches, pantherlog.FieldAWSAccountID, value, m)
+	}
+	for _, value := range pl.PantherAnyAWSARNs {
+		pl.PantherThreatIntelMatches = pantherlog.AppendThreatIntelMatches(pl.PantherThreatIntelMatches, pantherlog.FieldAWSARN, value, m)
+	}
+}
@@ -0,0 +1,78 @@
+package azurelogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// ActivityRecord is an Azure Activity log record.
+// nolint:lll
+type ActivityRecord struct {
+	ResourceLogFields
+	Category   pantherlog.String     `json:"category" validate:"required,oneof=Administrative ServiceHealth ResourceHealth Alert Autoscale Security Recommendation Policy" description:"The category of the activity log event."`
+	Identity   *ActivityIdentity     `json:"identity" description:"The authorization and claims of the caller."`
+	Level      pantherlog.String     `json:"level" description:"The severity level of the event (Critical, Error, Warning, Informational)."`
+	Location   pantherlog.String     `json:"location" description:"The region of the resource emitting the event."`
+	Properties pantherlog.RawMessage `json:"properties" description:"The details of the event."`
+}
+
+// nolint:lll
+type ActivityIdentity struct {
+	Authorization *ActivityAuthorization `json:"authorization" description:"The Azure RBAC properties of the event."`
+	Claims        map[string]string      `json:"claims" description:"The claims of the JWT token used by Active Directory to authenticate the caller."`
+}
+
+// Claims used as indicators
+const (
+	claimUPN      = "http://schemas.xmlsoap.org/ws/2005/05/identity/claims/upn"
+	claimTenantID = "http://schemas.microsoft.com/identity/claims/tenantid"
+	claimIPAddr   = "ipaddr"
+)
+
+var _ pantherlog.ValueWriterTo = (*ActivityIdentity)(nil)
+
+// WriteValuesTo implements pantherlog.ValueWriterTo interface
+func (id *ActivityIdentity) WriteValuesTo(w pantherlog.ValueWriter) {
+	if upn := id.Claims[claimUPN]; upn != "" {
+		pantherlog.ScanEmail(w, upn)
+		w.WriteValues(pantherlog.FieldUsername, upn)
+	}
+	if tenantID := id.Claims[claimTenantID]; tenantID != "" {
+		w.WriteValues(pantherlog.FieldAzureTenantID, tenantID)
+	}
+	pantherlog.ScanIPAddress(w, id.Claims[claimIPAddr])
+}
+
+// nolint:lll
+type ActivityAuthorization struct {
+	Scope    pantherlog.String `json:"scope" description:"The scope of the authorized action."`
+	Action   pantherlog.String `json:"action" description:"The authorized action."`
+	Evidence *ActivityEvidence `json:"evidence" description:"The role assignment that authorized the action."`
+}
+
+// nolint:lll
+type ActivityEvidence struct {
+	Role                pantherlog.String `json:"role" description:"The name of the role."`
+	RoleAssignmentScope pantherlog.String `json:"roleAssignmentScope" description:"The scope of the role assignment."`
+	RoleAssignmentID    pantherlog.String `json:"roleAssignmentId" description:"The ID of the role assignment."`
+	RoleDefinitionID    pantherlog.String `json:"roleDefinitionId" description:"The ID of the role definition."`
+	PrincipalID         pantherlog.String `json:"principalId" description:"The ID of the principal the role is assigned to."`
+	PrincipalType       pantherlog.String `json:"principalType" description:"The type of the principal the role is assigned to."`
+}
@@ -0,0 +1,88 @@
+package azurelogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// AuditRecord is an Azure Active Directory audit log record.
+// nolint:lll
+type AuditRecord struct {
+	ResourceLogFields
+	Category   pantherlog.String `json:"category" validate:"required,eq=AuditLogs" description:"The category of the audit log."`
+	Identity   pantherlog.String `json:"identity" description:"The display name of the identity that performed the activity."`
+	Level      pantherlog.String `json:"Level" description:"The severity level of the event."`
+	Properties *AuditProperties  `json:"properties" validate:"required" description:"The details of the audited activity."`
+}
+
+// nolint:lll
+type AuditProperties struct {
+	ID                  pantherlog.String     `json:"id" description:"The unique ID of the activity."`
+	Category            pantherlog.String     `json:"category" description:"The category of the resource targeted by the activity (UserManagement, GroupManagement, ApplicationManagement)."`
+	CorrelationID       pantherlog.String     `json:"correlationId" panther:"trace_id" description:"A GUID used to group together a set of related activities."`
+	Result              pantherlog.String     `json:"result" description:"The result of the activity (success, failure, timeout, unknownFutureValue)."`
+	ResultReason        pantherlog.String     `json:"resultReason" description:"The reason of the activity result."`
+	ActivityDisplayName pantherlog.String     `json:"activityDisplayName" description:"The name of the activity."`
+	ActivityDateTime    pantherlog.Time       `json:"activityDateTime" tcodec:"rfc3339" description:"The date and time the activity was performed."`
+	LoggedByService     pantherlog.String     `json:"loggedByService" description:"The service that initiated the activity."`
+	OperationType       pantherlog.String     `json:"operationType" description:"The type of the operation (Add, Assign, Update, Delete)."`
+	InitiatedBy         *AuditInitiator       `json:"initiatedBy" description:"The user or application that initiated the activity."`
+	TargetResources     []AuditTargetResource `json:"targetResources" description:"The resources changed by the activity."`
+	AdditionalDetails   []KeyValue            `json:"additionalDetails" description:"Additional details about the activity."`
+}
+
+// nolint:lll
+type AuditInitiator struct {
+	User *AuditUser `json:"user" description:"The user that initiated the activity."`
+	App  *AuditApp  `json:"app" description:"The application that initiated the activity."`
+}
+
+// nolint:lll
+type AuditUser struct {
+	ID                pantherlog.String `json:"id" description:"The object ID of the user."`
+	DisplayName       pantherlog.String `json:"displayName" description:"The display name of the user."`
+	UserPrincipalName pantherlog.String `json:"userPrincipalName" panther:"email,username" description:"The user principal name (UPN) of the user."`
+	IPAddress         pantherlog.String `json:"ipAddress" panther:"ip" description:"The IP address of the user."`
+}
+
+// nolint:lll
+type AuditApp struct {
+	AppID                pantherlog.String `json:"appId" description:"The ID of the application."`
+	DisplayName          pantherlog.String `json:"displayName" description:"The name of the application."`
+	ServicePrincipalID   pantherlog.String `json:"servicePrincipalId" description:"The ID of the service principal of the application."`
+	ServicePrincipalName pantherlog.String `json:"servicePrincipalName" description:"The name of the service principal of the application."`
+}
+
+// nolint:lll
+type AuditTargetResource struct {
+	ID                 pantherlog.String  `json:"id" description:"The ID of the resource."`
+	DisplayName        pantherlog.String  `json:"displayName" description:"The display name of the resource."`
+	Type               pantherlog.String  `json:"type" description:"The type of the resource (User, Group, Application, Policy, Device)."`
+	UserPrincipalName  pantherlog.String  `json:"userPrincipalName" panther:"email,username" description:"The user principal name (UPN) of a user resource."`
+	GroupType          pantherlog.String  `json:"groupType" description:"The type of a group resource."`
+	ModifiedProperties []ModifiedProperty `json:"modifiedProperties" description:"The properties of the resource modified by the activity."`
+}
+
+// nolint:lll
+type ModifiedProperty struct {
+	DisplayName pantherlog.String `json:"displayName" description:"The name of the property."`
+	OldValue    pantherlog.String `json:"oldValue" description:"The JSON encoded value of the property before the activity."`
+	NewValue    pantherlog.String `json:"newValue" description:"The JSON encoded value of the property after the activity."`
+}
@@ -0,0 +1,132 @@
+package azurelogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+const (
+	TypeADSignIn = "Azure.ADSignIn"
+	TypeADAudit  = "Azure.ADAudit"
+	TypeActivity = "Azure.Activity"
+)
+
+// LogTypes exports the available log type entries
+func LogTypes() logtypes.Group {
+	return logTypes
+}
+
+// nolint:lll
+var logTypes = logtypes.Must("Azure",
+	logtypes.Config{
+		Name: TypeADSignIn,
+		Description: `Azure Active Directory sign-in logs exported by diagnostic settings.
+Includes interactive and non-interactive user sign-ins, service principal sign-ins and managed identity sign-ins.`,
+		ReferenceURL: `https://docs.microsoft.com/en-us/azure/active-directory/reports-monitoring/reference-azure-monitor-sign-ins-log-schema`,
+		Schema:       pantherlog.MustBuildEventSchema(&SignInRecord{}),
+		NewParser:    newRecordsFactory(TypeADSignIn, func() interface{} { return &SignInRecord{} }),
+	},
+	logtypes.Config{
+		Name:         TypeADAudit,
+		Description:  `Azure Active Directory audit logs exported by diagnostic settings.`,
+		ReferenceURL: `https://docs.microsoft.com/en-us/azure/active-directory/reports-monitoring/reference-azure-monitor-audit-log-schema`,
+		Schema:       pantherlog.MustBuildEventSchema(&AuditRecord{}),
+		NewParser:    newRecordsFactory(TypeADAudit, func() interface{} { return &AuditRecord{} }),
+	},
+	logtypes.Config{
+		Name:         TypeActivity,
+		Description:  `Azure Activity log events of a subscription exported by diagnostic settings.`,
+		ReferenceURL: `https://docs.microsoft.com/en-us/azure/azure-monitor/platform/activity-log-schema`,
+		// The identity claims are not present in the struct but added by ActivityIdentity.WriteValuesTo
+		Schema:    pantherlog.MustBuildEventSchema(&ActivityRecord{}, pantherlog.FieldEmail, pantherlog.FieldUsername),
+		NewParser: newRecordsFactory(TypeActivity, func() interface{} { return &ActivityRecord{} }),
+	},
+)
+
+// recordsEnvelope holds the records of Azure Monitor logs exported to Event Hubs or to a Storage account.
+// Event Hub messages wrap the records in a `records` array.
+// Event Hub Capture files store each message as an Avro record with the message in its `Body`.
+type recordsEnvelope struct {
+	Records []jsoniter.RawMessage `json:"records"`
+	Body    []byte                `json:"Body"`
+}
+
+// readRecords reads the records of a log entry.
+// Storage accounts store each record on a separate line so any other log entry is a single record.
+func readRecords(log string) ([]string, error) {
+	envelope := recordsEnvelope{}
+	if err := jsoniter.ConfigCompatibleWithStandardLibrary.UnmarshalFromString(log, &envelope); err != nil {
+		return nil, errors.Wrap(err, "failed to read Azure Monitor records")
+	}
+	if envelope.Body != nil {
+		return readRecords(string(envelope.Body))
+	}
+	if envelope.Records == nil {
+		return []string{log}, nil
+	}
+	records := make([]string, len(envelope.Records))
+	for i, record := range envelope.Records {
+		records[i] = strings.TrimSpace(string(record))
+	}
+	return records, nil
+}
+
+type recordsFactory struct {
+	logType  string
+	newEvent func() interface{}
+}
+
+func newRecordsFactory(logType string, newEvent func() interface{}) pantherlog.LogParserFactory {
+	return &recordsFactory{
+		logType:  logType,
+		newEvent: newEvent,
+	}
+}
+
+// NewParser implements pantherlog.LogParserFactory interface
+func (f *recordsFactory) NewParser(_ interface{}) (pantherlog.LogParser, error) {
+	return &recordsParser{
+		logType:  f.logType,
+		newEvent: f.newEvent,
+	}, nil
+}
+
+type recordsParser struct {
+	logType  string
+	newEvent func() interface{}
+	builder  pantherlog.ResultBuilder
+}
+
+// ParseLog implements pantherlog.LogParser interface.
+// Diagnostic settings can export many categories to the same Event Hub, so records of other categories
+// fail validation and are rejected with a *pantherlog.RecordsError to be classified separately.
+func (p *recordsParser) ParseLog(log string) ([]*pantherlog.Result, error) {
+	records, err := readRecords(log)
+	if err != nil {
+		return nil, err
+	}
+	return p.builder.ParseRecords(p.logType, records, p.newEvent)
+}
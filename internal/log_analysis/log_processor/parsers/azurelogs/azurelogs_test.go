@@ -0,0 +1,70 @@
+package azurelogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes/logtesting"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+func TestAzureLogParsers(t *testing.T) {
+	logtesting.RunTestsFromYAML(t, LogTypes(), "./testdata/azure_tests.yml")
+}
+
+func TestReadRecords(t *testing.T) {
+	assert := require.New(t)
+	records, err := readRecords(`{"time":"2020-10-01T12:00:00Z","records":null}`)
+	assert.NoError(err)
+	assert.Equal([]string{`{"time":"2020-10-01T12:00:00Z","records":null}`}, records)
+
+	records, err = readRecords(`{"records":[{"foo":"bar"}, {"baz":42}]}`)
+	assert.NoError(err)
+	assert.Equal([]string{`{"foo":"bar"}`, `{"baz":42}`}, records)
+
+	body := base64.StdEncoding.EncodeToString([]byte(`{"records":[{"foo":"bar"}]}`))
+	records, err = readRecords(`{"SequenceNumber":1,"Body":"` + body + `"}`)
+	assert.NoError(err)
+	assert.Equal([]string{`{"foo":"bar"}`}, records)
+
+	records, err = readRecords(`{"records":{}}`)
+	assert.Error(err)
+	assert.Nil(records)
+}
+
+// nolint:lll
+func TestMixedCategories(t *testing.T) {
+	assert := require.New(t)
+	// Records of other categories are rejected to be classified separately
+	const input = `{"records":[{"time":"2020-10-01T13:00:00Z","operationName":"Add member to role","category":"AuditLogs","properties":{}},{"time":"2020-10-01T12:00:00Z","operationName":"Sign-in activity","category":"SignInLogs","properties":{}}]}`
+	parser, err := LogTypes().Find(TypeADAudit).NewParser(nil)
+	assert.NoError(err)
+	results, err := parser.ParseLog(input)
+	assert.Nil(results)
+	partial := &pantherlog.RecordsError{}
+	assert.True(errors.As(err, &partial))
+	assert.Len(partial.Results, 1)
+	assert.Equal(TypeADAudit, partial.Results[0].PantherLogType)
+	assert.Equal([]string{`{"time":"2020-10-01T12:00:00Z","operationName":"Sign-in activity","category":"SignInLogs","properties":{}}`}, partial.Rejected)
+}
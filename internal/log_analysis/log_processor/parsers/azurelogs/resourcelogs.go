@@ -0,0 +1,46 @@
+package azurelogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// ResourceLogFields are the fields of the common schema of Azure Monitor resource logs.
+// nolint:lll
+type ResourceLogFields struct {
+	Time              pantherlog.Time   `json:"time" validate:"required" event_time:"true" tcodec:"rfc3339" description:"The timestamp (UTC) of the event."`
+	ResourceID        pantherlog.String `json:"resourceId" description:"The resource ID of the resource that emitted the event. For tenant services it is of the form /tenants/<tenant-id>/providers/<provider-name>."`
+	TenantID          pantherlog.String `json:"tenantId" panther:"azure_tenant_id" description:"The tenant ID of the Active Directory tenant that the event is tied to."`
+	OperationName     pantherlog.String `json:"operationName" validate:"required" description:"The name of the operation that the event represents."`
+	OperationVersion  pantherlog.String `json:"operationVersion" description:"The API version associated with the operation."`
+	ResultType        pantherlog.String `json:"resultType" description:"The status of the operation."`
+	ResultSignature   pantherlog.String `json:"resultSignature" description:"The sub status of the operation."`
+	ResultDescription pantherlog.String `json:"resultDescription" description:"The static text description of the operation."`
+	DurationMs        pantherlog.Int64  `json:"durationMs" description:"The duration of the operation in milliseconds."`
+	CallerIPAddress   pantherlog.String `json:"callerIpAddress" panther:"ip" description:"The IP address of the caller."`
+	CorrelationID     pantherlog.String `json:"correlationId" panther:"trace_id" description:"A GUID used to group together a set of related events."`
+}
+
+// KeyValue is a key value pair of additional details
+// nolint:lll
+type KeyValue struct {
+	Key   pantherlog.String `json:"key" description:"The key of the detail."`
+	Value pantherlog.String `json:"value" description:"The value of the detail."`
+}
@@ -0,0 +1,138 @@
+package azurelogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// SignInRecord is an Azure Active Directory sign-in log record.
+// nolint:lll,maligned
+type SignInRecord struct {
+	ResourceLogFields
+	Category   pantherlog.String `json:"category" validate:"required,oneof=SignInLogs NonInteractiveUserSignInLogs ServicePrincipalSignInLogs ManagedIdentitySignInLogs" description:"The category of the sign-in log."`
+	Identity   pantherlog.String `json:"identity" description:"The display name of the identity that signed in."`
+	Level      pantherlog.String `json:"Level" description:"The severity level of the event."`
+	Location   pantherlog.String `json:"location" description:"The country code of the sign-in location."`
+	Properties *SignInProperties `json:"properties" validate:"required" description:"The details of the sign-in."`
+}
+
+// nolint:lll,maligned
+type SignInProperties struct {
+	ID                               pantherlog.String         `json:"id" description:"The unique ID of the sign-in."`
+	CreatedDateTime                  pantherlog.Time           `json:"createdDateTime" tcodec:"rfc3339" description:"The date and time the sign-in was initiated."`
+	UserDisplayName                  pantherlog.String         `json:"userDisplayName" description:"The display name of the user."`
+	UserPrincipalName                pantherlog.String         `json:"userPrincipalName" panther:"email,username" description:"The user principal name (UPN) of the user."`
+	UserID                           pantherlog.String         `json:"userId" description:"The object ID of the user."`
+	UserType                         pantherlog.String         `json:"userType" description:"Whether the user is a member or a guest of the tenant."`
+	AlternateSignInName              pantherlog.String         `json:"alternateSignInName" panther:"email,username" description:"The identifier the user used to sign in, if it is not the UPN."`
+	AppID                            pantherlog.String         `json:"appId" description:"The ID of the application the user signed in to."`
+	AppDisplayName                   pantherlog.String         `json:"appDisplayName" description:"The name of the application the user signed in to."`
+	ServicePrincipalID               pantherlog.String         `json:"servicePrincipalId" description:"The ID of the service principal that signed in."`
+	ServicePrincipalName             pantherlog.String         `json:"servicePrincipalName" description:"The name of the service principal that signed in."`
+	ResourceID                       pantherlog.String         `json:"resourceId" description:"The ID of the resource the user signed in to."`
+	ResourceDisplayName              pantherlog.String         `json:"resourceDisplayName" description:"The name of the resource the user signed in to."`
+	ResourceTenantID                 pantherlog.String         `json:"resourceTenantId" panther:"azure_tenant_id" description:"The tenant ID of the resource the user signed in to."`
+	HomeTenantID                     pantherlog.String         `json:"homeTenantId" panther:"azure_tenant_id" description:"The tenant ID of the home tenant of the user."`
+	CrossTenantAccessType            pantherlog.String         `json:"crossTenantAccessType" description:"The type of cross tenant access of the sign-in."`
+	IPAddress                        pantherlog.String         `json:"ipAddress" panther:"ip" description:"The IP address of the client the user signed in from."`
+	Status                           *SignInStatus             `json:"status" description:"The status of the sign-in."`
+	ClientAppUsed                    pantherlog.String         `json:"clientAppUsed" description:"The legacy client used for the sign-in."`
+	UserAgent                        pantherlog.String         `json:"userAgent" description:"The user agent of the client."`
+	DeviceDetail                     *DeviceDetail             `json:"deviceDetail" description:"The device the sign-in was initiated from."`
+	Location                         *SignInLocation           `json:"location" description:"The location the sign-in was initiated from."`
+	CorrelationID                    pantherlog.String         `json:"correlationId" panther:"trace_id" description:"The request ID sent from the client when the sign-in is initiated."`
+	OriginalRequestID                pantherlog.String         `json:"originalRequestId" description:"The request ID of the first request in the authentication sequence."`
+	UniqueTokenIdentifier            pantherlog.String         `json:"uniqueTokenIdentifier" description:"The ID of the token issued by the sign-in."`
+	IsInteractive                    pantherlog.Bool           `json:"isInteractive" description:"Whether the sign-in is interactive."`
+	TokenIssuerName                  pantherlog.String         `json:"tokenIssuerName" description:"The name of the identity provider."`
+	TokenIssuerType                  pantherlog.String         `json:"tokenIssuerType" description:"The type of the identity provider (AzureAD, ADFederationServices)."`
+	ProcessingTimeInMilliseconds     pantherlog.Int64          `json:"processingTimeInMilliseconds" description:"The request processing time in milliseconds."`
+	ConditionalAccessStatus          pantherlog.String         `json:"conditionalAccessStatus" description:"The status of the conditional access policies triggered by the sign-in (success, failure, notApplied)."`
+	AppliedConditionalAccessPolicies []ConditionalAccessPolicy `json:"appliedConditionalAccessPolicies" description:"The conditional access policies triggered by the sign-in."`
+	AuthenticationRequirement        pantherlog.String         `json:"authenticationRequirement" description:"The type of authentication required for the sign-in (singleFactorAuthentication, multiFactorAuthentication)."`
+	AuthenticationDetails            []AuthenticationDetail    `json:"authenticationDetails" description:"The steps of the authentication."`
+	AuthenticationProcessingDetails  []KeyValue                `json:"authenticationProcessingDetails" description:"Additional authentication processing details."`
+	MFADetail                        *MFADetail                `json:"mfaDetail" description:"The details of the multi-factor authentication."`
+	NetworkLocationDetails           pantherlog.RawMessage     `json:"networkLocationDetails" description:"The named networks of the sign-in location."`
+	RiskDetail                       pantherlog.String         `json:"riskDetail" description:"The reason behind a specific state of a risky user, sign-in or risk event."`
+	RiskLevelAggregated              pantherlog.String         `json:"riskLevelAggregated" description:"The aggregated risk level (none, low, medium, high, hidden)."`
+	RiskLevelDuringSignIn            pantherlog.String         `json:"riskLevelDuringSignIn" description:"The risk level during the sign-in (none, low, medium, high, hidden)."`
+	RiskState                        pantherlog.String         `json:"riskState" description:"The risk state of a risky user, sign-in or risk event."`
+	RiskEventTypes                   []pantherlog.String       `json:"riskEventTypes" description:"The risk event types associated with the sign-in."`
+	RiskEventTypesV2                 []pantherlog.String       `json:"riskEventTypes_v2" description:"The risk event types associated with the sign-in."`
+	FlaggedForReview                 pantherlog.Bool           `json:"flaggedForReview" description:"Whether the user or admin flagged the sign-in for review."`
+}
+
+// nolint:lll
+type SignInStatus struct {
+	ErrorCode         pantherlog.Int64  `json:"errorCode" description:"The error code of the sign-in. It is 0 for successful sign-ins."`
+	FailureReason     pantherlog.String `json:"failureReason" description:"The reason the sign-in failed."`
+	AdditionalDetails pantherlog.String `json:"additionalDetails" description:"Additional details about the sign-in status."`
+}
+
+// nolint:lll
+type DeviceDetail struct {
+	DeviceID        pantherlog.String `json:"deviceId" description:"The ID of the device."`
+	DisplayName     pantherlog.String `json:"displayName" description:"The display name of the device."`
+	OperatingSystem pantherlog.String `json:"operatingSystem" description:"The operating system of the device."`
+	Browser         pantherlog.String `json:"browser" description:"The browser used for the sign-in."`
+	IsCompliant     pantherlog.Bool   `json:"isCompliant" description:"Whether the device is compliant."`
+	IsManaged       pantherlog.Bool   `json:"isManaged" description:"Whether the device is managed."`
+	TrustType       pantherlog.String `json:"trustType" description:"How the device is joined to Azure AD."`
+}
+
+// nolint:lll
+type SignInLocation struct {
+	City            pantherlog.String `json:"city" description:"The city of the sign-in."`
+	State           pantherlog.String `json:"state" description:"The state of the sign-in."`
+	CountryOrRegion pantherlog.String `json:"countryOrRegion" description:"The country code of the sign-in."`
+	GeoCoordinates  *GeoCoordinates   `json:"geoCoordinates" description:"The coordinates of the sign-in."`
+}
+
+// nolint:lll
+type GeoCoordinates struct {
+	Latitude  pantherlog.Float64 `json:"latitude" description:"The latitude of the location."`
+	Longitude pantherlog.Float64 `json:"longitude" description:"The longitude of the location."`
+}
+
+// nolint:lll
+type ConditionalAccessPolicy struct {
+	ID                      pantherlog.String   `json:"id" description:"The ID of the policy."`
+	DisplayName             pantherlog.String   `json:"displayName" description:"The name of the policy."`
+	EnforcedGrantControls   []pantherlog.String `json:"enforcedGrantControls" description:"The grant controls enforced by the policy."`
+	EnforcedSessionControls []pantherlog.String `json:"enforcedSessionControls" description:"The session controls enforced by the policy."`
+	Result                  pantherlog.String   `json:"result" description:"The result of the policy (success, failure, notApplied, notEnabled)."`
+}
+
+// nolint:lll
+type AuthenticationDetail struct {
+	AuthenticationStepDateTime     pantherlog.Time   `json:"authenticationStepDateTime" tcodec:"rfc3339" description:"The date and time of the authentication step."`
+	AuthenticationMethod           pantherlog.String `json:"authenticationMethod" description:"The authentication method used in the step."`
+	AuthenticationMethodDetail     pantherlog.String `json:"authenticationMethodDetail" description:"The details of the authentication method."`
+	Succeeded                      pantherlog.Bool   `json:"succeeded" description:"Whether the authentication step succeeded."`
+	AuthenticationStepResultDetail pantherlog.String `json:"authenticationStepResultDetail" description:"The result of the authentication step."`
+	AuthenticationStepRequirement  pantherlog.String `json:"authenticationStepRequirement" description:"The requirement that triggered the authentication step."`
+}
+
+// nolint:lll
+type MFADetail struct {
+	AuthMethod pantherlog.String `json:"authMethod" description:"The multi-factor authentication method used."`
+	AuthDetail pantherlog.String `json:"authDetail" description:"The details of the multi-factor authentication."`
+}
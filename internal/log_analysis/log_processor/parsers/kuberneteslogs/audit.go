@@ -0,0 +1,102 @@
+package kuberneteslogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"strings"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// AuditEvent is an audit.k8s.io/v1 Event.
+// It records a stage of the handling of a request by the Kubernetes API server.
+// nolint:lll,maligned
+type AuditEvent struct {
+	Kind                     pantherlog.String     `json:"kind" validate:"required,eq=Event" description:"The kind of the object (Event)."`
+	APIVersion               pantherlog.String     `json:"apiVersion" validate:"required" description:"The versioned schema of the event (i.e. audit.k8s.io/v1)."`
+	Level                    pantherlog.String     `json:"level" validate:"required" description:"The audit level at which the event was generated (None, Metadata, Request or RequestResponse)."`
+	AuditID                  pantherlog.String     `json:"auditID" validate:"required" panther:"trace_id" description:"A unique audit ID, generated for each request. All stages of a request share the same audit ID."`
+	Stage                    pantherlog.String     `json:"stage" validate:"required" description:"The stage of the request handling when the event was generated (RequestReceived, ResponseStarted, ResponseComplete or Panic)."`
+	RequestURI               pantherlog.String     `json:"requestURI" validate:"required" description:"The request URI as sent by the client to a server."`
+	Verb                     pantherlog.String     `json:"verb" validate:"required" description:"The Kubernetes verb associated with the request (i.e. get, list, create, delete). For non-resource requests, this is the lower-cased HTTP method."`
+	User                     UserInfo              `json:"user" validate:"required" description:"The authenticated user information."`
+	ImpersonatedUser         *UserInfo             `json:"impersonatedUser" description:"The impersonated user information."`
+	SourceIPs                []pantherlog.String   `json:"sourceIPs" panther:"ip" description:"The source IPs, from where the request originated and intermediate proxies."`
+	UserAgent                pantherlog.String     `json:"userAgent" description:"The user agent string reported by the client."`
+	ObjectRef                *ObjectReference      `json:"objectRef" panther:"k8s_object" description:"The object reference this request is targeted at. Does not apply for list-type requests or non-resource requests."`
+	ResponseStatus           *Status               `json:"responseStatus" description:"The response status, populated even when the response object is not a status type."`
+	RequestObject            pantherlog.RawMessage `json:"requestObject" description:"The API object from the request, in JSON format. Only logged at Request level and higher."`
+	ResponseObject           pantherlog.RawMessage `json:"responseObject" description:"The API object returned in the response, in JSON format. Only logged at RequestResponse level."`
+	RequestReceivedTimestamp pantherlog.Time       `json:"requestReceivedTimestamp" validate:"required" event_time:"true" tcodec:"rfc3339" description:"The time the request reached the API server."`
+	StageTimestamp           pantherlog.Time       `json:"stageTimestamp" tcodec:"rfc3339" description:"The time the request reached the current audit stage."`
+	Annotations              map[string]string     `json:"annotations" description:"Unstructured key value map stored with the audit event, set by plugins (i.e. authorization decisions)."`
+}
+
+// UserInfo holds the information about the user needed to implement the user.Info interface.
+// nolint:lll
+type UserInfo struct {
+	Username pantherlog.String              `json:"username" panther:"username" description:"The name that uniquely identifies this user among all active users."`
+	UID      pantherlog.String              `json:"uid" description:"A unique value that identifies this user across time."`
+	Groups   []pantherlog.String            `json:"groups" description:"The names of groups this user is a part of."`
+	Extra    map[string][]pantherlog.String `json:"extra" description:"Any additional information provided by the authenticator."`
+}
+
+// ObjectReference contains enough information to let you inspect or modify the referred object.
+// nolint:lll
+type ObjectReference struct {
+	Resource        pantherlog.String `json:"resource" description:"The resource type of the object (i.e. pods)."`
+	Namespace       pantherlog.String `json:"namespace" description:"The namespace of the object."`
+	Name            pantherlog.String `json:"name" description:"The name of the object."`
+	UID             pantherlog.String `json:"uid" description:"The unique ID of the object."`
+	APIGroup        pantherlog.String `json:"apiGroup" description:"The name of the API group that contains the referred object. The empty string represents the core API group."`
+	APIVersion      pantherlog.String `json:"apiVersion" description:"The version of the API group that contains the referred object."`
+	ResourceVersion pantherlog.String `json:"resourceVersion" description:"The resource version of the object."`
+	Subresource     pantherlog.String `json:"subresource" description:"The subresource of the object (i.e. exec, log)."`
+}
+
+// String formats the reference as `[<namespace>/]<resource>[.<apiGroup>][/<name>]` to collect it as an indicator.
+func (ref *ObjectReference) String() string {
+	if ref == nil || ref.Resource.Value == "" {
+		return ""
+	}
+	parts := make([]string, 0, 3)
+	if ns := ref.Namespace.Value; ns != "" {
+		parts = append(parts, ns)
+	}
+	resource := ref.Resource.Value
+	if group := ref.APIGroup.Value; group != "" {
+		resource += "." + group
+	}
+	parts = append(parts, resource)
+	if name := ref.Name.Value; name != "" {
+		parts = append(parts, name)
+	}
+	return strings.Join(parts, "/")
+}
+
+// Status is the status of an API call.
+// nolint:lll
+type Status struct {
+	Status   pantherlog.String     `json:"status" description:"The status of the operation (Success or Failure)."`
+	Message  pantherlog.String     `json:"message" description:"A human-readable description of the status of this operation."`
+	Reason   pantherlog.String     `json:"reason" description:"A machine-readable description of why this operation is in the Failure status."`
+	Code     pantherlog.Int64      `json:"code" description:"The HTTP return code of this status."`
+	Details  pantherlog.RawMessage `json:"details" description:"Extended data associated with the reason."`
+	Metadata pantherlog.RawMessage `json:"metadata" description:"Standard list metadata."`
+}
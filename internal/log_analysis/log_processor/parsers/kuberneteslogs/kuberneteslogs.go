@@ -0,0 +1,43 @@
+package kuberneteslogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+)
+
+const TypeAudit = "Kubernetes.Audit"
+
+// LogTypes exports the available log type entries
+func LogTypes() logtypes.Group {
+	return logTypes
+}
+
+var logTypes = logtypes.Must("Kubernetes",
+	logtypes.ConfigJSON{
+		Name: TypeAudit,
+		Description: `Kubernetes API server audit events written by the log audit backend.
+Amazon EKS control plane audit logs delivered to S3 through a CloudWatch Logs subscription are unwrapped
+and keep their log group and log stream in p_log_group and p_log_stream.`,
+		ReferenceURL: `https://kubernetes.io/docs/tasks/debug-application-cluster/audit/`,
+		NewEvent: func() interface{} {
+			return &AuditEvent{}
+		},
+	},
+)
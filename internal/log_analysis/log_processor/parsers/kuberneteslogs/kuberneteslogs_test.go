@@ -0,0 +1,71 @@
+package kuberneteslogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes/logtesting"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/logstream"
+)
+
+func TestKubernetesLogParsers(t *testing.T) {
+	logtesting.RunTestsFromYAML(t, LogTypes(), "./testdata/kubernetes_tests.yml")
+}
+
+func TestObjectReferenceString(t *testing.T) {
+	assert := require.New(t)
+	assert.Equal("", (*ObjectReference)(nil).String())
+	assert.Equal("", (&ObjectReference{}).String())
+	assert.Equal("namespaces/kube-system", (&ObjectReference{
+		Resource: pantherlog.String{Value: "namespaces", Exists: true},
+		Name:     pantherlog.String{Value: "kube-system", Exists: true},
+	}).String())
+	assert.Equal("prod/deployments.apps", (&ObjectReference{
+		Resource:  pantherlog.String{Value: "deployments", Exists: true},
+		Namespace: pantherlog.String{Value: "prod", Exists: true},
+		APIGroup:  pantherlog.String{Value: "apps", Exists: true},
+	}).String())
+}
+
+// nolint:lll
+const testEKSAuditEnvelope = `{"messageType":"DATA_MESSAGE","owner":"123456789012","logGroup":"/aws/eks/prod/cluster","logStream":"kube-apiserver-audit-0123456789abcdef0123456789abcdef","subscriptionFilters":["panther"],"logEvents":[{"id":"35689263648391837472973739781728019701390240798247944192","timestamp":1601553600123,"message":"{\"kind\":\"Event\",\"apiVersion\":\"audit.k8s.io/v1\",\"level\":\"Metadata\",\"auditID\":\"4d2ea952-1b5d-4b4b-9a1d-8a1e3e1a8a0f\",\"stage\":\"ResponseComplete\",\"requestURI\":\"/api/v1/namespaces/default/pods/nginx/exec?command=sh&stdin=true&tty=true\",\"verb\":\"create\",\"user\":{\"username\":\"alice@example.com\",\"uid\":\"heptio-authenticator-aws:123456789012:AROAEXAMPLE\",\"groups\":[\"system:masters\",\"system:authenticated\"],\"extra\":{\"accessKeyId\":[\"AKIAEXAMPLE\"]}},\"sourceIPs\":[\"10.0.0.1\"],\"userAgent\":\"kubectl/v1.19.2 (darwin/amd64) kubernetes/f5743093\",\"objectRef\":{\"resource\":\"pods\",\"namespace\":\"default\",\"name\":\"nginx\",\"apiVersion\":\"v1\",\"subresource\":\"exec\"},\"responseStatus\":{\"metadata\":{},\"code\":101},\"requestReceivedTimestamp\":\"2020-10-01T12:00:00.123456Z\",\"stageTimestamp\":\"2020-10-01T12:00:05.654321Z\",\"annotations\":{\"authorization.k8s.io/decision\":\"allow\",\"authorization.k8s.io/reason\":\"\"}}"},{"id":"35689263648391837472973739781728019701390240798247944193","timestamp":1601553600133,"message":"{\"kind\":\"Event\",\"apiVersion\":\"audit.k8s.io/v1\",\"level\":\"Request\",\"auditID\":\"9a7c2f1e-0b3d-4c5e-8f6a-1b2c3d4e5f60\",\"stage\":\"ResponseComplete\",\"requestURI\":\"/apis/apps/v1/namespaces/prod/deployments/web\",\"verb\":\"delete\",\"user\":{\"username\":\"system:serviceaccount:ci:deployer\",\"groups\":[\"system:serviceaccounts\"]},\"impersonatedUser\":{\"username\":\"bob\"},\"sourceIPs\":[\"192.168.1.10\",\"10.0.0.2\"],\"objectRef\":{\"resource\":\"deployments\",\"namespace\":\"prod\",\"name\":\"web\",\"apiGroup\":\"apps\",\"apiVersion\":\"v1\"},\"responseStatus\":{\"metadata\":{},\"status\":\"Failure\",\"reason\":\"Forbidden\",\"code\":403},\"requestObject\":{\"kind\":\"DeleteOptions\",\"apiVersion\":\"v1\",\"propagationPolicy\":\"Foreground\"},\"requestReceivedTimestamp\":\"2020-10-01T12:00:00.123456Z\",\"stageTimestamp\":\"2020-10-01T12:00:00.133456Z\"}"}]}`
+
+// EKS audit logs are unwrapped from their CloudWatch Logs envelope before they are parsed
+func TestEKSAudit(t *testing.T) {
+	assert := require.New(t)
+	parser, err := LogTypes().Find(TypeAudit).NewParser(nil)
+	assert.NoError(err)
+	stream := logstream.NewCloudWatchLogsStream(strings.NewReader(testEKSAuditEnvelope), logstream.MinBufferSize)
+	var usernames []string
+	for entry := stream.Next(); entry != nil; entry = stream.Next() {
+		results, err := parser.ParseLog(string(entry))
+		assert.NoError(err)
+		assert.Len(results, 1)
+		assert.Equal(TypeAudit, results[0].PantherLogType)
+		usernames = append(usernames, results[0].Event.(*AuditEvent).User.Username.Value)
+	}
+	assert.NoError(stream.Err())
+	assert.Equal("/aws/eks/prod/cluster", stream.LogGroup())
+	assert.Equal([]string{"alice@example.com", "system:serviceaccount:ci:deployer"}, usernames)
+}
@@ -0,0 +1,113 @@
+package microsoft365logs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"net"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// AuditRecord is a record of the common schema of the Office 365 Management Activity API.
+// Workload specific properties of Azure Active Directory, Exchange and SharePoint records are also included.
+// nolint:lll,maligned
+type AuditRecord struct {
+	ID                            pantherlog.String     `json:"Id" validate:"required" description:"The unique identifier of the audit record."`
+	RecordType                    pantherlog.Int64      `json:"RecordType" validate:"required" description:"The type of operation indicated by the record."`
+	CreationTime                  pantherlog.Time       `json:"CreationTime" validate:"required" event_time:"true" tcodec:"layout=2006-01-02T15:04:05.999999999" description:"The date and time in Coordinated Universal Time (UTC) when the user performed the activity."`
+	Operation                     pantherlog.String     `json:"Operation" validate:"required" description:"The name of the user or admin activity."`
+	OrganizationID                pantherlog.String     `json:"OrganizationId" validate:"required" panther:"azure_tenant_id" description:"The GUID for your organization's Office 365 tenant."`
+	UserType                      pantherlog.Int64      `json:"UserType" description:"The type of user that performed the operation (0 Regular, 1 Reserved, 2 Admin, 3 DcAdmin, 4 System, 5 Application, 6 ServicePrincipal, 7 CustomPolicy, 8 SystemPolicy)."`
+	UserKey                       pantherlog.String     `json:"UserKey" description:"An alternative ID for the user identified in the UserId property."`
+	Workload                      pantherlog.String     `json:"Workload" description:"The Office 365 service where the activity occurred."`
+	ResultStatus                  pantherlog.String     `json:"ResultStatus" description:"Indicates whether the action (specified in the Operation property) was successful or not."`
+	ObjectID                      pantherlog.String     `json:"ObjectId" description:"For SharePoint and OneDrive activity, the full path name of the file or folder accessed by the user. For Exchange admin audit logging, the name of the object that was modified by the cmdlet."`
+	UserID                        pantherlog.String     `json:"UserId" panther:"email,username" description:"The UPN (User Principal Name) of the user who performed the action."`
+	ClientIP                      pantherlog.String     `json:"ClientIP" description:"The IP address of the device that was used when the activity was logged. The IP address is displayed in either an IPv4 or IPv6 address format."`
+	Scope                         pantherlog.Int64      `json:"Scope" description:"Whether the event was created by a hosted Office 365 service (0) or an on-premises server (1)."`
+	Version                       pantherlog.Int64      `json:"Version" description:"The version of the audit record schema."`
+	AzureActiveDirectoryEventType pantherlog.Int64      `json:"AzureActiveDirectoryEventType" description:"The type of Azure Active Directory event (0 AccountLogon, 1 AzureApplicationAuditEvent)."`
+	ExtendedProperties            []NameValue           `json:"ExtendedProperties" description:"The extended properties of the Azure AD event."`
+	ModifiedProperties            []ModifiedProperty    `json:"ModifiedProperties" description:"The properties modified by the activity."`
+	Actor                         []Identity            `json:"Actor" description:"The user and service principal that performed the action."`
+	ActorContextID                pantherlog.String     `json:"ActorContextId" panther:"azure_tenant_id" description:"The GUID of the organization that the actor belongs to."`
+	ActorIPAddress                pantherlog.String     `json:"ActorIpAddress" panther:"ip" description:"The IP address of the actor."`
+	InterSystemsID                pantherlog.String     `json:"InterSystemsId" panther:"trace_id" description:"The GUID that tracks the actions across components within the Office 365 service."`
+	IntraSystemID                 pantherlog.String     `json:"IntraSystemId" description:"The GUID generated by Azure Active Directory to track the action."`
+	SupportTicketID               pantherlog.String     `json:"SupportTicketId" description:"The customer support ticket ID for the action in 'act-on-behalf-of' situations."`
+	Target                        []Identity            `json:"Target" description:"The user that the action was performed on."`
+	TargetContextID               pantherlog.String     `json:"TargetContextId" panther:"azure_tenant_id" description:"The GUID of the organization that the targeted user belongs to."`
+	ApplicationID                 pantherlog.String     `json:"ApplicationId" description:"The ID of the application performing the operation."`
+	ErrorNumber                   pantherlog.String     `json:"ErrorNumber" description:"The error code of failed sign-ins."`
+	LogonError                    pantherlog.String     `json:"LogonError" description:"The reason of failed sign-ins."`
+	ClientIPAddress               pantherlog.String     `json:"ClientIPAddress" panther:"ip" description:"The IP address of the device that was used when the operation was logged in Exchange."`
+	ClientInfoString              pantherlog.String     `json:"ClientInfoString" description:"Information about the email client that was used to perform the operation."`
+	ExternalAccess                pantherlog.Bool       `json:"ExternalAccess" description:"Whether the Exchange cmdlet was run by a user in your organization, by Microsoft datacenter personnel or a datacenter service account, or by a delegated administrator."`
+	OriginatingServer             pantherlog.String     `json:"OriginatingServer" description:"The name of the server from which the Exchange cmdlet was executed."`
+	OrganizationName              pantherlog.String     `json:"OrganizationName" description:"The name of the tenant."`
+	Parameters                    []NameValue           `json:"Parameters" description:"The name and value for all parameters that were used with the Exchange cmdlet."`
+	LogonType                     pantherlog.Int64      `json:"LogonType" description:"The type of user who accessed the Exchange mailbox (0 Owner, 1 Admin, 2 Delegated, 3 Transport, 4 SystemService, 5 BestAccess, 6 DelegatedAdmin)."`
+	MailboxGUID                   pantherlog.String     `json:"MailboxGuid" description:"The Exchange GUID of the mailbox that was accessed."`
+	MailboxOwnerUPN               pantherlog.String     `json:"MailboxOwnerUPN" panther:"email,username" description:"The email address of the person who owns the mailbox that was accessed."`
+	Site                          pantherlog.String     `json:"Site" description:"The GUID of the SharePoint site where the file or folder accessed by the user is located."`
+	SiteURL                       pantherlog.String     `json:"SiteUrl" description:"The URL of the SharePoint site where the file or folder accessed by the user is located."`
+	ItemType                      pantherlog.String     `json:"ItemType" description:"The type of SharePoint object that was accessed or modified (File, Folder, Web, Site, Tenant, DocumentLibrary)."`
+	EventSource                   pantherlog.String     `json:"EventSource" description:"Whether the SharePoint event occurred in SharePoint (SharePoint) or ObjectModel."`
+	SourceFileName                pantherlog.String     `json:"SourceFileName" description:"The name of the file or folder accessed by the user."`
+	SourceFileExtension           pantherlog.String     `json:"SourceFileExtension" description:"The file extension of the file that was accessed by the user."`
+	SourceRelativeURL             pantherlog.String     `json:"SourceRelativeUrl" description:"The URL of the folder that contains the file accessed by the user."`
+	UserAgent                     pantherlog.String     `json:"UserAgent" description:"Information about the user's client or browser."`
+	DeviceDisplayName             pantherlog.String     `json:"DeviceDisplayName" description:"The name of the device of the SharePoint activity."`
+	ListID                        pantherlog.String     `json:"ListId" description:"The GUID of the SharePoint list."`
+	ListItemUniqueID              pantherlog.String     `json:"ListItemUniqueId" description:"The GUID of the SharePoint list item."`
+	TeamName                      pantherlog.String     `json:"TeamName" description:"The name of the team in Microsoft Teams."`
+	TeamGUID                      pantherlog.String     `json:"TeamGuid" description:"The unique identifier of the team in Microsoft Teams."`
+	Members                       pantherlog.RawMessage `json:"Members" description:"The users within the team in Microsoft Teams."`
+}
+
+var _ pantherlog.ValueWriterTo = (*AuditRecord)(nil)
+
+// WriteValuesTo implements pantherlog.ValueWriterTo interface
+func (r *AuditRecord) WriteValuesTo(w pantherlog.ValueWriter) {
+	// SharePoint records include the client port in the IP address
+	ip := r.ClientIP.Value
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	pantherlog.ScanIPAddress(w, ip)
+}
+
+// nolint:lll
+type NameValue struct {
+	Name  pantherlog.String `json:"Name" description:"The name of the property."`
+	Value pantherlog.String `json:"Value" description:"The value of the property."`
+}
+
+// nolint:lll
+type ModifiedProperty struct {
+	Name     pantherlog.String `json:"Name" description:"The name of the property."`
+	OldValue pantherlog.String `json:"OldValue" description:"The value of the property before the change."`
+	NewValue pantherlog.String `json:"NewValue" description:"The value of the property after the change."`
+}
+
+// nolint:lll
+type Identity struct {
+	ID   pantherlog.String `json:"ID" description:"The identity of the user or service principal."`
+	Type pantherlog.Int64  `json:"Type" description:"The type of the identity (0 Other, 1 PUID, 2 UPN, 3 SPN, 4 AzureAD object ID, 5 SID)."`
+}
@@ -0,0 +1,76 @@
+package microsoft365logs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+const TypeAudit = "Microsoft365.Audit"
+
+// LogTypes exports the available log type entries
+func LogTypes() logtypes.Group {
+	return logTypes
+}
+
+// nolint:lll
+var logTypes = logtypes.Must("Microsoft365", logtypes.Config{
+	Name: TypeAudit,
+	Description: `Audit records of user, admin, system and policy actions in Microsoft 365 workloads (Azure Active Directory, Exchange, SharePoint, OneDrive, Teams).
+Records are read from the content blobs of the Office 365 Management Activity API or one record per line.`,
+	ReferenceURL: `https://docs.microsoft.com/en-us/office/office-365-management-api/office-365-management-activity-api-schema`,
+	// The client IP address is not tagged in the struct but added by AuditRecord.WriteValuesTo
+	Schema:    pantherlog.MustBuildEventSchema(&AuditRecord{}, pantherlog.FieldIPAddress),
+	NewParser: pantherlog.FactoryFunc(newAuditParser),
+})
+
+type auditParser struct {
+	builder pantherlog.ResultBuilder
+}
+
+func newAuditParser(_ interface{}) (pantherlog.LogParser, error) {
+	return &auditParser{}, nil
+}
+
+// ParseLog implements pantherlog.LogParser interface.
+// The content blobs of the Management Activity API are JSON arrays of audit records.
+func (p *auditParser) ParseLog(log string) ([]*pantherlog.Result, error) {
+	if !strings.HasPrefix(strings.TrimSpace(log), "[") {
+		return p.builder.ParseRecords(TypeAudit, []string{log}, newAuditRecord)
+	}
+	var content []jsoniter.RawMessage
+	if err := pantherlog.ConfigJSON().UnmarshalFromString(log, &content); err != nil {
+		return nil, errors.Wrap(err, "failed to read Management Activity API content")
+	}
+	records := make([]string, len(content))
+	for i, record := range content {
+		records[i] = string(record)
+	}
+	return p.builder.ParseRecords(TypeAudit, records, newAuditRecord)
+}
+
+func newAuditRecord() interface{} {
+	return &AuditRecord{}
+}
@@ -0,0 +1,43 @@
+package microsoft365logs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes/logtesting"
+)
+
+func TestMicrosoft365LogParsers(t *testing.T) {
+	logtesting.RunTestsFromYAML(t, LogTypes(), "./testdata/microsoft365_tests.yml")
+}
+
+func TestInvalidContent(t *testing.T) {
+	parser, err := LogTypes().Find(TypeAudit).NewParser(nil)
+	require.NoError(t, err)
+	// Every record of the content needs to be valid
+	results, err := parser.ParseLog(`[{"Id":"1f0e9d8c-7b6a-4594-8372-615049382716","Operation":"FileDownloaded"}]`)
+	require.Error(t, err)
+	require.Nil(t, results)
+	results, err = parser.ParseLog(`[{"Id":`)
+	require.Error(t, err)
+	require.Nil(t, results)
+}
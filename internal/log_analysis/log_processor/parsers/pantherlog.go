@@ -22,6 +22,7 @@ import (
 	"net"
 	"regexp"
 	"sort"
+	"strconv"
 	"time"
 
 	jsoniter "github.com/json-iterator/go"
@@ -64,6 +65,16 @@ type PantherLog struct {
 	PantherAnySHA1Hashes   PantherAnyString `json:"p_any_sha1_hashes,omitempty" description:"Panther added field with collection of SHA1 hashes associated with the row"`
 	PantherAnyMD5Hashes    PantherAnyString `json:"p_any_md5_hashes,omitempty" description:"Panther added field with collection of MD5 hashes associated with the row"`
 	PantherAnySHA256Hashes PantherAnyString `json:"p_any_sha256_hashes,omitempty" description:"Panther added field with collection of SHA256 hashes of any algorithm associated with the row"`
+
+	// enrichment
+	PantherAnyCountries  PantherAnyString              `json:"p_any_countries,omitempty" description:"Panther added field with collection of the countries of the ip addresses associated with the row"`
+	PantherAnyASNs       PantherAnyString              `json:"p_any_asns,omitempty" description:"Panther added field with collection of the autonomous system numbers of the ip addresses associated with the row"`
+	PantherEnrichmentGeo map[string]pantherlog.GeoInfo `json:"p_enrichment_geo,omitempty" description:"Panther added field with the geolocation of the ip addresses associated with the row"`
+
+	PantherThreatIntelMatches map[string][]string `json:"p_threat_intel_matches,omitempty" description:"Panther added field with the indicator values associated with the row found in each threat intel lookup table"`
+
+	PantherLogGroup  *string `json:"p_log_group,omitempty" description:"Panther added field with the CloudWatch Logs log group of events delivered by a subscription"`
+	PantherLogStream *string `json:"p_log_stream,omitempty" description:"Panther added field with the CloudWatch Logs log stream of events delivered by a subscription"`
 }
 
 type PantherAnyString []string
@@ -111,6 +122,49 @@ func (pl *PantherLog) SetCoreFields(logType string, eventTime *timestamp.RFC3339
 	pl.PantherParseTime = &parseTime
 }
 
+var _ pantherlog.GeoEnricher = (*PantherLog)(nil)
+
+// EnrichGeo implements pantherlog.GeoEnricher interface.
+// The IP addresses of legacy events are collected when they are parsed so they can be enriched before serialization.
+func (pl *PantherLog) EnrichGeo(r pantherlog.GeoResolver) {
+	for _, ip := range pl.PantherAnyIPAddresses {
+		info := r.ResolveGeo(ip)
+		if info == nil {
+			continue
+		}
+		if pl.PantherEnrichmentGeo == nil {
+			pl.PantherEnrichmentGeo = make(map[string]pantherlog.GeoInfo)
+		}
+		pl.PantherEnrichmentGeo[ip] = *info
+		if info.Country != "" {
+			pl.PantherAnyCountries = stringset.Append(pl.PantherAnyCountries, info.Country)
+		}
+		if info.ASN != 0 {
+			pl.PantherAnyASNs = stringset.Append(pl.PantherAnyASNs, strconv.FormatUint(uint64(info.ASN), 10))
+		}
+	}
+}
+
+var _ pantherlog.ThreatIntelEnricher = (*PantherLog)(nil)
+
+// EnrichThreatIntel implements pantherlog.ThreatIntelEnricher interface.
+func (pl *PantherLog) EnrichThreatIntel(m pantherlog.ThreatIntelMatcher) {
+	for _, indicators := range []struct {
+		id     pantherlog.FieldID
+		values PantherAnyString
+	}{
+		{pantherlog.FieldIPAddress, pl.PantherAnyIPAddresses},
+		{pantherlog.FieldDomainName, pl.PantherAnyDomainNames},
+		{pantherlog.FieldSHA1Hash, pl.PantherAnySHA1Hashes},
+		{pantherlog.FieldMD5Hash, pl.PantherAnyMD5Hashes},
+		{pantherlog.FieldSHA256Hash, pl.PantherAnySHA256Hashes},
+	} {
+		for _, value := range indicators.values {
+			pl.PantherThreatIntelMatches = pantherlog.AppendThreatIntelMatches(pl.PantherThreatIntelMatches, indicators.id, value, m)
+		}
+	}
+}
+
 type PantherSourceSetter interface {
 	SetPantherSource(id, label string)
 }
@@ -122,6 +176,17 @@ func (pl *PantherLog) SetPantherSource(id, label string) {
 	pl.PantherSourceID = box.NonEmpty(id)
 }
 
+type PantherLogGroupSetter interface {
+	SetPantherLogGroup(group, stream string)
+}
+
+var _ PantherLogGroupSetter = (*PantherLog)(nil)
+
+func (pl *PantherLog) SetPantherLogGroup(group, stream string) {
+	pl.PantherLogGroup = box.NonEmpty(group)
+	pl.PantherLogStream = box.NonEmpty(stream)
+}
+
 // AppendAnyIPAddressPtr returns true if the IP address was successfully appended,
 // otherwise false if the value was not an IP
 func (pl *PantherLog) AppendAnyIPAddressPtr(value *string) bool {
@@ -243,6 +308,8 @@ func (pl *PantherLog) Result() *Result {
 			PantherEventTime:   ((*time.Time)(eventTime)).UTC(),
 			PantherSourceID:    unbox.String(pl.PantherSourceID),
 			PantherSourceLabel: unbox.String(pl.PantherSourceLabel),
+			PantherLogGroup:    unbox.String(pl.PantherLogGroup),
+			PantherLogStream:   unbox.String(pl.PantherLogStream),
 		},
 	}
 }
@@ -26,6 +26,7 @@ import (
 	jsoniter "github.com/json-iterator/go"
 	"github.com/stretchr/testify/require"
 
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
 )
 
@@ -236,3 +237,27 @@ func TestAppendAnyMD5Hashes(t *testing.T) {
 	event.AppendAnyMD5HashPtrs(&value)
 	require.Equal(t, expectedAny, event.PantherAnyMD5Hashes)
 }
+
+type testThreatIntel map[string]string
+
+func (m testThreatIntel) MatchThreatIntel(_ pantherlog.FieldID, value string) []string {
+	if table, ok := m[value]; ok {
+		return []string{table}
+	}
+	return nil
+}
+
+func TestEnrichThreatIntel(t *testing.T) {
+	pl := PantherLog{}
+	pl.AppendAnyIPAddress("1.2.3.4")
+	pl.AppendAnyIPAddress("5.6.7.8")
+	pl.AppendAnyDomainNames("evil.example.com")
+	pl.EnrichThreatIntel(testThreatIntel{
+		"1.2.3.4":          "scanners",
+		"evil.example.com": "c2",
+	})
+	require.Equal(t, map[string][]string{
+		"scanners": {"1.2.3.4"},
+		"c2":       {"evil.example.com"},
+	}, pl.PantherThreatIntelMatches)
+}
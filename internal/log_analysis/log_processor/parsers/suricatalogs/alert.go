@@ -0,0 +1,69 @@
+package suricatalogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// nolint:lll,maligned
+type Alert struct {
+	EventType pantherlog.String `json:"event_type" validate:"required,eq=alert" description:"The type of the EVE event (alert)"`
+	EventFields
+	FlowFields
+	Alert            *AlertDetails         `json:"alert" validate:"required" description:"The rule that triggered the alert"`
+	Payload          pantherlog.String     `json:"payload" description:"The base64 encoded payload of the packet that triggered the alert"`
+	PayloadPrintable pantherlog.String     `json:"payload_printable" description:"The printable characters of the payload of the packet that triggered the alert"`
+	Packet           pantherlog.String     `json:"packet" description:"The base64 encoded packet that triggered the alert"`
+	PacketInfo       *PacketInfo           `json:"packet_info" description:"Information about the packet that triggered the alert"`
+	Stream           pantherlog.Int64      `json:"stream" description:"Set to 1 if the alert was triggered by a reassembled stream"`
+	Flow             *FlowStats            `json:"flow" description:"The state of the flow when the alert was triggered"`
+	HTTP             *HTTPDetails          `json:"http" description:"The HTTP transaction of the alert, if the application protocol is HTTP"`
+	TLS              *TLSDetails           `json:"tls" description:"The TLS handshake of the alert, if the application protocol is TLS"`
+	SMTP             *SMTPDetails          `json:"smtp" description:"The SMTP transaction of the alert, if the application protocol is SMTP"`
+	SSH              *SSHDetails           `json:"ssh" description:"The SSH handshake of the alert, if the application protocol is SSH"`
+	DNS              pantherlog.RawMessage `json:"dns" description:"The DNS transaction of the alert, if the application protocol is DNS"`
+	Files            []FileInfoDetails     `json:"files" description:"The files transferred in the transaction of the alert"`
+}
+
+// nolint:lll
+type AlertDetails struct {
+	Action      pantherlog.String     `json:"action" description:"The action taken by the rule (allowed or blocked)"`
+	GID         pantherlog.Int64      `json:"gid" description:"The group ID of the rule"`
+	SignatureID pantherlog.Int64      `json:"signature_id" validate:"required" description:"The signature ID of the rule"`
+	Rev         pantherlog.Int64      `json:"rev" description:"The revision of the rule"`
+	Signature   pantherlog.String     `json:"signature" description:"The message of the rule"`
+	Category    pantherlog.String     `json:"category" description:"The classification of the rule"`
+	Severity    pantherlog.Int64      `json:"severity" description:"The priority of the rule (1 is the highest)"`
+	Tenant      pantherlog.Int64      `json:"tenant_id" description:"The tenant ID, if multi tenancy is enabled"`
+	Metadata    pantherlog.RawMessage `json:"metadata" description:"The metadata keywords of the rule"`
+	Source      *AlertEndpoint        `json:"source" description:"The source of the attack, if the rule has a target keyword"`
+	Target      *AlertEndpoint        `json:"target" description:"The target of the attack, if the rule has a target keyword"`
+}
+
+// nolint:lll
+type AlertEndpoint struct {
+	IP   pantherlog.String `json:"ip" panther:"ip" description:"The IP address"`
+	Port pantherlog.Uint16 `json:"port" description:"The port"`
+}
+
+// nolint:lll
+type PacketInfo struct {
+	Linktype pantherlog.Int64 `json:"linktype" description:"The link type of the packet"`
+}
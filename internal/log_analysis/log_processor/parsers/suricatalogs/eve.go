@@ -0,0 +1,52 @@
+package suricatalogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// EventFields are the fields that Suricata writes in all EVE events
+// nolint:lll
+type EventFields struct {
+	Timestamp pantherlog.Time   `json:"timestamp" validate:"required" event_time:"true" tcodec:"layout=2006-01-02T15:04:05.999999999Z0700" description:"The time of the event"`
+	Host      pantherlog.String `json:"host" panther:"hostname" description:"The name of the sensor, if sensor-name is set in the Suricata configuration"`
+}
+
+// FlowFields are the fields that identify the flow of the packets that triggered an EVE event
+// nolint:lll
+type FlowFields struct {
+	FlowID       pantherlog.Int64      `json:"flow_id" description:"The ID of the flow, used to correlate the events of the same flow"`
+	ParentID     pantherlog.Int64      `json:"parent_id" description:"The flow ID of the parent flow for flows that are related to another flow (i.e. FTP data)"`
+	PcapCnt      pantherlog.Int64      `json:"pcap_cnt" description:"The number of the packet in the pcap file or capture"`
+	PcapFilename pantherlog.String     `json:"pcap_filename" description:"The pcap file the packet was read from"`
+	InIface      pantherlog.String     `json:"in_iface" description:"The network interface the packet was captured on"`
+	VLAN         []pantherlog.Int64    `json:"vlan" description:"The VLAN IDs of the packet"`
+	SrcIP        pantherlog.String     `json:"src_ip" panther:"ip" description:"The source IP address"`
+	SrcPort      pantherlog.Uint16     `json:"src_port" description:"The source port"`
+	DestIP       pantherlog.String     `json:"dest_ip" panther:"ip" description:"The destination IP address"`
+	DestPort     pantherlog.Uint16     `json:"dest_port" description:"The destination port"`
+	Proto        pantherlog.String     `json:"proto" description:"The transport protocol (i.e. TCP, UDP, ICMP) or the protocol number if unknown"`
+	AppProto     pantherlog.String     `json:"app_proto" description:"The application protocol detected on the flow"`
+	CommunityID  pantherlog.String     `json:"community_id" description:"The Community ID flow hash, if enabled in the Suricata configuration"`
+	TxID         pantherlog.Int64      `json:"tx_id" description:"The ID of the application layer transaction of the event"`
+	ICMPType     pantherlog.Int64      `json:"icmp_type" description:"The ICMP type"`
+	ICMPCode     pantherlog.Int64      `json:"icmp_code" description:"The ICMP code"`
+	Metadata     pantherlog.RawMessage `json:"metadata" description:"Flowbits, flowints and pktvars set on the flow, if metadata logging is enabled"`
+}
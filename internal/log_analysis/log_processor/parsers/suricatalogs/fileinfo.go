@@ -0,0 +1,51 @@
+package suricatalogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// nolint:lll,maligned
+type FileInfo struct {
+	EventType pantherlog.String `json:"event_type" validate:"required,eq=fileinfo" description:"The type of the EVE event (fileinfo)"`
+	EventFields
+	FlowFields
+	FileInfo *FileInfoDetails `json:"fileinfo" validate:"required" description:"The file transferred"`
+	HTTP     *HTTPDetails     `json:"http" description:"The HTTP transaction of the file, if the application protocol is HTTP"`
+	SMTP     *SMTPDetails     `json:"smtp" description:"The SMTP transaction of the file, if the application protocol is SMTP"`
+	Email    *EmailDetails    `json:"email" description:"The email of the file, if the application protocol is SMTP"`
+}
+
+// nolint:lll,maligned
+type FileInfoDetails struct {
+	Filename pantherlog.String `json:"filename" description:"The name of the file"`
+	Magic    pantherlog.String `json:"magic" description:"The libmagic description of the file type"`
+	Gaps     pantherlog.Bool   `json:"gaps" description:"Whether parts of the file are missing"`
+	State    pantherlog.String `json:"state" description:"The state of the file transfer (CLOSED, TRUNCATED or ERROR)"`
+	MD5      pantherlog.String `json:"md5" panther:"md5" description:"The MD5 hash of the file, if enabled"`
+	SHA1     pantherlog.String `json:"sha1" panther:"sha1" description:"The SHA1 hash of the file, if enabled"`
+	SHA256   pantherlog.String `json:"sha256" panther:"sha256" description:"The SHA256 hash of the file, if enabled"`
+	Stored   pantherlog.Bool   `json:"stored" description:"Whether the file was stored to disk"`
+	FileID   pantherlog.Int64  `json:"file_id" description:"The ID of the stored file"`
+	Size     pantherlog.Int64  `json:"size" description:"The size of the file in bytes"`
+	TxID     pantherlog.Int64  `json:"tx_id" description:"The ID of the transaction that transferred the file"`
+	Start    pantherlog.Int64  `json:"start" description:"The offset of the start of the file, for partial content transfers"`
+	End      pantherlog.Int64  `json:"end" description:"The offset of the end of the file, for partial content transfers"`
+}
@@ -0,0 +1,65 @@
+package suricatalogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// nolint:lll,maligned
+type Flow struct {
+	EventType pantherlog.String `json:"event_type" validate:"required,eq=flow" description:"The type of the EVE event (flow)"`
+	EventFields
+	FlowFields
+	Flow *FlowStats `json:"flow" validate:"required" description:"The flow statistics"`
+	TCP  *TCPFlags  `json:"tcp" description:"The TCP flags seen on the flow"`
+}
+
+// FlowStats are the statistics of a flow in both directions
+// nolint:lll
+type FlowStats struct {
+	PktsToServer  pantherlog.Int64  `json:"pkts_toserver" description:"The number of packets sent to the server"`
+	PktsToClient  pantherlog.Int64  `json:"pkts_toclient" description:"The number of packets sent to the client"`
+	BytesToServer pantherlog.Int64  `json:"bytes_toserver" description:"The number of bytes sent to the server"`
+	BytesToClient pantherlog.Int64  `json:"bytes_toclient" description:"The number of bytes sent to the client"`
+	Start         pantherlog.Time   `json:"start" tcodec:"layout=2006-01-02T15:04:05.999999999Z0700" description:"The time of the first packet of the flow"`
+	End           pantherlog.Time   `json:"end" tcodec:"layout=2006-01-02T15:04:05.999999999Z0700" description:"The time of the last packet of the flow"`
+	Age           pantherlog.Int64  `json:"age" description:"The duration of the flow in seconds"`
+	State         pantherlog.String `json:"state" description:"The state of the flow (new, established, closed or bypassed)"`
+	Reason        pantherlog.String `json:"reason" description:"The reason the flow was logged (timeout, forced or shutdown)"`
+	Alerted       pantherlog.Bool   `json:"alerted" description:"Whether an alert was triggered on the flow"`
+	Bypass        pantherlog.String `json:"bypass" description:"The bypass state of the flow"`
+}
+
+// TCPFlags are the TCP flags of a flow
+// nolint:lll
+type TCPFlags struct {
+	TCPFlags   pantherlog.String `json:"tcp_flags" description:"The hex encoded TCP flags seen in both directions"`
+	TCPFlagsTS pantherlog.String `json:"tcp_flags_ts" description:"The hex encoded TCP flags seen to the server"`
+	TCPFlagsTC pantherlog.String `json:"tcp_flags_tc" description:"The hex encoded TCP flags seen to the client"`
+	SYN        pantherlog.Bool   `json:"syn" description:"A SYN flag was seen"`
+	FIN        pantherlog.Bool   `json:"fin" description:"A FIN flag was seen"`
+	RST        pantherlog.Bool   `json:"rst" description:"A RST flag was seen"`
+	PSH        pantherlog.Bool   `json:"psh" description:"A PSH flag was seen"`
+	ACK        pantherlog.Bool   `json:"ack" description:"An ACK flag was seen"`
+	URG        pantherlog.Bool   `json:"urg" description:"An URG flag was seen"`
+	ECN        pantherlog.Bool   `json:"ecn" description:"An ECN flag was seen"`
+	CWR        pantherlog.Bool   `json:"cwr" description:"A CWR flag was seen"`
+	State      pantherlog.String `json:"state" description:"The state of the TCP session"`
+}
@@ -0,0 +1,55 @@
+package suricatalogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// nolint:lll,maligned
+type HTTP struct {
+	EventType pantherlog.String `json:"event_type" validate:"required,eq=http" description:"The type of the EVE event (http)"`
+	EventFields
+	FlowFields
+	HTTP *HTTPDetails `json:"http" validate:"required" description:"The HTTP transaction"`
+}
+
+// nolint:lll,maligned
+type HTTPDetails struct {
+	Hostname        pantherlog.String `json:"hostname" panther:"hostname" description:"The hostname of the request"`
+	HTTPPort        pantherlog.Uint16 `json:"http_port" description:"The port of the Host header, if it is not the default"`
+	URL             pantherlog.String `json:"url" description:"The URL of the request"`
+	HTTPUserAgent   pantherlog.String `json:"http_user_agent" description:"The User-Agent header of the request"`
+	HTTPContentType pantherlog.String `json:"http_content_type" description:"The Content-Type header of the response"`
+	HTTPRefer       pantherlog.String `json:"http_refer" description:"The Referer header of the request"`
+	HTTPMethod      pantherlog.String `json:"http_method" description:"The method of the request"`
+	Protocol        pantherlog.String `json:"protocol" description:"The protocol version of the request"`
+	Status          pantherlog.Int64  `json:"status" description:"The status code of the response"`
+	Redirect        pantherlog.String `json:"redirect" description:"The Location header of a redirect response"`
+	Length          pantherlog.Int64  `json:"length" description:"The size of the response body"`
+	XForwardedFor   pantherlog.String `json:"xff" description:"The X-Forwarded-For header of the request"`
+	RequestHeaders  []HTTPHeader      `json:"request_headers" description:"The headers of the request, if extended logging of all headers is enabled"`
+	ResponseHeaders []HTTPHeader      `json:"response_headers" description:"The headers of the response, if extended logging of all headers is enabled"`
+}
+
+// nolint:lll
+type HTTPHeader struct {
+	Name  pantherlog.String `json:"name" description:"The header name"`
+	Value pantherlog.String `json:"value" description:"The header value"`
+}
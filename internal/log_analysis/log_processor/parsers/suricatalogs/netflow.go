@@ -0,0 +1,44 @@
+package suricatalogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// nolint:lll,maligned
+type NetFlow struct {
+	EventType pantherlog.String `json:"event_type" validate:"required,eq=netflow" description:"The type of the EVE event (netflow)"`
+	EventFields
+	FlowFields
+	NetFlow *NetFlowStats `json:"netflow" validate:"required" description:"The statistics of one direction of the flow"`
+	TCP     *TCPFlags     `json:"tcp" description:"The TCP flags seen in this direction of the flow"`
+}
+
+// NetFlowStats are the statistics of a flow in a single direction
+// nolint:lll
+type NetFlowStats struct {
+	Pkts   pantherlog.Int64 `json:"pkts" description:"The number of packets"`
+	Bytes  pantherlog.Int64 `json:"bytes" description:"The number of bytes"`
+	Start  pantherlog.Time  `json:"start" tcodec:"layout=2006-01-02T15:04:05.999999999Z0700" description:"The time of the first packet"`
+	End    pantherlog.Time  `json:"end" tcodec:"layout=2006-01-02T15:04:05.999999999Z0700" description:"The time of the last packet"`
+	Age    pantherlog.Int64 `json:"age" description:"The duration in seconds"`
+	MinTTL pantherlog.Int64 `json:"min_ttl" description:"The minimum TTL of the packets"`
+	MaxTTL pantherlog.Int64 `json:"max_ttl" description:"The maximum TTL of the packets"`
+}
@@ -0,0 +1,52 @@
+package suricatalogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// nolint:lll,maligned
+type SMTP struct {
+	EventType pantherlog.String `json:"event_type" validate:"required,eq=smtp" description:"The type of the EVE event (smtp)"`
+	EventFields
+	FlowFields
+	SMTP  *SMTPDetails  `json:"smtp" validate:"required" description:"The SMTP transaction"`
+	Email *EmailDetails `json:"email" description:"The email of the transaction"`
+}
+
+// nolint:lll
+type SMTPDetails struct {
+	Helo     pantherlog.String   `json:"helo" panther:"hostname" description:"The hostname of the client in the HELO command"`
+	MailFrom pantherlog.String   `json:"mail_from" description:"The sender of the MAIL FROM command"`
+	RcptTo   []pantherlog.String `json:"rcpt_to" description:"The recipients of the RCPT TO commands"`
+}
+
+// nolint:lll
+type EmailDetails struct {
+	Status      pantherlog.String   `json:"status" description:"The status of the email parsing"`
+	From        pantherlog.String   `json:"from" description:"The From header of the email"`
+	To          []pantherlog.String `json:"to" description:"The To header of the email"`
+	Cc          []pantherlog.String `json:"cc" description:"The Cc header of the email"`
+	Subject     pantherlog.String   `json:"subject" description:"The subject of the email, if enabled"`
+	MessageID   pantherlog.String   `json:"message_id" description:"The Message-ID header of the email, if enabled"`
+	XMailer     pantherlog.String   `json:"x_mailer" description:"The X-Mailer header of the email, if enabled"`
+	Attachments []pantherlog.String `json:"attachment" description:"The file names of the email attachments"`
+	URL         []pantherlog.String `json:"url" panther:"url" description:"The URLs found in the email body"`
+}
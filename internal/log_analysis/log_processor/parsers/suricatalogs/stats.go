@@ -0,0 +1,50 @@
+package suricatalogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// nolint:lll,maligned
+type Stats struct {
+	EventType pantherlog.String `json:"event_type" validate:"required,eq=stats" description:"The type of the EVE event (stats)"`
+	EventFields
+	Stats *StatsDetails `json:"stats" validate:"required" description:"The engine statistics"`
+}
+
+// StatsDetails are the counters of the Suricata engine.
+// The counters of each section depend on the version and configuration of Suricata.
+// nolint:lll
+type StatsDetails struct {
+	Uptime    pantherlog.Int64      `json:"uptime" description:"The uptime of the engine in seconds"`
+	Capture   pantherlog.RawMessage `json:"capture" description:"Packet capture counters"`
+	Decoder   pantherlog.RawMessage `json:"decoder" description:"Packet decoder counters"`
+	Flow      pantherlog.RawMessage `json:"flow" description:"Flow engine counters"`
+	Defrag    pantherlog.RawMessage `json:"defrag" description:"IP defragmentation counters"`
+	TCP       pantherlog.RawMessage `json:"tcp" description:"TCP stream engine counters"`
+	Detect    pantherlog.RawMessage `json:"detect" description:"Detection engine counters"`
+	AppLayer  pantherlog.RawMessage `json:"app_layer" description:"Application layer counters"`
+	FlowMgr   pantherlog.RawMessage `json:"flow_mgr" description:"Flow manager counters"`
+	FileStore pantherlog.RawMessage `json:"file_store" description:"File store counters"`
+	HTTP      pantherlog.RawMessage `json:"http" description:"HTTP parser counters"`
+	FTP       pantherlog.RawMessage `json:"ftp" description:"FTP parser counters"`
+	DNS       pantherlog.RawMessage `json:"dns" description:"DNS parser counters"`
+	Threads   pantherlog.RawMessage `json:"threads" description:"Per thread counters, if enabled"`
+}
@@ -19,15 +19,32 @@ package suricatalogs
  */
 
 import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/classification"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
 )
 
 const (
-	TypeDNS     = "Suricata.DNS"
-	TypeAnomaly = "Suricata.Anomaly"
+	TypeDNS      = "Suricata.DNS"
+	TypeAnomaly  = "Suricata.Anomaly"
+	TypeAlert    = "Suricata.Alert"
+	TypeFlow     = "Suricata.Flow"
+	TypeNetFlow  = "Suricata.NetFlow"
+	TypeHTTP     = "Suricata.HTTP"
+	TypeTLS      = "Suricata.TLS"
+	TypeFileInfo = "Suricata.FileInfo"
+	TypeSMTP     = "Suricata.SMTP"
+	TypeSSH      = "Suricata.SSH"
+	TypeDHCP     = "Suricata.DHCP"
+	TypeStats    = "Suricata.Stats"
 )
 
+// EventTypeField is the field of EVE JSON events with the event type.
+// Parsers of all Suricata log types are routed by the value of this field so that a single EVE JSON stream
+// can be mapped to all Suricata log types.
+const EventTypeField = "event_type"
+
 func LogTypes() logtypes.Group {
 	return logTypes
 }
@@ -38,13 +55,176 @@ var logTypes = logtypes.Must("Suricata",
 		Description:  `Suricata parser for the Anomaly event type in the EVE JSON output.`,
 		ReferenceURL: `https://suricata.readthedocs.io/en/suricata-5.0.2/output/eve/eve-json-output.html#anomaly`,
 		Schema:       Anomaly{},
-		NewParser:    parsers.AdapterFactory(&AnomalyParser{}),
+		NewParser:    routedParserFactory{"anomaly", parsers.AdapterFactory(&AnomalyParser{})},
 	},
 	logtypes.Config{
 		Name:         TypeDNS,
 		Description:  `Suricata parser for the DNS event type in the EVE JSON output.`,
 		ReferenceURL: `https://suricata.readthedocs.io/en/suricata-5.0.2/output/eve/eve-json-output.html#dns`,
 		Schema:       DNS{},
-		NewParser:    parsers.AdapterFactory(&DNSParser{}),
+		NewParser:    routedParserFactory{"dns", parsers.AdapterFactory(&DNSParser{})},
+	},
+	eveConfig{
+		EventType: "alert",
+		ConfigJSON: logtypes.ConfigJSON{
+			Name:         TypeAlert,
+			Description:  `Suricata alerts triggered by signature matches in the EVE JSON output.`,
+			ReferenceURL: `https://suricata.readthedocs.io/en/suricata-5.0.2/output/eve/eve-json-format.html#event-type-alert`,
+			NewEvent: func() interface{} {
+				return &Alert{}
+			},
+		},
+	},
+	eveConfig{
+		EventType: "flow",
+		ConfigJSON: logtypes.ConfigJSON{
+			Name:         TypeFlow,
+			Description:  `Suricata bidirectional flow records in the EVE JSON output.`,
+			ReferenceURL: `https://suricata.readthedocs.io/en/suricata-5.0.2/output/eve/eve-json-format.html#event-type-flow`,
+			NewEvent: func() interface{} {
+				return &Flow{}
+			},
+		},
+	},
+	eveConfig{
+		EventType: "netflow",
+		ConfigJSON: logtypes.ConfigJSON{
+			Name:         TypeNetFlow,
+			Description:  `Suricata unidirectional flow records in the EVE JSON output.`,
+			ReferenceURL: `https://suricata.readthedocs.io/en/suricata-5.0.2/output/eve/eve-json-format.html#event-type-netflow`,
+			NewEvent: func() interface{} {
+				return &NetFlow{}
+			},
+		},
+	},
+	eveConfig{
+		EventType: "http",
+		ConfigJSON: logtypes.ConfigJSON{
+			Name:         TypeHTTP,
+			Description:  `Suricata HTTP transactions in the EVE JSON output.`,
+			ReferenceURL: `https://suricata.readthedocs.io/en/suricata-5.0.2/output/eve/eve-json-format.html#event-type-http`,
+			NewEvent: func() interface{} {
+				return &HTTP{}
+			},
+		},
+	},
+	eveConfig{
+		EventType: "tls",
+		ConfigJSON: logtypes.ConfigJSON{
+			Name:         TypeTLS,
+			Description:  `Suricata TLS handshakes in the EVE JSON output.`,
+			ReferenceURL: `https://suricata.readthedocs.io/en/suricata-5.0.2/output/eve/eve-json-format.html#event-type-tls`,
+			NewEvent: func() interface{} {
+				return &TLS{}
+			},
+		},
+	},
+	eveConfig{
+		EventType: "fileinfo",
+		ConfigJSON: logtypes.ConfigJSON{
+			Name:         TypeFileInfo,
+			Description:  `Suricata files extracted from application layer transfers in the EVE JSON output.`,
+			ReferenceURL: `https://suricata.readthedocs.io/en/suricata-5.0.2/output/eve/eve-json-format.html#event-type-fileinfo`,
+			NewEvent: func() interface{} {
+				return &FileInfo{}
+			},
+		},
+	},
+	eveConfig{
+		EventType: "smtp",
+		ConfigJSON: logtypes.ConfigJSON{
+			Name:         TypeSMTP,
+			Description:  `Suricata SMTP transactions in the EVE JSON output.`,
+			ReferenceURL: `https://suricata.readthedocs.io/en/suricata-5.0.2/output/eve/eve-json-format.html#event-type-smtp`,
+			NewEvent: func() interface{} {
+				return &SMTP{}
+			},
+		},
+	},
+	eveConfig{
+		EventType: "ssh",
+		ConfigJSON: logtypes.ConfigJSON{
+			Name:         TypeSSH,
+			Description:  `Suricata SSH handshakes in the EVE JSON output.`,
+			ReferenceURL: `https://suricata.readthedocs.io/en/suricata-5.0.2/output/eve/eve-json-format.html#event-type-ssh`,
+			NewEvent: func() interface{} {
+				return &SSH{}
+			},
+		},
+	},
+	eveConfig{
+		EventType: "dhcp",
+		ConfigJSON: logtypes.ConfigJSON{
+			Name:         TypeDHCP,
+			Description:  `Suricata DHCP messages in the EVE JSON output.`,
+			ReferenceURL: `https://suricata.readthedocs.io/en/suricata-5.0.2/output/eve/eve-json-format.html#event-type-dhcp`,
+			NewEvent: func() interface{} {
+				return &DHCP{}
+			},
+		},
+	},
+	eveConfig{
+		EventType: "stats",
+		ConfigJSON: logtypes.ConfigJSON{
+			Name:         TypeStats,
+			Description:  `Suricata engine statistics in the EVE JSON output.`,
+			ReferenceURL: `https://suricata.readthedocs.io/en/suricata-5.0.2/output/eve/eve-json-format.html#event-type-stats`,
+			NewEvent: func() interface{} {
+				return &Stats{}
+			},
+		},
 	},
 )
+
+// eveConfig builds a log type entry for an EVE JSON event type
+type eveConfig struct {
+	logtypes.ConfigJSON
+	EventType string
+}
+
+// BuildEntry implements logtypes.EntryBuilder interface
+func (c eveConfig) BuildEntry() (logtypes.Entry, error) {
+	entry, err := c.ConfigJSON.BuildEntry()
+	if err != nil {
+		return nil, err
+	}
+	config := logtypes.Config{
+		Name:         c.Name,
+		Description:  c.Description,
+		ReferenceURL: c.ReferenceURL,
+		Schema:       entry.Schema(),
+		NewParser:    routedParserFactory{c.EventType, entry},
+	}
+	return config.BuildEntry()
+}
+
+// routedParserFactory creates parsers that are routed by the event type of EVE JSON events
+type routedParserFactory struct {
+	eventType string
+	pantherlog.LogParserFactory
+}
+
+// NewParser implements pantherlog.LogParserFactory interface
+func (f routedParserFactory) NewParser(params interface{}) (pantherlog.LogParser, error) {
+	p, err := f.LogParserFactory.NewParser(params)
+	if err != nil {
+		return nil, err
+	}
+	return &routedParser{
+		LogParser: p,
+		eventType: f.eventType,
+	}, nil
+}
+
+// routedParser exposes the event type of a Suricata parser to the classifier
+type routedParser struct {
+	pantherlog.LogParser
+	eventType string
+}
+
+var _ classification.Router = (*routedParser)(nil)
+
+// Route implements classification.Router interface
+func (p *routedParser) Route() (string, []string) {
+	return EventTypeField, []string{p.eventType}
+}
@@ -0,0 +1,64 @@
+package suricatalogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/classification"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes/logtesting"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+)
+
+func TestSuricataLogParsers(t *testing.T) {
+	logtesting.RunTestsFromYAML(t, LogTypes(), "./testdata/suricata_tests.yml")
+}
+
+func TestEventTypeRouting(t *testing.T) {
+	assert := require.New(t)
+	index := map[string]parsers.Interface{}
+	for _, entry := range LogTypes().Entries() {
+		parser, err := entry.NewParser(nil)
+		assert.NoError(err)
+		field, values := parser.(classification.Router).Route()
+		assert.Equal(EventTypeField, field)
+		assert.Len(values, 1)
+		index[entry.String()] = parser
+	}
+	classifier := classification.NewClassifier(index)
+	// nolint:lll
+	logs := map[string]string{
+		TypeFlow:  `{"timestamp":"2020-10-01T12:01:00.000000+0000","flow_id":1234567890123456,"event_type":"flow","src_ip":"10.0.0.1","src_port":51234,"dest_ip":"93.184.216.34","dest_port":443,"proto":"TCP","flow":{"pkts_toserver":10,"pkts_toclient":9}}`,
+		TypeStats: `{"timestamp":"2020-10-01T12:00:00.123456+0000","event_type":"stats","stats":{"uptime":3600}}`,
+		TypeDNS:   `{"timestamp":"2015-10-22T06:31:06.520370+0000","event_type":"dns","src_ip":"192.168.89.2","dest_ip":"8.8.8.8","proto":"017","dns":{"type":"query","id":62705,"rrname":"localhost","rrtype":"A"}}`,
+	}
+	for logType, log := range logs {
+		result, err := classifier.Classify(log)
+		assert.NoError(err)
+		assert.Zero(result.NumMiss, "only the parser of the event type should be tried")
+		assert.Len(result.Events, 1)
+		assert.Equal(logType, result.Events[0].PantherLogType)
+	}
+	// Unknown event types are not tried by any parser
+	result, err := classifier.Classify(`{"timestamp":"2020-10-01T12:00:00.123456+0000","event_type":"rdp"}`)
+	assert.Error(err)
+	assert.Zero(result.NumMiss)
+}
@@ -0,0 +1,55 @@
+package suricatalogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// nolint:lll,maligned
+type TLS struct {
+	EventType pantherlog.String `json:"event_type" validate:"required,eq=tls" description:"The type of the EVE event (tls)"`
+	EventFields
+	FlowFields
+	TLS *TLSDetails `json:"tls" validate:"required" description:"The TLS handshake"`
+}
+
+// nolint:lll,maligned
+type TLSDetails struct {
+	Subject        pantherlog.String   `json:"subject" description:"The subject of the server certificate"`
+	IssuerDN       pantherlog.String   `json:"issuerdn" description:"The issuer of the server certificate"`
+	Serial         pantherlog.String   `json:"serial" description:"The serial number of the server certificate"`
+	Fingerprint    pantherlog.String   `json:"fingerprint" description:"The SHA1 fingerprint of the server certificate in colon separated hex"`
+	SNI            pantherlog.String   `json:"sni" panther:"domain" description:"The Server Name Indication of the client"`
+	Version        pantherlog.String   `json:"version" description:"The TLS version"`
+	NotBefore      pantherlog.Time     `json:"notbefore" tcodec:"layout=2006-01-02T15:04:05" description:"The start of the validity of the server certificate"`
+	NotAfter       pantherlog.Time     `json:"notafter" tcodec:"layout=2006-01-02T15:04:05" description:"The end of the validity of the server certificate"`
+	SessionResumed pantherlog.Bool     `json:"session_resumed" description:"Whether the session was resumed, in which case no certificate is exchanged"`
+	Certificate    pantherlog.String   `json:"certificate" description:"The base64 encoded server certificate, if enabled"`
+	Chain          []pantherlog.String `json:"chain" description:"The base64 encoded certificate chain, if enabled"`
+	JA3            *JA3                `json:"ja3" description:"The JA3 fingerprint of the client, if enabled"`
+	JA3S           *JA3                `json:"ja3s" description:"The JA3S fingerprint of the server, if enabled"`
+}
+
+// JA3 is a TLS fingerprint
+// nolint:lll
+type JA3 struct {
+	Hash   pantherlog.String `json:"hash" panther:"md5" description:"The MD5 hash of the fingerprint"`
+	String pantherlog.String `json:"string" description:"The fingerprint string"`
+}
@@ -0,0 +1,60 @@
+package zeeklogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// ConnID is the connection 4-tuple that Zeek logs as `id.*` fields
+// nolint:lll
+type ConnID struct {
+	OrigH pantherlog.String `json:"id.orig_h" validate:"required" panther:"ip" description:"The originator's IP address."`
+	OrigP pantherlog.Uint16 `json:"id.orig_p" validate:"required" description:"The originator's port number."`
+	RespH pantherlog.String `json:"id.resp_h" validate:"required" panther:"ip" description:"The responder's IP address."`
+	RespP pantherlog.Uint16 `json:"id.resp_p" validate:"required" description:"The responder's port number."`
+}
+
+// nolint:lll,maligned
+type Conn struct {
+	Path pantherlog.String `json:"_path" validate:"omitempty,eq=conn" description:"The name of the Zeek log (conn), set for logs converted from TSV or written by the json-streaming-logs package."`
+	TS   pantherlog.Time   `json:"ts" validate:"required" event_time:"true" tcodec:"zeek" description:"This is the time of the first packet."`
+	UID  pantherlog.String `json:"uid" validate:"required" panther:"trace_id" description:"A unique identifier of the connection."`
+	ConnID
+	Proto         pantherlog.String   `json:"proto" validate:"required" description:"The transport layer protocol of the connection."`
+	Service       pantherlog.String   `json:"service" description:"An identification of an application protocol being sent over the connection."`
+	Duration      pantherlog.Float64  `json:"duration" description:"How long the connection lasted in seconds."`
+	OrigBytes     pantherlog.Int64    `json:"orig_bytes" description:"The number of payload bytes the originator sent."`
+	RespBytes     pantherlog.Int64    `json:"resp_bytes" description:"The number of payload bytes the responder sent."`
+	ConnState     pantherlog.String   `json:"conn_state" description:"The state of the connection (i.e. S0, SF, REJ, RSTO)."`
+	LocalOrig     pantherlog.Bool     `json:"local_orig" description:"If the connection is originated locally, this value will be true."`
+	LocalResp     pantherlog.Bool     `json:"local_resp" description:"If the connection is responded to locally, this value will be true."`
+	MissedBytes   pantherlog.Int64    `json:"missed_bytes" description:"Indicates the number of bytes missed in content gaps, which is representative of packet loss."`
+	History       pantherlog.String   `json:"history" description:"Records the state history of connections as a string of letters."`
+	OrigPkts      pantherlog.Int64    `json:"orig_pkts" description:"Number of packets that the originator sent."`
+	OrigIPBytes   pantherlog.Int64    `json:"orig_ip_bytes" description:"Number of IP level bytes that the originator sent."`
+	RespPkts      pantherlog.Int64    `json:"resp_pkts" description:"Number of packets that the responder sent."`
+	RespIPBytes   pantherlog.Int64    `json:"resp_ip_bytes" description:"Number of IP level bytes that the responder sent."`
+	TunnelParents []pantherlog.String `json:"tunnel_parents" description:"If this connection was over a tunnel, the unique identifiers of any encapsulating parent connections."`
+	OrigL2Addr    pantherlog.String   `json:"orig_l2_addr" description:"Link-layer address of the originator, if available."`
+	RespL2Addr    pantherlog.String   `json:"resp_l2_addr" description:"Link-layer address of the responder, if available."`
+	VLAN          pantherlog.Int64    `json:"vlan" description:"The outer VLAN for this connection, if applicable."`
+	InnerVLAN     pantherlog.Int64    `json:"inner_vlan" description:"The inner VLAN for this connection, if applicable."`
+	CommunityID   pantherlog.String   `json:"community_id" description:"The Community ID flow hash of the connection, if the community-id package is loaded."`
+}
@@ -0,0 +1,50 @@
+package zeeklogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// nolint:lll,maligned
+type DHCP struct {
+	Path           pantherlog.String   `json:"_path" validate:"omitempty,eq=dhcp" description:"The name of the Zeek log (dhcp), set for logs converted from TSV or written by the json-streaming-logs package."`
+	TS             pantherlog.Time     `json:"ts" validate:"required" event_time:"true" tcodec:"zeek" description:"The earliest time at which a DHCP message over the associated connection is observed."`
+	UIDs           []pantherlog.String `json:"uids" validate:"required" panther:"trace_id" description:"A series of unique identifiers of the connections over which DHCP is occurring."`
+	ClientAddr     pantherlog.String   `json:"client_addr" panther:"ip" description:"IP address of the client."`
+	ServerAddr     pantherlog.String   `json:"server_addr" panther:"ip" description:"IP address of the server."`
+	ClientPort     pantherlog.Uint16   `json:"client_port" description:"Client port number seen at time of server handing out IP."`
+	ServerPort     pantherlog.Uint16   `json:"server_port" description:"Server port number seen at time of server handing out IP."`
+	MAC            pantherlog.String   `json:"mac" description:"Client's hardware address."`
+	HostName       pantherlog.String   `json:"host_name" description:"Name given by client in Hostname option 12."`
+	ClientFQDN     pantherlog.String   `json:"client_fqdn" panther:"domain" description:"FQDN given by client in Client FQDN option 81."`
+	Domain         pantherlog.String   `json:"domain" panther:"domain" description:"Domain given by the server in option 15."`
+	RequestedAddr  pantherlog.String   `json:"requested_addr" panther:"ip" description:"IP address requested by the client."`
+	AssignedAddr   pantherlog.String   `json:"assigned_addr" panther:"ip" description:"IP address assigned by the server."`
+	LeaseTime      pantherlog.Float64  `json:"lease_time" description:"IP address lease interval in seconds."`
+	ClientMessage  pantherlog.String   `json:"client_message" description:"Message typically accompanied with a DHCP_DECLINE so the client can tell the server why it rejected an address."`
+	ServerMessage  pantherlog.String   `json:"server_message" description:"Message typically accompanied with a DHCP_NAK to let the client know why it rejected the request."`
+	MsgTypes       []pantherlog.String `json:"msg_types" description:"The DHCP message types seen by this DHCP transaction."`
+	Duration       pantherlog.Float64  `json:"duration" description:"Duration of the DHCP session in seconds, from the first to the last message."`
+	ClientSoftware pantherlog.String   `json:"client_software" description:"Software reported by the client in the vendor_class option."`
+	ServerSoftware pantherlog.String   `json:"server_software" description:"Software reported by the server in the vendor_class option."`
+	CircuitID      pantherlog.String   `json:"circuit_id" description:"Added by DHCP relay agents which terminate switched or permanent circuits."`
+	AgentRemoteID  pantherlog.String   `json:"agent_remote_id" description:"A globally unique identifier added by relay agents to identify the remote host end of the circuit."`
+	SubscriberID   pantherlog.String   `json:"subscriber_id" description:"The subscriber ID is a value independent of the physical network configuration."`
+}
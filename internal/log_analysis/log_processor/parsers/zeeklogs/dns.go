@@ -33,6 +33,7 @@ const (
 
 // nolint:lll
 type ZeekDNS struct {
+	Path       *string              `json:"_path,omitempty" validate:"omitempty,eq=dns" description:"The name of the Zeek log (dns), set for logs converted from TSV or written by the json-streaming-logs package."`
 	TS         *timestamp.UnixFloat `json:"ts,omitempty" validate:"required" description:"The earliest time at which a DNS protocol message over the associated connection is observed."`
 	UID        *string              `json:"uid,omitempty" validate:"required" description:"A unique identifier of the connection over which DNS messages are being transferred."`
 	IDOrigH    *string              `json:"id.orig_h" validate:"required" description:"The originator’s IP address."`
@@ -0,0 +1,46 @@
+package zeeklogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// nolint:lll,maligned
+type Kerberos struct {
+	Path pantherlog.String `json:"_path" validate:"omitempty,eq=kerberos" description:"The name of the Zeek log (kerberos), set for logs converted from TSV or written by the json-streaming-logs package."`
+	TS   pantherlog.Time   `json:"ts" validate:"required" event_time:"true" tcodec:"zeek" description:"Timestamp for when the event happened."`
+	UID  pantherlog.String `json:"uid" validate:"required" panther:"trace_id" description:"Unique ID for the connection."`
+	ConnID
+	RequestType       pantherlog.String `json:"request_type" description:"Request type - Authentication Service (AS) or Ticket Granting Service (TGS)."`
+	Client            pantherlog.String `json:"client" panther:"username" description:"Client."`
+	Service           pantherlog.String `json:"service" description:"Service."`
+	Success           pantherlog.Bool   `json:"success" description:"Request result."`
+	ErrorCode         pantherlog.Int64  `json:"error_code" description:"Error code."`
+	ErrorMsg          pantherlog.String `json:"error_msg" description:"Error message."`
+	From              pantherlog.Time   `json:"from" tcodec:"zeek" description:"Ticket valid from."`
+	Till              pantherlog.Time   `json:"till" tcodec:"zeek" description:"Ticket valid till."`
+	Cipher            pantherlog.String `json:"cipher" description:"Ticket encryption type."`
+	Forwardable       pantherlog.Bool   `json:"forwardable" description:"Forwardable ticket requested."`
+	Renewable         pantherlog.Bool   `json:"renewable" description:"Renewable ticket requested."`
+	ClientCertSubject pantherlog.String `json:"client_cert_subject" description:"Subject of client certificate, if any."`
+	ClientCertFUID    pantherlog.String `json:"client_cert_fuid" description:"File unique ID of client cert, if any."`
+	ServerCertSubject pantherlog.String `json:"server_cert_subject" description:"Subject of server certificate, if any."`
+	ServerCertFUID    pantherlog.String `json:"server_cert_fuid" description:"File unique ID of server cert, if any."`
+}
@@ -0,0 +1,52 @@
+package zeeklogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// nolint:lll,maligned
+type SMTP struct {
+	Path pantherlog.String `json:"_path" validate:"omitempty,eq=smtp" description:"The name of the Zeek log (smtp), set for logs converted from TSV or written by the json-streaming-logs package."`
+	TS   pantherlog.Time   `json:"ts" validate:"required" event_time:"true" tcodec:"zeek" description:"Time when the message was first seen."`
+	UID  pantherlog.String `json:"uid" validate:"required" panther:"trace_id" description:"Unique ID for the connection."`
+	ConnID
+	TransDepth     pantherlog.Int64    `json:"trans_depth" validate:"required" description:"A count to represent the depth of this message transaction in a single connection where multiple messages were transferred."`
+	Helo           pantherlog.String   `json:"helo" panther:"hostname" description:"Contents of the Helo header."`
+	MailFrom       pantherlog.String   `json:"mailfrom" panther:"email" description:"Email addresses found in the From header."`
+	RcptTo         []pantherlog.String `json:"rcptto" panther:"email" description:"Email addresses found in the Rcpt header."`
+	Date           pantherlog.String   `json:"date" description:"Contents of the Date header."`
+	From           pantherlog.String   `json:"from" description:"Contents of the From header."`
+	To             []pantherlog.String `json:"to" description:"Contents of the To header."`
+	CC             []pantherlog.String `json:"cc" description:"Contents of the CC header."`
+	ReplyTo        pantherlog.String   `json:"reply_to" description:"Contents of the ReplyTo header."`
+	MsgID          pantherlog.String   `json:"msg_id" description:"Contents of the MsgID header."`
+	InReplyTo      pantherlog.String   `json:"in_reply_to" description:"Contents of the In-Reply-To header."`
+	Subject        pantherlog.String   `json:"subject" description:"Contents of the Subject header."`
+	XOriginatingIP pantherlog.String   `json:"x_originating_ip" panther:"ip" description:"Contents of the X-Originating-IP header."`
+	FirstReceived  pantherlog.String   `json:"first_received" description:"Contents of the first Received header."`
+	SecondReceived pantherlog.String   `json:"second_received" description:"Contents of the second Received header."`
+	LastReply      pantherlog.String   `json:"last_reply" description:"The last message that the server sent to the client."`
+	MailPath       []pantherlog.String `json:"path" panther:"ip" description:"The message transmission path, as extracted from the headers."`
+	UserAgent      pantherlog.String   `json:"user_agent" description:"Value of the User-Agent header from the client."`
+	TLS            pantherlog.Bool     `json:"tls" description:"Indicates that the connection has switched to using TLS."`
+	FUIDs          []pantherlog.String `json:"fuids" description:"An ordered vector of file unique IDs seen attached to the message."`
+	IsWebmail      pantherlog.Bool     `json:"is_webmail" description:"Boolean indicator of if the message was sent through a webmail interface."`
+}
@@ -0,0 +1,52 @@
+package zeeklogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// nolint:lll,maligned
+type SSH struct {
+	Path pantherlog.String `json:"_path" validate:"omitempty,eq=ssh" description:"The name of the Zeek log (ssh), set for logs converted from TSV or written by the json-streaming-logs package."`
+	TS   pantherlog.Time   `json:"ts" validate:"required" event_time:"true" tcodec:"zeek" description:"Time when the SSH connection began."`
+	UID  pantherlog.String `json:"uid" validate:"required" panther:"trace_id" description:"Unique ID for the connection."`
+	ConnID
+	Version                   pantherlog.Int64   `json:"version" description:"SSH major version (1, 2, or unset)."`
+	AuthSuccess               pantherlog.Bool    `json:"auth_success" description:"Authentication result (true=success, false=failure, unset=unknown)."`
+	AuthAttempts              pantherlog.Int64   `json:"auth_attempts" description:"The number of authentication attempts we observed."`
+	Direction                 pantherlog.String  `json:"direction" description:"Direction of the connection (INBOUND or OUTBOUND)."`
+	Client                    pantherlog.String  `json:"client" description:"The client's version string."`
+	Server                    pantherlog.String  `json:"server" description:"The server's version string."`
+	CipherAlg                 pantherlog.String  `json:"cipher_alg" description:"The encryption algorithm in use."`
+	MACAlg                    pantherlog.String  `json:"mac_alg" description:"The signing (MAC) algorithm in use."`
+	CompressionAlg            pantherlog.String  `json:"compression_alg" description:"The compression algorithm in use."`
+	KexAlg                    pantherlog.String  `json:"kex_alg" description:"The key exchange algorithm in use."`
+	HostKeyAlg                pantherlog.String  `json:"host_key_alg" description:"The server host key's algorithm."`
+	HostKey                   pantherlog.String  `json:"host_key" description:"The server's key fingerprint."`
+	RemoteLocationCountryCode pantherlog.String  `json:"remote_location.country_code" description:"The country code of the remote host."`
+	RemoteLocationRegion      pantherlog.String  `json:"remote_location.region" description:"The region of the remote host."`
+	RemoteLocationCity        pantherlog.String  `json:"remote_location.city" description:"The city of the remote host."`
+	RemoteLocationLatitude    pantherlog.Float64 `json:"remote_location.latitude" description:"The latitude of the remote host."`
+	RemoteLocationLongitude   pantherlog.Float64 `json:"remote_location.longitude" description:"The longitude of the remote host."`
+	HASSH                     pantherlog.String  `json:"hassh" panther:"md5" description:"The HASSH fingerprint of the client, if the hassh package is loaded."`
+	HASSHServer               pantherlog.String  `json:"hasshServer" panther:"md5" description:"The HASSH fingerprint of the server, if the hassh package is loaded."`
+	HASSHAlgorithms           pantherlog.String  `json:"hasshAlgorithms" description:"The client algorithms the HASSH fingerprint is computed from."`
+	HASSHServerAlgorithms     pantherlog.String  `json:"hasshServerAlgorithms" description:"The server algorithms the HASSH server fingerprint is computed from."`
+}
@@ -0,0 +1,52 @@
+package zeeklogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// nolint:lll,maligned
+type SSL struct {
+	Path pantherlog.String `json:"_path" validate:"omitempty,eq=ssl" description:"The name of the Zeek log (ssl), set for logs converted from TSV or written by the json-streaming-logs package."`
+	TS   pantherlog.Time   `json:"ts" validate:"required" event_time:"true" tcodec:"zeek" description:"Time when the SSL connection was first detected."`
+	UID  pantherlog.String `json:"uid" validate:"required" panther:"trace_id" description:"Unique ID for the connection."`
+	ConnID
+	Version              pantherlog.String   `json:"version" description:"SSL/TLS version that the server chose."`
+	Cipher               pantherlog.String   `json:"cipher" description:"SSL/TLS cipher suite that the server chose."`
+	Curve                pantherlog.String   `json:"curve" description:"Elliptic curve the server chose when using ECDH/ECDHE."`
+	ServerName           pantherlog.String   `json:"server_name" panther:"domain" description:"Value of the Server Name Indicator SSL/TLS extension."`
+	Resumed              pantherlog.Bool     `json:"resumed" description:"Flag to indicate if the session was resumed reusing the key material exchanged in an earlier connection."`
+	LastAlert            pantherlog.String   `json:"last_alert" description:"Last alert that was seen during the connection."`
+	NextProtocol         pantherlog.String   `json:"next_protocol" description:"Next protocol the server chose using the application layer next protocol extension, if present."`
+	Established          pantherlog.Bool     `json:"established" description:"Flag to indicate if this SSL session has been established successfully, or if it was aborted during the handshake."`
+	SSLHistory           pantherlog.String   `json:"ssl_history" description:"SSL history showing which types of packets were received in which order."`
+	CertChainFPs         []pantherlog.String `json:"cert_chain_fps" panther:"sha1,sha256" description:"An ordered vector of all certificate fingerprints for the certificates offered by the server."`
+	ClientCertChainFPs   []pantherlog.String `json:"client_cert_chain_fps" panther:"sha1,sha256" description:"An ordered vector of all certificate fingerprints for the certificates offered by the client."`
+	CertChainFUIDs       []pantherlog.String `json:"cert_chain_fuids" description:"An ordered vector of all certificate file unique IDs for the certificates offered by the server."`
+	ClientCertChainFUIDs []pantherlog.String `json:"client_cert_chain_fuids" description:"An ordered vector of all certificate file unique IDs for the certificates offered by the client."`
+	Subject              pantherlog.String   `json:"subject" description:"Subject of the X.509 certificate offered by the server."`
+	Issuer               pantherlog.String   `json:"issuer" description:"Subject of the signer of the X.509 certificate offered by the server."`
+	ClientSubject        pantherlog.String   `json:"client_subject" description:"Subject of the X.509 certificate offered by the client."`
+	ClientIssuer         pantherlog.String   `json:"client_issuer" description:"Subject of the signer of the X.509 certificate offered by the client."`
+	SNIMatchesCert       pantherlog.Bool     `json:"sni_matches_cert" description:"Set to true if the hostname sent in the SNI matches the certificate."`
+	ValidationStatus     pantherlog.String   `json:"validation_status" description:"Result of certificate validation for this connection."`
+	JA3                  pantherlog.String   `json:"ja3" panther:"md5" description:"The JA3 fingerprint of the client hello, if the ja3 package is loaded."`
+	JA3S                 pantherlog.String   `json:"ja3s" panther:"md5" description:"The JA3S fingerprint of the server hello, if the ja3 package is loaded."`
+}
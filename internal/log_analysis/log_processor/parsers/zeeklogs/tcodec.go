@@ -0,0 +1,62 @@
+package zeeklogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"strconv"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog/tcodec"
+)
+
+type timeDecoder struct{}
+
+// DecodeTime implements tcodec.TimeDecoder for timestamps in Zeek logs.
+// Zeek writes timestamps as seconds since UNIX epoch by default.
+// If `LogAscii::json_timestamps` is set to `JSON::TS_ISO8601` timestamps are RFC3339 strings.
+func (*timeDecoder) DecodeTime(iter *jsoniter.Iterator) time.Time {
+	const opName = "ParseZeekTimestamp"
+	switch iter.WhatIsNext() {
+	case jsoniter.NumberValue:
+		return tcodec.UnixSeconds(iter.ReadFloat64()).UTC()
+	case jsoniter.StringValue:
+		s := iter.ReadString()
+		if s == "" {
+			return time.Time{}
+		}
+		if sec, err := strconv.ParseFloat(s, 64); err == nil {
+			return tcodec.UnixSeconds(sec).UTC()
+		}
+		tm, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			iter.ReportError(opName, err.Error())
+			return time.Time{}
+		}
+		return tm.UTC()
+	case jsoniter.NilValue:
+		iter.ReadNil()
+		return time.Time{}
+	default:
+		iter.Skip()
+		iter.ReportError(opName, "invalid JSON value")
+		return time.Time{}
+	}
+}
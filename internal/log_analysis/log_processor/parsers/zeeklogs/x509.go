@@ -0,0 +1,53 @@
+package zeeklogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// nolint:lll,maligned
+type X509 struct {
+	Path                      pantherlog.String   `json:"_path" validate:"omitempty,eq=x509" description:"The name of the Zeek log (x509), set for logs converted from TSV or written by the json-streaming-logs package."`
+	TS                        pantherlog.Time     `json:"ts" validate:"required" event_time:"true" tcodec:"zeek" description:"Current timestamp."`
+	ID                        pantherlog.String   `json:"id" description:"File id of this certificate (Zeek versions before 5.0)."`
+	FUID                      pantherlog.String   `json:"fuid" description:"File id of this certificate."`
+	Fingerprint               pantherlog.String   `json:"fingerprint" panther:"md5,sha1,sha256" description:"Fingerprint of the certificate, uses the hash algorithm set in X509::hash_function (SHA256 by default)."`
+	CertificateVersion        pantherlog.Int64    `json:"certificate.version" validate:"required" description:"Version number."`
+	CertificateSerial         pantherlog.String   `json:"certificate.serial" validate:"required" description:"Serial number."`
+	CertificateSubject        pantherlog.String   `json:"certificate.subject" description:"Subject."`
+	CertificateIssuer         pantherlog.String   `json:"certificate.issuer" description:"Issuer."`
+	CertificateCN             pantherlog.String   `json:"certificate.cn" description:"Last (most specific) common name."`
+	CertificateNotValidBefore pantherlog.Time     `json:"certificate.not_valid_before" tcodec:"zeek" description:"Timestamp before when certificate is not valid."`
+	CertificateNotValidAfter  pantherlog.Time     `json:"certificate.not_valid_after" tcodec:"zeek" description:"Timestamp after when certificate is not valid."`
+	CertificateKeyAlg         pantherlog.String   `json:"certificate.key_alg" description:"Name of the key algorithm."`
+	CertificateSigAlg         pantherlog.String   `json:"certificate.sig_alg" description:"Name of the signature algorithm."`
+	CertificateKeyType        pantherlog.String   `json:"certificate.key_type" description:"Key type, if key parseable by openssl (either rsa, dsa or ec)."`
+	CertificateKeyLength      pantherlog.Int64    `json:"certificate.key_length" description:"Key length in bits."`
+	CertificateExponent       pantherlog.String   `json:"certificate.exponent" description:"Exponent, if RSA-certificate."`
+	CertificateCurve          pantherlog.String   `json:"certificate.curve" description:"Curve, if EC-certificate."`
+	SANDNS                    []pantherlog.String `json:"san.dns" panther:"domain" description:"List of DNS entries in the Subject Alternative Name extension."`
+	SANURI                    []pantherlog.String `json:"san.uri" panther:"url" description:"List of URI entries in the Subject Alternative Name extension."`
+	SANEmail                  []pantherlog.String `json:"san.email" panther:"email" description:"List of email entries in the Subject Alternative Name extension."`
+	SANIP                     []pantherlog.String `json:"san.ip" panther:"ip" description:"List of IP entries in the Subject Alternative Name extension."`
+	BasicConstraintsCA        pantherlog.Bool     `json:"basic_constraints.ca" description:"CA flag set or not."`
+	BasicConstraintsPathLen   pantherlog.Int64    `json:"basic_constraints.path_len" description:"Maximum path length."`
+	HostCert                  pantherlog.Bool     `json:"host_cert" description:"Indicates if this certificate was a end-host certificate, or sent as part of a chain."`
+	ClientCert                pantherlog.Bool     `json:"client_cert" description:"Indicates if this certificate was sent from the client."`
+}
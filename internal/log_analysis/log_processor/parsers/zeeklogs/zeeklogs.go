@@ -20,21 +20,184 @@ package zeeklogs
 
 import (
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog/tcodec"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/logstream"
 )
 
 const (
-	TypeZeekDNS = "Zeek.DNS"
+	TypeZeekDNS      = "Zeek.DNS"
+	TypeZeekConn     = "Zeek.Conn"
+	TypeZeekHTTP     = "Zeek.HTTP"
+	TypeZeekSSL      = "Zeek.SSL"
+	TypeZeekX509     = "Zeek.X509"
+	TypeZeekFiles    = "Zeek.Files"
+	TypeZeekNotice   = "Zeek.Notice"
+	TypeZeekWeird    = "Zeek.Weird"
+	TypeZeekSMTP     = "Zeek.SMTP"
+	TypeZeekSSH      = "Zeek.SSH"
+	TypeZeekDHCP     = "Zeek.DHCP"
+	TypeZeekKerberos = "Zeek.Kerberos"
 )
 
 func LogTypes() logtypes.Group {
 	return logTypes
 }
 
-var logTypes = logtypes.Must("Zeek", logtypes.Config{
-	Name:         TypeZeekDNS,
-	Description:  `Zeek DNS activity`,
-	ReferenceURL: `https://docs.zeek.org/en/current/scripts/base/protocols/dns/main.zeek.html#type-DNS::Info`,
-	Schema:       &ZeekDNS{},
-	NewParser:    parsers.AdapterFactory(&ZeekDNSParser{}),
-})
+// We use an immediately called function to register the time decoder before building the logtype entries.
+var logTypes = func() logtypes.Group {
+	tcodec.MustRegister(`zeek`, tcodec.Join(
+		&timeDecoder{},
+		tcodec.UnixSecondsCodec(), // encoder
+	))
+	return logtypes.Must("Zeek",
+		logtypes.Config{
+			Name:         TypeZeekDNS,
+			Description:  `Zeek DNS activity`,
+			ReferenceURL: `https://docs.zeek.org/en/current/scripts/base/protocols/dns/main.zeek.html#type-DNS::Info`,
+			Schema:       &ZeekDNS{},
+			NewParser:    framedParserFactory{parsers.AdapterFactory(&ZeekDNSParser{})},
+		},
+		zeekConfig{
+			Name:         TypeZeekConn,
+			Description:  `Zeek TCP, UDP and ICMP connection summaries`,
+			ReferenceURL: `https://docs.zeek.org/en/current/scripts/base/protocols/conn/main.zeek.html#type-Conn::Info`,
+			NewEvent: func() interface{} {
+				return &Conn{}
+			},
+		},
+		zeekConfig{
+			Name:         TypeZeekHTTP,
+			Description:  `Zeek HTTP requests and replies`,
+			ReferenceURL: `https://docs.zeek.org/en/current/scripts/base/protocols/http/main.zeek.html#type-HTTP::Info`,
+			NewEvent: func() interface{} {
+				return &HTTP{}
+			},
+		},
+		zeekConfig{
+			Name:         TypeZeekSSL,
+			Description:  `Zeek SSL/TLS handshake info`,
+			ReferenceURL: `https://docs.zeek.org/en/current/scripts/base/protocols/ssl/main.zeek.html#type-SSL::Info`,
+			NewEvent: func() interface{} {
+				return &SSL{}
+			},
+		},
+		zeekConfig{
+			Name:         TypeZeekX509,
+			Description:  `Zeek X.509 certificate info`,
+			ReferenceURL: `https://docs.zeek.org/en/current/scripts/base/files/x509/main.zeek.html#type-X509::Info`,
+			NewEvent: func() interface{} {
+				return &X509{}
+			},
+		},
+		zeekConfig{
+			Name:         TypeZeekFiles,
+			Description:  `Zeek file analysis results`,
+			ReferenceURL: `https://docs.zeek.org/en/current/scripts/base/frameworks/files/main.zeek.html#type-Files::Info`,
+			NewEvent: func() interface{} {
+				return &Files{}
+			},
+		},
+		zeekConfig{
+			Name:         TypeZeekNotice,
+			Description:  `Zeek notices raised by detection scripts`,
+			ReferenceURL: `https://docs.zeek.org/en/current/scripts/base/frameworks/notice/main.zeek.html#type-Notice::Info`,
+			NewEvent: func() interface{} {
+				return &Notice{}
+			},
+		},
+		zeekConfig{
+			Name:         TypeZeekWeird,
+			Description:  `Zeek unexpected network-level activity`,
+			ReferenceURL: `https://docs.zeek.org/en/current/scripts/base/frameworks/notice/weird.zeek.html#type-Weird::Info`,
+			NewEvent: func() interface{} {
+				return &Weird{}
+			},
+		},
+		zeekConfig{
+			Name:         TypeZeekSMTP,
+			Description:  `Zeek SMTP transactions`,
+			ReferenceURL: `https://docs.zeek.org/en/current/scripts/base/protocols/smtp/main.zeek.html#type-SMTP::Info`,
+			NewEvent: func() interface{} {
+				return &SMTP{}
+			},
+		},
+		zeekConfig{
+			Name:         TypeZeekSSH,
+			Description:  `Zeek SSH connections`,
+			ReferenceURL: `https://docs.zeek.org/en/current/scripts/base/protocols/ssh/main.zeek.html#type-SSH::Info`,
+			NewEvent: func() interface{} {
+				return &SSH{}
+			},
+		},
+		zeekConfig{
+			Name:         TypeZeekDHCP,
+			Description:  `Zeek DHCP lease activity`,
+			ReferenceURL: `https://docs.zeek.org/en/current/scripts/base/protocols/dhcp/main.zeek.html#type-DHCP::Info`,
+			NewEvent: func() interface{} {
+				return &DHCP{}
+			},
+		},
+		zeekConfig{
+			Name:         TypeZeekKerberos,
+			Description:  `Zeek Kerberos authentication requests`,
+			ReferenceURL: `https://docs.zeek.org/en/current/scripts/base/protocols/krb/main.zeek.html#type-KRB::Info`,
+			NewEvent: func() interface{} {
+				return &Kerberos{}
+			},
+		},
+	)
+}()
+
+// zeekConfig builds a log type entry for a Zeek log.
+// Zeek logs are JSON objects, either written directly by Zeek or converted from the tab-separated
+// output of Zeek by the framing that the parsers require.
+type zeekConfig logtypes.ConfigJSON
+
+// BuildEntry implements logtypes.EntryBuilder interface
+func (c zeekConfig) BuildEntry() (logtypes.Entry, error) {
+	entry, err := logtypes.ConfigJSON(c).BuildEntry()
+	if err != nil {
+		return nil, err
+	}
+	config := logtypes.Config{
+		Name:         c.Name,
+		Description:  c.Description,
+		ReferenceURL: c.ReferenceURL,
+		Schema:       entry.Schema(),
+		NewParser:    framedParserFactory{entry},
+	}
+	return config.BuildEntry()
+}
+
+// framedParserFactory creates parsers that require Zeek TSV framing
+type framedParserFactory struct {
+	pantherlog.LogParserFactory
+}
+
+// NewParser implements pantherlog.LogParserFactory interface
+func (f framedParserFactory) NewParser(params interface{}) (pantherlog.LogParser, error) {
+	p, err := f.LogParserFactory.NewParser(params)
+	if err != nil {
+		return nil, err
+	}
+	return &framedParser{
+		LogParser: p,
+	}, nil
+}
+
+// framedParser exposes the framing required by Zeek parsers to the processor.
+// All Zeek log types require the same framing so a source can mix Zeek logs of different types.
+type framedParser struct {
+	pantherlog.LogParser
+}
+
+var _ logstream.Framer = (*framedParser)(nil)
+
+// Framing implements logstream.Framer interface
+func (*framedParser) Framing() *logstream.FramingConfig {
+	return &logstream.FramingConfig{
+		ZeekTSV: true,
+	}
+}
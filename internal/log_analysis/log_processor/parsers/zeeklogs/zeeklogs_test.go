@@ -0,0 +1,109 @@
+package zeeklogs
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes/logtesting"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/logstream"
+)
+
+func TestZeekLogParsers(t *testing.T) {
+	logtesting.RunTestsFromYAML(t, LogTypes(), "./testdata/zeek_tests.yml")
+}
+
+// nolint:lll
+const testConnTSV = `#separator \x09
+#set_separator	,
+#empty_field	(empty)
+#unset_field	-
+#path	conn
+#open	2020-10-01-12-00-00
+#fields	ts	uid	id.orig_h	id.orig_p	id.resp_h	id.resp_p	proto	service	duration	orig_bytes	resp_bytes	conn_state	local_orig	local_resp	missed_bytes	history	orig_pkts	orig_ip_bytes	resp_pkts	resp_ip_bytes	tunnel_parents
+#types	time	string	addr	port	addr	port	enum	string	interval	count	count	string	bool	bool	count	string	count	count	count	count	set[string]
+1601553600.123456	CHhAvVGS1DHFjwGM9	10.0.0.1	51234	93.184.216.34	443	tcp	ssl	1.500000	517	4210	SF	T	F	0	ShADadFf	10	1049	9	4690	(empty)
+#close	2020-10-01-13-00-00
+`
+
+func TestZeekTSV(t *testing.T) {
+	assert := require.New(t)
+	conn := newFramedParser(t, TypeZeekConn)
+	lines := logstream.NewLineStream(strings.NewReader(testConnTSV), logstream.MinBufferSize)
+	stream, err := logstream.NewFramedStream(lines, conn.(logstream.Framer).Framing())
+	assert.NoError(err)
+	entry := stream.Next()
+	assert.NotNil(entry)
+	assert.Nil(stream.Next())
+	assert.NoError(stream.Err())
+
+	results, err := conn.ParseLog(string(entry))
+	assert.NoError(err)
+	assert.Len(results, 1)
+	expect := `{
+		"_path": "conn",
+		"ts": 1601553600.123456,
+		"uid": "CHhAvVGS1DHFjwGM9",
+		"id.orig_h": "10.0.0.1",
+		"id.orig_p": 51234,
+		"id.resp_h": "93.184.216.34",
+		"id.resp_p": 443,
+		"proto": "tcp",
+		"service": "ssl",
+		"duration": 1.5,
+		"orig_bytes": 517,
+		"resp_bytes": 4210,
+		"conn_state": "SF",
+		"local_orig": true,
+		"local_resp": false,
+		"missed_bytes": 0,
+		"history": "ShADadFf",
+		"orig_pkts": 10,
+		"orig_ip_bytes": 1049,
+		"resp_pkts": 9,
+		"resp_ip_bytes": 4690,
+		"p_log_type": "Zeek.Conn",
+		"p_event_time": "2020-10-01T12:00:00.123456Z",
+		"p_any_ip_addresses": ["10.0.0.1", "93.184.216.34"],
+		"p_any_trace_ids": ["CHhAvVGS1DHFjwGM9"]
+	}`
+	logtesting.TestResult(t, expect, results[0])
+
+	// The log path prevents other Zeek log types with the same required fields from parsing the entry
+	_, err = newFramedParser(t, TypeZeekKerberos).ParseLog(string(entry))
+	assert.Error(err)
+	_, err = newFramedParser(t, TypeZeekDNS).ParseLog(string(entry))
+	assert.Error(err)
+}
+
+func newFramedParser(t *testing.T, logType string) pantherlog.LogParser {
+	t.Helper()
+	entry := LogTypes().Find(logType)
+	require.NotNil(t, entry)
+	parser, err := entry.NewParser(nil)
+	require.NoError(t, err)
+	framer, ok := parser.(logstream.Framer)
+	require.True(t, ok)
+	require.Equal(t, &logstream.FramingConfig{ZeekTSV: true}, framer.Framing())
+	return parser
+}
@@ -0,0 +1,178 @@
+package preprocessors
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// nolint:lll
+type CEFConfig struct {
+	PrefixField  string            `json:"prefixField,omitempty" yaml:"prefixField,omitempty" description:"Store any text preceding the 'CEF:' header (ie a syslog header) in this field"`
+	SkipPrefix   string            `json:"skipPrefix,omitempty" yaml:"skipPrefix,omitempty" description:"Skip comment lines by prefix"`
+	EmptyValues  []string          `json:"emptyValues,omitempty" yaml:"emptyValues,omitempty" description:"Placeholder value for empty or missing data"`
+	ExpandFields map[string]string `json:"expandFields,omitempty" yaml:"expandFields,omitempty" description:"Add fields by text templates"`
+	TrimSpace    bool              `json:"trimSpace,omitempty" yaml:"trimSpace,omitempty" description:"Trim space surrounding values"`
+}
+
+// Field names for the CEF header values.
+// Extension fields keep their CEF key names (ie `src`, `dpt`, `cs1Label`).
+var cefHeaderFields = []string{
+	"version",
+	"deviceVendor",
+	"deviceProduct",
+	"deviceVersion",
+	"deviceEventClassId",
+	"name",
+	"severity",
+}
+
+func (config CEFConfig) BuildPreprocessor() (Interface, error) {
+	prefixField := config.PrefixField
+	return &matchTextPreprocessor{
+		match: func(dst []string, src string) ([]string, error) {
+			return matchCEF(dst, src, prefixField)
+		},
+		skipPrefix:   config.SkipPrefix,
+		emptyValues:  config.EmptyValues,
+		expandFields: compileFieldTemplates(config.ExpandFields),
+		stream:       buildJSONStream(),
+		trimSpace:    config.TrimSpace,
+	}, nil
+}
+
+// matchCEF splits a CEF entry to key/value pairs
+//
+//	CEF:Version|Device Vendor|Device Product|Device Version|Device Event Class ID|Name|Severity|Extension
+func matchCEF(dst []string, src, prefixField string) ([]string, error) {
+	const tag = "CEF:"
+	pos := strings.Index(src, tag)
+	if pos == -1 {
+		return dst, errors.New("missing CEF header")
+	}
+	if prefix := strings.TrimSpace(src[:pos]); prefix != "" && prefixField != "" {
+		dst = append(dst, prefixField, prefix)
+	}
+	header, extension, err := splitHeader(nil, src[pos+len(tag):], len(cefHeaderFields))
+	if err != nil {
+		return dst, errors.Wrap(err, "invalid CEF header")
+	}
+	dst = zipFields(dst, cefHeaderFields, header)
+	return splitCEFExtension(dst, extension)
+}
+
+// splitHeader reads n pipe delimited header values, handling `\|` and `\\` escapes.
+// It returns the header values and the remaining text.
+func splitHeader(dst []string, src string, n int) ([]string, string, error) {
+	for ; n > 0; n-- {
+		end := indexUnescaped(src, '|')
+		if end == -1 {
+			return dst, src, errors.Errorf("expected %d more header fields", n)
+		}
+		dst = append(dst, unescapeCEF(src[:end], false))
+		src = src[end+1:]
+	}
+	return dst, src, nil
+}
+
+// splitCEFExtension splits CEF extension `key=value` pairs.
+// Values can contain spaces so a value extends up to the last space before the next unescaped `=`.
+func splitCEFExtension(dst []string, src string) ([]string, error) {
+	src = strings.TrimSpace(src)
+	key := ""
+	for src != "" {
+		eq := indexUnescaped(src, '=')
+		if key == "" {
+			if eq == -1 {
+				return dst, errors.Errorf("invalid CEF extension %q", src)
+			}
+			key, src = strings.TrimSpace(src[:eq]), src[eq+1:]
+			if key == "" || strings.ContainsAny(key, " \t") {
+				return dst, errors.Errorf("invalid CEF extension key %q", key)
+			}
+			continue
+		}
+		end := len(src)
+		next := ""
+		// Skip unescaped '=' inside values that are not preceded by a key
+		for offset := 0; eq != -1; {
+			eq += offset
+			if sp := strings.LastIndexByte(src[:eq], ' '); sp != -1 && sp+1 < eq {
+				end, next = sp, src[sp+1:eq]
+				break
+			}
+			offset = eq + 1
+			if eq = indexUnescaped(src[offset:], '='); eq == -1 {
+				break
+			}
+		}
+		dst = append(dst, key, unescapeCEF(strings.TrimRight(src[:end], " "), true))
+		if next == "" {
+			return dst, nil
+		}
+		key, src = next, src[end+1+len(next)+1:]
+	}
+	// Key with empty value at the end of the extension
+	if key != "" {
+		dst = append(dst, key, "")
+	}
+	return dst, nil
+}
+
+// indexUnescaped finds the first occurrence of c not escaped by a `\`.
+func indexUnescaped(s string, c byte) int {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case c:
+			return i
+		}
+	}
+	return -1
+}
+
+// unescapeCEF handles backslash escapes in CEF header and extension values.
+// Extension values can also include escaped line breaks.
+func unescapeCEF(s string, extension bool) string {
+	if strings.IndexByte(s, '\\') == -1 {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '\\' || i+1 == len(s) {
+			b.WriteByte(c)
+			continue
+		}
+		i++
+		switch c = s[i]; {
+		case extension && c == 'n':
+			b.WriteByte('\n')
+		case extension && c == 'r':
+			b.WriteByte('\r')
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,114 @@
+package preprocessors
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCEFPreprocessor(t *testing.T) {
+	p, err := CEFConfig{
+		PrefixField: "syslogHeader",
+	}.BuildPreprocessor()
+	require.NoError(t, err)
+	for _, tc := range []struct {
+		Log       string
+		Expect    string
+		ExpectErr bool
+	}{
+		{
+			Log: `Sep 19 08:26:10 host CEF:0|Security|threatmanager|1.0|100|worm successfully stopped|10|src=10.0.0.1 dst=2.1.2.2 spt=1232`,
+			Expect: `{
+				"syslogHeader":"Sep 19 08:26:10 host",
+				"version":"0",
+				"deviceVendor":"Security",
+				"deviceProduct":"threatmanager",
+				"deviceVersion":"1.0",
+				"deviceEventClassId":"100",
+				"name":"worm successfully stopped",
+				"severity":"10",
+				"src":"10.0.0.1",
+				"dst":"2.1.2.2",
+				"spt":"1232"
+			}`,
+		},
+		{
+			Log: `CEF:0|security|threatmanager|1.0|100|detected a \| in message|10|src=10.0.0.1 act=blocked a \= dst=1.1.1.1`,
+			Expect: `{
+				"version":"0",
+				"deviceVendor":"security",
+				"deviceProduct":"threatmanager",
+				"deviceVersion":"1.0",
+				"deviceEventClassId":"100",
+				"name":"detected a | in message",
+				"severity":"10",
+				"src":"10.0.0.1",
+				"act":"blocked a =",
+				"dst":"1.1.1.1"
+			}`,
+		},
+		{
+			Log: `CEF:0|Vendor|Product|2.1|login|User \\ login|Low|msg=Detected a threat.\nNo action needed. cs1Label=url cs1=http://x.com/?a=b&c=d duser=`,
+			Expect: `{
+				"version":"0",
+				"deviceVendor":"Vendor",
+				"deviceProduct":"Product",
+				"deviceVersion":"2.1",
+				"deviceEventClassId":"login",
+				"name":"User \\ login",
+				"severity":"Low",
+				"msg":"Detected a threat.\nNo action needed.",
+				"cs1Label":"url",
+				"cs1":"http://x.com/?a=b&c=d",
+				"duser":""
+			}`,
+		},
+		{
+			Log: `CEF:1|Vendor|Product|2.1|100|Name|5|`,
+			Expect: `{
+				"version":"1",
+				"deviceVendor":"Vendor",
+				"deviceProduct":"Product",
+				"deviceVersion":"2.1",
+				"deviceEventClassId":"100",
+				"name":"Name",
+				"severity":"5"
+			}`,
+		},
+		{
+			Log:       `CEF:0|Vendor|Product|2.1|100|Name`,
+			ExpectErr: true,
+		},
+		{
+			Log:       `LEEF:1.0|Vendor|Product|2.1|100|src=1.1.1.1`,
+			ExpectErr: true,
+		},
+	} {
+		tc := tc
+		actual, err := p.PreProcessLog(tc.Log)
+		if tc.ExpectErr {
+			require.Error(t, err, tc.Log)
+			continue
+		}
+		require.NoError(t, err, tc.Log)
+		require.JSONEq(t, tc.Expect, actual, tc.Log)
+	}
+}
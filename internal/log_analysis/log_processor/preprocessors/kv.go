@@ -0,0 +1,179 @@
+package preprocessors
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// nolint:lll
+type KVConfig struct {
+	Delimiter      string            `json:"delimiter,omitempty" yaml:"delimiter,omitempty" description:"Delimiter between key/value pairs (defaults to space)"`
+	ValueSeparator string            `json:"valueSeparator,omitempty" yaml:"valueSeparator,omitempty" description:"Separator between a key and its value (defaults to '=')"`
+	Quote          string            `json:"quote,omitempty" yaml:"quote,omitempty" description:"Quote character for keys and values containing delimiters (defaults to '\"')"`
+	SkipLines      int               `json:"skipLines,omitempty" yaml:"skipLines,omitempty" description:"Number of lines to skip at start of file"`
+	SkipPrefix     string            `json:"skipPrefix,omitempty" yaml:"skipPrefix,omitempty" description:"Skip comment lines by prefix"`
+	EmptyValues    []string          `json:"emptyValues,omitempty" yaml:"emptyValues,omitempty" description:"Placeholder value for empty or missing data"`
+	ExpandFields   map[string]string `json:"expandFields,omitempty" yaml:"expandFields,omitempty" description:"Add fields by text templates"`
+	TrimSpace      bool              `json:"trimSpace,omitempty" yaml:"trimSpace,omitempty" description:"Trim space surrounding values"`
+}
+
+const (
+	defaultKVDelimiter      = " "
+	defaultKVValueSeparator = "="
+	defaultKVQuote          = '"'
+)
+
+func (config KVConfig) BuildPreprocessor() (Interface, error) {
+	s := kvScanner{
+		delimiter: config.Delimiter,
+		separator: config.ValueSeparator,
+		quote:     defaultKVQuote,
+	}
+	if s.delimiter == "" {
+		s.delimiter = defaultKVDelimiter
+	}
+	if s.separator == "" {
+		s.separator = defaultKVValueSeparator
+	}
+	if q := config.Quote; q != "" {
+		if len(q) != 1 {
+			return nil, errors.Errorf("invalid quote character %q", q)
+		}
+		s.quote = q[0]
+	}
+	if strings.Contains(s.delimiter, s.separator) || strings.Contains(s.separator, s.delimiter) {
+		return nil, errors.Errorf("delimiter %q conflicts with value separator %q", s.delimiter, s.separator)
+	}
+	return &matchTextPreprocessor{
+		match:        s.scan,
+		skipLines:    config.SkipLines,
+		skipPrefix:   config.SkipPrefix,
+		emptyValues:  config.EmptyValues,
+		expandFields: compileFieldTemplates(config.ExpandFields),
+		stream:       buildJSONStream(),
+		trimSpace:    config.TrimSpace,
+	}, nil
+}
+
+// kvScanner splits logfmt style text into key/value pairs.
+// Keys and values can be quoted to include delimiters, using `\` to escape quotes inside a quoted string.
+// Keys without a value separator are added with an empty value.
+type kvScanner struct {
+	delimiter string
+	separator string
+	quote     byte
+}
+
+func (s *kvScanner) scan(dst []string, src string) ([]string, error) {
+	var key, value string
+	var err error
+	for {
+		// Skip repeated delimiters
+		for strings.HasPrefix(src, s.delimiter) {
+			src = src[len(s.delimiter):]
+		}
+		// Ignore space surrounding keys
+		if src = strings.TrimLeft(src, " \t"); src == "" {
+			return dst, nil
+		}
+		key, src, err = s.token(src, s.separator)
+		if err != nil {
+			return dst, err
+		}
+		key = strings.TrimRight(key, " \t")
+		if key == "" {
+			return dst, errors.New("empty key")
+		}
+		if !strings.HasPrefix(src, s.separator) {
+			dst = append(dst, key, "")
+			continue
+		}
+		value, src, err = s.token(src[len(s.separator):], "")
+		if err != nil {
+			return dst, err
+		}
+		dst = append(dst, key, value)
+	}
+}
+
+// token reads a possibly quoted token up to the delimiter or stop.
+func (s *kvScanner) token(src, stop string) (string, string, error) {
+	if src != "" && src[0] == s.quote {
+		tok, tail, err := unquote(src, s.quote)
+		if err != nil {
+			return "", src, err
+		}
+		if tail != "" && !strings.HasPrefix(tail, s.delimiter) && !(stop != "" && strings.HasPrefix(tail, stop)) {
+			return "", src, errors.Errorf("unexpected text after quoted string at %q", tail)
+		}
+		return tok, tail, nil
+	}
+	end := strings.Index(src, s.delimiter)
+	if end == -1 {
+		end = len(src)
+	}
+	if stop != "" {
+		if n := strings.Index(src[:end], stop); n != -1 {
+			end = n
+		}
+	}
+	return src[:end], src[end:], nil
+}
+
+// unquote reads a quoted string at the start of src, handling backslash escapes.
+func unquote(src string, quote byte) (string, string, error) {
+	var b strings.Builder
+	start := 1
+	for i := 1; i < len(src); i++ {
+		switch c := src[i]; c {
+		case quote:
+			if b.Len() == 0 {
+				// No escapes, avoid allocating
+				return src[1:i], src[i+1:], nil
+			}
+			b.WriteString(src[start:i])
+			return b.String(), src[i+1:], nil
+		case '\\':
+			if i+1 == len(src) {
+				break
+			}
+			b.WriteString(src[start:i])
+			i++
+			b.WriteByte(unescapeByte(src[i]))
+			start = i + 1
+		}
+	}
+	return "", src, errors.New("unterminated quoted string")
+}
+
+func unescapeByte(c byte) byte {
+	switch c {
+	case 'n':
+		return '\n'
+	case 'r':
+		return '\r'
+	case 't':
+		return '\t'
+	default:
+		return c
+	}
+}
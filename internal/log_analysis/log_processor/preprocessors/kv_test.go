@@ -0,0 +1,94 @@
+package preprocessors
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKVPreprocessor(t *testing.T) {
+	p, err := KVConfig{
+		EmptyValues: []string{"-"},
+	}.BuildPreprocessor()
+	require.NoError(t, err)
+	for _, tc := range []struct {
+		Log       string
+		Expect    string
+		ExpectErr bool
+	}{
+		{
+			Log:    `level=info msg="user logged in" user=alice`,
+			Expect: `{"level":"info","msg":"user logged in","user":"alice"}`,
+		},
+		{
+			Log:    `at=error  msg="quote \" and \\ backslash" path=/api empty= ref=- debug`,
+			Expect: `{"at":"error","msg":"quote \" and \\ backslash","path":"/api","empty":"","debug":""}`,
+		},
+		{
+			Log:    `"quoted key"=1 url=http://example.com/?q=a`,
+			Expect: `{"quoted key":"1","url":"http://example.com/?q=a"}`,
+		},
+		{
+			Log:       `msg="unterminated`,
+			ExpectErr: true,
+		},
+		{
+			Log:       `msg="foo"bar`,
+			ExpectErr: true,
+		},
+	} {
+		tc := tc
+		actual, err := p.PreProcessLog(tc.Log)
+		if tc.ExpectErr {
+			require.Error(t, err, tc.Log)
+			continue
+		}
+		require.NoError(t, err, tc.Log)
+		require.JSONEq(t, tc.Expect, actual, tc.Log)
+	}
+}
+
+func TestKVPreprocessorDelimiters(t *testing.T) {
+	p, err := KVConfig{
+		Delimiter:      ", ",
+		ValueSeparator: ":",
+		Quote:          "'",
+		TrimSpace:      true,
+		ExpandFields: map[string]string{
+			"client": "%{host}:%{port}",
+		},
+	}.BuildPreprocessor()
+	require.NoError(t, err)
+	actual, err := p.PreProcessLog(`host:10.0.0.1, port:22, msg:'Accepted, publickey',  user: root `)
+	require.NoError(t, err)
+	expect := `{"host":"10.0.0.1","port":"22","msg":"Accepted, publickey","user":"root","client":"10.0.0.1:22"}`
+	require.JSONEq(t, expect, actual)
+
+	_, err = KVConfig{
+		Delimiter:      "=",
+		ValueSeparator: "=",
+	}.BuildPreprocessor()
+	require.Error(t, err)
+	_, err = KVConfig{
+		Quote: `""`,
+	}.BuildPreprocessor()
+	require.Error(t, err)
+}
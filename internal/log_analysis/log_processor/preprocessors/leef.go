@@ -0,0 +1,134 @@
+package preprocessors
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// nolint:lll
+type LEEFConfig struct {
+	Delimiter    string            `json:"delimiter,omitempty" yaml:"delimiter,omitempty" description:"Attribute delimiter for LEEF 1.0 entries (defaults to tab). LEEF 2.0 entries define the delimiter in the header"`
+	PrefixField  string            `json:"prefixField,omitempty" yaml:"prefixField,omitempty" description:"Store any text preceding the 'LEEF:' header (ie a syslog header) in this field"`
+	SkipPrefix   string            `json:"skipPrefix,omitempty" yaml:"skipPrefix,omitempty" description:"Skip comment lines by prefix"`
+	EmptyValues  []string          `json:"emptyValues,omitempty" yaml:"emptyValues,omitempty" description:"Placeholder value for empty or missing data"`
+	ExpandFields map[string]string `json:"expandFields,omitempty" yaml:"expandFields,omitempty" description:"Add fields by text templates"`
+	TrimSpace    bool              `json:"trimSpace,omitempty" yaml:"trimSpace,omitempty" description:"Trim space surrounding values"`
+}
+
+// Field names for the LEEF header values.
+// Attributes keep their LEEF key names (ie `src`, `devTime`, `usrName`).
+var leefHeaderFields = []string{
+	"version",
+	"vendor",
+	"product",
+	"productVersion",
+	"eventId",
+}
+
+const defaultLEEFDelimiter = "\t"
+
+func (config LEEFConfig) BuildPreprocessor() (Interface, error) {
+	delimiter := defaultLEEFDelimiter
+	if config.Delimiter != "" {
+		d, err := parseLEEFDelimiter(config.Delimiter)
+		if err != nil {
+			return nil, err
+		}
+		delimiter = d
+	}
+	prefixField := config.PrefixField
+	return &matchTextPreprocessor{
+		match: func(dst []string, src string) ([]string, error) {
+			return matchLEEF(dst, src, delimiter, prefixField)
+		},
+		skipPrefix:   config.SkipPrefix,
+		emptyValues:  config.EmptyValues,
+		expandFields: compileFieldTemplates(config.ExpandFields),
+		stream:       buildJSONStream(),
+		trimSpace:    config.TrimSpace,
+	}, nil
+}
+
+// matchLEEF splits a LEEF entry to key/value pairs
+//
+//	LEEF:1.0|Vendor|Product|Version|EventID|Attributes
+//	LEEF:2.0|Vendor|Product|Version|EventID|DelimiterCharacter|Attributes
+func matchLEEF(dst []string, src, delimiter, prefixField string) ([]string, error) {
+	const tag = "LEEF:"
+	pos := strings.Index(src, tag)
+	if pos == -1 {
+		return dst, errors.New("missing LEEF header")
+	}
+	if prefix := strings.TrimSpace(src[:pos]); prefix != "" && prefixField != "" {
+		dst = append(dst, prefixField, prefix)
+	}
+	header, attributes, err := splitHeader(nil, src[pos+len(tag):], len(leefHeaderFields))
+	if err != nil {
+		return dst, errors.Wrap(err, "invalid LEEF header")
+	}
+	dst = zipFields(dst, leefHeaderFields, header)
+	if strings.HasPrefix(header[0], "2.") {
+		// LEEF 2.0 headers have an extra field for the attribute delimiter
+		end := strings.IndexByte(attributes, '|')
+		if end == -1 {
+			return dst, errors.New("invalid LEEF header: missing delimiter field")
+		}
+		if d := attributes[:end]; d != "" {
+			if delimiter, err = parseLEEFDelimiter(d); err != nil {
+				return dst, err
+			}
+		}
+		attributes = attributes[end+1:]
+	}
+	for _, attr := range strings.Split(attributes, delimiter) {
+		if attr == "" {
+			continue
+		}
+		eq := strings.IndexByte(attr, '=')
+		if eq == -1 {
+			return dst, errors.Errorf("invalid LEEF attribute %q", attr)
+		}
+		key := strings.TrimSpace(attr[:eq])
+		if key == "" {
+			return dst, errors.Errorf("invalid LEEF attribute %q", attr)
+		}
+		dst = append(dst, key, attr[eq+1:])
+	}
+	return dst, nil
+}
+
+// parseLEEFDelimiter parses a delimiter character, either as a literal or as a hex value (ie `x09` or `0x5E`)
+func parseLEEFDelimiter(d string) (string, error) {
+	if len(d) > 1 {
+		hex := strings.TrimPrefix(strings.TrimPrefix(strings.ToLower(d), "0"), "x")
+		if len(hex) == len(d) {
+			return "", errors.Errorf("invalid LEEF delimiter %q", d)
+		}
+		c, err := strconv.ParseUint(hex, 16, 8)
+		if err != nil {
+			return "", errors.Errorf("invalid LEEF delimiter %q", d)
+		}
+		return string([]byte{byte(c)}), nil
+	}
+	return d, nil
+}
@@ -0,0 +1,133 @@
+package preprocessors
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLEEFPreprocessor(t *testing.T) {
+	p, err := LEEFConfig{}.BuildPreprocessor()
+	require.NoError(t, err)
+	for _, tc := range []struct {
+		Log       string
+		Expect    string
+		ExpectErr bool
+	}{
+		{
+			Log: "LEEF:1.0|Microsoft|MSExchange|4.0 SP1|15345|src=192.0.2.0\tdst=172.50.123.1\tsev=5\tcat=anomaly\tmsg=this is a message",
+			Expect: `{
+				"version":"1.0",
+				"vendor":"Microsoft",
+				"product":"MSExchange",
+				"productVersion":"4.0 SP1",
+				"eventId":"15345",
+				"src":"192.0.2.0",
+				"dst":"172.50.123.1",
+				"sev":"5",
+				"cat":"anomaly",
+				"msg":"this is a message"
+			}`,
+		},
+		{
+			Log: "LEEF:2.0|Lancope|StealthWatch|1.0|41|^|src=10.0.1.8^dst=10.0.0.5^sev=5^url=http://x.com/?a=b",
+			Expect: `{
+				"version":"2.0",
+				"vendor":"Lancope",
+				"product":"StealthWatch",
+				"productVersion":"1.0",
+				"eventId":"41",
+				"src":"10.0.1.8",
+				"dst":"10.0.0.5",
+				"sev":"5",
+				"url":"http://x.com/?a=b"
+			}`,
+		},
+		{
+			Log: "LEEF:2.0|Vendor|Product|1.0|41|0x7C|src=10.0.1.8|dst=10.0.0.5",
+			Expect: `{
+				"version":"2.0",
+				"vendor":"Vendor",
+				"product":"Product",
+				"productVersion":"1.0",
+				"eventId":"41",
+				"src":"10.0.1.8",
+				"dst":"10.0.0.5"
+			}`,
+		},
+		{
+			// Non-ASCII delimiters are single bytes
+			Log: "LEEF:2.0|Vendor|Product|1.0|41|xA6|src=10.0.1.8\xa6dst=10.0.0.5",
+			Expect: `{
+				"version":"2.0",
+				"vendor":"Vendor",
+				"product":"Product",
+				"productVersion":"1.0",
+				"eventId":"41",
+				"src":"10.0.1.8",
+				"dst":"10.0.0.5"
+			}`,
+		},
+		{
+			Log:       "LEEF:1.0|Vendor|Product|1.0|41|src",
+			ExpectErr: true,
+		},
+		{
+			Log:       "LEEF:2.0|Vendor|Product|1.0|41|xZZ|src=10.0.1.8",
+			ExpectErr: true,
+		},
+	} {
+		tc := tc
+		actual, err := p.PreProcessLog(tc.Log)
+		if tc.ExpectErr {
+			require.Error(t, err, tc.Log)
+			continue
+		}
+		require.NoError(t, err, tc.Log)
+		require.JSONEq(t, tc.Expect, actual, tc.Log)
+	}
+}
+
+func TestLEEFPreprocessorDelimiter(t *testing.T) {
+	p, err := LEEFConfig{
+		Delimiter:   "x7C",
+		PrefixField: "syslogHeader",
+	}.BuildPreprocessor()
+	require.NoError(t, err)
+	actual, err := p.PreProcessLog("<13>Jan 18 11:07:53 host LEEF:1.0|Vendor|Product|1.0|41|src=10.0.1.8|usrName=root")
+	require.NoError(t, err)
+	expect := `{
+		"syslogHeader":"<13>Jan 18 11:07:53 host",
+		"version":"1.0",
+		"vendor":"Vendor",
+		"product":"Product",
+		"productVersion":"1.0",
+		"eventId":"41",
+		"src":"10.0.1.8",
+		"usrName":"root"
+	}`
+	require.JSONEq(t, expect, actual)
+
+	_, err = LEEFConfig{
+		Delimiter: "||",
+	}.BuildPreprocessor()
+	require.Error(t, err)
+}
@@ -31,3 +31,18 @@ func TestExpandFieldTempalte(t *testing.T) {
 	result := expandFieldTemplate(nil, fields, tpl)
 	require.Equal(t, "10", string(result))
 }
+
+func TestMatchTextPreprocessorResetsOutput(t *testing.T) {
+	assert := require.New(t)
+	p, err := CSVMatchConfig{
+		Columns: []string{"foo", "bar"},
+	}.BuildPreprocessor()
+	assert.NoError(err)
+	// Each log entry should produce a single JSON object regardless of previous entries
+	result, err := p.PreProcessLog("1,2")
+	assert.NoError(err)
+	assert.JSONEq(`{"foo":"1","bar":"2"}`, result)
+	result, err = p.PreProcessLog("3,4")
+	assert.NoError(err)
+	assert.JSONEq(`{"foo":"3","bar":"4"}`, result)
+}
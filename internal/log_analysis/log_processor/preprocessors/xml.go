@@ -0,0 +1,211 @@
+package preprocessors
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+)
+
+// XMLConfig maps an XML document to a JSON object using the following rules:
+//   - The root element is mapped to the event object, its name is discarded
+//   - Attributes are mapped to fields named `attributePrefix` + attribute name, the prefix keeps them apart from child elements
+//   - Elements with neither attributes nor child elements are mapped to a string field with the element text
+//   - Empty elements without attributes are mapped to `null`
+//   - Elements with attributes or child elements are mapped to objects, their text is stored in the `textField` field
+//   - Repeated sibling elements are mapped to an array, elements listed in `arrayElements` always map to an array
+//   - Namespace prefixes are dropped, `xmlns` attributes, comments and processing instructions are ignored
+//   - Surrounding space is trimmed from all text
+//
+// XML documents that span multiple lines should use parser framing to read one document per log entry.
+// nolint:lll
+type XMLConfig struct {
+	AttributePrefix string   `json:"attributePrefix,omitempty" yaml:"attributePrefix,omitempty" description:"Prefix for the field names of element attributes (defaults to '@')"`
+	TextField       string   `json:"textField,omitempty" yaml:"textField,omitempty" description:"Field name for the text of elements with attributes or child elements (defaults to 'text')"`
+	ArrayElements   []string `json:"arrayElements,omitempty" yaml:"arrayElements,omitempty" description:"Names of elements that always map to an array, even if they occur once"`
+	SkipPrefix      string   `json:"skipPrefix,omitempty" yaml:"skipPrefix,omitempty" description:"Skip comment lines by prefix"`
+}
+
+const (
+	defaultXMLAttributePrefix = "@"
+	defaultXMLTextField       = "text"
+)
+
+func (config XMLConfig) BuildPreprocessor() (Interface, error) {
+	attributePrefix := config.AttributePrefix
+	if attributePrefix == "" {
+		attributePrefix = defaultXMLAttributePrefix
+	}
+	textField := config.TextField
+	if textField == "" {
+		textField = defaultXMLTextField
+	}
+	if strings.HasPrefix(textField, attributePrefix) {
+		return nil, errors.Errorf("text field %q conflicts with attribute prefix %q", textField, attributePrefix)
+	}
+	return &xmlPreprocessor{
+		stream:          buildJSONStream(),
+		attributePrefix: attributePrefix,
+		textField:       textField,
+		arrayElements:   config.ArrayElements,
+		skipPrefix:      config.SkipPrefix,
+	}, nil
+}
+
+type xmlPreprocessor struct {
+	stream          *jsoniter.Stream
+	attributePrefix string
+	textField       string
+	arrayElements   []string
+	skipPrefix      string
+}
+
+type xmlNode struct {
+	name     string
+	attrs    []xml.Attr
+	children []*xmlNode
+	text     strings.Builder
+}
+
+func (p *xmlPreprocessor) PreProcessLog(log string) (string, error) {
+	if prefix := p.skipPrefix; prefix != "" && strings.HasPrefix(log, prefix) {
+		return "", nil
+	}
+	root, err := readXMLDocument(log)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid XML document")
+	}
+	p.stream.SetBuffer(p.stream.Buffer()[:0])
+	p.writeObject(root)
+	return string(p.stream.Buffer()), nil
+}
+
+// readXMLDocument reads the root element of an XML document
+func readXMLDocument(log string) (*xmlNode, error) {
+	// The decoder keeps state between documents so we need a new one for each log entry
+	decoder := xml.NewDecoder(strings.NewReader(log))
+	var stack []*xmlNode
+	for {
+		tok, err := decoder.Token()
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			node := &xmlNode{
+				name: tok.Name.Local,
+			}
+			for _, attr := range tok.Attr {
+				if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" {
+					continue
+				}
+				node.attrs = append(node.attrs, attr)
+			}
+			if n := len(stack); n > 0 {
+				parent := stack[n-1]
+				parent.children = append(parent.children, node)
+			}
+			stack = append(stack, node)
+		case xml.EndElement:
+			n := len(stack) - 1
+			if n == 0 {
+				return stack[0], nil
+			}
+			stack = stack[:n]
+		case xml.CharData:
+			if n := len(stack); n > 0 {
+				stack[n-1].text.Write(tok)
+			}
+		}
+	}
+}
+
+func (p *xmlPreprocessor) writeObject(node *xmlNode) {
+	stream := p.stream
+	stream.WriteObjectStart()
+	more := false
+	for _, attr := range node.attrs {
+		if more {
+			stream.WriteMore()
+		}
+		stream.WriteObjectField(p.attributePrefix + attr.Name.Local)
+		stream.WriteString(attr.Value)
+		more = true
+	}
+	for i, child := range node.children {
+		if indexChild(node.children[:i], child.name) != -1 {
+			// Already written as part of an array
+			continue
+		}
+		if more {
+			stream.WriteMore()
+		}
+		stream.WriteObjectField(child.name)
+		more = true
+		if !contains(p.arrayElements, child.name) && indexChild(node.children[i+1:], child.name) == -1 {
+			p.writeValue(child)
+			continue
+		}
+		stream.WriteArrayStart()
+		p.writeValue(child)
+		for _, sibling := range node.children[i+1:] {
+			if sibling.name == child.name {
+				stream.WriteMore()
+				p.writeValue(sibling)
+			}
+		}
+		stream.WriteArrayEnd()
+	}
+	if text := strings.TrimSpace(node.text.String()); text != "" {
+		if more {
+			stream.WriteMore()
+		}
+		stream.WriteObjectField(p.textField)
+		stream.WriteString(text)
+	}
+	stream.WriteObjectEnd()
+}
+
+func (p *xmlPreprocessor) writeValue(node *xmlNode) {
+	if len(node.attrs) > 0 || len(node.children) > 0 {
+		p.writeObject(node)
+		return
+	}
+	if text := strings.TrimSpace(node.text.String()); text != "" {
+		p.stream.WriteString(text)
+		return
+	}
+	p.stream.WriteNil()
+}
+
+func indexChild(nodes []*xmlNode, name string) int {
+	for i, node := range nodes {
+		if node.name == name {
+			return i
+		}
+	}
+	return -1
+}
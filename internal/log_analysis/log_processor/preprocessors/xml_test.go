@@ -0,0 +1,121 @@
+package preprocessors
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestXMLPreprocessor(t *testing.T) {
+	p, err := XMLConfig{
+		ArrayElements: []string{"Data"},
+	}.BuildPreprocessor()
+	require.NoError(t, err)
+	for _, tc := range []struct {
+		Log       string
+		Expect    string
+		ExpectErr bool
+	}{
+		{
+			// nolint:lll
+			Log: `<?xml version="1.0"?><Event xmlns="http://schemas.microsoft.com/win/2004/08/events/event"><System><Provider Name="Microsoft-Windows-Security-Auditing" Guid="{54849625}"/><EventID>4624</EventID><Keywords/><TimeCreated SystemTime="2020-06-02T00:01:07.000Z"/></System><EventData><Data Name="SubjectUserSid">S-1-5-18</Data><Data Name="TargetUserName">alice</Data></EventData></Event>`,
+			Expect: `{
+				"System": {
+					"Provider": {"@Name": "Microsoft-Windows-Security-Auditing", "@Guid": "{54849625}"},
+					"EventID": "4624",
+					"Keywords": null,
+					"TimeCreated": {"@SystemTime": "2020-06-02T00:01:07.000Z"}
+				},
+				"EventData": {
+					"Data": [
+						{"@Name": "SubjectUserSid", "text": "S-1-5-18"},
+						{"@Name": "TargetUserName", "text": "alice"}
+					]
+				}
+			}`,
+		},
+		{
+			Log: `<log level="info"><!-- comment --><host> web-1 </host><tag>a</tag><tag>b</tag><Data Name="x"/><![CDATA[some <text>]]></log>`,
+			Expect: `{
+				"@level": "info",
+				"host": "web-1",
+				"tag": ["a", "b"],
+				"Data": [{"@Name": "x"}],
+				"text": "some <text>"
+			}`,
+		},
+		{
+			Log:    `<msg>hello</msg>`,
+			Expect: `{"text": "hello"}`,
+		},
+		{
+			// Attributes do not collide with child elements of the same name
+			Log:    `<log host="a"><host>b</host></log>`,
+			Expect: `{"@host": "a", "host": "b"}`,
+		},
+		{
+			Log:       `<Event><System></Event>`,
+			ExpectErr: true,
+		},
+		{
+			Log:       `<Event><System>`,
+			ExpectErr: true,
+		},
+		{
+			Log:       `{"foo":"bar"}`,
+			ExpectErr: true,
+		},
+	} {
+		tc := tc
+		actual, err := p.PreProcessLog(tc.Log)
+		if tc.ExpectErr {
+			require.Error(t, err, tc.Log)
+			continue
+		}
+		require.NoError(t, err, tc.Log)
+		require.JSONEq(t, tc.Expect, actual, tc.Log)
+	}
+}
+
+func TestXMLPreprocessorConfig(t *testing.T) {
+	p, err := XMLConfig{
+		AttributePrefix: "@",
+		TextField:       "#text",
+		SkipPrefix:      "#",
+	}.BuildPreprocessor()
+	require.NoError(t, err)
+	actual, err := p.PreProcessLog(`<a:event xmlns:a="urn:a" id="1"><a:id>2</a:id><note lang="en">hi</note></a:event>`)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"@id": "1", "id": "2", "note": {"@lang": "en", "#text": "hi"}}`, actual)
+	actual, err = p.PreProcessLog(`# comment`)
+	require.NoError(t, err)
+	require.Empty(t, actual)
+
+	_, err = XMLConfig{
+		AttributePrefix: "_",
+		TextField:       "_text",
+	}.BuildPreprocessor()
+	require.Error(t, err)
+	_, err = XMLConfig{
+		TextField: "@text",
+	}.BuildPreprocessor()
+	require.Error(t, err)
+}
@@ -0,0 +1,666 @@
+package logstream
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/flate"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"math"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pkg/errors"
+)
+
+// AvroMagic is the header of Avro Object Container Files
+var AvroMagic = []byte{'O', 'b', 'j', 1}
+
+const (
+	avroSyncSize = 16
+	// maxAvroBlockSize protects against corrupt block headers making us allocate huge buffers
+	maxAvroBlockSize = 64 * 1024 * 1024
+)
+
+// NewAvroStream creates a stream that reads the records of an Avro Object Container File as JSON objects.
+// r is the underlying io.Reader
+// size is the read buffer size
+// Unions are written as the value of the selected branch and logical types are converted to their JSON
+// representation, so records can be classified by log schemas that expect plain JSON events.
+func NewAvroStream(r io.Reader, size int) *AvroStream {
+	if size <= 0 {
+		size = DefaultBufferSize
+	} else if size < MinBufferSize {
+		size = MinBufferSize
+	}
+	return &AvroStream{
+		r:      bufio.NewReaderSize(r, size),
+		stream: newRecordStream(),
+	}
+}
+
+// AvroStream is a log entry stream that iterates over the records of an Avro Object Container File.
+type AvroStream struct {
+	r      *bufio.Reader
+	schema *avroSchema
+	codec  string
+	sync   [avroSyncSize]byte
+	// block holds the decompressed data of the current block
+	block      avroDecoder
+	remaining  int64
+	scratch    []byte
+	stream     *jsoniter.Stream
+	numEntries int64
+	err        error
+}
+
+// Err implements the Stream interface
+func (s *AvroStream) Err() error {
+	if errors.Is(s.err, io.EOF) {
+		return nil
+	}
+	return s.err
+}
+
+// Next implements the Stream interface
+func (s *AvroStream) Next() []byte {
+	if s.err != nil {
+		return nil
+	}
+	if s.schema == nil {
+		if err := s.readHeader(); err != nil {
+			s.err = err
+			return nil
+		}
+	}
+	// Blocks can have zero records
+	for s.remaining == 0 {
+		if err := s.readBlock(); err != nil {
+			s.err = err
+			return nil
+		}
+	}
+	s.stream.SetBuffer(s.stream.Buffer()[:0])
+	if err := s.block.decode(s.stream, s.schema); err != nil {
+		s.err = errors.Wrapf(err, "failed to decode avro record %d", s.numEntries)
+		return nil
+	}
+	s.remaining--
+	s.numEntries++
+	// Return the entry data. It is valid until the next call to Next
+	return s.stream.Buffer()
+}
+
+func (s *AvroStream) readHeader() error {
+	magic := make([]byte, len(AvroMagic))
+	if _, err := io.ReadFull(s.r, magic); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return errors.New("invalid avro file header")
+		}
+		return errors.WithStack(err)
+	}
+	if !bytes.Equal(magic, AvroMagic) {
+		return errors.New("invalid avro file header")
+	}
+	meta := map[string][]byte{}
+	for {
+		n, err := binary.ReadVarint(s.r)
+		if err != nil {
+			return errors.Wrap(err, "failed to read avro file metadata")
+		}
+		if n == 0 {
+			break
+		}
+		if n < 0 {
+			// Negative counts are followed by the size of the block in bytes
+			n = -n
+			if _, err := binary.ReadVarint(s.r); err != nil {
+				return errors.Wrap(err, "failed to read avro file metadata")
+			}
+		}
+		for i := int64(0); i < n; i++ {
+			key, err := s.readBytes()
+			if err != nil {
+				return errors.Wrap(err, "failed to read avro file metadata")
+			}
+			value, err := s.readBytes()
+			if err != nil {
+				return errors.Wrap(err, "failed to read avro file metadata")
+			}
+			meta[string(key)] = value
+		}
+	}
+	if _, err := io.ReadFull(s.r, s.sync[:]); err != nil {
+		return errors.Wrap(err, "failed to read avro sync marker")
+	}
+	schema, err := parseAvroSchema(meta["avro.schema"])
+	if err != nil {
+		return err
+	}
+	s.schema = schema
+	s.codec = string(meta["avro.codec"])
+	switch s.codec {
+	case "":
+		s.codec = "null"
+	case "null", "deflate", "snappy", "zstandard", "bzip2":
+	default:
+		return errors.Errorf("unsupported avro codec %q", s.codec)
+	}
+	return nil
+}
+
+func (s *AvroStream) readBytes() ([]byte, error) {
+	n, err := binary.ReadVarint(s.r)
+	if err != nil {
+		return nil, err
+	}
+	if n < 0 || n > maxAvroBlockSize {
+		return nil, errors.Errorf("invalid avro bytes length %d", n)
+	}
+	data := make([]byte, n)
+	if _, err := io.ReadFull(s.r, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (s *AvroStream) readBlock() error {
+	count, err := binary.ReadVarint(s.r)
+	if err != nil {
+		if err == io.EOF {
+			return err
+		}
+		return errors.Wrap(err, "failed to read avro block header")
+	}
+	size, err := binary.ReadVarint(s.r)
+	if err != nil {
+		return errors.Wrap(err, "failed to read avro block header")
+	}
+	if count < 0 || size < 0 || size > maxAvroBlockSize {
+		return errors.Errorf("invalid avro block header (count=%d, size=%d)", count, size)
+	}
+	if int64(cap(s.scratch)) < size {
+		s.scratch = make([]byte, size)
+	}
+	s.scratch = s.scratch[:size]
+	if _, err := io.ReadFull(s.r, s.scratch); err != nil {
+		return errors.Wrap(err, "failed to read avro block")
+	}
+	sync := [avroSyncSize]byte{}
+	if _, err := io.ReadFull(s.r, sync[:]); err != nil {
+		return errors.Wrap(err, "failed to read avro sync marker")
+	}
+	if sync != s.sync {
+		return errors.New("invalid avro sync marker")
+	}
+	data, err := s.decompress(s.scratch)
+	if err != nil {
+		return errors.Wrapf(err, "failed to decompress avro block with %s codec", s.codec)
+	}
+	s.block = avroDecoder{buf: data}
+	s.remaining = count
+	return nil
+}
+
+func (s *AvroStream) decompress(block []byte) ([]byte, error) {
+	switch s.codec {
+	case "deflate":
+		return ioutil.ReadAll(flate.NewReader(bytes.NewReader(block)))
+	case "bzip2":
+		return ioutil.ReadAll(bzip2.NewReader(bytes.NewReader(block)))
+	case "snappy":
+		// Snappy blocks are followed by the CRC32 checksum of the uncompressed data
+		if len(block) < 4 {
+			return nil, errors.New("block too short")
+		}
+		data, err := snappy.Decode(nil, block[:len(block)-4])
+		if err != nil {
+			return nil, err
+		}
+		if crc32.ChecksumIEEE(data) != binary.BigEndian.Uint32(block[len(block)-4:]) {
+			return nil, errors.New("checksum mismatch")
+		}
+		return data, nil
+	case "zstandard":
+		dec, err := zstd.NewReader(bytes.NewReader(block), zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		defer dec.Close()
+		return ioutil.ReadAll(dec)
+	default:
+		// The block is only valid until the next block is read, so it can be used without copying
+		return block, nil
+	}
+}
+
+// Avro schema types
+const (
+	avroNull    = "null"
+	avroBoolean = "boolean"
+	avroInt     = "int"
+	avroLong    = "long"
+	avroFloat   = "float"
+	avroDouble  = "double"
+	avroBytes   = "bytes"
+	avroString  = "string"
+	avroRecord  = "record"
+	avroEnum    = "enum"
+	avroArray   = "array"
+	avroMap     = "map"
+	avroFixed   = "fixed"
+	avroUnion   = "union"
+)
+
+type avroSchema struct {
+	Type        string
+	Name        string
+	LogicalType string
+	Scale       int
+	Fields      []avroField
+	Symbols     []string
+	Size        int
+	Items       *avroSchema
+	Values      *avroSchema
+	Branches    []*avroSchema
+}
+
+type avroField struct {
+	Name   string
+	Schema *avroSchema
+}
+
+func parseAvroSchema(data []byte) (*avroSchema, error) {
+	if len(data) == 0 {
+		return nil, errors.New("missing avro schema")
+	}
+	var v interface{}
+	if err := jsoniter.Unmarshal(data, &v); err != nil {
+		return nil, errors.Wrap(err, "invalid avro schema")
+	}
+	p := avroSchemaParser{
+		named: map[string]*avroSchema{},
+	}
+	schema, err := p.parse(v, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid avro schema")
+	}
+	return schema, nil
+}
+
+// avroSchemaParser keeps track of named types so they can be referenced by name
+type avroSchemaParser struct {
+	named map[string]*avroSchema
+}
+
+func (p *avroSchemaParser) parse(v interface{}, namespace string) (*avroSchema, error) {
+	switch v := v.(type) {
+	case string:
+		switch v {
+		case avroNull, avroBoolean, avroInt, avroLong, avroFloat, avroDouble, avroBytes, avroString:
+			return &avroSchema{Type: v}, nil
+		}
+		if s, ok := p.named[avroFullName(v, namespace)]; ok {
+			return s, nil
+		}
+		if s, ok := p.named[v]; ok {
+			return s, nil
+		}
+		return nil, errors.Errorf("unknown type %q", v)
+	case []interface{}:
+		s := &avroSchema{Type: avroUnion}
+		for _, b := range v {
+			branch, err := p.parse(b, namespace)
+			if err != nil {
+				return nil, err
+			}
+			s.Branches = append(s.Branches, branch)
+		}
+		return s, nil
+	case map[string]interface{}:
+		return p.parseComplex(v, namespace)
+	default:
+		return nil, errors.Errorf("invalid type definition %v", v)
+	}
+}
+
+func (p *avroSchemaParser) parseComplex(v map[string]interface{}, namespace string) (*avroSchema, error) {
+	typ, ok := v["type"].(string)
+	if !ok {
+		return p.parse(v["type"], namespace)
+	}
+	switch typ {
+	case avroRecord, "error":
+		name, ns := p.name(v, namespace)
+		s := &avroSchema{
+			Type: avroRecord,
+			Name: name,
+		}
+		// Register the record before parsing fields to allow recursive types
+		p.named[name] = s
+		fields, _ := v["fields"].([]interface{})
+		for _, f := range fields {
+			field, ok := f.(map[string]interface{})
+			if !ok {
+				return nil, errors.Errorf("invalid field in record %q", name)
+			}
+			fieldName, _ := field["name"].(string)
+			fieldSchema, err := p.parse(field["type"], ns)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid field %q in record %q", fieldName, name)
+			}
+			s.Fields = append(s.Fields, avroField{
+				Name:   fieldName,
+				Schema: fieldSchema,
+			})
+		}
+		return s, nil
+	case avroEnum:
+		name, _ := p.name(v, namespace)
+		s := &avroSchema{
+			Type: avroEnum,
+			Name: name,
+		}
+		symbols, _ := v["symbols"].([]interface{})
+		for _, sym := range symbols {
+			symbol, _ := sym.(string)
+			s.Symbols = append(s.Symbols, symbol)
+		}
+		p.named[name] = s
+		return s, nil
+	case avroFixed:
+		name, _ := p.name(v, namespace)
+		size, _ := v["size"].(float64)
+		s := &avroSchema{
+			Type:        avroFixed,
+			Name:        name,
+			Size:        int(size),
+			LogicalType: avroLogicalType(v),
+			Scale:       avroScale(v),
+		}
+		p.named[name] = s
+		return s, nil
+	case avroArray:
+		items, err := p.parse(v["items"], namespace)
+		if err != nil {
+			return nil, err
+		}
+		return &avroSchema{
+			Type:  avroArray,
+			Items: items,
+		}, nil
+	case avroMap:
+		values, err := p.parse(v["values"], namespace)
+		if err != nil {
+			return nil, err
+		}
+		return &avroSchema{
+			Type:   avroMap,
+			Values: values,
+		}, nil
+	default:
+		s, err := p.parse(typ, namespace)
+		if err != nil {
+			return nil, err
+		}
+		if logicalType := avroLogicalType(v); logicalType != "" {
+			// Primitive schemas are not shared so we can annotate them
+			s.LogicalType = logicalType
+			s.Scale = avroScale(v)
+		}
+		return s, nil
+	}
+}
+
+// name resolves the full name and the namespace for the fields of a named type
+func (p *avroSchemaParser) name(v map[string]interface{}, namespace string) (string, string) {
+	name, _ := v["name"].(string)
+	if ns, ok := v["namespace"].(string); ok {
+		namespace = ns
+	}
+	fullName := avroFullName(name, namespace)
+	if pos := strings.LastIndexByte(fullName, '.'); pos != -1 {
+		return fullName, fullName[:pos]
+	}
+	return fullName, ""
+}
+
+func avroFullName(name, namespace string) string {
+	if namespace == "" || strings.Contains(name, ".") {
+		return name
+	}
+	return namespace + "." + name
+}
+
+func avroLogicalType(v map[string]interface{}) string {
+	logicalType, _ := v["logicalType"].(string)
+	return logicalType
+}
+
+func avroScale(v map[string]interface{}) int {
+	scale, _ := v["scale"].(float64)
+	return int(scale)
+}
+
+var errAvroShortBuffer = errors.New("unexpected end of avro data")
+
+// avroDecoder decodes Avro binary data to JSON
+type avroDecoder struct {
+	buf []byte
+	pos int
+}
+
+func (d *avroDecoder) readLong() (int64, error) {
+	n, size := binary.Varint(d.buf[d.pos:])
+	if size <= 0 {
+		return 0, errAvroShortBuffer
+	}
+	d.pos += size
+	return n, nil
+}
+
+func (d *avroDecoder) readFixed(n int) ([]byte, error) {
+	if n < 0 || len(d.buf)-d.pos < n {
+		return nil, errAvroShortBuffer
+	}
+	data := d.buf[d.pos : d.pos+n]
+	d.pos += n
+	return data, nil
+}
+
+func (d *avroDecoder) readBytes() ([]byte, error) {
+	n, err := d.readLong()
+	if err != nil {
+		return nil, err
+	}
+	if n > int64(len(d.buf)-d.pos) {
+		return nil, errAvroShortBuffer
+	}
+	return d.readFixed(int(n))
+}
+
+// readBlockCount reads the item count of an array or map block
+func (d *avroDecoder) readBlockCount() (int64, error) {
+	n, err := d.readLong()
+	if err != nil {
+		return 0, err
+	}
+	if n < 0 {
+		// Negative counts are followed by the size of the block in bytes
+		if _, err := d.readLong(); err != nil {
+			return 0, err
+		}
+		return -n, nil
+	}
+	return n, nil
+}
+
+//nolint:gocyclo
+func (d *avroDecoder) decode(stream *jsoniter.Stream, s *avroSchema) error {
+	switch s.Type {
+	case avroNull:
+		stream.WriteNil()
+	case avroBoolean:
+		data, err := d.readFixed(1)
+		if err != nil {
+			return err
+		}
+		stream.WriteBool(data[0] != 0)
+	case avroInt, avroLong:
+		n, err := d.readLong()
+		if err != nil {
+			return err
+		}
+		switch s.LogicalType {
+		case "date":
+			writeDate(stream, n)
+		case "timestamp-millis", "local-timestamp-millis":
+			writeTimestamp(stream, time.Unix(n/1e3, (n%1e3)*1e6))
+		case "timestamp-micros", "local-timestamp-micros":
+			writeTimestamp(stream, time.Unix(n/1e6, (n%1e6)*1e3))
+		default:
+			stream.WriteInt64(n)
+		}
+	case avroFloat:
+		data, err := d.readFixed(4)
+		if err != nil {
+			return err
+		}
+		writeFloat(stream, float64(math.Float32frombits(binary.LittleEndian.Uint32(data))))
+	case avroDouble:
+		data, err := d.readFixed(8)
+		if err != nil {
+			return err
+		}
+		writeFloat(stream, math.Float64frombits(binary.LittleEndian.Uint64(data)))
+	case avroString:
+		data, err := d.readBytes()
+		if err != nil {
+			return err
+		}
+		stream.WriteString(string(data))
+	case avroBytes, avroFixed:
+		var data []byte
+		var err error
+		if s.Type == avroFixed {
+			data, err = d.readFixed(s.Size)
+		} else {
+			data, err = d.readBytes()
+		}
+		if err != nil {
+			return err
+		}
+		if s.LogicalType == "decimal" {
+			writeDecimal(stream, bigIntFromBytes(data), s.Scale)
+			return nil
+		}
+		writeBinary(stream, data)
+	case avroEnum:
+		n, err := d.readLong()
+		if err != nil {
+			return err
+		}
+		if n < 0 || n >= int64(len(s.Symbols)) {
+			return errors.Errorf("invalid symbol index %d for enum %q", n, s.Name)
+		}
+		stream.WriteString(s.Symbols[n])
+	case avroUnion:
+		n, err := d.readLong()
+		if err != nil {
+			return err
+		}
+		if n < 0 || n >= int64(len(s.Branches)) {
+			return errors.Errorf("invalid union branch %d", n)
+		}
+		return d.decode(stream, s.Branches[n])
+	case avroArray:
+		stream.WriteArrayStart()
+		numItems := 0
+		for {
+			n, err := d.readBlockCount()
+			if err != nil {
+				return err
+			}
+			if n == 0 {
+				break
+			}
+			for i := int64(0); i < n; i++ {
+				if numItems > 0 {
+					stream.WriteMore()
+				}
+				if err := d.decode(stream, s.Items); err != nil {
+					return err
+				}
+				numItems++
+			}
+		}
+		stream.WriteArrayEnd()
+	case avroMap:
+		stream.WriteObjectStart()
+		numItems := 0
+		for {
+			n, err := d.readBlockCount()
+			if err != nil {
+				return err
+			}
+			if n == 0 {
+				break
+			}
+			for i := int64(0); i < n; i++ {
+				if numItems > 0 {
+					stream.WriteMore()
+				}
+				key, err := d.readBytes()
+				if err != nil {
+					return err
+				}
+				stream.WriteObjectField(string(key))
+				if err := d.decode(stream, s.Values); err != nil {
+					return err
+				}
+				numItems++
+			}
+		}
+		stream.WriteObjectEnd()
+	case avroRecord:
+		stream.WriteObjectStart()
+		for i := range s.Fields {
+			field := &s.Fields[i]
+			if i > 0 {
+				stream.WriteMore()
+			}
+			stream.WriteObjectField(field.Name)
+			if err := d.decode(stream, field.Schema); err != nil {
+				return err
+			}
+		}
+		stream.WriteObjectEnd()
+	default:
+		return errors.Errorf("unsupported avro type %q", s.Type)
+	}
+	return nil
+}
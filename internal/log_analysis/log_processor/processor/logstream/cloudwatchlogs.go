@@ -0,0 +1,147 @@
+package logstream
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"io"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+)
+
+// CloudWatchLogsMagic is the start of the JSON envelopes delivered by CloudWatch Logs subscriptions
+var CloudWatchLogsMagic = []byte(`{"messageType":`)
+
+const (
+	cloudWatchLogsDataMessage    = "DATA_MESSAGE"
+	cloudWatchLogsControlMessage = "CONTROL_MESSAGE"
+)
+
+// NewCloudWatchLogsStream creates a stream of the log events in CloudWatch Logs subscription envelopes.
+// r is the underlying io.Reader
+// size is the read buffer size for the jsoniter.Iterator
+// Kinesis Firehose concatenates the envelopes of a subscription so the input can have multiple envelopes
+// with or without whitespace between them.
+func NewCloudWatchLogsStream(r io.Reader, size int) *CloudWatchLogsStream {
+	if size <= 0 {
+		size = DefaultBufferSize
+	} else if size < MinBufferSize {
+		size = MinBufferSize
+	}
+	return &CloudWatchLogsStream{
+		iter: jsoniter.Parse(jsoniter.ConfigDefault, r, size),
+	}
+}
+
+// CloudWatchLogsStream is a log entry stream that unwraps the log events of CloudWatch Logs subscription envelopes.
+// Each log event message is a separate log entry.
+// The log group and log stream of the envelope that contains the current entry are available until the next call to Next.
+type CloudWatchLogsStream struct {
+	iter       *jsoniter.Iterator
+	data       cloudWatchLogsData
+	events     []cloudWatchLogEvent
+	err        error
+	entry      []byte
+	numEntries int64
+}
+
+// cloudWatchLogsData is the envelope of log events delivered by a CloudWatch Logs subscription
+// See https://docs.aws.amazon.com/AmazonCloudWatch/latest/logs/SubscriptionFilters.html
+type cloudWatchLogsData struct {
+	MessageType string               `json:"messageType"`
+	Owner       string               `json:"owner"`
+	LogGroup    string               `json:"logGroup"`
+	LogStream   string               `json:"logStream"`
+	LogEvents   []cloudWatchLogEvent `json:"logEvents"`
+}
+
+type cloudWatchLogEvent struct {
+	ID        string `json:"id"`
+	Timestamp int64  `json:"timestamp"`
+	Message   string `json:"message"`
+}
+
+// LogGroup returns the log group of the current entry
+func (s *CloudWatchLogsStream) LogGroup() string {
+	return s.data.LogGroup
+}
+
+// LogStream returns the log stream of the current entry
+func (s *CloudWatchLogsStream) LogStream() string {
+	return s.data.LogStream
+}
+
+// Err implements the Stream interface
+func (s *CloudWatchLogsStream) Err() error {
+	if errors.Is(s.err, io.EOF) {
+		return nil
+	}
+	return s.err
+}
+
+// Next implements the Stream interface
+func (s *CloudWatchLogsStream) Next() []byte {
+	if s.err != nil {
+		return nil
+	}
+	// Envelopes can have no log events so we read until we find one that has
+	for len(s.events) == 0 {
+		if err := s.readEnvelope(); err != nil {
+			s.err = err
+			return nil
+		}
+	}
+	event := &s.events[0]
+	s.events = s.events[1:]
+	s.entry = append(s.entry[:0], event.Message...)
+	s.numEntries++
+	// Return the entry data. It is valid until the next call to Next
+	return s.entry
+}
+
+func (s *CloudWatchLogsStream) readEnvelope() error {
+	if s.iter.WhatIsNext() == jsoniter.InvalidValue {
+		// The iterator reports io.EOF if there are no more envelopes
+		if err := s.iter.Error; err != nil {
+			return errors.WithStack(err)
+		}
+		return errors.New("invalid CloudWatch Logs envelope")
+	}
+	// Reuse the log events slice of the previous envelope
+	s.data = cloudWatchLogsData{
+		LogEvents: s.data.LogEvents[:0],
+	}
+	s.iter.ReadVal(&s.data)
+	if err := s.iter.Error; err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return errors.Wrap(err, "failed to read CloudWatch Logs envelope")
+	}
+	switch s.data.MessageType {
+	case cloudWatchLogsDataMessage:
+		s.events = s.data.LogEvents
+	case cloudWatchLogsControlMessage:
+		// CloudWatch Logs sends control messages to check that the destination is reachable
+		s.events = nil
+	default:
+		return errors.Errorf("invalid CloudWatch Logs message type %q", s.data.MessageType)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package logstream
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bytes"
+	"compress/gzip"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// nolint:lll
+const (
+	testCloudWatchLogsDataA   = `{"messageType":"DATA_MESSAGE","owner":"123456789012","logGroup":"/aws/lambda/foo","logStream":"2020/10/01/[$LATEST]0123456789abcdef","subscriptionFilters":["panther"],"logEvents":[{"id":"1","timestamp":1601553600000,"message":"{\"foo\":\"bar\"}"},{"id":"2","timestamp":1601553600001,"message":"plain text"}]}`
+	testCloudWatchLogsDataB   = `{"messageType":"DATA_MESSAGE","owner":"123456789012","logGroup":"/aws/lambda/bar","logStream":"2020/10/01/[$LATEST]fedcba9876543210","subscriptionFilters":["panther"],"logEvents":[{"id":"3","timestamp":1601553600002,"message":"baz"}]}`
+	testCloudWatchLogsControl = `{"messageType":"CONTROL_MESSAGE","owner":"CloudwatchLogs","logGroup":"","logStream":"","subscriptionFilters":[],"logEvents":[{"id":"","timestamp":1601553600000,"message":"CWL CONTROL MESSAGE: Checking health of destination Firehose."}]}`
+)
+
+func TestCloudWatchLogsStream(t *testing.T) {
+	assert := require.New(t)
+	// Firehose concatenates the gzip members of each envelope without a separator
+	var buf bytes.Buffer
+	for _, data := range []string{testCloudWatchLogsControl, testCloudWatchLogsDataA, testCloudWatchLogsDataB} {
+		gz := gzip.NewWriter(&buf)
+		_, err := gz.Write([]byte(data))
+		assert.NoError(err)
+		assert.NoError(gz.Close())
+	}
+	r, err := gzip.NewReader(&buf)
+	assert.NoError(err)
+	s := NewCloudWatchLogsStream(r, MinBufferSize)
+	assert.Equal(`{"foo":"bar"}`, string(s.Next()))
+	assert.Equal("/aws/lambda/foo", s.LogGroup())
+	assert.Equal("2020/10/01/[$LATEST]0123456789abcdef", s.LogStream())
+	assert.Equal("plain text", string(s.Next()))
+	assert.Equal("/aws/lambda/foo", s.LogGroup())
+	assert.Equal("baz", string(s.Next()))
+	assert.Equal("/aws/lambda/bar", s.LogGroup())
+	assert.Equal("2020/10/01/[$LATEST]fedcba9876543210", s.LogStream())
+	assert.Nil(s.Next())
+	assert.NoError(s.Err())
+}
+
+func TestCloudWatchLogsStreamNewlines(t *testing.T) {
+	assert := require.New(t)
+	input := testCloudWatchLogsDataB + "\n" + testCloudWatchLogsDataA + "\n"
+	s := NewCloudWatchLogsStream(strings.NewReader(input), MinBufferSize)
+	var entries []string
+	for entry := s.Next(); entry != nil; entry = s.Next() {
+		entries = append(entries, string(entry))
+	}
+	assert.NoError(s.Err())
+	assert.Equal([]string{"baz", `{"foo":"bar"}`, "plain text"}, entries)
+}
+
+func TestCloudWatchLogsStreamErrors(t *testing.T) {
+	for _, input := range []string{
+		`{"messageType":"FOO","logEvents":[{"message":"foo"}]}`,
+		testCloudWatchLogsDataA[:len(testCloudWatchLogsDataA)-10],
+		`{"messageType":"DATA_MESSAGE","logEvents":[]} foo`,
+	} {
+		s := NewCloudWatchLogsStream(strings.NewReader(input), MinBufferSize)
+		require.Nil(t, s.Next())
+		require.Error(t, s.Err(), input)
+	}
+}
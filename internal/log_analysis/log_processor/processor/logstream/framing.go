@@ -0,0 +1,286 @@
+package logstream
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bytes"
+	"regexp"
+
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/pkg/x/fastmatch"
+)
+
+const (
+	// DefaultMaxFrameLines is the max number of lines in a multi-line log entry if not set in the config
+	DefaultMaxFrameLines = 1000
+	// MaxFrameSize is the max size of an octet-counted log entry
+	MaxFrameSize = 64 * 1024 * 1024
+)
+
+// FramingConfig configures how log entries that span multiple lines are reassembled.
+// Exactly one of the framing modes must be set.
+// nolint:lll
+type FramingConfig struct {
+	StartRegex         string `json:"startRegex,omitempty" yaml:"startRegex,omitempty" description:"Regular expression matching the first line of each log entry"`
+	StartMatch         string `json:"startMatch,omitempty" yaml:"startMatch,omitempty" description:"Fastmatch pattern matching the start of the first line of each log entry"`
+	ContinuationIndent bool   `json:"continuationIndent,omitempty" yaml:"continuationIndent,omitempty" description:"Lines starting with whitespace continue the previous log entry"`
+	OctetCounted       bool   `json:"octetCounted,omitempty" yaml:"octetCounted,omitempty" description:"Log entries are prefixed by their length in bytes (RFC 6587)"`
+	ZeekTSV            bool   `json:"zeekTSV,omitempty" yaml:"zeekTSV,omitempty" description:"Lines in Zeek tab-separated format are converted to JSON using the #fields header"`
+	MaxLines           int    `json:"maxLines,omitempty" yaml:"maxLines,omitempty" description:"Max number of lines in a log entry"`
+}
+
+// Framer is implemented by log parsers that require a specific framing of log entries
+type Framer interface {
+	Framing() *FramingConfig
+}
+
+// Validate checks that exactly one framing mode is set and that the patterns compile
+func (c *FramingConfig) Validate() error {
+	_, err := c.buildMatcher()
+	return err
+}
+
+// Equal checks if two configs frame log entries the same way
+func (c *FramingConfig) Equal(other *FramingConfig) bool {
+	if c == nil || other == nil {
+		return c == other
+	}
+	return *c == *other
+}
+
+// NewFramedStream reassembles log entries from a stream of lines.
+// If config is nil the lines are returned as is.
+func NewFramedStream(lines Stream, config *FramingConfig) (Stream, error) {
+	if config == nil {
+		return lines, nil
+	}
+	if config.OctetCounted {
+		if err := config.Validate(); err != nil {
+			return nil, err
+		}
+		return &octetCountedStream{
+			lines: lines,
+		}, nil
+	}
+	if config.ZeekTSV {
+		if err := config.Validate(); err != nil {
+			return nil, err
+		}
+		return newZeekTSVStream(lines), nil
+	}
+	isStart, err := config.buildMatcher()
+	if err != nil {
+		return nil, err
+	}
+	maxLines := config.MaxLines
+	if maxLines <= 0 {
+		maxLines = DefaultMaxFrameLines
+	}
+	return &multiLineStream{
+		lines:    lines,
+		isStart:  isStart,
+		maxLines: maxLines,
+	}, nil
+}
+
+func (c *FramingConfig) buildMatcher() (func(line []byte) bool, error) {
+	if c == nil {
+		return nil, errors.New("nil framing config")
+	}
+	var (
+		numModes int
+		isStart  func(line []byte) bool
+	)
+	if c.StartRegex != "" {
+		numModes++
+		re, err := regexp.Compile(c.StartRegex)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid framing start regex")
+		}
+		isStart = re.Match
+	}
+	if c.StartMatch != "" {
+		numModes++
+		pattern, err := compilePrefixPattern(c.StartMatch)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid framing start pattern")
+		}
+		var scratch []string
+		isStart = func(line []byte) bool {
+			var err error
+			scratch, err = pattern.MatchString(scratch[:0], string(line))
+			return err == nil
+		}
+	}
+	if c.ContinuationIndent {
+		numModes++
+		isStart = isNotIndented
+	}
+	if c.OctetCounted {
+		numModes++
+	}
+	if c.ZeekTSV {
+		numModes++
+	}
+	if numModes != 1 {
+		return nil, errors.New("exactly one framing mode must be set")
+	}
+	if c.MaxLines < 0 {
+		return nil, errors.New("invalid framing max lines")
+	}
+	return isStart, nil
+}
+
+var fieldSuffix = regexp.MustCompile(`%{[^}]*}$`)
+
+// compilePrefixPattern compiles a fastmatch pattern that matches the start of a line
+func compilePrefixPattern(src string) (*fastmatch.Pattern, error) {
+	// Capture the rest of the line in an unnamed field if the pattern ends with text
+	if !fieldSuffix.MatchString(src) {
+		src += "%{}"
+	}
+	return fastmatch.Compile(src)
+}
+
+func isNotIndented(line []byte) bool {
+	return len(line) == 0 || (line[0] != ' ' && line[0] != '\t')
+}
+
+// multiLineStream joins lines with the previous line until a line matches the start of a log entry.
+type multiLineStream struct {
+	lines    Stream
+	isStart  func(line []byte) bool
+	maxLines int
+	// entry is the log entry returned by the last call to Next()
+	entry []byte
+	// next holds the first line of the next log entry
+	next    []byte
+	hasNext bool
+}
+
+// Err implements Stream interface
+func (s *multiLineStream) Err() error {
+	return s.lines.Err()
+}
+
+// Next implements Stream interface
+func (s *multiLineStream) Next() []byte {
+	// Reuse the buffer of the previous entry for the first line of the following entry
+	s.entry, s.next = s.next, s.entry[:0]
+	if !s.hasNext {
+		line := s.lines.Next()
+		if line == nil {
+			return nil
+		}
+		s.entry = append(s.entry[:0], line...)
+	}
+	s.hasNext = false
+	numLines := 1
+	for {
+		line := s.lines.Next()
+		if line == nil {
+			return s.entry
+		}
+		if numLines >= s.maxLines || s.isStart(line) {
+			s.next = append(s.next[:0], line...)
+			s.hasNext = true
+			return s.entry
+		}
+		s.entry = append(s.entry, '\n')
+		s.entry = append(s.entry, line...)
+		numLines++
+	}
+}
+
+// octetCountedStream reads log entries framed with octet counting as described in RFC 6587.
+// Each log entry is prefixed by its length in bytes and a space.
+// Since the stream reads lines, newlines inside a log entry are assumed to be a single '\n'.
+// Lines without a length prefix fall back to newline framing.
+type octetCountedStream struct {
+	lines Stream
+	// buffer holds the data of the current entry followed by the data remaining in the current line
+	buffer []byte
+	// rest is the offset in buffer of the data that has not been read yet
+	rest int
+}
+
+// Err implements Stream interface
+func (s *octetCountedStream) Err() error {
+	return s.lines.Err()
+}
+
+// Next implements Stream interface
+func (s *octetCountedStream) Next() []byte {
+	// Drop the previous entry
+	s.buffer = append(s.buffer[:0], s.buffer[s.rest:]...)
+	s.rest = 0
+	for len(s.buffer) == 0 {
+		line := s.lines.Next()
+		if line == nil {
+			return nil
+		}
+		s.buffer = append(s.buffer, bytes.TrimLeft(line, " ")...)
+	}
+	size, pos := parseOctetCount(s.buffer)
+	if pos == -1 {
+		s.rest = len(s.buffer)
+		return s.buffer
+	}
+	for len(s.buffer)-pos < size {
+		line := s.lines.Next()
+		if line == nil {
+			// Truncated entry, return what we have
+			s.rest = len(s.buffer)
+			return s.buffer[pos:]
+		}
+		s.buffer = append(s.buffer, '\n')
+		s.buffer = append(s.buffer, line...)
+	}
+	end := pos + size
+	s.rest = end
+	// Skip the spaces that separate entries on the same line
+	for s.rest < len(s.buffer) && s.buffer[s.rest] == ' ' {
+		s.rest++
+	}
+	return s.buffer[pos:end]
+}
+
+// parseOctetCount parses a 'MSG-LEN SP' prefix returning the length and the position of the message.
+// If no valid prefix is found it returns -1 as the position.
+func parseOctetCount(data []byte) (size, pos int) {
+	for i, c := range data {
+		switch {
+		case '0' <= c && c <= '9':
+			// Message length cannot start with zero
+			if i == 0 && c == '0' {
+				return 0, -1
+			}
+			size = size*10 + int(c-'0')
+			if size > MaxFrameSize {
+				return 0, -1
+			}
+		case c == ' ' && i > 0:
+			return size, i + 1
+		default:
+			return 0, -1
+		}
+	}
+	return 0, -1
+}
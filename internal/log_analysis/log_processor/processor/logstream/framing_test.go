@@ -0,0 +1,126 @@
+package logstream
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testStackTrace = `2020-10-01 12:00:00 ERROR Request failed
+java.lang.IllegalStateException: foo
+	at com.example.Foo.bar(Foo.java:42)
+	at com.example.Main.main(Main.java:7)
+2020-10-01 12:00:01 INFO Request ok
+2020-10-01 12:00:02 ERROR Request failed
+Caused by: java.io.IOException
+	at com.example.Foo.baz(Foo.java:12)`
+
+func TestFramedStream(t *testing.T) {
+	expectTraces := []string{
+		`2020-10-01 12:00:00 ERROR Request failed
+java.lang.IllegalStateException: foo
+	at com.example.Foo.bar(Foo.java:42)
+	at com.example.Main.main(Main.java:7)`,
+		`2020-10-01 12:00:01 INFO Request ok`,
+		`2020-10-01 12:00:02 ERROR Request failed
+Caused by: java.io.IOException
+	at com.example.Foo.baz(Foo.java:12)`,
+	}
+	type testCase struct {
+		Name   string
+		Config *FramingConfig
+		Input  string
+		Expect []string
+	}
+	for _, tc := range []testCase{
+		{
+			Name:   "regex",
+			Config: &FramingConfig{StartRegex: `^\d{4}-\d{2}-\d{2} `},
+			Input:  testStackTrace,
+			Expect: expectTraces,
+		},
+		{
+			Name:   "fastmatch",
+			Config: &FramingConfig{StartMatch: `%{date} %{time} %{level} `},
+			Input:  testStackTrace,
+			Expect: expectTraces,
+		},
+		{
+			Name:   "fastmatch ends with field",
+			Config: &FramingConfig{StartMatch: `2020-%{rest}`},
+			Input:  testStackTrace,
+			Expect: expectTraces,
+		},
+		{
+			Name:   "indent",
+			Config: &FramingConfig{ContinuationIndent: true},
+			Input:  "foo\n bar\n\tbaz\nqux\n  quux",
+			Expect: []string{"foo\n bar\n\tbaz", "qux\n  quux"},
+		},
+		{
+			Name:   "max lines",
+			Config: &FramingConfig{ContinuationIndent: true, MaxLines: 2},
+			Input:  "foo\n bar\n baz\nqux",
+			Expect: []string{"foo\n bar", " baz", "qux"},
+		},
+		{
+			Name:   "octet counted",
+			Config: &FramingConfig{OctetCounted: true},
+			Input:  "3 foo7 bar\nbaz 3 qux\n\n11 foo bar baz\nno prefix\n12 truncated",
+			Expect: []string{"foo", "bar\nbaz", "qux", "foo bar baz", "no prefix", "truncated"},
+		},
+		{
+			Name:   "nil config",
+			Input:  "foo\n bar",
+			Expect: []string{"foo", " bar"},
+		},
+	} {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			assert := require.New(t)
+			lines := NewLineStream(strings.NewReader(tc.Input), MinBufferSize)
+			stream, err := NewFramedStream(lines, tc.Config)
+			assert.NoError(err)
+			var actual []string
+			for entry := stream.Next(); entry != nil; entry = stream.Next() {
+				actual = append(actual, string(entry))
+			}
+			assert.NoError(stream.Err())
+			assert.Equal(tc.Expect, actual)
+		})
+	}
+}
+
+func TestFramingConfigValidate(t *testing.T) {
+	assert := require.New(t)
+	assert.NoError((&FramingConfig{StartRegex: `^\d`}).Validate())
+	assert.NoError((&FramingConfig{OctetCounted: true}).Validate())
+	assert.Error((&FramingConfig{}).Validate())
+	assert.Error((&FramingConfig{StartRegex: `(`}).Validate())
+	assert.Error((&FramingConfig{StartMatch: `%{foo}%{bar}`}).Validate())
+	assert.Error((&FramingConfig{StartRegex: `^\d`, ContinuationIndent: true}).Validate())
+	assert.Error((&FramingConfig{OctetCounted: true, MaxLines: -1}).Validate())
+	assert.NoError((&FramingConfig{ZeekTSV: true}).Validate())
+	assert.Error((&FramingConfig{ZeekTSV: true, OctetCounted: true}).Validate())
+	assert.True((&FramingConfig{OctetCounted: true}).Equal(&FramingConfig{OctetCounted: true}))
+	assert.False((&FramingConfig{OctetCounted: true}).Equal(nil))
+}
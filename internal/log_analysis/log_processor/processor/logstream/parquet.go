@@ -0,0 +1,405 @@
+package logstream
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+	"reflect"
+	"sort"
+	"time"
+	"unicode/utf8"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/reader"
+	"github.com/xitongsys/parquet-go/source"
+	"github.com/xitongsys/parquet-go/types"
+)
+
+// ParquetMagic is the header (and footer) of Parquet files
+var ParquetMagic = []byte("PAR1")
+
+// parquetBatchSize is the number of rows to read from a Parquet file at a time
+const parquetBatchSize = 1000
+
+// NewParquetStream creates a stream that reads the rows of a Parquet file as JSON objects.
+// Parquet files need random access to read their metadata at the end of the file,
+// so the whole file needs to be available through r.
+// Logical types are converted to their JSON representation, so rows can be classified by log schemas that
+// expect plain JSON events.
+func NewParquetStream(r io.ReaderAt, size int64) *ParquetStream {
+	return &ParquetStream{
+		file: &parquetFile{
+			r:    r,
+			size: size,
+		},
+		stream: newRecordStream(),
+	}
+}
+
+// ParquetStream is a log entry stream that iterates over the rows of a Parquet file.
+type ParquetStream struct {
+	file       *parquetFile
+	reader     *reader.ParquetReader
+	rows       []interface{}
+	remaining  int64
+	stream     *jsoniter.Stream
+	numEntries int64
+	err        error
+}
+
+// Err implements the Stream interface
+func (s *ParquetStream) Err() error {
+	if errors.Is(s.err, io.EOF) {
+		return nil
+	}
+	return s.err
+}
+
+// Next implements the Stream interface
+func (s *ParquetStream) Next() []byte {
+	if s.err != nil {
+		return nil
+	}
+	if s.reader == nil {
+		if err := s.open(); err != nil {
+			s.err = err
+			return nil
+		}
+	}
+	if len(s.rows) == 0 {
+		if err := s.readRows(); err != nil {
+			s.err = err
+			s.reader.ReadStop()
+			return nil
+		}
+	}
+	row := s.rows[0]
+	// Release the row so it can be garbage collected
+	s.rows[0] = nil
+	s.rows = s.rows[1:]
+
+	s.stream.SetBuffer(s.stream.Buffer()[:0])
+	s.writeValue(reflect.ValueOf(row), s.reader.SchemaHandler.GetRootInName())
+	if err := s.stream.Error; err != nil {
+		s.err = errors.Wrapf(err, "failed to write parquet row %d", s.numEntries)
+		return nil
+	}
+	s.numEntries++
+	// Return the entry data. It is valid until the next call to Next
+	return s.stream.Buffer()
+}
+
+// The parquet-go library panics on some invalid input, we recover to report an error instead.
+func recoverParquetError(err *error) {
+	if p := recover(); p != nil {
+		*err = errors.Errorf("invalid parquet file: %v", p)
+	}
+}
+
+func (s *ParquetStream) open() (err error) {
+	defer recoverParquetError(&err)
+	r, err := reader.NewParquetReader(s.file, nil, 1)
+	if err != nil {
+		return errors.Wrap(err, "failed to read parquet file")
+	}
+	s.reader = r
+	s.remaining = r.GetNumRows()
+	return nil
+}
+
+func (s *ParquetStream) readRows() (err error) {
+	if s.remaining <= 0 {
+		return io.EOF
+	}
+	defer recoverParquetError(&err)
+	n := s.remaining
+	if n > parquetBatchSize {
+		n = parquetBatchSize
+	}
+	rows, err := s.reader.ReadByNumber(int(n))
+	if err != nil {
+		return errors.Wrap(err, "failed to read parquet rows")
+	}
+	if len(rows) == 0 {
+		return io.EOF
+	}
+	s.rows = rows
+	s.remaining -= int64(len(rows))
+	return nil
+}
+
+// writeValue writes a row value read by parquet-go as JSON.
+// Rows are read as dynamically created struct types with field names mangled to be valid Go identifiers.
+// We use the path of each value in the schema to resolve the original field names and logical types.
+func (s *ParquetStream) writeValue(v reflect.Value, path string) {
+	stream := s.stream
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			stream.WriteNil()
+			return
+		}
+		s.writeValue(v.Elem(), path)
+	case reflect.Struct:
+		stream.WriteObjectStart()
+		typ := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			if i > 0 {
+				stream.WriteMore()
+			}
+			fieldPath := path + "." + typ.Field(i).Name
+			stream.WriteObjectField(s.fieldName(fieldPath, typ.Field(i).Name))
+			s.writeValue(v.Field(i), fieldPath)
+		}
+		stream.WriteObjectEnd()
+	case reflect.Slice:
+		if v.IsNil() {
+			stream.WriteNil()
+			return
+		}
+		// parquet-go reads LIST groups as slices of their element
+		elemPath := path + ".List.Element"
+		if _, ok := s.reader.SchemaHandler.MapIndex[elemPath]; !ok {
+			// Repeated fields are read as slices of the field type
+			elemPath = path
+		}
+		stream.WriteArrayStart()
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				stream.WriteMore()
+			}
+			s.writeValue(v.Index(i), elemPath)
+		}
+		stream.WriteArrayEnd()
+	case reflect.Map:
+		if v.IsNil() {
+			stream.WriteNil()
+			return
+		}
+		// parquet-go reads MAP groups as Go maps
+		keys := make([]string, 0, v.Len())
+		values := make(map[string]reflect.Value, v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			key := fmt.Sprint(iter.Key().Interface())
+			keys = append(keys, key)
+			values[key] = iter.Value()
+		}
+		// Sort keys so that output is stable
+		sort.Strings(keys)
+		stream.WriteObjectStart()
+		for i, key := range keys {
+			if i > 0 {
+				stream.WriteMore()
+			}
+			stream.WriteObjectField(key)
+			s.writeValue(values[key], path+".Key_value.Value")
+		}
+		stream.WriteObjectEnd()
+	case reflect.String:
+		s.writeBinary(v.String(), s.schemaElement(path))
+	case reflect.Int32, reflect.Int64:
+		s.writeInt(v.Int(), s.schemaElement(path))
+	case reflect.Bool:
+		stream.WriteBool(v.Bool())
+	case reflect.Float32, reflect.Float64:
+		writeFloat(stream, v.Float())
+	default:
+		stream.WriteNil()
+	}
+}
+
+// fieldName resolves the original name of a field
+func (s *ParquetStream) fieldName(path, name string) string {
+	if idx, ok := s.reader.SchemaHandler.MapIndex[path]; ok {
+		return s.reader.SchemaHandler.Infos[idx].ExName
+	}
+	return name
+}
+
+func (s *ParquetStream) schemaElement(path string) *parquet.SchemaElement {
+	if idx, ok := s.reader.SchemaHandler.MapIndex[path]; ok {
+		return s.reader.SchemaHandler.SchemaElements[idx]
+	}
+	return nil
+}
+
+// writeBinary writes BYTE_ARRAY, FIXED_LEN_BYTE_ARRAY and INT96 values
+func (s *ParquetStream) writeBinary(value string, el *parquet.SchemaElement) {
+	stream := s.stream
+	if el == nil {
+		stream.WriteString(value)
+		return
+	}
+	if el.GetType() == parquet.Type_INT96 {
+		writeTimestamp(stream, types.INT96ToTime(value))
+		return
+	}
+	if scale, ok := parquetDecimalScale(el); ok {
+		writeDecimal(stream, bigIntFromBytes([]byte(value)), scale)
+		return
+	}
+	isJSON := el.IsSetConvertedType() && el.GetConvertedType() == parquet.ConvertedType_JSON
+	if lt := el.GetLogicalType(); lt != nil && lt.IsSetJSON() {
+		isJSON = true
+	}
+	if isJSON && jsoniter.Valid([]byte(value)) {
+		stream.WriteRaw(value)
+		return
+	}
+	// Many writers do not annotate strings as UTF8, so we only encode binary data that is not valid UTF8.
+	if utf8.ValidString(value) {
+		stream.WriteString(value)
+		return
+	}
+	writeBinary(stream, []byte(value))
+}
+
+// writeInt writes INT32 and INT64 values
+func (s *ParquetStream) writeInt(n int64, el *parquet.SchemaElement) {
+	stream := s.stream
+	if el == nil {
+		stream.WriteInt64(n)
+		return
+	}
+	if scale, ok := parquetDecimalScale(el); ok {
+		writeDecimal(stream, big.NewInt(n), scale)
+		return
+	}
+	if lt := el.GetLogicalType(); lt != nil {
+		switch {
+		case lt.IsSetDATE():
+			writeDate(stream, n)
+			return
+		case lt.IsSetTIMESTAMP() && lt.TIMESTAMP.Unit != nil:
+			switch unit := lt.TIMESTAMP.Unit; {
+			case unit.IsSetMILLIS():
+				writeTimestamp(stream, time.Unix(n/1e3, (n%1e3)*1e6))
+			case unit.IsSetMICROS():
+				writeTimestamp(stream, time.Unix(n/1e6, (n%1e6)*1e3))
+			default:
+				writeTimestamp(stream, time.Unix(0, n))
+			}
+			return
+		case lt.IsSetINTEGER() && !lt.INTEGER.IsSigned && el.GetType() == parquet.Type_INT64:
+			stream.WriteUint64(uint64(n))
+			return
+		}
+	}
+	if el.IsSetConvertedType() {
+		switch el.GetConvertedType() {
+		case parquet.ConvertedType_DATE:
+			writeDate(stream, n)
+			return
+		case parquet.ConvertedType_TIMESTAMP_MILLIS:
+			writeTimestamp(stream, time.Unix(n/1e3, (n%1e3)*1e6))
+			return
+		case parquet.ConvertedType_TIMESTAMP_MICROS:
+			writeTimestamp(stream, time.Unix(n/1e6, (n%1e6)*1e3))
+			return
+		case parquet.ConvertedType_UINT_64:
+			stream.WriteUint64(uint64(n))
+			return
+		case parquet.ConvertedType_UINT_32:
+			stream.WriteUint32(uint32(n))
+			return
+		}
+	}
+	stream.WriteInt64(n)
+}
+
+func parquetDecimalScale(el *parquet.SchemaElement) (int, bool) {
+	if lt := el.GetLogicalType(); lt != nil && lt.IsSetDECIMAL() {
+		return int(lt.DECIMAL.Scale), true
+	}
+	if el.IsSetConvertedType() && el.GetConvertedType() == parquet.ConvertedType_DECIMAL {
+		return int(el.GetScale()), true
+	}
+	return 0, false
+}
+
+// parquetFile implements source.ParquetFile for reading from an io.ReaderAt
+type parquetFile struct {
+	r      io.ReaderAt
+	size   int64
+	offset int64
+}
+
+var _ source.ParquetFile = (*parquetFile)(nil)
+
+// Open implements source.ParquetFile.
+// The reader opens the file once for each column so we return a new file with its own offset.
+func (f *parquetFile) Open(_ string) (source.ParquetFile, error) {
+	return &parquetFile{
+		r:    f.r,
+		size: f.size,
+	}, nil
+}
+
+// Create implements source.ParquetFile
+func (f *parquetFile) Create(_ string) (source.ParquetFile, error) {
+	return nil, errors.New("parquet file is read only")
+}
+
+// Read implements io.Reader
+func (f *parquetFile) Read(p []byte) (int, error) {
+	if f.offset >= f.size {
+		return 0, io.EOF
+	}
+	if max := f.size - f.offset; int64(len(p)) > max {
+		p = p[:max]
+	}
+	n, err := f.r.ReadAt(p, f.offset)
+	f.offset += int64(n)
+	if err == io.EOF && n > 0 {
+		err = nil
+	}
+	return n, err
+}
+
+// Seek implements io.Seeker
+func (f *parquetFile) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += f.offset
+	case io.SeekEnd:
+		offset += f.size
+	default:
+		return 0, errors.Errorf("invalid whence %d", whence)
+	}
+	if offset < 0 {
+		return 0, errors.New("negative offset")
+	}
+	f.offset = offset
+	return offset, nil
+}
+
+// Write implements io.Writer
+func (f *parquetFile) Write(_ []byte) (int, error) {
+	return 0, errors.New("parquet file is read only")
+}
+
+// Close implements io.Closer
+func (f *parquetFile) Close() error {
+	return nil
+}
@@ -0,0 +1,91 @@
+package logstream
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"encoding/base64"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Helpers to write values of binary record formats (Avro, Parquet) as JSON.
+// Logical types are converted to the JSON representations that log schemas understand,
+// timestamps become RFC3339 strings in UTC and dates become YYYY-MM-DD strings.
+
+const layoutDate = "2006-01-02"
+
+func newRecordStream() *jsoniter.Stream {
+	return jsoniter.NewStream(jsoniter.ConfigDefault, nil, MinBufferSize)
+}
+
+// writeTimestamp writes a timestamp as an RFC3339 string in UTC
+func writeTimestamp(stream *jsoniter.Stream, tm time.Time) {
+	stream.WriteString(tm.UTC().Format(time.RFC3339Nano))
+}
+
+// writeDate writes the date that is n days after the UNIX epoch
+func writeDate(stream *jsoniter.Stream, days int64) {
+	stream.WriteString(time.Unix(days*24*60*60, 0).UTC().Format(layoutDate))
+}
+
+// writeFloat writes a float number or null if the value cannot be represented in JSON
+func writeFloat(stream *jsoniter.Stream, f float64) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		stream.WriteNil()
+		return
+	}
+	stream.WriteFloat64(f)
+}
+
+// writeBinary writes binary data as a base64 string
+func writeBinary(stream *jsoniter.Stream, data []byte) {
+	stream.WriteString(base64.StdEncoding.EncodeToString(data))
+}
+
+// writeDecimal writes a decimal number with the given scale
+func writeDecimal(stream *jsoniter.Stream, unscaled *big.Int, scale int) {
+	digits := unscaled.String()
+	if scale <= 0 {
+		stream.WriteRaw(digits + strings.Repeat("0", -scale))
+		return
+	}
+	sign := ""
+	if strings.HasPrefix(digits, "-") {
+		sign, digits = "-", digits[1:]
+	}
+	if len(digits) <= scale {
+		digits = strings.Repeat("0", scale-len(digits)+1) + digits
+	}
+	point := len(digits) - scale
+	stream.WriteRaw(sign + digits[:point] + "." + digits[point:])
+}
+
+// bigIntFromBytes decodes a big-endian two's complement integer
+func bigIntFromBytes(data []byte) *big.Int {
+	n := new(big.Int).SetBytes(data)
+	if len(data) > 0 && data[0]&0x80 != 0 {
+		// Negative number, subtract 2^(8*len)
+		n.Sub(n, new(big.Int).Lsh(big.NewInt(1), uint(len(data)*8)))
+	}
+	return n
+}
@@ -0,0 +1,198 @@
+package logstream
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"io/ioutil"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParquetStream(t *testing.T) {
+	assert := require.New(t)
+	// Generated with parquet-go JSONWriter using the default (snappy) compression
+	data, err := ioutil.ReadFile("testdata/sample.parquet")
+	assert.NoError(err)
+	assert.True(bytes.HasPrefix(data, ParquetMagic))
+	s := NewParquetStream(bytes.NewReader(data), int64(len(data)))
+	expect := []string{
+		`{"name":"alice","age":30,"ts":"2020-09-13T12:26:40Z","tags":["a","b"],"attrs":{"x":1},"amount":12345.00,` +
+			`"day":"2021-01-01","src":{"ip":"10.0.0.1","port":22}}`,
+		`{"name":"bob","age":null,"ts":"2020-09-13T12:26:41Z","tags":null,"attrs":null,"amount":null,` +
+			`"day":null,"src":null}`,
+	}
+	var actual []string
+	for entry := s.Next(); entry != nil; entry = s.Next() {
+		actual = append(actual, string(entry))
+	}
+	assert.NoError(s.Err())
+	assert.Equal(expect, actual)
+}
+
+func TestParquetStreamInvalid(t *testing.T) {
+	data := []byte("PAR1 foo bar baz PAR1")
+	s := NewParquetStream(bytes.NewReader(data), int64(len(data)))
+	require.Nil(t, s.Next())
+	require.Error(t, s.Err())
+}
+
+const testAvroSchema = `{
+	"type": "record",
+	"name": "Event",
+	"namespace": "com.example",
+	"fields": [
+		{"name": "id", "type": "long"},
+		{"name": "user", "type": ["null", "string"]},
+		{"name": "time", "type": {"type": "long", "logicalType": "timestamp-millis"}},
+		{"name": "level", "type": {"type": "enum", "name": "Level", "symbols": ["INFO", "WARN"]}},
+		{"name": "tags", "type": {"type": "array", "items": "string"}},
+		{"name": "attrs", "type": {"type": "map", "values": "double"}},
+		{"name": "ok", "type": "boolean"},
+		{"name": "parent", "type": ["null", "Event"]}
+	]
+}`
+
+func TestAvroStream(t *testing.T) {
+	for _, codec := range []string{"null", "deflate"} {
+		codec := codec
+		t.Run(codec, func(t *testing.T) {
+			assert := require.New(t)
+			records := [][]byte{
+				concat(
+					encodeLong(1),
+					encodeLong(1), encodeString("alice"),
+					encodeLong(1600000000000),
+					encodeLong(1),
+					encodeLong(2), encodeString("a"), encodeString("b"), encodeLong(0),
+					encodeLong(1), encodeString("x"), encodeDouble(1.5), encodeLong(0),
+					[]byte{1},
+					encodeLong(0),
+				),
+				concat(
+					encodeLong(2),
+					encodeLong(0),
+					encodeLong(1600000001000),
+					encodeLong(0),
+					encodeLong(0),
+					encodeLong(0),
+					[]byte{0},
+					encodeLong(1),
+					encodeLong(3), encodeLong(0), encodeLong(0), encodeLong(0), encodeLong(0), encodeLong(0), []byte{0}, encodeLong(0),
+				),
+			}
+			data := avroFile(t, codec, records)
+			s := NewAvroStream(bytes.NewReader(data), 0)
+			var actual []string
+			for entry := s.Next(); entry != nil; entry = s.Next() {
+				actual = append(actual, string(entry))
+			}
+			assert.NoError(s.Err())
+			expect := []string{
+				`{"id":1,"user":"alice","time":"2020-09-13T12:26:40Z","level":"WARN","tags":["a","b"],"attrs":{"x":1.5},` +
+					`"ok":true,"parent":null}`,
+				`{"id":2,"user":null,"time":"2020-09-13T12:26:41Z","level":"INFO","tags":[],"attrs":{},"ok":false,` +
+					`"parent":{"id":3,"user":null,"time":"1970-01-01T00:00:00Z","level":"INFO","tags":[],"attrs":{},` +
+					`"ok":false,"parent":null}}`,
+			}
+			assert.Equal(expect, actual)
+		})
+	}
+}
+
+func TestAvroStreamInvalid(t *testing.T) {
+	s := NewAvroStream(bytes.NewReader([]byte("foo bar baz")), 0)
+	require.Nil(t, s.Next())
+	require.Error(t, s.Err())
+
+	// Truncated block
+	data := avroFile(t, "null", [][]byte{encodeLong(1)})
+	s = NewAvroStream(bytes.NewReader(data[:len(data)-20]), 0)
+	require.Nil(t, s.Next())
+	require.Error(t, s.Err())
+}
+
+func TestWriteDecimal(t *testing.T) {
+	for _, tc := range []struct {
+		Data   []byte
+		Scale  int
+		Expect string
+	}{
+		{[]byte{0x30, 0x39}, 2, "123.45"},
+		{[]byte{0x30, 0x39}, 0, "12345"},
+		{[]byte{0x30, 0x39}, -1, "123450"},
+		{[]byte{0x05}, 3, "0.005"},
+		{[]byte{0xff, 0x85}, 2, "-1.23"},
+	} {
+		s := newRecordStream()
+		writeDecimal(s, bigIntFromBytes(tc.Data), tc.Scale)
+		require.Equal(t, tc.Expect, string(s.Buffer()))
+	}
+}
+
+func avroFile(t *testing.T, codec string, records [][]byte) []byte {
+	sync := []byte("0123456789abcdef")
+	buf := bytes.Buffer{}
+	buf.Write(AvroMagic)
+	buf.Write(encodeLong(2))
+	buf.Write(encodeString("avro.schema"))
+	buf.Write(encodeString(testAvroSchema))
+	buf.Write(encodeString("avro.codec"))
+	buf.Write(encodeString(codec))
+	buf.Write(encodeLong(0))
+	buf.Write(sync)
+	block := concat(records...)
+	if codec == "deflate" {
+		compressed := bytes.Buffer{}
+		w, err := flate.NewWriter(&compressed, flate.DefaultCompression)
+		require.NoError(t, err)
+		_, err = w.Write(block)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		block = compressed.Bytes()
+	}
+	buf.Write(encodeLong(int64(len(records))))
+	buf.Write(encodeLong(int64(len(block))))
+	buf.Write(block)
+	buf.Write(sync)
+	return buf.Bytes()
+}
+
+func encodeLong(n int64) []byte {
+	buf := make([]byte, binary.MaxVarintLen64)
+	return buf[:binary.PutVarint(buf, n)]
+}
+
+func encodeString(s string) []byte {
+	return append(encodeLong(int64(len(s))), s...)
+}
+
+func encodeDouble(f float64) []byte {
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint64(buf, math.Float64bits(f))
+	return buf
+}
+
+func concat(parts ...[]byte) []byte {
+	return bytes.Join(parts, nil)
+}
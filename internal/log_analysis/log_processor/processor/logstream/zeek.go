@@ -0,0 +1,268 @@
+package logstream
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Default values of the Zeek ASCII writer header directives
+const (
+	zeekDefaultSeparator    = "\t"
+	zeekDefaultSetSeparator = ","
+	zeekDefaultEmptyField   = "(empty)"
+	zeekDefaultUnsetField   = "-"
+)
+
+// ZeekPathField is the key of the log path (i.e. `conn`) added to log entries converted from Zeek TSV logs.
+// It is the same key the Zeek JSON streaming logs use.
+const ZeekPathField = "_path"
+
+// zeekTSVStream converts Zeek logs in tab-separated format to JSON log entries.
+//
+// The header directives (`#separator`, `#fields`, `#types` etc) at the start of each log file describe the lines
+// that follow. Each line is converted to a JSON object with the keys in `#fields` and values typed by `#types`.
+// Unset fields are omitted and the log path is added to each entry as `_path`.
+// Lines that are not preceded by a `#fields` header and JSON lines are returned as is,
+// so Zeek logs in JSON format can be read by the same stream.
+type zeekTSVStream struct {
+	lines  Stream
+	header zeekHeader
+	stream *jsoniter.Stream
+}
+
+type zeekHeader struct {
+	separator    string
+	setSeparator string
+	emptyField   string
+	unsetField   string
+	path         string
+	fields       []string
+	types        []string
+}
+
+func newZeekTSVStream(lines Stream) *zeekTSVStream {
+	s := &zeekTSVStream{
+		lines:  lines,
+		stream: newRecordStream(),
+	}
+	s.header.reset()
+	return s
+}
+
+func (h *zeekHeader) reset() {
+	*h = zeekHeader{
+		separator:    zeekDefaultSeparator,
+		setSeparator: zeekDefaultSetSeparator,
+		emptyField:   zeekDefaultEmptyField,
+		unsetField:   zeekDefaultUnsetField,
+	}
+}
+
+// Err implements Stream interface
+func (s *zeekTSVStream) Err() error {
+	return s.lines.Err()
+}
+
+// Next implements Stream interface
+func (s *zeekTSVStream) Next() []byte {
+	for {
+		line := s.lines.Next()
+		if line == nil {
+			return nil
+		}
+		if len(line) > 0 && line[0] == '#' && s.readDirective(string(line)) {
+			continue
+		}
+		if s.header.fields == nil || len(line) == 0 || line[0] == '{' {
+			return line
+		}
+		return s.convert(string(line))
+	}
+}
+
+// readDirective reads a header directive line, it returns false if the line is not a Zeek directive
+func (s *zeekTSVStream) readDirective(line string) bool {
+	// The separator directive is always separated by a space, it starts the header of a new log file
+	if value := strings.TrimPrefix(line, "#separator "); value != line {
+		s.header.reset()
+		s.header.separator = zeekUnescape(value)
+		return true
+	}
+	h := &s.header
+	pos := strings.Index(line, h.separator)
+	if pos == -1 {
+		return false
+	}
+	name, value := line[1:pos], line[pos+len(h.separator):]
+	switch name {
+	case "set_separator":
+		h.setSeparator = zeekUnescape(value)
+	case "empty_field":
+		h.emptyField = zeekUnescape(value)
+	case "unset_field":
+		h.unsetField = zeekUnescape(value)
+	case "path":
+		h.path = zeekUnescape(value)
+	case "fields":
+		h.fields = strings.Split(value, h.separator)
+		h.types = nil
+	case "types":
+		h.types = strings.Split(value, h.separator)
+	case "open", "close":
+	default:
+		return false
+	}
+	return true
+}
+
+// convert converts a tab-separated line to a JSON object.
+// Lines that do not match the fields of the header are returned as is so that they fail to classify.
+func (s *zeekTSVStream) convert(line string) []byte {
+	h := &s.header
+	values := strings.Split(line, h.separator)
+	if len(values) != len(h.fields) {
+		return []byte(line)
+	}
+	stream := s.stream
+	stream.Reset(nil)
+	stream.WriteObjectStart()
+	numFields := 0
+	if h.path != "" {
+		stream.WriteObjectField(ZeekPathField)
+		stream.WriteString(h.path)
+		numFields++
+	}
+	for i, value := range values {
+		field := h.fields[i]
+		if value == h.unsetField || field == ZeekPathField && h.path != "" {
+			continue
+		}
+		typ := "string"
+		if i < len(h.types) {
+			typ = h.types[i]
+		}
+		if value == h.emptyField && !zeekIsContainer(typ) && typ != "string" {
+			continue
+		}
+		if numFields > 0 {
+			stream.WriteMore()
+		}
+		numFields++
+		stream.WriteObjectField(field)
+		s.writeValue(value, typ)
+	}
+	stream.WriteObjectEnd()
+	return stream.Buffer()
+}
+
+func (s *zeekTSVStream) writeValue(value, typ string) {
+	h := &s.header
+	if !zeekIsContainer(typ) {
+		if value == h.emptyField {
+			value = ""
+		}
+		writeZeekValue(s.stream, value, typ)
+		return
+	}
+	stream := s.stream
+	stream.WriteArrayStart()
+	if value != h.emptyField {
+		elemType := typ[strings.IndexByte(typ, '[')+1 : len(typ)-1]
+		for i, elem := range strings.Split(value, h.setSeparator) {
+			if i > 0 {
+				stream.WriteMore()
+			}
+			if elem == h.unsetField {
+				stream.WriteNil()
+				continue
+			}
+			writeZeekValue(stream, elem, elemType)
+		}
+	}
+	stream.WriteArrayEnd()
+}
+
+// writeZeekValue writes a Zeek value as JSON, numbers and booleans that fail to convert are written as strings
+func writeZeekValue(stream *jsoniter.Stream, value, typ string) {
+	switch typ {
+	case "count", "int", "port", "double", "time", "interval":
+		if isJSONNumber(value) {
+			stream.WriteRaw(value)
+			return
+		}
+	case "bool":
+		switch value {
+		case "T":
+			stream.WriteTrue()
+			return
+		case "F":
+			stream.WriteFalse()
+			return
+		}
+	}
+	stream.WriteString(zeekUnescape(value))
+}
+
+func zeekIsContainer(typ string) bool {
+	return strings.HasSuffix(typ, "]") && (strings.HasPrefix(typ, "set[") || strings.HasPrefix(typ, "vector["))
+}
+
+func isJSONNumber(s string) bool {
+	if s == "" || s[0] != '-' && (s[0] < '0' || '9' < s[0]) {
+		return false
+	}
+	return json.Valid([]byte(s))
+}
+
+// zeekUnescape decodes the `\xHH` escape sequences of the Zeek ASCII writer
+func zeekUnescape(s string) string {
+	if strings.IndexByte(s, '\\') == -1 {
+		return s
+	}
+	var b bytes.Buffer
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '\\' || i+1 == len(s) {
+			b.WriteByte(c)
+			continue
+		}
+		switch next := s[i+1]; {
+		case next == '\\':
+			b.WriteByte('\\')
+			i++
+		case next == 'x' && i+3 < len(s):
+			n, err := strconv.ParseUint(s[i+2:i+4], 16, 8)
+			if err != nil {
+				b.WriteByte(c)
+				continue
+			}
+			b.WriteByte(byte(n))
+			i += 3
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,107 @@
+package logstream
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// nolint:lll
+const testZeekTSV = `#separator \x09
+#set_separator	,
+#empty_field	(empty)
+#unset_field	-
+#path	conn
+#open	2020-10-01-12-00-00
+#fields	ts	uid	id.orig_h	id.orig_p	proto	service	duration	local_orig	missed_bytes	history	tunnel_parents
+#types	time	string	addr	port	enum	string	interval	bool	count	string	set[string]
+1601553600.123456	CHhAvVGS1DHFjwGM9	10.0.0.1	51234	tcp	-	0.000305	T	0	ShADad	(empty)
+1601553601.000000	C4J4Th3PJpwUYZZ6gc	10.0.0.2	53	udp	dns	-	F	0	Dd	CUM0KZ3MLUfNB0cl11,Cx2Fk82CTsn1Bajhi3
+#close	2020-10-01-13-00-00
+{"ts":1601553602.0,"uid":"CUM0KZ3MLUfNB0cl11","_path":"dns"}
+#separator \x20
+#fields x509.fingerprint msg names
+#types string string vector[string]
+abc\x20def a\x5cb\\c a,b\x2cc,-
+`
+
+func TestZeekTSVStream(t *testing.T) {
+	assert := require.New(t)
+	lines := NewLineStream(strings.NewReader(testZeekTSV), MinBufferSize)
+	stream, err := NewFramedStream(lines, &FramingConfig{ZeekTSV: true})
+	assert.NoError(err)
+	var actual []string
+	for entry := stream.Next(); entry != nil; entry = stream.Next() {
+		actual = append(actual, string(entry))
+	}
+	assert.NoError(stream.Err())
+	assert.Len(actual, 4)
+	assert.JSONEq(`{
+		"_path": "conn",
+		"ts": 1601553600.123456,
+		"uid": "CHhAvVGS1DHFjwGM9",
+		"id.orig_h": "10.0.0.1",
+		"id.orig_p": 51234,
+		"proto": "tcp",
+		"duration": 0.000305,
+		"local_orig": true,
+		"missed_bytes": 0,
+		"history": "ShADad",
+		"tunnel_parents": []
+	}`, actual[0])
+	assert.JSONEq(`{
+		"_path": "conn",
+		"ts": 1601553601.000000,
+		"uid": "C4J4Th3PJpwUYZZ6gc",
+		"id.orig_h": "10.0.0.2",
+		"id.orig_p": 53,
+		"proto": "udp",
+		"service": "dns",
+		"local_orig": false,
+		"missed_bytes": 0,
+		"history": "Dd",
+		"tunnel_parents": ["CUM0KZ3MLUfNB0cl11", "Cx2Fk82CTsn1Bajhi3"]
+	}`, actual[1])
+	// JSON lines are kept as is
+	assert.Equal(`{"ts":1601553602.0,"uid":"CUM0KZ3MLUfNB0cl11","_path":"dns"}`, actual[2])
+	// A new header resets the path and separators
+	assert.JSONEq(`{
+		"x509.fingerprint": "abc def",
+		"msg": "a\\b\\c",
+		"names": ["a", "b,c", null]
+	}`, actual[3])
+}
+
+func TestZeekTSVStreamNoHeader(t *testing.T) {
+	assert := require.New(t)
+	const input = "#comment\nfoo\tbar\n{\"foo\":\"bar\"}\n#fields\ta\tb\nfoo"
+	lines := NewLineStream(strings.NewReader(input), MinBufferSize)
+	stream, err := NewFramedStream(lines, &FramingConfig{ZeekTSV: true})
+	assert.NoError(err)
+	var actual []string
+	for entry := stream.Next(); entry != nil; entry = stream.Next() {
+		actual = append(actual, string(entry))
+	}
+	assert.NoError(stream.Err())
+	// Lines that do not match the fields are returned as is
+	assert.Equal([]string{"#comment", "foo\tbar", `{"foo":"bar"}`, "foo"}, actual)
+}
@@ -21,7 +21,9 @@ package processor
 import (
 	"context"
 	"sync"
+	"time"
 
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/pkg/errors"
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
@@ -33,6 +35,9 @@ import (
 	logmetrics "github.com/panther-labs/panther/internal/log_analysis/log_processor/metrics"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/logstream"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/quarantine"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/sampling"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/sources"
 	"github.com/panther-labs/panther/pkg/metrics"
 	"github.com/panther-labs/panther/pkg/oplog"
@@ -152,6 +157,13 @@ type Processor struct {
 	input      *common.DataStream
 	classifier classification.ClassifierAPI
 	operation  *oplog.Operation
+	// quarantine stores the log lines that failed to classify, it is disabled if nil
+	quarantine *quarantine.Writer
+	// limiter controls the volume of the stored events, all events are stored if nil
+	limiter *sampling.Limiter
+	// cold stores the events diverted by the limiter, diverted events are dropped if nil
+	cold          *sampling.ColdWriter
+	samplingStats map[string]*sampling.Stats
 }
 
 type Factory func(r *common.DataStream) (*Processor, error)
@@ -159,40 +171,33 @@ type Factory func(r *common.DataStream) (*Processor, error)
 func NewFactory(resolver pantherlog.ParserResolver) Factory {
 	return func(input *common.DataStream) (*Processor, error) {
 		switch src := input.Source; src.IntegrationType {
-		case models.IntegrationTypeSqs:
-			return &Processor{
-				operation: common.OpLogManager.Start(operationName),
-				input:     input,
-				classifier: &sources.SQSClassifier{
-					Resolver:   resolver,
-					LoadSource: sources.LoadSource,
-				},
-			}, nil
+		case models.IntegrationTypeSqs, models.IntegrationTypeHTTP:
+			// The message forwarder and the HTTP receiver wrap log entries in messages of the source
+			return newSourceProcessor(input, &sources.SQSClassifier{
+				Resolver:   resolver,
+				LoadSource: sources.LoadSource,
+			})
 		case models.IntegrationTypeAWS3:
 			var availableLogTypes []string
 			// S3 sources has multiple prefix<>logtypes mappings specified.
 			if m, matched := src.S3PrefixLogTypes.LongestPrefixMatch(input.S3ObjectKey); matched {
 				availableLogTypes = m.LogTypes
+				// Reassemble multi-line log entries before they reach the classifier
+				if err := frameStream(input, &m, resolver); err != nil {
+					return nil, err
+				}
 			}
 			c, err := sources.BuildClassifier(availableLogTypes, src, resolver)
 			if err != nil {
 				return nil, err
 			}
-			return &Processor{
-				operation:  common.OpLogManager.Start(operationName),
-				input:      input,
-				classifier: c,
-			}, nil
-		case models.IntegrationTypeAWSScan:
+			return newSourceProcessor(input, c)
+		case models.IntegrationTypeAWSScan, models.IntegrationTypePoller:
 			c, err := sources.BuildClassifier(src.RequiredLogTypes(), src, resolver)
 			if err != nil {
 				return nil, err
 			}
-			return &Processor{
-				operation:  common.OpLogManager.Start(operationName),
-				input:      input,
-				classifier: c,
-			}, nil
+			return newSourceProcessor(input, c)
 
 		default:
 			return nil, errors.Errorf("invalid source type %s", src.IntegrationType)
@@ -200,12 +205,73 @@ func NewFactory(resolver pantherlog.ParserResolver) Factory {
 	}
 }
 
+func newSourceProcessor(input *common.DataStream, c classification.ClassifierAPI) (*Processor, error) {
+	limiter, err := sampling.New(input.Source.IntegrationID, input.Source.Sampling, nil)
+	if err != nil {
+		return nil, err
+	}
+	p := &Processor{
+		operation:  common.OpLogManager.Start(operationName),
+		input:      input,
+		classifier: c,
+		quarantine: newQuarantineWriter(input),
+		limiter:    limiter,
+	}
+	if limiter != nil {
+		p.cold = newColdWriter(input)
+	}
+	return p, nil
+}
+
+// newQuarantineWriter creates a writer for the lines of a data stream that fail to classify.
+// Quarantine is disabled if there is no processed data bucket configured.
+func newQuarantineWriter(input *common.DataStream) *quarantine.Writer {
+	if common.Config.ProcessedDataBucket == "" || common.S3Client == nil {
+		return nil
+	}
+	uploader := s3manager.NewUploaderWithClient(common.S3Client)
+	return quarantine.NewWriter(uploader, common.Config.ProcessedDataBucket, input.Source.IntegrationID)
+}
+
+// newColdWriter creates a writer for the events of a data stream diverted by the sampling limits.
+// Diverting is disabled if there is no processed data bucket configured.
+func newColdWriter(input *common.DataStream) *sampling.ColdWriter {
+	if common.Config.ProcessedDataBucket == "" || common.S3Client == nil {
+		return nil
+	}
+	uploader := s3manager.NewUploaderWithClient(common.S3Client)
+	return sampling.NewColdWriter(uploader, common.Config.ProcessedDataBucket, input.Source.IntegrationID,
+		common.ConfigForDataLakeWriters())
+}
+
+// frameStream wraps the line stream of an S3 object to reassemble multi-line log entries.
+func frameStream(input *common.DataStream, m *models.S3PrefixLogtypesMapping, resolver pantherlog.ParserResolver) error {
+	// Only text logs are split into lines, streams of JSON arrays or records already produce complete log entries
+	lines, ok := input.Stream.(*logstream.LineStream)
+	if !ok {
+		return nil
+	}
+	framing, err := sources.ResolveFraming(m, resolver)
+	if err != nil {
+		return err
+	}
+	stream, err := logstream.NewFramedStream(lines, framing)
+	if err != nil {
+		return errors.Wrapf(err, "failed to frame log entries for prefix %q", m.S3Prefix)
+	}
+	input.Stream = stream
+	return nil
+}
+
 // processStream reads the data from an S3 the dataStream, parses it and writes events to the output channel
 func (p *Processor) run(ctx context.Context, outputChan chan<- *parsers.Result) (err error) {
 	// Instrument downloads. The time will include time to parse the file.
 	// NOTE: dashboards depend on the operation name below! Do not change w/out updating dashboard
 	operation := common.OpLogManager.Start("readS3Object", common.OpLogS3ServiceDim)
 	defer func() {
+		p.flushQuarantine()
+		p.flushCold()
+		p.syncLimiter(ctx, true)
 		p.logStats(err) // emit log line describing the processing of the file and any errors
 		operation.Stop()
 		operation.Log(err,
@@ -223,6 +289,7 @@ func (p *Processor) run(ctx context.Context, outputChan chan<- *parsers.Result)
 			break
 		}
 		p.processLogLine(ctx, string(line), outputChan)
+		p.syncLimiter(ctx, false)
 	}
 	if err = stream.Err(); err != nil {
 		err = errors.Wrap(err, "failed to read log line")
@@ -242,18 +309,158 @@ func (p *Processor) processLogLine(ctx context.Context, line string, outputChan
 			zap.String("s3Bucket", p.input.S3Bucket),
 			zap.String("s3ObjectKey", p.input.S3ObjectKey),
 		)
+		var parserErrors map[string]error
+		if result != nil {
+			parserErrors = result.ParserErrors
+		}
+		p.quarantineLogLine(line, parserErrors)
 		return
 	}
 	if result == nil {
 		return
 	}
+	// Log events unwrapped from CloudWatch Logs subscription envelopes keep the log group and log stream
+	if s, ok := p.input.Stream.(*logstream.CloudWatchLogsStream); ok {
+		setLogGroup(result.Events, s.LogGroup(), s.LogStream())
+	}
+	threatIntel := common.ThreatIntelMatcher()
+	now := time.Now()
 	for _, event := range result.Events {
+		// Events parsed from the same log entry share its size
+		if decision := p.limiter.Decide(event, len(line)/len(result.Events), now); decision != sampling.Keep {
+			p.limitEvent(event, decision)
+			continue
+		}
+		event.GeoResolver = common.GeoResolver
+		event.ThreatIntel = threatIntel
 		select {
 		case outputChan <- event:
 		case <-ctx.Done():
 			return
 		}
 	}
+	// Records of the log entry that could not be classified are quarantined on their own
+	for _, r := range result.Rejected {
+		p.quarantineLogLine(r.Log, r.ParserErrors)
+	}
+}
+
+// setLogGroup sets the CloudWatch Logs log group and log stream of events
+func setLogGroup(events []*parsers.Result, group, stream string) {
+	for _, event := range events {
+		if event.EventIncludesPantherFields {
+			if e, ok := event.Event.(parsers.PantherLogGroupSetter); ok {
+				e.SetPantherLogGroup(group, stream)
+				continue
+			}
+		}
+		event.PantherLogGroup = group
+		event.PantherLogStream = stream
+	}
+}
+
+// limitEvent handles an event that is not stored due to the sampling limits of the source
+func (p *Processor) limitEvent(event *parsers.Result, decision sampling.Decision) {
+	if decision == sampling.Divert {
+		if p.cold == nil {
+			decision = sampling.Drop
+		} else if err := p.cold.Write(event); err != nil {
+			zap.L().Warn("failed to divert event",
+				zap.String("sourceId", p.input.Source.IntegrationID),
+				zap.String("logType", event.PantherLogType),
+				zap.Error(err))
+			decision = sampling.Drop
+		}
+	}
+	stats, ok := p.samplingStats[event.PantherLogType]
+	if !ok {
+		if p.samplingStats == nil {
+			p.samplingStats = make(map[string]*sampling.Stats)
+		}
+		stats = &sampling.Stats{LogType: event.PantherLogType}
+		p.samplingStats[event.PantherLogType] = stats
+	}
+	stats.Add(decision)
+}
+
+// quarantineLogLine stores a log line that failed to classify along with the errors of each parser
+func (p *Processor) quarantineLogLine(line string, parserErrors map[string]error) {
+	if p.quarantine == nil {
+		return
+	}
+	entry := quarantine.Entry{
+		SourceID:    p.input.Source.IntegrationID,
+		SourceLabel: p.input.Source.IntegrationLabel,
+		S3Bucket:    p.input.S3Bucket,
+		S3ObjectKey: p.input.S3ObjectKey,
+		LineNum:     p.classifier.Stats().LogLineCount,
+		Log:         line,
+	}
+	if len(parserErrors) > 0 {
+		entry.Errors = make(map[string]string, len(parserErrors))
+		for logType, err := range parserErrors {
+			entry.Errors[logType] = err.Error()
+		}
+	}
+	if err := p.quarantine.Write(&entry); err != nil {
+		// Quarantine is best effort, we do not want to fail processing the data stream
+		zap.L().Warn("failed to quarantine log line",
+			zap.String("sourceId", entry.SourceID),
+			zap.String("s3ObjectKey", entry.S3ObjectKey),
+			zap.Uint64("lineNum", entry.LineNum),
+			zap.Error(err))
+	}
+}
+
+// flushQuarantine uploads the quarantined log lines of the data stream
+func (p *Processor) flushQuarantine() {
+	numEntries := p.quarantine.NumEntries()
+	if numEntries == 0 {
+		return
+	}
+	if err := p.quarantine.Flush(); err != nil {
+		zap.L().Warn("failed to upload quarantined log lines",
+			zap.String("sourceId", p.input.Source.IntegrationID),
+			zap.String("s3Bucket", p.input.S3Bucket),
+			zap.String("s3ObjectKey", p.input.S3ObjectKey),
+			zap.Int("numEntries", numEntries),
+			zap.Error(err))
+		return
+	}
+	logmetrics.EventsQuarantined.Add(float64(numEntries))
+}
+
+// flushCold uploads the diverted events of the data stream
+func (p *Processor) flushCold() {
+	numEvents := p.cold.NumEvents()
+	if numEvents == 0 {
+		return
+	}
+	if err := p.cold.Flush(); err != nil {
+		zap.L().Warn("failed to upload diverted events",
+			zap.String("sourceId", p.input.Source.IntegrationID),
+			zap.String("s3Bucket", p.input.S3Bucket),
+			zap.String("s3ObjectKey", p.input.S3ObjectKey),
+			zap.Int("numEvents", numEvents),
+			zap.Error(err))
+	}
+}
+
+// syncLimiter syncs the sampling counts with the other log processor instances.
+// Unless flushing, the counts are only synced every few seconds.
+// The caps are enforced with the counts of this instance until the next successful sync.
+func (p *Processor) syncLimiter(ctx context.Context, flush bool) {
+	var err error
+	if flush {
+		err = p.limiter.Flush(ctx)
+	} else {
+		err = p.limiter.Sync(ctx, time.Now())
+	}
+	if err != nil {
+		zap.L().Warn("failed to sync sampling counts",
+			zap.String("sourceId", p.input.Source.IntegrationID),
+			zap.Error(err))
+	}
 }
 
 func (p *Processor) logStats(err error) {
@@ -262,5 +469,19 @@ func (p *Processor) logStats(err error) {
 	for _, stats := range p.classifier.ParserStats() {
 		logmetrics.BytesProcessed.With(metrics.LogTypeDimension, stats.LogType).Add(float64(stats.BytesProcessedCount))
 		logmetrics.EventsProcessed.With(metrics.LogTypeDimension, stats.LogType).Add(float64(stats.EventCount))
+		if stats.EventFilteredCount > 0 {
+			logmetrics.EventsFiltered.With(metrics.LogTypeDimension, stats.LogType).Add(float64(stats.EventFilteredCount))
+		}
+	}
+	for _, stats := range p.samplingStats {
+		if stats.EventsSampled > 0 {
+			logmetrics.EventsSampled.With(metrics.LogTypeDimension, stats.LogType).Add(float64(stats.EventsSampled))
+		}
+		if stats.EventsDropped > 0 {
+			logmetrics.EventsDropped.With(metrics.LogTypeDimension, stats.LogType).Add(float64(stats.EventsDropped))
+		}
+		if stats.EventsDiverted > 0 {
+			logmetrics.EventsDiverted.With(metrics.LogTypeDimension, stats.LogType).Add(float64(stats.EventsDiverted))
+		}
 	}
 }
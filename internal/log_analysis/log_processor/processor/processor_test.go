@@ -26,6 +26,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -45,6 +47,8 @@ import (
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/testutil"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/timestamp"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/logstream"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/quarantine"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/sampling"
 	"github.com/panther-labs/panther/pkg/oplog"
 	"github.com/panther-labs/panther/pkg/testutils"
 )
@@ -379,6 +383,128 @@ func TestProcessClassifyFailure(t *testing.T) {
 	assert.True(t, dataStream.Closer.(*dummyCloser).closed)
 }
 
+func TestProcessQuarantine(t *testing.T) {
+	mockLogger()
+	metrics := setupMockMetrics()
+	metrics.bytesProcessed.On("With", mock.Anything).Return(metrics.bytesProcessed).Once()
+	metrics.bytesProcessed.On("Add", mock.Anything).Once()
+	metrics.eventsProcessed.On("With", mock.Anything).Return(metrics.eventsProcessed).Once()
+	metrics.eventsProcessed.On("Add", mock.Anything).Once()
+	metrics.eventsQuarantined.On("Add", float64(2)).Once()
+
+	destination := (&testDestination{}).standardMock()
+	dataStream := makeDataStream()
+	f := NewFactory(testResolver)
+	p, err := f(dataStream)
+	require.NoError(t, err)
+	mockClassifier := &testClassifier{}
+	p.classifier = mockClassifier
+	uploader := &testutils.S3UploaderMock{}
+	p.quarantine = quarantine.NewWriter(uploader, "processed", testSourceID)
+
+	// first one fails
+	mockClassifier.On("Classify", mock.Anything).Return(&classification.ClassifierResult{
+		ParserErrors: map[string]error{
+			testLogType: errors.New("invalid"),
+		},
+	}, errFailingReader).Once()
+	// second one has a record that fails
+	mockClassifier.On("Classify", mock.Anything).Return(&classification.ClassifierResult{
+		Events:  []*parsers.Result{newTestLog()},
+		Matched: true,
+		Rejected: []*classification.RejectedRecord{
+			{
+				Log: `{"record":2}`,
+				ParserErrors: map[string]error{
+					testLogType: errors.New("invalid record"),
+				},
+			},
+		},
+	}, nil).Once()
+	mockClassifier.On("Classify", mock.Anything).Return(&classification.ClassifierResult{
+		Events:  []*parsers.Result{newTestLog()},
+		Matched: true,
+	}, nil)
+	mockClassifier.On("Stats", mock.Anything).Return(&classification.ClassifierStats{LogLineCount: 1})
+	mockClassifier.On("ParserStats", mock.Anything).Return(map[string]*classification.ParserStats{})
+
+	var entries []*quarantine.Entry
+	uploader.On("Upload", mock.Anything, mock.Anything).Return(&s3manager.UploadOutput{}, nil).Run(func(args mock.Arguments) {
+		input := args.Get(0).(*s3manager.UploadInput)
+		require.Equal(t, "processed", aws.StringValue(input.Bucket))
+		require.True(t, strings.HasPrefix(aws.StringValue(input.Key), quarantine.SourcePrefix(testSourceID)))
+		err := quarantine.ReadEntries(input.Body, func(entry *quarantine.Entry) error {
+			entries = append(entries, entry)
+			return nil
+		})
+		require.NoError(t, err)
+	}).Once()
+
+	newProcessorFunc := func(*common.DataStream) (*Processor, error) { return p, nil }
+	streamChan := make(chan *common.DataStream, 1)
+	streamChan <- dataStream
+	close(streamChan)
+	err = Process(context.Background(), streamChan, destination, newProcessorFunc)
+	require.NoError(t, err)
+
+	uploader.AssertExpectations(t)
+	metrics.eventsQuarantined.AssertExpectations(t)
+	require.Len(t, entries, 2)
+	require.Equal(t, testLogLine, entries[0].Log)
+	require.Equal(t, testBucket, entries[0].S3Bucket)
+	require.Equal(t, testKey, entries[0].S3ObjectKey)
+	require.Equal(t, uint64(1), entries[0].LineNum)
+	require.Equal(t, map[string]string{testLogType: "invalid"}, entries[0].Errors)
+	require.Equal(t, `{"record":2}`, entries[1].Log)
+	require.Equal(t, map[string]string{testLogType: "invalid record"}, entries[1].Errors)
+}
+
+func TestProcessSampling(t *testing.T) {
+	destination := (&testDestination{}).standardMock()
+	metrics := setupMockMetrics()
+	metrics.bytesProcessed.On("With", mock.Anything).Return(metrics.bytesProcessed).Once()
+	metrics.bytesProcessed.On("Add", mock.Anything).Once()
+	metrics.eventsProcessed.On("With", mock.Anything).Return(metrics.eventsProcessed).Once()
+	metrics.eventsProcessed.On("Add", mock.Anything).Once()
+	metrics.eventsDropped.On("With", []string{"LogType", "testLogType"}).Return(metrics.eventsDropped).Once()
+	metrics.eventsDropped.On("Add", float64(testLogLines-100)).Once()
+
+	dataStream := makeDataStream()
+	f := NewFactory(testResolver)
+	p, err := f(dataStream)
+	require.NoError(t, err)
+	mockClassifier := &testClassifier{}
+	p.classifier = mockClassifier
+	mockClassifier.standardMocks(&classification.ClassifierStats{}, map[string]*classification.ParserStats{
+		testLogType: {LogType: testLogType},
+	})
+	p.limiter, err = sampling.New(testSourceID, []sampling.Config{
+		{LogType: testLogType, MaxEventsPerHour: 100},
+	}, sampling.NewCounters())
+	require.NoError(t, err)
+
+	newProcessorFunc := func(*common.DataStream) (*Processor, error) { return p, nil }
+	streamChan := make(chan *common.DataStream, 1)
+	streamChan <- dataStream
+	close(streamChan)
+	err = Process(context.Background(), streamChan, destination, newProcessorFunc)
+	require.NoError(t, err)
+	require.Equal(t, uint64(100), destination.nEvents)
+	metrics.eventsDropped.AssertExpectations(t)
+}
+
+func TestSetLogGroup(t *testing.T) {
+	legacy := newTestLog()
+	event := &pantherlog.Result{Event: struct{}{}}
+	setLogGroup([]*parsers.Result{legacy, event}, "/aws/lambda/foo", "2020/10/01/[$LATEST]0123456789abcdef")
+	require.Equal(t, "/aws/lambda/foo", event.PantherLogGroup)
+	require.Equal(t, "2020/10/01/[$LATEST]0123456789abcdef", event.PantherLogStream)
+	// Events that embed parsers.PantherLog write their own panther fields
+	log := legacy.Event.(*testLog)
+	require.Equal(t, "/aws/lambda/foo", aws.StringValue(log.PantherLogGroup))
+	require.Equal(t, "2020/10/01/[$LATEST]0123456789abcdef", aws.StringValue(log.PantherLogStream))
+}
+
 // deals with the error package inserting line numbers into errors
 func assertLogEqual(t *testing.T, expected, actual observer.LoggedEntry) {
 	for k, v := range expected.ContextMap() {
@@ -509,8 +635,10 @@ func mockLogger() *observer.ObservedLogs {
 }
 
 type mockMetrics struct {
-	bytesProcessed  *testutils.CounterMock
-	eventsProcessed *testutils.CounterMock
+	bytesProcessed    *testutils.CounterMock
+	eventsProcessed   *testutils.CounterMock
+	eventsQuarantined *testutils.CounterMock
+	eventsDropped     *testutils.CounterMock
 }
 
 func setupMockMetrics() *mockMetrics {
@@ -520,8 +648,16 @@ func setupMockMetrics() *mockMetrics {
 	eventsProcessedMock := &testutils.CounterMock{}
 	logmetrics.EventsProcessed = eventsProcessedMock
 
+	eventsQuarantinedMock := &testutils.CounterMock{}
+	logmetrics.EventsQuarantined = eventsQuarantinedMock
+
+	eventsDroppedMock := &testutils.CounterMock{}
+	logmetrics.EventsDropped = eventsDroppedMock
+
 	return &mockMetrics{
-		bytesProcessed:  bytesProcessedMock,
-		eventsProcessed: eventsProcessedMock,
+		bytesProcessed:    bytesProcessedMock,
+		eventsProcessed:   eventsProcessedMock,
+		eventsQuarantined: eventsQuarantinedMock,
+		eventsDropped:     eventsDroppedMock,
 	}
 }
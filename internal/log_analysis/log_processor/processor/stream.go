@@ -20,7 +20,6 @@ package processor
 
 import (
 	"context"
-	"io"
 	"runtime"
 	"time"
 
@@ -30,16 +29,13 @@ import (
 	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
-	"golang.org/x/sync/errgroup"
 
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/destinations"
-	logmetrics "github.com/panther-labs/panther/internal/log_analysis/log_processor/metrics"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/sources"
 	"github.com/panther-labs/panther/pkg/awsbatch/sqsbatch"
 	"github.com/panther-labs/panther/pkg/awsutils"
-	"github.com/panther-labs/panther/pkg/metrics"
 )
 
 const (
@@ -126,14 +122,8 @@ func pollEvents(
 
 			for _, msg := range messages {
 				// pass lambda context to set FULL deadline to process which is pushed down into downloader
+				// S3 streams are readable once generated since detecting their format reads the first bytes of each object
 				dataStreams, err := generateDataStreamsFunc(ctx, aws.StringValue(msg.Body))
-				if err == nil {
-					// This is a temporary workaround to ensure all S3 streams are readable.
-					// The proper solution for this require an SQS message tracker that allows true concurrent processing.
-					// The overall behavior of the system does not change since reading was triggered
-					// when we were detecting MIME types by using `Peek()`.
-					err = kickOffReaders(ctx, dataStreams)
-				}
 				if err != nil {
 					// No need for error here. This issue can happen due to
 					// 1. Persistent AWS issues while accessing S3 object
@@ -198,40 +188,3 @@ func receiveFromSqs(ctx context.Context, sqsClient sqsiface.SQSAPI) ([]*sqs.Mess
 
 	return output.Messages, nil
 }
-
-func kickOffReaders(ctx context.Context, streams []*common.DataStream) error {
-	grp, _ := errgroup.WithContext(ctx)
-	for _, s := range streams {
-		s := s
-		r, ok := s.Closer.(io.ReadCloser)
-		if !ok {
-			continue
-		}
-		grp.Go(func() error {
-			err := readZero(r)
-			logmetrics.GetObject.With(
-				metrics.SourceIDDimension, s.Source.IntegrationID,
-				metrics.StatusDimension, statusFromErr(err),
-			).Add(1)
-			return err
-		})
-	}
-	return grp.Wait()
-}
-
-func readZero(r io.Reader) error {
-	buf := [0]byte{}
-	_, err := r.Read(buf[:])
-	return err
-}
-
-// Returns the correct Status dimension from the provided error
-func statusFromErr(err error) string {
-	if err == nil {
-		return logmetrics.StatusOK
-	}
-	if awsutils.IsAnyError(err, "AccessDenied") {
-		return logmetrics.StatusAuthErr
-	}
-	return logmetrics.StatusErr
-}
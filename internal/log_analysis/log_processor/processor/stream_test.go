@@ -37,7 +37,6 @@ import (
 	"github.com/panther-labs/panther/api/lambda/source/models"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/destinations"
-	"github.com/panther-labs/panther/internal/log_analysis/log_processor/metrics"
 	"github.com/panther-labs/panther/pkg/testutils"
 )
 
@@ -56,10 +55,6 @@ func init() {
 	// set these once at start of test
 	common.Config.AwsLambdaFunctionMemorySize = 1024
 	common.Config.SqsQueueURL = "https://fakesqsurl"
-	getObjectMock := &testutils.CounterMock{}
-	getObjectMock.On("With", mock.Anything).Return(getObjectMock).Maybe()
-	getObjectMock.On("Add", mock.Anything).Maybe()
-	metrics.GetObject = getObjectMock
 }
 
 func TestStreamEvents(t *testing.T) {
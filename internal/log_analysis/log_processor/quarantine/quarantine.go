@@ -0,0 +1,206 @@
+// Package quarantine stores log entries that could not be classified so that they can be inspected and replayed.
+package quarantine
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager/s3manageriface"
+	"github.com/google/uuid"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+)
+
+const (
+	// Prefix is the key prefix of quarantined log entries in the processed data bucket
+	Prefix = "quarantine/"
+
+	// MaxObjectSize is the max size of the compressed entries stored in a single quarantine object
+	MaxObjectSize = 10 * 1024 * 1024
+
+	// The timestamp layout used in the S3 object key filename part with second precision: yyyyMMddTHHmmssZ
+	objectTimestampLayout = "20060102T150405Z"
+	// The layout of the hourly 'folders' under the source prefix: yyyy/MM/dd/HH
+	objectHourLayout = "2006/01/02/15"
+)
+
+// Entry is a log entry that could not be classified
+type Entry struct {
+	SourceID    string `json:"sourceId"`
+	SourceLabel string `json:"sourceLabel"`
+	S3Bucket    string `json:"s3Bucket,omitempty"`
+	S3ObjectKey string `json:"s3ObjectKey,omitempty"`
+	// LineNum is the number of the log entry in the S3 object
+	LineNum uint64 `json:"lineNum"`
+	Log     string `json:"log"`
+	// Errors has the error of each candidate parser keyed by log type
+	Errors        map[string]string `json:"errors,omitempty"`
+	QuarantinedAt time.Time         `json:"quarantinedAt"`
+}
+
+// LogTypes returns the log types of the candidate parsers for the entry
+func (e *Entry) LogTypes() []string {
+	logTypes := make([]string, 0, len(e.Errors))
+	for logType := range e.Errors {
+		logTypes = append(logTypes, logType)
+	}
+	sort.Strings(logTypes)
+	return logTypes
+}
+
+// SourcePrefix returns the key prefix of all quarantine objects for a source
+func SourcePrefix(sourceID string) string {
+	return Prefix + sourceID + "/"
+}
+
+// ObjectKey returns a new unique key for a quarantine object of a source
+func ObjectKey(sourceID string, tm time.Time) string {
+	tm = tm.UTC()
+	name := tm.Format(objectTimestampLayout) + "-" + uuid.New().String() + ".json.gz"
+	return path.Join(SourcePrefix(sourceID), tm.Format(objectHourLayout), name)
+}
+
+// Writer collects quarantined entries of a source and uploads them to S3 as gzipped JSON lines.
+// All methods are no-op for a nil Writer so that quarantine can be disabled.
+type Writer struct {
+	Uploader s3manageriface.UploaderAPI
+	Bucket   string
+	SourceID string
+	// Now is used to timestamp entries and objects, defaults to time.Now
+	Now func() time.Time
+
+	buffer     bytes.Buffer
+	gzipWriter *gzip.Writer
+	stream     *jsoniter.Stream
+	numEntries int
+}
+
+// NewWriter creates a writer for the quarantined entries of a source
+func NewWriter(uploader s3manageriface.UploaderAPI, bucket, sourceID string) *Writer {
+	return &Writer{
+		Uploader: uploader,
+		Bucket:   bucket,
+		SourceID: sourceID,
+	}
+}
+
+func (w *Writer) now() time.Time {
+	if w.Now != nil {
+		return w.Now()
+	}
+	return time.Now()
+}
+
+// Write adds an entry to the current quarantine object.
+// If the object grows larger than MaxObjectSize it is uploaded.
+func (w *Writer) Write(entry *Entry) error {
+	if w == nil {
+		return nil
+	}
+	if entry.QuarantinedAt.IsZero() {
+		entry.QuarantinedAt = w.now().UTC()
+	}
+	if w.gzipWriter == nil {
+		w.gzipWriter = gzip.NewWriter(&w.buffer)
+		w.stream = jsoniter.NewStream(jsoniter.ConfigDefault, w.gzipWriter, 8192)
+	}
+	w.stream.WriteVal(entry)
+	w.stream.WriteRaw("\n")
+	if err := w.stream.Flush(); err != nil {
+		return errors.Wrap(err, "failed to write quarantine entry")
+	}
+	w.numEntries++
+	if w.buffer.Len() >= MaxObjectSize {
+		return w.Flush()
+	}
+	return nil
+}
+
+// NumEntries returns the number of entries that have not been uploaded yet
+func (w *Writer) NumEntries() int {
+	if w == nil {
+		return 0
+	}
+	return w.numEntries
+}
+
+// Flush uploads the pending entries to a new quarantine object
+func (w *Writer) Flush() error {
+	if w == nil || w.numEntries == 0 {
+		return nil
+	}
+	if err := w.gzipWriter.Close(); err != nil {
+		return errors.Wrap(err, "failed to close quarantine object")
+	}
+	key := ObjectKey(w.SourceID, w.now())
+	_, err := w.Uploader.Upload(&s3manager.UploadInput{
+		Bucket:          aws.String(w.Bucket),
+		Key:             aws.String(key),
+		Body:            bytes.NewReader(w.buffer.Bytes()),
+		ContentType:     aws.String("application/x-ndjson"),
+		ContentEncoding: aws.String("gzip"),
+	})
+	w.reset()
+	if err != nil {
+		return errors.Wrapf(err, "failed to upload quarantine object s3://%s/%s", w.Bucket, key)
+	}
+	return nil
+}
+
+func (w *Writer) reset() {
+	w.buffer.Reset()
+	w.gzipWriter = nil
+	w.stream = nil
+	w.numEntries = 0
+}
+
+// ReadEntries reads the entries of a gzipped quarantine object calling fn for each entry
+func ReadEntries(r io.Reader, fn func(entry *Entry) error) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return errors.Wrap(err, "failed to read quarantine object")
+	}
+	defer gz.Close()
+	scanner := bufio.NewScanner(gz)
+	// Quarantined log entries can be as large as a multi-line log entry
+	scanner.Buffer(nil, MaxObjectSize*10)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		entry := Entry{}
+		if err := jsoniter.Unmarshal(line, &entry); err != nil {
+			return errors.Wrap(err, "failed to decode quarantine entry")
+		}
+		if err := fn(&entry); err != nil {
+			return err
+		}
+	}
+	return errors.Wrap(scanner.Err(), "failed to read quarantine object")
+}
@@ -0,0 +1,118 @@
+package quarantine
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bytes"
+	"io/ioutil"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/pkg/testutils"
+)
+
+func TestObjectKey(t *testing.T) {
+	tm := time.Date(2020, 10, 1, 12, 30, 15, 0, time.UTC)
+	key := ObjectKey("source-id", tm)
+	require.Regexp(t, regexp.MustCompile(`^quarantine/source-id/2020/10/01/12/20201001T123015Z-[0-9a-f-]{36}\.json\.gz$`), key)
+	require.NotEqual(t, key, ObjectKey("source-id", tm))
+}
+
+func TestWriter(t *testing.T) {
+	assert := require.New(t)
+	tm := time.Date(2020, 10, 1, 12, 30, 15, 0, time.UTC)
+	uploader := &testutils.S3UploaderMock{}
+	w := NewWriter(uploader, "bucket", "source-id")
+	w.Now = func() time.Time { return tm }
+
+	// Nothing to flush
+	assert.NoError(w.Flush())
+	uploader.AssertNotCalled(t, "Upload", mock.Anything, mock.Anything)
+
+	entries := []*Entry{
+		{
+			SourceID:    "source-id",
+			SourceLabel: "source",
+			S3Bucket:    "logs",
+			S3ObjectKey: "foo.log",
+			LineNum:     1,
+			Log:         "foo",
+			Errors: map[string]string{
+				"Foo.Bar": "invalid",
+				"Foo.Baz": "invalid",
+			},
+		},
+		{
+			SourceID: "source-id",
+			LineNum:  3,
+			Log:      "multi\nline",
+		},
+	}
+	for _, entry := range entries {
+		assert.NoError(w.Write(entry))
+	}
+	assert.Equal(2, w.NumEntries())
+
+	var body []byte
+	uploader.On("Upload", mock.Anything, mock.Anything).Return(&s3manager.UploadOutput{}, nil).Run(func(args mock.Arguments) {
+		input := args.Get(0).(*s3manager.UploadInput)
+		assert.Equal("bucket", aws.StringValue(input.Bucket))
+		assert.Contains(aws.StringValue(input.Key), "quarantine/source-id/2020/10/01/12/")
+		assert.Equal("gzip", aws.StringValue(input.ContentEncoding))
+		data, err := ioutil.ReadAll(input.Body)
+		assert.NoError(err)
+		body = data
+	}).Once()
+	assert.NoError(w.Flush())
+	assert.Equal(0, w.NumEntries())
+	uploader.AssertExpectations(t)
+
+	var actual []*Entry
+	err := ReadEntries(bytes.NewReader(body), func(entry *Entry) error {
+		actual = append(actual, entry)
+		return nil
+	})
+	assert.NoError(err)
+	assert.Len(actual, 2)
+	assert.Equal(tm, actual[0].QuarantinedAt)
+	assert.Equal([]string{"Foo.Bar", "Foo.Baz"}, actual[0].LogTypes())
+	assert.Equal(entries[0].Log, actual[0].Log)
+	assert.Equal("multi\nline", actual[1].Log)
+	assert.Empty(actual[1].LogTypes())
+
+	// Failed uploads drop the pending entries
+	uploader.On("Upload", mock.Anything, mock.Anything).Return((*s3manager.UploadOutput)(nil), errors.New("failed")).Once()
+	assert.NoError(w.Write(&Entry{Log: "foo"}))
+	assert.Error(w.Flush())
+	assert.Equal(0, w.NumEntries())
+}
+
+func TestNilWriter(t *testing.T) {
+	var w *Writer
+	require.NoError(t, w.Write(&Entry{Log: "foo"}))
+	require.Equal(t, 0, w.NumEntries())
+	require.NoError(t, w.Flush())
+}
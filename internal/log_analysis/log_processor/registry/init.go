@@ -24,6 +24,7 @@ import (
 	// Packages that export log types
 	apachelogs "github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/apachelogs"
 	awslogs "github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/awslogs"
+	azurelogs "github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/azurelogs"
 	boxlogs "github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/boxlogs"
 	cloudflarelogs "github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/cloudflarelogs"
 	crowdstrikelogs "github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/crowdstrikelogs"
@@ -35,7 +36,9 @@ import (
 	gravitationallogs "github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/gravitationallogs"
 	gsuitelogs "github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/gsuitelogs"
 	juniperlogs "github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/juniperlogs"
+	kuberneteslogs "github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/kuberneteslogs"
 	laceworklogs "github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/laceworklogs"
+	microsoft365logs "github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/microsoft365logs"
 	nginxlogs "github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/nginxlogs"
 	oktalogs "github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/oktalogs"
 	oneloginlogs "github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/oneloginlogs"
@@ -57,6 +60,8 @@ func init() {
 
 		awslogs.LogTypes(),
 
+		azurelogs.LogTypes(),
+
 		boxlogs.LogTypes(),
 
 		cloudflarelogs.LogTypes(),
@@ -79,8 +84,12 @@ func init() {
 
 		juniperlogs.LogTypes(),
 
+		kuberneteslogs.LogTypes(),
+
 		laceworklogs.LogTypes(),
 
+		microsoft365logs.LogTypes(),
+
 		nginxlogs.LogTypes(),
 
 		oktalogs.LogTypes(),
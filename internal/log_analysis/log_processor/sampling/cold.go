@@ -0,0 +1,169 @@
+package sampling
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bytes"
+	"compress/gzip"
+	"path"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager/s3manageriface"
+	"github.com/google/uuid"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+const (
+	// ColdPrefix is the key prefix of diverted events in the processed data bucket
+	ColdPrefix = "cold/"
+
+	// MaxColdObjectSize is the max size of the compressed events stored in a single cold object
+	MaxColdObjectSize = 50 * 1024 * 1024
+
+	// The timestamp layout used in the S3 object key filename part with second precision: yyyyMMddTHHmmssZ
+	objectTimestampLayout = "20060102T150405Z"
+	// The layout of the hourly 'folders' under the log type prefix: yyyy/MM/dd/HH
+	objectHourLayout = "2006/01/02/15"
+)
+
+// ColdObjectKey returns a new unique key for a cold object with the events of a log type of a source
+func ColdObjectKey(sourceID, logType string, tm time.Time) string {
+	tm = tm.UTC()
+	name := tm.Format(objectTimestampLayout) + "-" + uuid.New().String() + ".json.gz"
+	return path.Join(ColdPrefix, sourceID, logType, tm.Format(objectHourLayout), name)
+}
+
+// ColdWriter collects the diverted events of a source and uploads them to S3 as gzipped JSON lines.
+// Events are stored with all Panther fields so they can be replayed or queried if needed.
+// All methods are no-op for a nil ColdWriter so that diverting can be disabled.
+type ColdWriter struct {
+	Uploader s3manageriface.UploaderAPI
+	Bucket   string
+	SourceID string
+	// JSON is the API used to encode events
+	JSON jsoniter.API
+	// Now is used to timestamp objects, defaults to time.Now
+	Now func() time.Time
+
+	objects map[string]*coldObject
+}
+
+type coldObject struct {
+	buffer     bytes.Buffer
+	gzipWriter *gzip.Writer
+	numEvents  int
+}
+
+// NewColdWriter creates a writer for the diverted events of a source
+func NewColdWriter(uploader s3manageriface.UploaderAPI, bucket, sourceID string, jsonAPI jsoniter.API) *ColdWriter {
+	return &ColdWriter{
+		Uploader: uploader,
+		Bucket:   bucket,
+		SourceID: sourceID,
+		JSON:     jsonAPI,
+	}
+}
+
+func (w *ColdWriter) now() time.Time {
+	if w.Now != nil {
+		return w.Now()
+	}
+	return time.Now()
+}
+
+// Write adds an event to the current cold object of its log type.
+// If the object grows larger than MaxColdObjectSize it is uploaded.
+func (w *ColdWriter) Write(result *pantherlog.Result) error {
+	if w == nil {
+		return nil
+	}
+	data, err := w.JSON.Marshal(result)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode diverted event")
+	}
+	logType := result.PantherLogType
+	obj, ok := w.objects[logType]
+	if !ok {
+		if w.objects == nil {
+			w.objects = make(map[string]*coldObject)
+		}
+		obj = &coldObject{}
+		obj.gzipWriter = gzip.NewWriter(&obj.buffer)
+		w.objects[logType] = obj
+	}
+	data = append(data, '\n')
+	if _, err := obj.gzipWriter.Write(data); err != nil {
+		return errors.Wrap(err, "failed to write diverted event")
+	}
+	obj.numEvents++
+	if obj.buffer.Len() >= MaxColdObjectSize {
+		return w.upload(logType, obj)
+	}
+	return nil
+}
+
+// NumEvents returns the number of events that have not been uploaded yet
+func (w *ColdWriter) NumEvents() (n int) {
+	if w == nil {
+		return 0
+	}
+	for _, obj := range w.objects {
+		n += obj.numEvents
+	}
+	return n
+}
+
+// Flush uploads the pending events to new cold objects.
+// Pending events are discarded if the upload fails.
+func (w *ColdWriter) Flush() error {
+	if w == nil {
+		return nil
+	}
+	var err error
+	for logType, obj := range w.objects {
+		if e := w.upload(logType, obj); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+func (w *ColdWriter) upload(logType string, obj *coldObject) error {
+	delete(w.objects, logType)
+	if err := obj.gzipWriter.Close(); err != nil {
+		return errors.Wrap(err, "failed to close cold object")
+	}
+	key := ColdObjectKey(w.SourceID, logType, w.now())
+	_, err := w.Uploader.Upload(&s3manager.UploadInput{
+		Bucket:          aws.String(w.Bucket),
+		Key:             aws.String(key),
+		Body:            bytes.NewReader(obj.buffer.Bytes()),
+		ContentType:     aws.String("application/x-ndjson"),
+		ContentEncoding: aws.String("gzip"),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to upload cold object s3://%s/%s", w.Bucket, key)
+	}
+	return nil
+}
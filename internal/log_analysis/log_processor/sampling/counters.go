@@ -0,0 +1,224 @@
+package sampling
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+)
+
+const (
+	// DefaultSyncInterval is the min interval between syncs of the counters with their store
+	DefaultSyncInterval = 10 * time.Second
+
+	// Hourly counts are only needed for the current hour, the TTL leaves some margin for late events
+	countsTTL = 2 * time.Hour
+)
+
+// DefaultCounters are the counters shared by all sources processed by a log processor instance.
+var DefaultCounters = NewCounters()
+
+// Store keeps the hourly counts of the log processor instances.
+type Store interface {
+	// Add adds events and bytes to the counts of the log type of a source for an hour and returns the updated counts
+	Add(ctx context.Context, sourceID, logType string, hour time.Time, events, bytes uint64) (totalEvents, totalBytes uint64, err error)
+}
+
+// Counters track the events and bytes stored each hour for each log type of a source.
+//
+// Counts are kept in memory and added to the Store on each Sync so that the caps are enforced across all log
+// processor instances. Between syncs an instance can exceed the caps by the events it stored since the last sync.
+// Without a Store the caps are enforced by each instance separately.
+type Counters struct {
+	// Store is shared by all log processor instances, the counts are kept only in memory if nil
+	Store Store
+	// SyncInterval is the min interval between syncs with the Store
+	SyncInterval time.Duration
+
+	mu       sync.Mutex
+	windows  map[counterKey]*window
+	lastSync time.Time
+}
+
+type counterKey struct {
+	sourceID string
+	logType  string
+}
+
+type window struct {
+	hour time.Time
+	// The counts of all instances as of the last sync plus the pending counts
+	events uint64
+	bytes  uint64
+	// The counts not yet added to the store
+	pendingEvents uint64
+	pendingBytes  uint64
+	synced        bool
+}
+
+// NewCounters creates empty counters
+func NewCounters() *Counters {
+	return &Counters{
+		SyncInterval: DefaultSyncInterval,
+		windows:      make(map[counterKey]*window),
+	}
+}
+
+// take adds an event to the window of the hour if it does not exceed the caps
+func (c *Counters) take(key counterKey, hour time.Time, size, maxEvents, maxBytes uint64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	w, ok := c.windows[key]
+	if !ok {
+		w = &window{}
+		c.windows[key] = w
+	}
+	if !w.hour.Equal(hour) {
+		*w = window{hour: hour}
+	}
+	if maxEvents > 0 && w.events+1 > maxEvents {
+		return false
+	}
+	if maxBytes > 0 && w.bytes+size > maxBytes {
+		return false
+	}
+	w.events++
+	w.bytes += size
+	w.pendingEvents++
+	w.pendingBytes += size
+	return true
+}
+
+// Sync syncs the counters with the store if the last sync was more than SyncInterval ago
+func (c *Counters) Sync(ctx context.Context, now time.Time) error {
+	c.mu.Lock()
+	if c.Store == nil || now.Sub(c.lastSync) < c.SyncInterval {
+		c.mu.Unlock()
+		return nil
+	}
+	c.lastSync = now
+	c.mu.Unlock()
+	return c.Flush(ctx)
+}
+
+// Flush adds the pending counts to the store and updates the counts of all instances
+func (c *Counters) Flush(ctx context.Context) error {
+	if c.Store == nil {
+		return nil
+	}
+	type update struct {
+		key    counterKey
+		hour   time.Time
+		events uint64
+		bytes  uint64
+	}
+	c.mu.Lock()
+	updates := make([]update, 0, len(c.windows))
+	for key, w := range c.windows {
+		if w.synced && w.pendingEvents == 0 && w.pendingBytes == 0 {
+			continue
+		}
+		updates = append(updates, update{
+			key:    key,
+			hour:   w.hour,
+			events: w.pendingEvents,
+			bytes:  w.pendingBytes,
+		})
+		w.pendingEvents, w.pendingBytes = 0, 0
+	}
+	c.mu.Unlock()
+
+	// The store is updated without holding the lock so that events are not blocked
+	var err error
+	for _, u := range updates {
+		events, bytes, addErr := c.Store.Add(ctx, u.key.sourceID, u.key.logType, u.hour, u.events, u.bytes)
+		c.mu.Lock()
+		switch w := c.windows[u.key]; {
+		case !w.hour.Equal(u.hour):
+			// The window moved to the next hour while syncing
+		case addErr != nil:
+			// The counts are added on the next sync
+			w.pendingEvents += u.events
+			w.pendingBytes += u.bytes
+		default:
+			w.events = events + w.pendingEvents
+			w.bytes = bytes + w.pendingBytes
+			w.synced = true
+		}
+		c.mu.Unlock()
+		err = multierr.Append(err, addErr)
+	}
+	return err
+}
+
+// DynamoDBStore keeps the hourly counts in a DynamoDB table with an `id` string hash key.
+// Items expire after the hour using the `expiresAt` TTL attribute.
+type DynamoDBStore struct {
+	Client    dynamodbiface.DynamoDBAPI
+	TableName string
+}
+
+var _ Store = (*DynamoDBStore)(nil)
+
+// Add implements Store
+func (s *DynamoDBStore) Add(ctx context.Context, sourceID, logType string, hour time.Time, events, bytes uint64) (uint64, uint64, error) {
+	update := expression.
+		Add(expression.Name("events"), expression.Value(events)).
+		Add(expression.Name("bytes"), expression.Value(bytes)).
+		Set(expression.Name("expiresAt"), expression.Value(hour.Add(countsTTL).Unix()))
+	expr, err := expression.NewBuilder().WithUpdate(update).Build()
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "failed to build sampling counts update")
+	}
+	output, err := s.Client.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(s.TableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {S: aws.String(countsID(sourceID, logType, hour))},
+		},
+		UpdateExpression:          expr.Update(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		ReturnValues:              aws.String(dynamodb.ReturnValueUpdatedNew),
+	})
+	if err != nil {
+		return 0, 0, errors.Wrapf(err, "failed to update sampling counts of %s for source %s", logType, sourceID)
+	}
+	counts := struct {
+		Events uint64 `dynamodbav:"events"`
+		Bytes  uint64 `dynamodbav:"bytes"`
+	}{}
+	if err := dynamodbattribute.UnmarshalMap(output.Attributes, &counts); err != nil {
+		return 0, 0, errors.Wrap(err, "failed to read sampling counts")
+	}
+	return counts.Events, counts.Bytes, nil
+}
+
+// countsID is the key of the counts of a log type of a source for an hour
+func countsID(sourceID, logType string, hour time.Time) string {
+	return sourceID + "/" + logType + "/" + hour.UTC().Format("2006-01-02T15")
+}
@@ -0,0 +1,238 @@
+// Package sampling limits the volume of events stored for each log type of a source.
+package sampling
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"hash/fnv"
+	"math"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/transform"
+)
+
+// Overflow policies
+const (
+	// OverflowDrop discards events exceeding the caps
+	OverflowDrop = "drop"
+	// OverflowDivert stores events exceeding the caps under ColdPrefix where they are not queried or analyzed
+	OverflowDivert = "divert"
+)
+
+// Config controls the volume of the events of a log type
+type Config struct {
+	// LogType is the log type of the events
+	LogType string `json:"logType" validate:"required"`
+	// SampleRate is the fraction of events to keep, all events are kept if not set
+	SampleRate float64 `json:"sampleRate,omitempty" validate:"omitempty,gt=0,lte=1"`
+	// SampleField is the path of the field that decides which events are sampled (defaults to p_row_id).
+	// Events with the same value for the field are either all kept or all dropped.
+	SampleField string `json:"sampleField,omitempty"`
+	// MaxEventsPerHour caps the number of events stored each hour
+	MaxEventsPerHour uint64 `json:"maxEventsPerHour,omitempty"`
+	// MaxBytesPerHour caps the size of the log entries stored each hour
+	MaxBytesPerHour uint64 `json:"maxBytesPerHour,omitempty"`
+	// Overflow is the policy for events exceeding the caps (defaults to drop)
+	Overflow string `json:"overflow,omitempty" validate:"omitempty,oneof=drop divert"`
+}
+
+// Decision is the outcome of the volume controls for an event
+type Decision int
+
+const (
+	// Keep stores the event
+	Keep Decision = iota
+	// Sample drops the event because it was not sampled
+	Sample
+	// Drop drops the event because it exceeds the caps
+	Drop
+	// Divert diverts the event to cold storage because it exceeds the caps
+	Divert
+)
+
+func (d Decision) String() string {
+	switch d {
+	case Keep:
+		return "keep"
+	case Sample:
+		return "sample"
+	case Drop:
+		return "drop"
+	case Divert:
+		return "divert"
+	default:
+		return "unknown"
+	}
+}
+
+// Validate checks the volume controls of a source
+func Validate(configs []Config) error {
+	_, err := New("", configs, nil)
+	return err
+}
+
+type policy struct {
+	sampleRate       float64
+	sampleField      transform.FieldPath
+	hasSampleField   bool
+	maxEventsPerHour uint64
+	maxBytesPerHour  uint64
+	overflow         Decision
+}
+
+func newPolicy(c *Config) (*policy, error) {
+	if c.SampleRate < 0 || c.SampleRate > 1 {
+		return nil, errors.Errorf("invalid sample rate %f", c.SampleRate)
+	}
+	p := policy{
+		sampleRate:       c.SampleRate,
+		maxEventsPerHour: c.MaxEventsPerHour,
+		maxBytesPerHour:  c.MaxBytesPerHour,
+	}
+	if c.SampleField != "" && c.SampleField != pantherlog.FieldRowIDJSON {
+		field, err := transform.ParseFieldPath(c.SampleField)
+		if err != nil {
+			return nil, err
+		}
+		p.sampleField, p.hasSampleField = field, true
+	}
+	switch c.Overflow {
+	case "", OverflowDrop:
+		p.overflow = Drop
+	case OverflowDivert:
+		p.overflow = Divert
+	default:
+		return nil, errors.Errorf("invalid overflow policy %q", c.Overflow)
+	}
+	return &p, nil
+}
+
+// Limiter decides which events of a source are stored.
+// All methods are safe to use on a nil Limiter which keeps all events.
+type Limiter struct {
+	sourceID string
+	policies map[string]*policy
+	counters *Counters
+}
+
+// New creates a limiter for the events of a source.
+// The hourly caps are tracked with counters, DefaultCounters are used if nil.
+// It returns nil if there are no volume controls.
+func New(sourceID string, configs []Config, counters *Counters) (*Limiter, error) {
+	if len(configs) == 0 {
+		return nil, nil
+	}
+	if counters == nil {
+		counters = DefaultCounters
+	}
+	policies := make(map[string]*policy, len(configs))
+	for i := range configs {
+		c := &configs[i]
+		if _, duplicate := policies[c.LogType]; duplicate {
+			return nil, errors.Errorf("duplicate sampling config for log type %q", c.LogType)
+		}
+		p, err := newPolicy(c)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "invalid sampling config for log type %q", c.LogType)
+		}
+		policies[c.LogType] = p
+	}
+	return &Limiter{
+		sourceID: sourceID,
+		policies: policies,
+		counters: counters,
+	}, nil
+}
+
+// Decide decides if an event is stored.
+// The size is the number of bytes of the log entry the event was parsed from.
+func (l *Limiter) Decide(result *pantherlog.Result, size int, now time.Time) Decision {
+	if l == nil {
+		return Keep
+	}
+	p, ok := l.policies[result.PantherLogType]
+	if !ok {
+		return Keep
+	}
+	if p.sampleRate > 0 && p.sampleRate < 1 && !p.sampled(result) {
+		return Sample
+	}
+	if p.maxEventsPerHour == 0 && p.maxBytesPerHour == 0 {
+		return Keep
+	}
+	key := counterKey{sourceID: l.sourceID, logType: result.PantherLogType}
+	if l.counters.take(key, now.Truncate(time.Hour), uint64(size), p.maxEventsPerHour, p.maxBytesPerHour) {
+		return Keep
+	}
+	return p.overflow
+}
+
+// Sync syncs the hourly counts with the other log processor instances if they are stale
+func (l *Limiter) Sync(ctx context.Context, now time.Time) error {
+	if l == nil {
+		return nil
+	}
+	return l.counters.Sync(ctx, now)
+}
+
+// Flush syncs the hourly counts with the other log processor instances
+func (l *Limiter) Flush(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	return l.counters.Flush(ctx)
+}
+
+// sampled deterministically decides if an event is sampled by hashing the value of the sample field
+func (p *policy) sampled(result *pantherlog.Result) bool {
+	value := result.PantherRowID
+	if p.hasSampleField {
+		// Events without a value for the sample field are sampled by row id
+		if v, ok, err := p.sampleField.Read(result.Event); err == nil && ok {
+			value = v
+		}
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(value))
+	return float64(h.Sum64()) < p.sampleRate*math.MaxUint64
+}
+
+// Stats are the counts of the decisions for the events of a log type
+type Stats struct {
+	LogType        string
+	EventsSampled  uint64
+	EventsDropped  uint64
+	EventsDiverted uint64
+}
+
+// Add counts a decision
+func (s *Stats) Add(d Decision) {
+	switch d {
+	case Sample:
+		s.EventsSampled++
+	case Drop:
+		s.EventsDropped++
+	case Divert:
+		s.EventsDiverted++
+	}
+}
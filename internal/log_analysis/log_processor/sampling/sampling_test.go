@@ -0,0 +1,264 @@
+package sampling
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+	"github.com/panther-labs/panther/pkg/testutils"
+)
+
+type testEvent struct {
+	User string `json:"user"`
+}
+
+func testResult(logType, rowID, user string) *pantherlog.Result {
+	return &pantherlog.Result{
+		CoreFields: pantherlog.CoreFields{
+			PantherLogType: logType,
+			PantherRowID:   rowID,
+		},
+		Event: &testEvent{User: user},
+	}
+}
+
+func TestSample(t *testing.T) {
+	assert := require.New(t)
+	limiter, err := New("source-id", []Config{
+		{LogType: "Foo", SampleRate: 0.25},
+		{LogType: "Bar", SampleRate: 0.5, SampleField: "user"},
+	}, NewCounters())
+	assert.NoError(err)
+	now := time.Now()
+
+	kept := 0
+	for i := 0; i < 10000; i++ {
+		result := testResult("Foo", strconv.Itoa(i), "")
+		decision := limiter.Decide(result, 10, now)
+		if decision == Keep {
+			kept++
+		} else {
+			assert.Equal(Sample, decision)
+		}
+		// Deterministic
+		assert.Equal(decision, limiter.Decide(result, 10, now))
+	}
+	assert.InDelta(2500, kept, 250)
+
+	// All events of a user share the same decision
+	for _, user := range []string{"alice", "bob", "carol", "dave"} {
+		decision := limiter.Decide(testResult("Bar", "1", user), 10, now)
+		for i := 2; i < 10; i++ {
+			assert.Equal(decision, limiter.Decide(testResult("Bar", strconv.Itoa(i), user), 10, now))
+		}
+	}
+
+	// Other log types are kept
+	assert.Equal(Keep, limiter.Decide(testResult("Baz", "1", ""), 10, now))
+
+	// Nil limiter keeps all events
+	limiter = nil
+	assert.Equal(Keep, limiter.Decide(testResult("Foo", "1", ""), 10, now))
+}
+
+func TestCaps(t *testing.T) {
+	assert := require.New(t)
+	counters := NewCounters()
+	limiter, err := New("source-id", []Config{
+		{LogType: "Foo", MaxEventsPerHour: 3},
+		{LogType: "Bar", MaxBytesPerHour: 100, Overflow: OverflowDivert},
+	}, counters)
+	assert.NoError(err)
+	hour := time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 3; i++ {
+		assert.Equal(Keep, limiter.Decide(testResult("Foo", "", ""), 10, hour.Add(time.Minute)))
+	}
+	assert.Equal(Drop, limiter.Decide(testResult("Foo", "", ""), 10, hour.Add(time.Minute)))
+	// Next hour resets the caps
+	assert.Equal(Keep, limiter.Decide(testResult("Foo", "", ""), 10, hour.Add(time.Hour)))
+
+	assert.Equal(Keep, limiter.Decide(testResult("Bar", "", ""), 60, hour))
+	assert.Equal(Divert, limiter.Decide(testResult("Bar", "", ""), 60, hour))
+	assert.Equal(Keep, limiter.Decide(testResult("Bar", "", ""), 40, hour))
+
+	// Caps are tracked per source
+	other, err := New("other-id", []Config{{LogType: "Foo", MaxEventsPerHour: 3}}, counters)
+	assert.NoError(err)
+	assert.Equal(Keep, other.Decide(testResult("Foo", "", ""), 10, hour.Add(time.Minute)))
+}
+
+// testStore is an in-memory Store shared by counters
+type testStore struct {
+	counts map[string][2]uint64
+	err    error
+}
+
+func (s *testStore) Add(_ context.Context, sourceID, logType string, hour time.Time, events, bytes uint64) (uint64, uint64, error) {
+	if s.err != nil {
+		return 0, 0, s.err
+	}
+	if s.counts == nil {
+		s.counts = make(map[string][2]uint64)
+	}
+	id := countsID(sourceID, logType, hour)
+	counts := s.counts[id]
+	counts[0] += events
+	counts[1] += bytes
+	s.counts[id] = counts
+	return counts[0], counts[1], nil
+}
+
+func TestSharedCaps(t *testing.T) {
+	assert := require.New(t)
+	ctx := context.Background()
+	store := &testStore{}
+	configs := []Config{{LogType: "Foo", MaxEventsPerHour: 5}}
+	counters := NewCounters()
+	counters.Store = store
+	limiter, err := New("source-id", configs, counters)
+	assert.NoError(err)
+	otherCounters := NewCounters()
+	otherCounters.Store = store
+	other, err := New("source-id", configs, otherCounters)
+	assert.NoError(err)
+	hour := time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 3; i++ {
+		assert.Equal(Keep, limiter.Decide(testResult("Foo", "", ""), 10, hour))
+	}
+	assert.NoError(limiter.Flush(ctx))
+	assert.Equal([2]uint64{3, 30}, store.counts["source-id/Foo/2020-10-01T12"])
+
+	// The other instance only sees the counts of the first after syncing
+	assert.Equal(Keep, other.Decide(testResult("Foo", "", ""), 10, hour))
+	assert.NoError(other.Sync(ctx, hour))
+	assert.Equal(Keep, other.Decide(testResult("Foo", "", ""), 10, hour))
+	assert.Equal(Drop, other.Decide(testResult("Foo", "", ""), 10, hour))
+
+	// Syncs are throttled
+	assert.NoError(limiter.Sync(ctx, hour))
+	assert.NoError(other.Sync(ctx, hour.Add(time.Second)))
+	assert.Equal(Keep, limiter.Decide(testResult("Foo", "", ""), 10, hour))
+	assert.NoError(limiter.Sync(ctx, hour.Add(DefaultSyncInterval)))
+	assert.Equal(Drop, limiter.Decide(testResult("Foo", "", ""), 10, hour))
+	// The last event of the other instance is pending until its next sync
+	assert.Equal([2]uint64{5, 50}, store.counts["source-id/Foo/2020-10-01T12"])
+
+	// Failed syncs keep the pending counts
+	store.err = errors.New("failed")
+	assert.Equal(Keep, limiter.Decide(testResult("Foo", "", ""), 10, hour.Add(time.Hour)))
+	assert.Error(limiter.Flush(ctx))
+	store.err = nil
+	assert.NoError(limiter.Flush(ctx))
+	assert.Equal([2]uint64{1, 10}, store.counts["source-id/Foo/2020-10-01T13"])
+
+	// Nil limiter has nothing to sync
+	limiter = nil
+	assert.NoError(limiter.Sync(ctx, hour))
+	assert.NoError(limiter.Flush(ctx))
+}
+
+func TestDynamoDBStore(t *testing.T) {
+	assert := require.New(t)
+	client := &testutils.DynamoDBMock{}
+	store := DynamoDBStore{Client: client, TableName: "panther-sampling-counters"}
+	hour := time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC)
+	client.On("UpdateItemWithContext", mock.Anything, mock.Anything).Return(&dynamodb.UpdateItemOutput{
+		Attributes: map[string]*dynamodb.AttributeValue{
+			"events": {N: aws.String("42")},
+			"bytes":  {N: aws.String("4200")},
+		},
+	}, nil).Run(func(args mock.Arguments) {
+		input := args.Get(1).(*dynamodb.UpdateItemInput)
+		assert.Equal("panther-sampling-counters", aws.StringValue(input.TableName))
+		assert.Equal("source-id/Foo/2020-10-01T12", aws.StringValue(input.Key["id"].S))
+		assert.Equal(dynamodb.ReturnValueUpdatedNew, aws.StringValue(input.ReturnValues))
+		assert.Contains(aws.StringValue(input.UpdateExpression), "ADD")
+	}).Once()
+	events, bytes, err := store.Add(context.Background(), "source-id", "Foo", hour, 2, 200)
+	assert.NoError(err)
+	assert.Equal(uint64(42), events)
+	assert.Equal(uint64(4200), bytes)
+
+	client.On("UpdateItemWithContext", mock.Anything, mock.Anything).Return(&dynamodb.UpdateItemOutput{}, errors.New("failed")).Once()
+	_, _, err = store.Add(context.Background(), "source-id", "Foo", hour, 2, 200)
+	assert.Error(err)
+	client.AssertExpectations(t)
+}
+
+func TestValidate(t *testing.T) {
+	assert := require.New(t)
+	assert.NoError(Validate(nil))
+	assert.NoError(Validate([]Config{{LogType: "Foo", SampleRate: 0.1, SampleField: "p_row_id"}}))
+	assert.Error(Validate([]Config{{LogType: "Foo", SampleRate: 2}}))
+	assert.Error(Validate([]Config{{LogType: "Foo", SampleField: "foo..bar"}}))
+	assert.Error(Validate([]Config{{LogType: "Foo", Overflow: "archive"}}))
+	assert.Error(Validate([]Config{{LogType: "Foo"}, {LogType: "Foo"}}))
+}
+
+func TestColdWriter(t *testing.T) {
+	assert := require.New(t)
+	tm := time.Date(2020, 10, 1, 12, 30, 15, 0, time.UTC)
+	uploader := &testutils.S3UploaderMock{}
+	w := NewColdWriter(uploader, "bucket", "source-id", jsoniter.ConfigCompatibleWithStandardLibrary)
+	w.Now = func() time.Time { return tm }
+
+	assert.NoError(w.Write(testResult("Foo", "1", "alice")))
+	assert.NoError(w.Write(testResult("Foo", "2", "bob")))
+	assert.Equal(2, w.NumEvents())
+
+	var body []byte
+	uploader.On("Upload", mock.Anything, mock.Anything).Return(&s3manager.UploadOutput{}, nil).Run(func(args mock.Arguments) {
+		input := args.Get(0).(*s3manager.UploadInput)
+		assert.Equal("bucket", aws.StringValue(input.Bucket))
+		assert.True(strings.HasPrefix(aws.StringValue(input.Key), "cold/source-id/Foo/2020/10/01/12/20201001T123015Z-"))
+		data, err := ioutil.ReadAll(input.Body)
+		assert.NoError(err)
+		body = data
+	}).Once()
+	assert.NoError(w.Flush())
+	uploader.AssertExpectations(t)
+	assert.Equal(0, w.NumEvents())
+
+	gz, err := gzip.NewReader(bytes.NewReader(body))
+	assert.NoError(err)
+	data, err := ioutil.ReadAll(gz)
+	assert.NoError(err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Len(lines, 2)
+	assert.Equal("bob", jsoniter.Get([]byte(lines[1]), "user").ToString())
+	assert.Equal("Foo", jsoniter.Get([]byte(lines[1]), "p_log_type").ToString())
+}
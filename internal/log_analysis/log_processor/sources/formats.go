@@ -0,0 +1,426 @@
+package sources
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"mime"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/logstream"
+)
+
+// Formats of S3 objects detected by sniffing their contents
+const (
+	formatPlain  = "plain"
+	formatGzip   = "gzip"
+	formatZstd   = "zstd"
+	formatBzip2  = "bzip2"
+	formatSnappy = "snappy"
+	// Raw snappy blocks have no magic bytes, they can only be detected by the object headers
+	formatSnappyRaw = "snappy-raw"
+	formatZip       = "zip"
+)
+
+// Formats of binary record files that are converted to JSON log entries
+const (
+	formatParquet = "parquet"
+	formatAvro    = "avro"
+)
+
+// formatCloudWatchLogs is the format of log events wrapped in CloudWatch Logs subscription envelopes.
+// Kinesis Firehose delivers the envelopes of a subscription to S3 without unwrapping them.
+const formatCloudWatchLogs = "cloudwatch-logs"
+
+const (
+	// MaxBufferedObjectSize is the max size of objects that need to be read in memory before decoding (zip, raw snappy, parquet)
+	MaxBufferedObjectSize = 256 * 1024 * 1024
+
+	// sniffSize is the number of bytes needed to detect all formats by their magic bytes
+	sniffSize = 10
+)
+
+var (
+	magicGzip     = []byte{0x1f, 0x8b}
+	magicZstd     = []byte{0x28, 0xb5, 0x2f, 0xfd}
+	magicSnappy   = []byte("\xff\x06\x00\x00sNaPpY")
+	magicZip      = []byte("PK\x03\x04")
+	magicZipEmpty = []byte("PK\x05\x06")
+	magicBzip2    = []byte("BZh")
+	// bzip2 streams start with either a block header or an end of stream marker (if empty) after the 4 byte header
+	magicBzip2Block = []byte{0x31, 0x41, 0x59, 0x26, 0x53, 0x59}
+	magicBzip2EOS   = []byte{0x17, 0x72, 0x45, 0x38, 0x50, 0x90}
+)
+
+// detectFormat detects the format of an object using the first bytes of its contents.
+// If no magic bytes are found it falls back to the Content-Encoding and Content-Type headers of the object.
+func detectFormat(head []byte, contentEncoding, contentType string) string {
+	switch {
+	case bytes.HasPrefix(head, magicGzip):
+		return formatGzip
+	case bytes.HasPrefix(head, magicZstd):
+		return formatZstd
+	case bytes.HasPrefix(head, magicSnappy):
+		return formatSnappy
+	case bytes.HasPrefix(head, magicZip), bytes.HasPrefix(head, magicZipEmpty):
+		return formatZip
+	case isBzip2(head):
+		return formatBzip2
+	}
+	// All other formats have magic bytes so the only format we can trust the headers for is raw snappy.
+	// This avoids decoding twice objects with 'Content-Encoding: gzip' that s3pipe already uncompressed.
+	for _, enc := range strings.Split(contentEncoding, ",") {
+		switch strings.ToLower(strings.TrimSpace(enc)) {
+		case "snappy", "x-snappy":
+			return formatSnappyRaw
+		}
+	}
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil && mediaType == "application/x-snappy" {
+		return formatSnappyRaw
+	}
+	return formatPlain
+}
+
+func isBzip2(head []byte) bool {
+	if len(head) < sniffSize || !bytes.HasPrefix(head, magicBzip2) {
+		return false
+	}
+	// Block size is '1'-'9' for 100k-900k
+	if level := head[3]; level < '1' || '9' < level {
+		return false
+	}
+	magic := head[4:sniffSize]
+	return bytes.Equal(magic, magicBzip2Block) || bytes.Equal(magic, magicBzip2EOS)
+}
+
+// objectMember is a log file inside an S3 object.
+// Zip archives have a member for each file they contain, all other formats have a single member.
+type objectMember struct {
+	// Key is the S3 object key, or the archive key joined with the file path for archive members
+	Key    string
+	Reader io.Reader
+	// Closer releases all resources used by the member
+	Closer io.ReadCloser
+	// Format is the record format of the decoded contents (parquet, avro) or empty for text logs.
+	// Parquet members are read in memory and their Reader is a *bytes.Reader.
+	Format string
+	// Open decodes a zip archive member and returns it with its Reader set.
+	// Archive members are opened lazily so that only the member being processed holds decoder buffers.
+	// It is nil for members that are already open.
+	Open func() (*objectMember, error)
+}
+
+// readObject detects the format of an object and returns readers for the decoded contents of its members.
+// If the object is not a zip archive the returned member takes ownership of the object reader.
+// Zip archive members are returned unopened, see objectMember.Open.
+func readObject(obj io.ReadCloser, key string, headers *objectHeaders) ([]*objectMember, error) {
+	// Sniffing blocks until the download starts and reports download errors (e.g. access denied) early.
+	r, head, err := sniff(obj, sniffSize)
+	if err != nil {
+		_ = obj.Close()
+		return nil, err
+	}
+	format := detectFormat(head, headers.ContentEncoding(), headers.ContentType())
+	if format != formatZip {
+		member, err := newObjectMember(key, r, obj, format)
+		if err != nil {
+			_ = obj.Close()
+			return nil, err
+		}
+		return []*objectMember{member}, nil
+	}
+	// Zip archives need random access to read the central directory at the end of the file
+	data, err := readAllMax(r, MaxBufferedObjectSize)
+	// We are done with the download, the archive is in memory
+	if closeErr := obj.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read zip archive")
+	}
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read zip archive")
+	}
+	var members []*objectMember
+	for _, f := range archive.File {
+		// Skip directory entries and empty files, they do not contain any logs
+		if f.FileInfo().IsDir() || f.UncompressedSize64 == 0 {
+			continue
+		}
+		f, memberKey := f, zipMemberKey(key, f.Name)
+		members = append(members, &objectMember{
+			Key: memberKey,
+			Open: func() (*objectMember, error) {
+				return openZipMember(memberKey, f)
+			},
+		})
+	}
+	return members, nil
+}
+
+// ObjectStream is the log stream of a file in an S3 object
+type ObjectStream struct {
+	// Key is the S3 object key, or the archive key joined with the file path for archive members
+	Key string
+	logstream.Stream
+	io.Closer
+}
+
+// OpenObject detects the format of an object and returns a log stream for each file it contains.
+// It supports the same compression, archive and record formats as the log processor.
+// Zip archive members are opened on the first call to Next. Callers must close all returned streams.
+func OpenObject(obj io.ReadCloser, key, contentEncoding, contentType string) ([]*ObjectStream, error) {
+	headers := objectHeaders{
+		contentEncoding: contentEncoding,
+		contentType:     contentType,
+	}
+	members, err := readObject(obj, key, &headers)
+	if err != nil {
+		return nil, err
+	}
+	streams := make([]*ObjectStream, 0, len(members))
+	for _, member := range members {
+		if member.Open != nil {
+			s := newMemberStream(member, newObjectStream)
+			streams = append(streams, &ObjectStream{Key: member.Key, Stream: s, Closer: s})
+			continue
+		}
+		streams = append(streams, &ObjectStream{Key: member.Key, Stream: newObjectStream(member), Closer: member.Closer})
+	}
+	return streams, nil
+}
+
+// newObjectStream builds the log stream of a member without the source specific stream formats (ie CloudTrail)
+func newObjectStream(member *objectMember) logstream.Stream {
+	if stream := newRecordStream(member); stream != nil {
+		return stream
+	}
+	return logstream.NewLineStream(member.Reader, DownloadMinPartSize)
+}
+
+func openZipMember(key string, f *zip.File) (*objectMember, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open zip archive member %q", f.Name)
+	}
+	r, head, err := sniff(rc, sniffSize)
+	if err != nil {
+		_ = rc.Close()
+		return nil, errors.Wrapf(err, "failed to read zip archive member %q", f.Name)
+	}
+	format := detectFormat(head, "", "")
+	if format == formatZip {
+		_ = rc.Close()
+		return nil, errors.Errorf("nested zip archive member %q is not supported", f.Name)
+	}
+	member, err := newObjectMember(key, r, rc, format)
+	if err != nil {
+		_ = rc.Close()
+		return nil, errors.Wrapf(err, "failed to read zip archive member %q", f.Name)
+	}
+	return member, nil
+}
+
+// zipMemberKey builds a key for a file inside a zip archive.
+// The key is prefixed with the archive key so that S3 prefix mappings also apply to the archive members.
+func zipMemberKey(key, name string) string {
+	return strings.TrimSuffix(key, "/") + "/" + strings.TrimPrefix(name, "/")
+}
+
+func newObjectMember(key string, r io.Reader, closer io.ReadCloser, format string) (*objectMember, error) {
+	member, err := decodeObjectMember(key, r, closer, format)
+	if err != nil {
+		return nil, err
+	}
+	if err := detectRecordFormat(member); err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+// detectRecordFormat checks the decoded contents of a member for binary record formats and CloudWatch Logs envelopes.
+// Record formats can be compressed with any of the supported codecs so detection happens after decoding.
+func detectRecordFormat(member *objectMember) error {
+	// Decoding errors are reported by the log stream when the member is read
+	r, head, _ := sniff(member.Reader, len(logstream.CloudWatchLogsMagic))
+	member.Reader = r
+	switch {
+	case bytes.HasPrefix(head, logstream.CloudWatchLogsMagic):
+		member.Format = formatCloudWatchLogs
+	case bytes.HasPrefix(head, logstream.AvroMagic):
+		member.Format = formatAvro
+	case bytes.HasPrefix(head, logstream.ParquetMagic):
+		// Parquet files need random access to read the footer at the end of the file
+		data, err := readAllMax(r, MaxBufferedObjectSize)
+		if err != nil {
+			return errors.Wrap(err, "failed to read parquet file")
+		}
+		member.Reader = bytes.NewReader(data)
+		// Text logs can start with the same bytes, parquet files also end with the magic bytes
+		if len(data) > 2*len(logstream.ParquetMagic) && bytes.HasSuffix(data, logstream.ParquetMagic) {
+			member.Format = formatParquet
+		}
+	}
+	return nil
+}
+
+func decodeObjectMember(key string, r io.Reader, closer io.ReadCloser, format string) (*objectMember, error) {
+	switch format {
+	case formatGzip:
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read gzip stream")
+		}
+		return &objectMember{
+			Key:    key,
+			Reader: gz,
+			Closer: &decoderCloser{ReadCloser: closer, decoder: gz},
+		}, nil
+	case formatZstd:
+		// Use a single goroutine and keep memory low, the processor reads one stream at a time.
+		dec, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1), zstd.WithDecoderLowmem(true))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read zstd stream")
+		}
+		rc := dec.IOReadCloser()
+		return &objectMember{
+			Key:    key,
+			Reader: rc,
+			Closer: &decoderCloser{ReadCloser: closer, decoder: rc},
+		}, nil
+	case formatBzip2:
+		return &objectMember{
+			Key:    key,
+			Reader: bzip2.NewReader(r),
+			Closer: closer,
+		}, nil
+	case formatSnappy:
+		return &objectMember{
+			Key:    key,
+			Reader: snappy.NewReader(r),
+			Closer: closer,
+		}, nil
+	case formatSnappyRaw:
+		block, err := readAllMax(r, MaxBufferedObjectSize)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read snappy block")
+		}
+		data, err := snappy.Decode(nil, block)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode snappy block")
+		}
+		return &objectMember{
+			Key:    key,
+			Reader: bytes.NewReader(data),
+			Closer: closer,
+		}, nil
+	default:
+		return &objectMember{
+			Key:    key,
+			Reader: r,
+			Closer: closer,
+		}, nil
+	}
+}
+
+// sniff reads the first n bytes of r and returns a reader that replays them before the rest of the contents.
+// The head is shorter than n bytes if r has less data.
+func sniff(r io.Reader, n int) (io.Reader, []byte, error) {
+	head := make([]byte, n)
+	k, err := io.ReadFull(r, head)
+	head = head[:k]
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		err = nil
+	}
+	return io.MultiReader(bytes.NewReader(head), r), head, err
+}
+
+// readAllMax reads all data from r failing if more than max bytes are read
+func readAllMax(r io.Reader, max int64) ([]byte, error) {
+	data, err := ioutil.ReadAll(io.LimitReader(r, max+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > max {
+		return nil, errors.Errorf("object size exceeds %d bytes", max)
+	}
+	return data, nil
+}
+
+// decoderCloser closes a decoder along with the underlying reader
+type decoderCloser struct {
+	io.ReadCloser
+	decoder io.Closer
+}
+
+// Close implements io.Closer
+func (c *decoderCloser) Close() error {
+	return multierr.Append(c.decoder.Close(), c.ReadCloser.Close())
+}
+
+// objectHeaders records the content headers of an S3 object while it is being downloaded.
+type objectHeaders struct {
+	once            sync.Once
+	contentEncoding string
+	contentType     string
+}
+
+// RequestOption is a request.Option that records the headers from the first GetObject response.
+// Headers are recorded before the response body is read, so they are available once the first bytes are read.
+func (h *objectHeaders) RequestOption(r *request.Request) {
+	r.Handlers.Complete.PushBack(func(r *request.Request) {
+		out, ok := r.Data.(*s3.GetObjectOutput)
+		if !ok || r.Error != nil {
+			return
+		}
+		h.once.Do(func() {
+			h.contentEncoding = aws.StringValue(out.ContentEncoding)
+			h.contentType = aws.StringValue(out.ContentType)
+		})
+	})
+}
+
+func (h *objectHeaders) ContentEncoding() string {
+	if h == nil {
+		return ""
+	}
+	return h.contentEncoding
+}
+
+func (h *objectHeaders) ContentType() string {
+	if h == nil {
+		return ""
+	}
+	return h.contentType
+}
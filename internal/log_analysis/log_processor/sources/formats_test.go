@@ -0,0 +1,189 @@
+package sources
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"io/ioutil"
+	"testing"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/require"
+)
+
+const testLogData = "foo bar baz\nfoo bar baz\n"
+
+// Generated with `printf 'foo bar baz\nfoo bar baz\n' | bzip2 | base64`
+const testBzip2Data = "QlpoOTFBWSZTWd/Cq9IAAAfRgAAQQAAxAJAQIAAxDAgKqaMakajTDFoh8XckU4UJDfwqvSA="
+
+func TestReadObject(t *testing.T) {
+	type testCase struct {
+		Name     string
+		Data     []byte
+		Encoding string
+		Format   string
+	}
+	for _, tc := range []testCase{
+		{"plain", []byte(testLogData), "", formatPlain},
+		{"gzip", gzipData(t, testLogData), "", formatGzip},
+		{"zstd", zstdData(t, testLogData), "", formatZstd},
+		{"bzip2", bzip2Data(t), "", formatBzip2},
+		{"snappy framed", snappyFramedData(t, testLogData), "", formatSnappy},
+		{
+			Name:     "snappy raw",
+			Data:     snappy.Encode(nil, []byte(testLogData)),
+			Encoding: "snappy",
+			Format:   formatSnappyRaw,
+		},
+		{
+			Name:     "gzip header on plain data",
+			Data:     []byte(testLogData),
+			Encoding: "gzip",
+			Format:   formatPlain,
+		},
+	} {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			assert := require.New(t)
+			head := tc.Data
+			if len(head) > sniffSize {
+				head = head[:sniffSize]
+			}
+			assert.Equal(tc.Format, detectFormat(head, tc.Encoding, ""))
+			headers := &objectHeaders{contentEncoding: tc.Encoding}
+			members, err := readObject(ioutil.NopCloser(bytes.NewReader(tc.Data)), "logs/key", headers)
+			assert.NoError(err)
+			assert.Len(members, 1)
+			assert.Equal("logs/key", members[0].Key)
+			data, err := ioutil.ReadAll(members[0].Reader)
+			assert.NoError(err)
+			assert.Equal(testLogData, string(data))
+			assert.NoError(members[0].Closer.Close())
+		})
+	}
+}
+
+func TestReadObjectZip(t *testing.T) {
+	assert := require.New(t)
+	buf := bytes.Buffer{}
+	w := zip.NewWriter(&buf)
+	addFile := func(name string, data []byte) {
+		f, err := w.Create(name)
+		assert.NoError(err)
+		_, err = f.Write(data)
+		assert.NoError(err)
+	}
+	addFile("app/", nil)
+	addFile("app/one.log", []byte(testLogData))
+	addFile("app/empty.log", nil)
+	addFile("app/two.log.zst", zstdData(t, "foo\n"))
+	assert.NoError(w.Close())
+
+	assert.Equal(formatZip, detectFormat(buf.Bytes()[:sniffSize], "", ""))
+	members, err := readObject(ioutil.NopCloser(&buf), "vendor/bundle.zip", nil)
+	assert.NoError(err)
+	assert.Len(members, 2)
+	expect := map[string]string{
+		"vendor/bundle.zip/app/one.log":     testLogData,
+		"vendor/bundle.zip/app/two.log.zst": "foo\n",
+	}
+	for _, m := range members {
+		// Members are opened lazily
+		assert.Nil(m.Reader)
+		assert.NotNil(m.Open)
+		opened, err := m.Open()
+		assert.NoError(err)
+		assert.Equal(m.Key, opened.Key)
+		data, err := ioutil.ReadAll(opened.Reader)
+		assert.NoError(err)
+		assert.Equal(expect[m.Key], string(data), m.Key)
+		assert.NoError(opened.Closer.Close())
+	}
+}
+
+func TestReadObjectRecords(t *testing.T) {
+	assert := require.New(t)
+	parquetData, err := ioutil.ReadFile("../processor/logstream/testdata/sample.parquet")
+	assert.NoError(err)
+	avroData := append([]byte("Obj\x01"), "foo bar"...)
+	for _, tc := range []struct {
+		Name   string
+		Data   []byte
+		Format string
+	}{
+		{"parquet", parquetData, formatParquet},
+		{"parquet zstd", zstdData(t, string(parquetData)), formatParquet},
+		{"avro", avroData, formatAvro},
+		{"avro gzip", gzipData(t, string(avroData)), formatAvro},
+		{"cloudwatch logs gzip", gzipData(t, `{"messageType":"DATA_MESSAGE","logEvents":[]}`), formatCloudWatchLogs},
+		{"text with parquet magic", []byte("PAR1 foo bar baz\n"), ""},
+		{"text", []byte(testLogData), ""},
+	} {
+		members, err := readObject(ioutil.NopCloser(bytes.NewReader(tc.Data)), "logs/key", nil)
+		assert.NoError(err, tc.Name)
+		assert.Len(members, 1, tc.Name)
+		assert.Equal(tc.Format, members[0].Format, tc.Name)
+		if tc.Format == formatParquet {
+			assert.IsType(&bytes.Reader{}, members[0].Reader, tc.Name)
+		}
+	}
+}
+
+func TestReadAllMax(t *testing.T) {
+	assert := require.New(t)
+	data, err := readAllMax(bytes.NewReader([]byte("foo")), 3)
+	assert.NoError(err)
+	assert.Equal("foo", string(data))
+	_, err = readAllMax(bytes.NewReader([]byte("foo")), 2)
+	assert.Error(err)
+}
+
+func gzipData(t *testing.T, data string) []byte {
+	buf := bytes.Buffer{}
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write([]byte(data))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func zstdData(t *testing.T, data string) []byte {
+	w, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+	return w.EncodeAll([]byte(data), nil)
+}
+
+func snappyFramedData(t *testing.T, data string) []byte {
+	buf := bytes.Buffer{}
+	w := snappy.NewBufferedWriter(&buf)
+	_, err := w.Write([]byte(data))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func bzip2Data(t *testing.T) []byte {
+	data, err := base64.StdEncoding.DecodeString(testBzip2Data)
+	require.NoError(t, err)
+	return data
+}
@@ -19,7 +19,9 @@ package sources
  */
 
 import (
+	"bytes"
 	"context"
+	"io"
 	"net/url"
 	"path"
 	"regexp"
@@ -28,17 +30,23 @@ import (
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/aws/aws-sdk-go/service/sns"
 	jsoniter "github.com/json-iterator/go"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/panther-labs/panther/api/lambda/source/models"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
+	logmetrics "github.com/panther-labs/panther/internal/log_analysis/log_processor/metrics"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/logstream"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/s3pipe"
+	"github.com/panther-labs/panther/pkg/awsutils"
+	"github.com/panther-labs/panther/pkg/metrics"
 	"github.com/panther-labs/panther/pkg/stringset"
 )
 
@@ -46,6 +54,10 @@ const (
 	DownloadMaxPartSize = 50 * 1024 * 1024                  // the max size of in memory buffers will be 3X as this due to multiple buffers
 	DownloadMinPartSize = s3manager.DefaultDownloadPartSize // the min part size for efficiency
 
+	// maxConcurrentObjects is the max number of objects of a notification whose streams are built concurrently.
+	// It bounds the memory used for objects that are read in memory before decoding (zip, raw snappy, parquet).
+	maxConcurrentObjects = 4
+
 	s3TestEvent                 = "s3:TestEvent"
 	cloudTrailValidationMessage = "CloudTrail validation message."
 )
@@ -106,20 +118,53 @@ func handleNotificationMessage(ctx context.Context, notification *SnsNotificatio
 	if err != nil {
 		return nil, err
 	}
-	for _, s3Object := range s3Objects {
-		if shouldIgnoreS3Object(s3Object) {
+	// Sources and clients are resolved in sequence since the caches are not safe for concurrent use
+	var objects []*s3Object
+	for _, info := range s3Objects {
+		if shouldIgnoreS3Object(info) {
 			continue
 		}
-		var dataStream *common.DataStream
-		dataStream, err = buildStream(ctx, s3Object)
+		obj, err := resolveS3Object(info)
 		if err != nil {
-			return
+			return nil, err
+		}
+		if obj == nil {
+			continue
 		}
-		if dataStream != nil {
-			result = append(result, dataStream)
+		objects = append(objects, obj)
+	}
+	// Building the streams blocks until the first bytes of each object are downloaded, so objects are read concurrently.
+	// The downloads use the parent context, they need to outlive this function.
+	streams := make([][]*common.DataStream, len(objects))
+	grp := errgroup.Group{}
+	sem := make(chan struct{}, maxConcurrentObjects)
+	for i, obj := range objects {
+		i, obj := i, obj
+		grp.Go(func() error {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			s, err := buildStreams(ctx, obj)
+			streams[i] = s
+			return err
+		})
+	}
+	err = grp.Wait()
+	for _, s := range streams {
+		result = append(result, s...)
+	}
+	if err != nil {
+		closeStreams(result)
+		return nil, err
+	}
+	return result, nil
+}
+
+func closeStreams(streams []*common.DataStream) {
+	for _, s := range streams {
+		if s.Closer != nil {
+			_ = s.Closer.Close()
 		}
 	}
-	return result, err
 }
 
 func shouldIgnoreS3Object(s3Object *S3ObjectInfo) bool {
@@ -130,8 +175,17 @@ func shouldIgnoreS3Object(s3Object *S3ObjectInfo) bool {
 	return s3Object.S3ObjectSize == 0 || strings.HasSuffix(s3Object.S3ObjectKey, "/")
 }
 
-func buildStream(ctx context.Context, s3Object *S3ObjectInfo) (*common.DataStream, error) {
-	key, bucket := s3Object.S3ObjectKey, s3Object.S3Bucket
+// s3Object is an S3 object of a configured source
+type s3Object struct {
+	*S3ObjectInfo
+	client s3iface.S3API
+	src    *models.SourceIntegration
+}
+
+// resolveS3Object resolves the source and the S3 client for an S3 object.
+// It returns nil if no source is configured for the object.
+func resolveS3Object(info *S3ObjectInfo) (*s3Object, error) {
+	key, bucket := info.S3ObjectKey, info.S3Bucket
 	s3Client, src, err := getS3Client(bucket, key)
 	if err != nil {
 		err = errors.Wrapf(err, "failed to get S3 client for s3://%s/%s", bucket, key)
@@ -143,38 +197,174 @@ func buildStream(ctx context.Context, s3Object *S3ObjectInfo) (*common.DataStrea
 			zap.String("key", key))
 		return nil, nil
 	}
+	return &s3Object{
+		S3ObjectInfo: info,
+		client:       s3Client,
+		src:          src,
+	}, nil
+}
 
+// buildStreams builds the data streams for an S3 object.
+// Compressed objects are transparently uncompressed and each file in a zip archive becomes a separate stream.
+func buildStreams(ctx context.Context, obj *s3Object) ([]*common.DataStream, error) {
+	key, bucket, src := obj.S3ObjectKey, obj.S3Bucket, obj.src
+	headers := objectHeaders{}
+	r := downloadObjectFunc(ctx, obj, headers.RequestOption)
+	// Detect other compression formats and archives by sniffing the object contents
+	members, err := readObject(r, key, &headers)
+	// Count downloads per S3 object, zip archives are downloaded once for all their members
+	logmetrics.GetObject.With(
+		metrics.SourceIDDimension, src.IntegrationID,
+		metrics.StatusDimension, statusFromErr(err),
+	).Add(1)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to read s3://%s/%s", bucket, key)
+		return nil, err
+	}
+	streams := make([]*common.DataStream, 0, len(members))
+	for _, member := range members {
+		var (
+			stream logstream.Stream
+			closer io.Closer
+		)
+		if member.Open != nil {
+			s := newMemberStream(member, func(m *objectMember) logstream.Stream {
+				return newLogStream(src, bucket, m)
+			})
+			stream, closer = s, s
+		} else {
+			stream, closer = newLogStream(src, bucket, member), member.Closer
+		}
+		streams = append(streams, &common.DataStream{
+			Stream:       stream,
+			Closer:       closer,
+			Source:       src,
+			S3Bucket:     bucket,
+			S3ObjectKey:  member.Key,
+			S3ObjectSize: obj.S3ObjectSize,
+		})
+	}
+	return streams, nil
+}
+
+// downloadObjectFunc is replaced in tests
+var downloadObjectFunc = downloadObject
+
+func downloadObject(ctx context.Context, obj *s3Object, options ...request.Option) io.ReadCloser {
 	downloader := s3pipe.Downloader{
-		S3:       s3Client,
-		PartSize: calculatePartSize(s3Object.S3ObjectSize),
+		S3:             obj.client,
+		PartSize:       calculatePartSize(obj.S3ObjectSize),
+		RequestOptions: options,
 	}
 	// gzip streams are transparently uncompressed
-	r := downloader.Download(ctx, &s3.GetObjectInput{
-		Bucket: &bucket,
-		Key:    &key,
+	return downloader.Download(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(obj.S3Bucket),
+		Key:    aws.String(obj.S3ObjectKey),
 	})
-	var stream logstream.Stream
+}
+
+// statusFromErr returns the correct Status dimension from the provided error
+func statusFromErr(err error) string {
+	if err == nil {
+		return logmetrics.StatusOK
+	}
+	if awsutils.IsAnyError(err, "AccessDenied") {
+		return logmetrics.StatusAuthErr
+	}
+	return logmetrics.StatusErr
+}
+
+func newLogStream(src *models.SourceIntegration, bucket string, member *objectMember) logstream.Stream {
+	key, r := member.Key, member.Reader
+	if member.Format == formatCloudWatchLogs {
+		zap.L().Debug("detected CloudWatch Logs envelopes", zap.String("bucket", bucket), zap.String("key", key))
+	}
+	if stream := newRecordStream(member); stream != nil {
+		return stream
+	}
 	switch src.IntegrationType {
 	case models.IntegrationTypeAWS3:
 		if isCloudTrailLog(key) && stringset.Contains(src.RequiredLogTypes(), "AWS.CloudTrail") {
 			zap.L().Debug("detected CloudTrail logs", zap.String("bucket", bucket), zap.String("key", key))
-			stream = logstream.NewJSONArrayStream(r, DownloadMinPartSize, "Records")
-		} else {
-			stream = logstream.NewLineStream(r, DownloadMinPartSize)
+			return logstream.NewJSONArrayStream(r, DownloadMinPartSize, "Records")
 		}
+		return logstream.NewLineStream(r, DownloadMinPartSize)
 	default:
 		// Set the buffer size to something big to avoid multiple fill() calls if possible
-		stream = logstream.NewLineStream(r, DownloadMinPartSize)
+		return logstream.NewLineStream(r, DownloadMinPartSize)
 	}
+}
 
-	return &common.DataStream{
-		Stream:       stream,
-		Closer:       r,
-		Source:       src,
-		S3Bucket:     s3Object.S3Bucket,
-		S3ObjectKey:  s3Object.S3ObjectKey,
-		S3ObjectSize: s3Object.S3ObjectSize,
-	}, nil
+// newRecordStream converts the records of binary formats and CloudWatch Logs envelopes to JSON log entries.
+// It returns nil for text logs.
+func newRecordStream(member *objectMember) logstream.Stream {
+	r := member.Reader
+	switch member.Format {
+	case formatParquet:
+		if file, ok := r.(*bytes.Reader); ok {
+			return logstream.NewParquetStream(file, file.Size())
+		}
+	case formatAvro:
+		return logstream.NewAvroStream(r, DownloadMinPartSize)
+	case formatCloudWatchLogs:
+		return logstream.NewCloudWatchLogsStream(r, DownloadMinPartSize)
+	}
+	return nil
+}
+
+// memberStream opens a zip archive member and builds its log stream on the first call to Next.
+// Data streams are processed serially so only one member of an archive is decoded at any time.
+type memberStream struct {
+	open      func() (*objectMember, error)
+	newStream func(member *objectMember) logstream.Stream
+	member    *objectMember
+	stream    logstream.Stream
+	err       error
+}
+
+func newMemberStream(member *objectMember, newStream func(member *objectMember) logstream.Stream) *memberStream {
+	return &memberStream{
+		open:      member.Open,
+		newStream: newStream,
+	}
+}
+
+// Next implements logstream.Stream
+func (s *memberStream) Next() []byte {
+	if s.stream == nil {
+		if s.open == nil {
+			return nil
+		}
+		member, err := s.open()
+		s.open = nil
+		if err != nil {
+			s.err = err
+			return nil
+		}
+		s.member = member
+		s.stream = s.newStream(member)
+	}
+	return s.stream.Next()
+}
+
+// Err implements logstream.Stream
+func (s *memberStream) Err() error {
+	if s.err != nil {
+		return s.err
+	}
+	if s.stream != nil {
+		return s.stream.Err()
+	}
+	return nil
+}
+
+// Close implements io.Closer
+func (s *memberStream) Close() error {
+	s.open = nil
+	if s.member != nil {
+		return s.member.Closer.Close()
+	}
+	return nil
 }
 
 func calculatePartSize(size int64) int64 {
@@ -19,13 +19,18 @@ package sources
  */
 
 import (
+	"archive/zip"
 	"bytes"
 	"context"
+	"errors"
+	"io"
 	"io/ioutil"
+	"strings"
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/lambda"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
@@ -35,6 +40,9 @@ import (
 
 	"github.com/panther-labs/panther/api/lambda/source/models"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
+	logmetrics "github.com/panther-labs/panther/internal/log_analysis/log_processor/metrics"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/logstream"
+	pkgmetrics "github.com/panther-labs/panther/pkg/metrics"
 	"github.com/panther-labs/panther/pkg/testutils"
 )
 
@@ -262,3 +270,115 @@ func TestIsCloudTrailLog(t *testing.T) {
 	require.True(t, isCloudTrailLog("AWSLogs/342363560528/CloudTrail/eu-west-1/2020/12/17/342363560528_CloudTrail_eu-west-1_20201217T1535Z_ZUnDvAcFwNysSIsp.json.gz"))
 	require.True(t, isCloudTrailLog("AWSLogs/342363560528/CloudTrail/eu-west-1/2020/12/17/342363560528_CloudTrail_us-west-2-lax-1a_20201217T1535Z_ZUnDvAcFwNysSIsp.json.gz"))
 }
+
+func TestNewLogStreamCloudWatchLogs(t *testing.T) {
+	src := &models.SourceIntegration{
+		SourceIntegrationMetadata: models.SourceIntegrationMetadata{
+			IntegrationType: models.IntegrationTypeAWS3,
+			S3PrefixLogTypes: models.S3PrefixLogtypes{
+				{S3Prefix: "firehose/", LogTypes: []string{"AWS.VPCFlow"}},
+				{S3Prefix: "eks/", LogTypes: []string{"Kubernetes.Audit"}},
+			},
+		},
+	}
+	member := &objectMember{
+		Key:    "firehose/2020/10/01/12/logs-1-2020-10-01-12-00-00-0123",
+		Reader: strings.NewReader(`{"messageType":"DATA_MESSAGE","logEvents":[]}`),
+		Format: formatCloudWatchLogs,
+	}
+	require.IsType(t, &logstream.CloudWatchLogsStream{}, newLogStream(src, "bucket", member))
+	// The envelopes are unwrapped for all log types
+	member.Key = "eks/2020/10/01/12/logs-1-2020-10-01-12-00-00-0123"
+	require.IsType(t, &logstream.CloudWatchLogsStream{}, newLogStream(src, "bucket", member))
+}
+
+func TestBuildStreamsZip(t *testing.T) {
+	assert := require.New(t)
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, name := range []string{"one.log", "two.log"} {
+		f, err := w.Create(name)
+		assert.NoError(err)
+		_, err = f.Write([]byte(testLogData))
+		assert.NoError(err)
+	}
+	assert.NoError(w.Close())
+	defer func() { downloadObjectFunc = downloadObject }()
+	downloadObjectFunc = func(_ context.Context, _ *s3Object, _ ...request.Option) io.ReadCloser {
+		return ioutil.NopCloser(bytes.NewReader(buf.Bytes()))
+	}
+	getObjectMock := &testutils.CounterMock{}
+	defer func(c pkgmetrics.Counter) { logmetrics.GetObject = c }(logmetrics.GetObject)
+	logmetrics.GetObject = getObjectMock
+	getObjectMock.On("With", []string{
+		pkgmetrics.SourceIDDimension, "source-id",
+		pkgmetrics.StatusDimension, logmetrics.StatusOK,
+	}).Return(getObjectMock).Once()
+	// The archive is downloaded once for all its members
+	getObjectMock.On("Add", 1.0).Once()
+
+	streams, err := buildStreams(context.Background(), &s3Object{
+		S3ObjectInfo: &S3ObjectInfo{
+			S3Bucket:     "bucket",
+			S3ObjectKey:  "logs/bundle.zip",
+			S3ObjectSize: int64(buf.Len()),
+		},
+		src: &models.SourceIntegration{
+			SourceIntegrationMetadata: models.SourceIntegrationMetadata{
+				IntegrationID:   "source-id",
+				IntegrationType: models.IntegrationTypeAWS3,
+			},
+		},
+	})
+	assert.NoError(err)
+	assert.Len(streams, 2)
+	assert.Equal("logs/bundle.zip/one.log", streams[0].S3ObjectKey)
+	assert.Equal("logs/bundle.zip/two.log", streams[1].S3ObjectKey)
+	getObjectMock.AssertExpectations(t)
+	for _, s := range streams {
+		// Members are opened on the first call to Next
+		assert.Nil(s.Stream.(*memberStream).stream)
+		var lines []string
+		for line := s.Stream.Next(); line != nil; line = s.Stream.Next() {
+			lines = append(lines, string(line))
+		}
+		assert.NoError(s.Stream.Err())
+		assert.Equal(strings.Split(strings.TrimSuffix(testLogData, "\n"), "\n"), lines, s.S3ObjectKey)
+		assert.NoError(s.Closer.Close())
+	}
+}
+
+func TestBuildStreamsDownloadError(t *testing.T) {
+	assert := require.New(t)
+	defer func() { downloadObjectFunc = downloadObject }()
+	downloadObjectFunc = func(_ context.Context, _ *s3Object, _ ...request.Option) io.ReadCloser {
+		r, w := io.Pipe()
+		_ = w.CloseWithError(errors.New("download failed"))
+		return r
+	}
+	getObjectMock := &testutils.CounterMock{}
+	defer func(c pkgmetrics.Counter) { logmetrics.GetObject = c }(logmetrics.GetObject)
+	logmetrics.GetObject = getObjectMock
+	getObjectMock.On("With", []string{
+		pkgmetrics.SourceIDDimension, "source-id",
+		pkgmetrics.StatusDimension, logmetrics.StatusErr,
+	}).Return(getObjectMock).Once()
+	getObjectMock.On("Add", 1.0).Once()
+
+	streams, err := buildStreams(context.Background(), &s3Object{
+		S3ObjectInfo: &S3ObjectInfo{
+			S3Bucket:     "bucket",
+			S3ObjectKey:  "logs/key",
+			S3ObjectSize: 1024,
+		},
+		src: &models.SourceIntegration{
+			SourceIntegrationMetadata: models.SourceIntegrationMetadata{
+				IntegrationID:   "source-id",
+				IntegrationType: models.IntegrationTypeAWS3,
+			},
+		},
+	})
+	assert.Error(err)
+	assert.Nil(streams)
+	getObjectMock.AssertExpectations(t)
+}
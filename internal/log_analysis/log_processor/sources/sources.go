@@ -29,6 +29,8 @@ import (
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/classification"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/logstream"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/transform"
 )
 
 // LoadSource loads the source configuration for an source id.
@@ -84,15 +86,67 @@ func BuildClassifier(
 		}
 		parserIndex[logType] = newSourceFieldsParser(src.IntegrationID, src.IntegrationLabel, parser)
 	}
-	return classification.NewClassifier(parserIndex), nil
+	classifier := classification.NewClassifier(parserIndex)
+	transforms, err := transform.Compile(src.Transforms)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "invalid event transforms for source %q", src.IntegrationID)
+	}
+	if transforms == nil {
+		return classifier, nil
+	}
+	return newTransformClassifier(classifier, src, transforms), nil
+}
+
+// ResolveFraming resolves the framing of multi-line log entries for an S3 prefix mapping.
+// The framing set in the mapping takes precedence over the framing required by the log types.
+// The framing required by the log types is only used if all log types in the mapping require the same framing.
+func ResolveFraming(m *models.S3PrefixLogtypesMapping, r pantherlog.ParserResolver) (*logstream.FramingConfig, error) {
+	if m.Framing != nil {
+		return m.Framing, nil
+	}
+	var framing *logstream.FramingConfig
+	for i, logType := range m.LogTypes {
+		parser, err := r.ResolveParser(context.TODO(), logType)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not resolve log type parser %q", logType)
+		}
+		var required *logstream.FramingConfig
+		if framer, ok := parser.(logstream.Framer); ok {
+			required = framer.Framing()
+		}
+		if i == 0 {
+			framing = required
+			continue
+		}
+		if !framing.Equal(required) {
+			zap.L().Warn("log types require different framing, falling back to lines",
+				zap.String("prefix", m.S3Prefix),
+				zap.Strings("logTypes", m.LogTypes))
+			return nil, nil
+		}
+	}
+	return framing, nil
 }
 
 func newSourceFieldsParser(id, label string, parser pantherlog.LogParser) pantherlog.LogParser {
-	return &sourceFieldsParser{
+	p := &sourceFieldsParser{
 		Interface:   parser,
 		SourceID:    id,
 		SourceLabel: label,
 	}
+	// Keep the routing of the parser visible to the classifier
+	if router, ok := parser.(classification.Router); ok {
+		return &routedParser{
+			LogParser: p,
+			Router:    router,
+		}
+	}
+	return p
+}
+
+type routedParser struct {
+	pantherlog.LogParser
+	classification.Router
 }
 
 type sourceFieldsParser struct {
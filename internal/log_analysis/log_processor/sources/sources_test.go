@@ -19,12 +19,17 @@ package sources
  */
 
 import (
+	"context"
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/logstream"
 	"github.com/panther-labs/panther/internal/log_analysis/log_processor/registry"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/transform"
 )
 
 func Test_BuildClassifier_NoLogTypes(t *testing.T) {
@@ -43,3 +48,102 @@ func Test_BuildClassifier_NoLogTypes(t *testing.T) {
 	require.Error(t, err)
 	require.Equal(t, "failed to classify log line", err.Error())
 }
+
+func TestBuildClassifierTransforms(t *testing.T) {
+	assert := require.New(t)
+	src := &models.SourceIntegration{
+		SourceIntegrationMetadata: models.SourceIntegrationMetadata{
+			IntegrationID: "integration-id",
+			Transforms: []transform.Config{{
+				LogType: "Test.Event",
+				Drop:    []transform.Rule{{Match: []transform.Condition{{Field: "name", Op: transform.OpPrefix, Value: "Describe"}}}},
+				Mask:    []string{"user"},
+			}},
+		},
+	}
+	c, err := BuildClassifier([]string{"Test.Event"}, src, testResolver{"Test.Event": &testEventParser{}})
+	assert.NoError(err)
+
+	result, err := c.Classify(`{"name":"DescribeInstances","user":"alice"}`)
+	assert.NoError(err)
+	assert.Empty(result.Events)
+	result, err = c.Classify(`{"name":"RunInstances","user":"alice"}`)
+	assert.NoError(err)
+	assert.Len(result.Events, 1)
+	assert.Equal(transform.MaskValue, result.Events[0].Event.(*testEvent).User)
+
+	stats := c.ParserStats()["Test.Event"]
+	assert.Equal(uint64(2), stats.EventCount)
+	assert.Equal(uint64(1), stats.EventFilteredCount)
+
+	// Invalid transforms fail to build
+	src.Transforms[0].Mask = []string{""}
+	_, err = BuildClassifier([]string{"Test.Event"}, src, testResolver{"Test.Event": &testEventParser{}})
+	assert.Error(err)
+}
+
+func TestResolveFraming(t *testing.T) {
+	assert := require.New(t)
+	indent := &logstream.FramingConfig{ContinuationIndent: true}
+	octets := &logstream.FramingConfig{OctetCounted: true}
+	resolver := testResolver{
+		"Custom.A": &testFramedParser{framing: indent},
+		"Custom.B": &testFramedParser{framing: indent},
+		"Custom.C": &testFramedParser{framing: octets},
+		"Custom.D": &testFramedParser{},
+	}
+	for _, tc := range []struct {
+		Mapping models.S3PrefixLogtypesMapping
+		Expect  *logstream.FramingConfig
+	}{
+		{models.S3PrefixLogtypesMapping{LogTypes: []string{"Custom.A", "Custom.B"}}, indent},
+		{models.S3PrefixLogtypesMapping{LogTypes: []string{"Custom.A", "Custom.C"}}, nil},
+		{models.S3PrefixLogtypesMapping{LogTypes: []string{"Custom.A", "Custom.D"}}, nil},
+		{models.S3PrefixLogtypesMapping{LogTypes: []string{"Custom.D"}}, nil},
+		{models.S3PrefixLogtypesMapping{LogTypes: []string{"Custom.A", "Custom.C"}, Framing: octets}, octets},
+	} {
+		tc := tc
+		framing, err := ResolveFraming(&tc.Mapping, resolver)
+		assert.NoError(err)
+		assert.Equal(tc.Expect, framing, "log types %v", tc.Mapping.LogTypes)
+	}
+}
+
+type testResolver map[string]pantherlog.LogParser
+
+func (r testResolver) ResolveParser(_ context.Context, name string) (pantherlog.LogParser, error) {
+	return r[name], nil
+}
+
+type testFramedParser struct {
+	framing *logstream.FramingConfig
+}
+
+func (p *testFramedParser) ParseLog(_ string) ([]*pantherlog.Result, error) {
+	return nil, nil
+}
+
+func (p *testFramedParser) Framing() *logstream.FramingConfig {
+	return p.framing
+}
+
+type testEvent struct {
+	Name string `json:"name"`
+	User string `json:"user"`
+}
+
+type testEventParser struct {
+	builder pantherlog.ResultBuilder
+}
+
+func (p *testEventParser) ParseLog(log string) ([]*pantherlog.Result, error) {
+	event := testEvent{}
+	if err := json.Unmarshal([]byte(log), &event); err != nil {
+		return nil, err
+	}
+	result, err := p.builder.BuildResult("Test.Event", &event)
+	if err != nil {
+		return nil, err
+	}
+	return []*pantherlog.Result{result}, nil
+}
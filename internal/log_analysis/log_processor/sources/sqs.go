@@ -29,6 +29,8 @@ import (
 	"github.com/panther-labs/panther/internal/log_analysis/message_forwarder/forwarder"
 )
 
+// SQSClassifier classifies the log entries of messages forwarded to Firehose by the message forwarder or the HTTP receiver.
+// Each message is classified using the log types of the source it belongs to.
 type SQSClassifier struct {
 	Resolver    pantherlog.ParserResolver
 	LoadSource  func(id string) (*models.SourceIntegration, error)
@@ -70,7 +72,7 @@ func (c *SQSClassifier) buildSourceClassifier(id string) (classification.Classif
 	if err != nil {
 		return nil, err
 	}
-	return BuildClassifier(src.SqsConfig.LogTypes, src, c.Resolver)
+	return BuildClassifier(src.RequiredLogTypes(), src, c.Resolver)
 }
 
 func (c *SQSClassifier) Stats() *classification.ClassifierStats {
@@ -20,6 +20,7 @@ package sources
 
 import (
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -48,6 +49,18 @@ func TestSQSClassifier(t *testing.T) {
 		},
 	}
 
+	// The HTTP receiver forwards log entries in the same messages
+	httpSource := &models.SourceIntegration{
+		SourceIntegrationMetadata: models.SourceIntegrationMetadata{
+			IntegrationID:    "httpSource",
+			IntegrationLabel: "httpSourceLabel",
+			IntegrationType:  models.IntegrationTypeHTTP,
+			HTTPConfig: &models.HTTPConfig{
+				LogTypes: []string{testLogType},
+			},
+		},
+	}
+
 	testGroup := logtypes.Must("test", logtypes.ConfigJSON{
 		Name:         testLogType,
 		Description:  "Test log type",
@@ -62,8 +75,11 @@ func TestSQSClassifier(t *testing.T) {
 	c := SQSClassifier{
 		Resolver: logtypes.ParserResolver(logtypes.LocalResolver(testGroup)),
 		LoadSource: func(id string) (*models.SourceIntegration, error) {
-			if id == testSourceID {
+			switch id {
+			case testSourceID:
 				return testSource, nil
+			case httpSource.IntegrationID:
+				return httpSource, nil
 			}
 			return nil, errors.New("source not found")
 		},
@@ -71,4 +87,11 @@ func TestSQSClassifier(t *testing.T) {
 	result, err := c.Classify(logData)
 	require.NoError(t, err)
 	require.NotNil(t, result)
+
+	httpData := strings.Replace(logData, `"sourceId":"testSource"`, `"sourceId":"httpSource"`, 1)
+	result, err = c.Classify(httpData)
+	require.NoError(t, err)
+	require.Len(t, result.Events, 1)
+	require.Equal(t, "httpSource", result.Events[0].PantherSourceID)
+	require.Equal(t, "httpSourceLabel", result.Events[0].PantherSourceLabel)
 }
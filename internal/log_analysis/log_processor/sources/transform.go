@@ -0,0 +1,83 @@
+package sources
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/classification"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/transform"
+)
+
+// transformClassifier applies the event transforms of a source to the events of a classifier.
+// Events dropped by the transforms are counted in the parser stats of their log type.
+type transformClassifier struct {
+	classification.ClassifierAPI
+	source     *models.SourceIntegration
+	transforms transform.Set
+	filtered   map[string]uint64
+}
+
+func newTransformClassifier(c classification.ClassifierAPI, src *models.SourceIntegration, transforms transform.Set) *transformClassifier {
+	return &transformClassifier{
+		ClassifierAPI: c,
+		source:        src,
+		transforms:    transforms,
+		filtered:      map[string]uint64{},
+	}
+}
+
+func (c *transformClassifier) Classify(log string) (*classification.ClassifierResult, error) {
+	result, err := c.ClassifierAPI.Classify(log)
+	if err != nil || result == nil {
+		return result, err
+	}
+	events := result.Events[:0]
+	for _, event := range result.Events {
+		keep, err := c.transforms.Apply(event)
+		if err != nil {
+			// Events that cannot be redacted are not stored to avoid leaking sensitive data
+			zap.L().Warn("failed to transform event, dropping it",
+				zap.String("sourceId", c.source.IntegrationID),
+				zap.String("logType", event.PantherLogType),
+				zap.Error(err))
+		}
+		if !keep || err != nil {
+			c.filtered[event.PantherLogType]++
+			continue
+		}
+		events = append(events, event)
+	}
+	result.Events = events
+	return result, nil
+}
+
+func (c *transformClassifier) ParserStats() map[string]*classification.ParserStats {
+	stats := map[string]*classification.ParserStats{}
+	classification.MergeParserStats(stats, c.ClassifierAPI.ParserStats())
+	for logType, n := range c.filtered {
+		s, ok := stats[logType]
+		if !ok {
+			continue
+		}
+		s.EventFilteredCount += n
+	}
+	return stats
+}
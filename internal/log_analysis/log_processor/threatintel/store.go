@@ -0,0 +1,229 @@
+package threatintel
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// DefaultRefreshInterval is how often lookup tables are reloaded
+const DefaultRefreshInterval = 5 * time.Minute
+
+// Store holds the lookup tables loaded from a local directory or an S3 prefix (s3://bucket/prefix/).
+//
+// Each file is a table named after the file (without the extensions).
+// The format of the table is detected by the file extension (.csv, .jsonl) and files ending in .gz are decompressed.
+type Store struct {
+	// Location is a local directory or an S3 prefix (s3://bucket/prefix/)
+	Location string
+	// S3 is the client used to read tables stored in S3
+	S3 s3iface.S3API
+	// RefreshInterval is how often tables are reloaded, DefaultRefreshInterval is used if not set
+	RefreshInterval time.Duration
+
+	mu       sync.RWMutex
+	tables   []*Table
+	loadedAt time.Time
+}
+
+var _ pantherlog.ThreatIntelMatcher = (*Store)(nil)
+
+// MatchThreatIntel implements pantherlog.ThreatIntelMatcher
+func (s *Store) MatchThreatIntel(id pantherlog.FieldID, value string) (names []string) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, t := range s.tables {
+		if t.Contains(id, value) {
+			names = append(names, t.Name)
+		}
+	}
+	return names
+}
+
+// Tables returns the loaded lookup tables
+func (s *Store) Tables() []*Table {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tables
+}
+
+// Refresh reloads the tables if they are older than the refresh interval.
+// The previous tables are kept if loading fails.
+func (s *Store) Refresh(ctx context.Context) error {
+	interval := s.RefreshInterval
+	if interval <= 0 {
+		interval = DefaultRefreshInterval
+	}
+	s.mu.RLock()
+	fresh := time.Since(s.loadedAt) < interval
+	s.mu.RUnlock()
+	if fresh {
+		return nil
+	}
+	return s.Load(ctx)
+}
+
+// Load loads all the tables
+func (s *Store) Load(ctx context.Context) error {
+	var tables []*Table
+	var err error
+	if strings.HasPrefix(s.Location, "s3://") {
+		tables, err = s.loadS3(ctx)
+	} else {
+		tables, err = s.loadDir()
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to load threat intel lookup tables from %q", s.Location)
+	}
+	sort.Slice(tables, func(i, j int) bool {
+		return tables[i].Name < tables[j].Name
+	})
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tables = tables
+	s.loadedAt = time.Now()
+	return nil
+}
+
+func (s *Store) loadDir() ([]*Table, error) {
+	files, err := ioutil.ReadDir(s.Location)
+	if err != nil {
+		return nil, err
+	}
+	var tables []*Table
+	for _, info := range files {
+		if info.IsDir() {
+			continue
+		}
+		name, format, compressed := tableFile(info.Name())
+		if format == "" {
+			continue
+		}
+		f, err := os.Open(filepath.Join(s.Location, info.Name()))
+		if err != nil {
+			return nil, err
+		}
+		t, err := readTableFile(name, format, compressed, f)
+		_ = f.Close()
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, t)
+	}
+	return tables, nil
+}
+
+func (s *Store) loadS3(ctx context.Context) ([]*Table, error) {
+	bucket, prefix := parseS3URL(s.Location)
+	if bucket == "" {
+		return nil, errors.Errorf("invalid S3 URL %q", s.Location)
+	}
+	var keys []string
+	input := s3.ListObjectsV2Input{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(prefix),
+	}
+	err := s.S3.ListObjectsV2PagesWithContext(ctx, &input, func(page *s3.ListObjectsV2Output, _ bool) bool {
+		for _, obj := range page.Contents {
+			keys = append(keys, aws.StringValue(obj.Key))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	var tables []*Table
+	for _, key := range keys {
+		name, format, compressed := tableFile(path.Base(key))
+		if format == "" {
+			zap.L().Debug("skipping threat intel object with unknown format", zap.String("key", key))
+			continue
+		}
+		output, err := s.S3.GetObjectWithContext(ctx, &s3.GetObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get %q", key)
+		}
+		t, err := readTableFile(name, format, compressed, output.Body)
+		_ = output.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, t)
+	}
+	return tables, nil
+}
+
+func readTableFile(name, format string, compressed bool, r io.Reader) (*Table, error) {
+	if compressed {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read lookup table %q", name)
+		}
+		defer gz.Close()
+		r = gz
+	}
+	return ReadTable(name, format, r)
+}
+
+// tableFile resolves the table name and format of a file
+func tableFile(filename string) (name, format string, compressed bool) {
+	name = filename
+	if strings.HasSuffix(name, ".gz") {
+		name = strings.TrimSuffix(name, ".gz")
+		compressed = true
+	}
+	switch ext := path.Ext(name); ext {
+	case ".csv":
+		format = FormatCSV
+	case ".jsonl", ".ndjson":
+		format = FormatJSONL
+	default:
+		return "", "", false
+	}
+	return strings.TrimSuffix(name, path.Ext(name)), format, compressed
+}
+
+func parseS3URL(u string) (bucket, prefix string) {
+	p := strings.TrimPrefix(u, "s3://")
+	if pos := strings.IndexByte(p, '/'); pos != -1 {
+		return p[:pos], p[pos+1:]
+	}
+	return p, ""
+}
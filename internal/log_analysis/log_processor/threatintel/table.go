@@ -0,0 +1,320 @@
+// Package threatintel matches the indicators of log events against lookup tables of known-bad values.
+package threatintel
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"hash/fnv"
+	"io"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+)
+
+// Table formats
+const (
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl"
+)
+
+// Kinds maps the indicator types of lookup table entries to the indicator fields they are matched against
+var Kinds = map[string]pantherlog.FieldID{
+	"ip":             pantherlog.FieldIPAddress,
+	"domain":         pantherlog.FieldDomainName,
+	"md5":            pantherlog.FieldMD5Hash,
+	"sha1":           pantherlog.FieldSHA1Hash,
+	"sha256":         pantherlog.FieldSHA256Hash,
+	"email":          pantherlog.FieldEmail,
+	"username":       pantherlog.FieldUsername,
+	"aws_account_id": pantherlog.FieldAWSAccountID,
+	"aws_arn":        pantherlog.FieldAWSARN,
+}
+
+// Set is a memory-efficient set of strings.
+// It stores the sorted 64-bit hashes of its values, so the chance of a false match is negligible for any practical size.
+type Set []uint64
+
+// Contains checks if a value is in the set
+func (s Set) Contains(value string) bool {
+	h := hashValue(value)
+	i := sort.Search(len(s), func(i int) bool {
+		return s[i] >= h
+	})
+	return i < len(s) && s[i] == h
+}
+
+// Len returns the number of values in the set
+func (s Set) Len() int {
+	return len(s)
+}
+
+func hashValue(value string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(value))
+	return h.Sum64()
+}
+
+// Table is a threat intel lookup table
+type Table struct {
+	Name string
+	// Skipped is the number of invalid entries left out of the table
+	Skipped int
+	sets    map[pantherlog.FieldID]Set
+}
+
+// Len returns the number of indicators in the table
+func (t *Table) Len() (n int) {
+	for _, set := range t.sets {
+		n += set.Len()
+	}
+	return n
+}
+
+// Contains checks if the table contains an indicator value
+func (t *Table) Contains(id pantherlog.FieldID, value string) bool {
+	set, ok := t.sets[id]
+	return ok && set.Contains(normalize(id, value))
+}
+
+// Entry is an entry of a lookup table
+type Entry struct {
+	// Indicator is the indicator value
+	Indicator string `json:"indicator"`
+	// Type is the type of the indicator (ie 'ip', 'domain', 'sha256').
+	// It is detected from the value of the indicator if not set.
+	Type string `json:"type,omitempty"`
+}
+
+// TableBuilder builds lookup tables
+type TableBuilder struct {
+	Name    string
+	hashes  map[pantherlog.FieldID][]uint64
+	skipped int
+}
+
+// Add adds an entry to the table
+func (b *TableBuilder) Add(entry Entry) error {
+	value := strings.TrimSpace(entry.Indicator)
+	if value == "" {
+		return nil
+	}
+	kind := strings.ToLower(strings.TrimSpace(entry.Type))
+	if kind == "" {
+		kind = DetectKind(value)
+	}
+	id, ok := Kinds[kind]
+	if !ok && kind == "" {
+		return errors.Errorf("failed to detect the type of indicator %q", value)
+	}
+	if !ok {
+		return errors.Errorf("invalid indicator type %q", entry.Type)
+	}
+	if b.hashes == nil {
+		b.hashes = make(map[pantherlog.FieldID][]uint64)
+	}
+	b.hashes[id] = append(b.hashes[id], hashValue(normalize(id, value)))
+	return nil
+}
+
+// addOrSkip adds an entry to the table, logging and counting invalid entries instead of failing
+func (b *TableBuilder) addOrSkip(entry Entry, line int) {
+	if err := b.Add(entry); err != nil {
+		b.skipped++
+		zap.L().Debug("skipping invalid lookup table entry",
+			zap.String("table", b.Name),
+			zap.Int("line", line),
+			zap.Error(err))
+	}
+}
+
+// Build builds the table and resets the builder
+func (b *TableBuilder) Build() *Table {
+	t := Table{
+		Name:    b.Name,
+		Skipped: b.skipped,
+		sets:    make(map[pantherlog.FieldID]Set, len(b.hashes)),
+	}
+	for id, hashes := range b.hashes {
+		sort.Slice(hashes, func(i, j int) bool {
+			return hashes[i] < hashes[j]
+		})
+		// Remove duplicates in place
+		set := hashes[:0]
+		for i, h := range hashes {
+			if i > 0 && h == hashes[i-1] {
+				continue
+			}
+			set = append(set, h)
+		}
+		t.sets[id] = Set(set)
+	}
+	b.hashes = nil
+	b.skipped = 0
+	return &t
+}
+
+var (
+	hashPattern   = regexp.MustCompile(`^[0-9a-fA-F]+$`)
+	digitsPattern = regexp.MustCompile(`^[0-9]+$`)
+)
+
+// DetectKind detects the type of an indicator value.
+// It returns an empty string if the type cannot be detected.
+func DetectKind(value string) string {
+	if net.ParseIP(value) != nil {
+		return "ip"
+	}
+	if digitsPattern.MatchString(value) {
+		// All-digit values are never domains
+		if len(value) == 12 {
+			return "aws_account_id"
+		}
+		return ""
+	}
+	if hashPattern.MatchString(value) {
+		switch len(value) {
+		case 32:
+			return "md5"
+		case 40:
+			return "sha1"
+		case 64:
+			return "sha256"
+		}
+	}
+	if strings.HasPrefix(value, "arn:") {
+		return "aws_arn"
+	}
+	if strings.Contains(value, "@") {
+		return "email"
+	}
+	return "domain"
+}
+
+// normalize normalizes case insensitive indicator values
+func normalize(id pantherlog.FieldID, value string) string {
+	switch id {
+	case pantherlog.FieldDomainName, pantherlog.FieldMD5Hash, pantherlog.FieldSHA1Hash, pantherlog.FieldSHA256Hash, pantherlog.FieldEmail:
+		return strings.ToLower(strings.TrimSuffix(value, "."))
+	case pantherlog.FieldIPAddress:
+		if ip := net.ParseIP(value); ip != nil {
+			return ip.String()
+		}
+		return value
+	default:
+		return value
+	}
+}
+
+// ReadTable reads a lookup table in CSV or JSONL format.
+//
+// CSV tables can have a header with an `indicator` and an optional `type` column.
+// If there is no such header, the first column of each row is the indicator.
+// JSONL tables have an Entry object per line.
+// Entries with an invalid or undetectable type are skipped, only I/O and syntax errors fail the whole table.
+func ReadTable(name, format string, r io.Reader) (*Table, error) {
+	b := TableBuilder{Name: name}
+	var err error
+	switch format {
+	case FormatCSV:
+		err = readCSV(&b, r)
+	case FormatJSONL:
+		err = readJSONL(&b, r)
+	default:
+		err = errors.Errorf("unsupported format %q", format)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read lookup table %q", name)
+	}
+	t := b.Build()
+	if t.Skipped > 0 {
+		zap.L().Warn("skipped invalid lookup table entries",
+			zap.String("table", name),
+			zap.Int("skipped", t.Skipped),
+			zap.Int("indicators", t.Len()))
+	}
+	return t, nil
+}
+
+func readCSV(b *TableBuilder, r io.Reader) error {
+	rd := csv.NewReader(r)
+	rd.FieldsPerRecord = -1
+	rd.Comment = '#'
+	rd.ReuseRecord = true
+	indicatorCol, typeCol := 0, -1
+	for line := 0; ; line++ {
+		row, err := rd.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if line == 0 {
+			if col, ok := findColumn(row, "indicator"); ok {
+				indicatorCol = col
+				typeCol, _ = findColumn(row, "type")
+				continue
+			}
+		}
+		entry := Entry{}
+		if indicatorCol < len(row) {
+			entry.Indicator = row[indicatorCol]
+		}
+		if 0 <= typeCol && typeCol < len(row) {
+			entry.Type = row[typeCol]
+		}
+		b.addOrSkip(entry, line+1)
+	}
+}
+
+func findColumn(header []string, name string) (int, bool) {
+	for i, col := range header {
+		if strings.EqualFold(strings.TrimSpace(col), name) {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+func readJSONL(b *TableBuilder, r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 4096), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		data := scanner.Bytes()
+		if len(bytes.TrimSpace(data)) == 0 {
+			continue
+		}
+		entry := Entry{}
+		if err := jsoniter.ConfigFastest.Unmarshal(data, &entry); err != nil {
+			return errors.Wrapf(err, "line %d", line)
+		}
+		b.addOrSkip(entry, line)
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,200 @@
+package threatintel
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+	"github.com/panther-labs/panther/pkg/testutils"
+)
+
+const testCSV = `type,indicator,description
+ip,1.2.3.4,scanner
+,Evil.Example.com.,c2
+sha256,E3B0C44298FC1C149AFBF4C8996FB92427AE41E4649B934CA495991B7852B855,empty
+`
+
+const testJSONL = `{"indicator":"5.6.7.8"}
+
+{"indicator":"d41d8cd98f00b204e9800998ecf8427e","type":"md5"}
+`
+
+func TestReadTable(t *testing.T) {
+	assert := require.New(t)
+	table, err := ReadTable("bad", FormatCSV, strings.NewReader(testCSV))
+	assert.NoError(err)
+	assert.Equal(3, table.Len())
+	assert.True(table.Contains(pantherlog.FieldIPAddress, "1.2.3.4"))
+	assert.True(table.Contains(pantherlog.FieldDomainName, "evil.example.com"))
+	assert.True(table.Contains(pantherlog.FieldSHA256Hash, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"))
+	assert.False(table.Contains(pantherlog.FieldDomainName, "1.2.3.4"))
+	assert.False(table.Contains(pantherlog.FieldIPAddress, "1.2.3.5"))
+
+	// No header
+	table, err = ReadTable("bad", FormatCSV, strings.NewReader("1.2.3.4\n1.2.3.4\n# comment\n::1\n"))
+	assert.NoError(err)
+	assert.Equal(2, table.Len())
+	assert.True(table.Contains(pantherlog.FieldIPAddress, "0:0::1"))
+
+	table, err = ReadTable("bad", FormatCSV, strings.NewReader("123456789012\n"))
+	assert.NoError(err)
+	assert.True(table.Contains(pantherlog.FieldAWSAccountID, "123456789012"))
+	assert.False(table.Contains(pantherlog.FieldDomainName, "123456789012"))
+
+	table, err = ReadTable("bad", FormatJSONL, strings.NewReader(testJSONL))
+	assert.NoError(err)
+	assert.Equal(2, table.Len())
+	assert.True(table.Contains(pantherlog.FieldIPAddress, "5.6.7.8"))
+	assert.True(table.Contains(pantherlog.FieldMD5Hash, "d41d8cd98f00b204e9800998ecf8427e"))
+
+	// Invalid entries are skipped
+	table, err = ReadTable("bad", FormatJSONL, strings.NewReader(`{"indicator":"foo","type":"color"}`))
+	assert.NoError(err)
+	assert.Equal(0, table.Len())
+	assert.Equal(1, table.Skipped)
+	table, err = ReadTable("bad", FormatCSV, strings.NewReader(testCSV+"color,red,bad type\n,12345,undetectable\nip,5.6.7.8,scanner\n"))
+	assert.NoError(err)
+	assert.Equal(4, table.Len())
+	assert.Equal(2, table.Skipped)
+	assert.True(table.Contains(pantherlog.FieldIPAddress, "5.6.7.8"))
+
+	_, err = ReadTable("bad", FormatCSV, strings.NewReader("ip,\"1.2.3.4\n"))
+	assert.Error(err)
+	_, err = ReadTable("bad", FormatJSONL, strings.NewReader(`foo`))
+	assert.Error(err)
+	_, err = ReadTable("bad", "xml", strings.NewReader(`foo`))
+	assert.Error(err)
+}
+
+func TestDetectKind(t *testing.T) {
+	for value, kind := range map[string]string{
+		"1.2.3.4":                          "ip",
+		"2001:db8::1":                      "ip",
+		"example.com":                      "domain",
+		"d41d8cd98f00b204e9800998ecf8427e": "md5",
+		"da39a3ee5e6b4b0d3255bfef95601890afd80709":                         "sha1",
+		"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855": "sha256",
+		"arn:aws:iam::123456789012:root":                                   "aws_arn",
+		"foo@example.com":                                                  "email",
+		"123456789012":                                                     "aws_account_id",
+		"12345":                                                            "",
+	} {
+		require.Equal(t, kind, DetectKind(value), value)
+	}
+}
+
+func TestStoreS3(t *testing.T) {
+	assert := require.New(t)
+	gz := bytes.Buffer{}
+	w := gzip.NewWriter(&gz)
+	_, _ = w.Write([]byte(testJSONL))
+	assert.NoError(w.Close())
+
+	s3Mock := &testutils.S3Mock{}
+	s3Mock.On("ListObjectsV2PagesWithContext", mock.Anything, &s3.ListObjectsV2Input{
+		Bucket: aws.String("bucket"),
+		Prefix: aws.String("threat_intel/"),
+	}, mock.Anything, mock.Anything).Return(&s3.ListObjectsV2Output{
+		Contents: []*s3.Object{
+			{Key: aws.String("threat_intel/scanners.jsonl.gz")},
+			{Key: aws.String("threat_intel/c2.csv")},
+			{Key: aws.String("threat_intel/README.md")},
+		},
+	}, nil).Once()
+	s3Mock.On("GetObjectWithContext", mock.Anything, &s3.GetObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("threat_intel/scanners.jsonl.gz"),
+	}, mock.Anything).Return(&s3.GetObjectOutput{
+		Body: ioutil.NopCloser(bytes.NewReader(gz.Bytes())),
+	}, nil).Once()
+	s3Mock.On("GetObjectWithContext", mock.Anything, &s3.GetObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("threat_intel/c2.csv"),
+	}, mock.Anything).Return(&s3.GetObjectOutput{
+		Body: ioutil.NopCloser(strings.NewReader(testCSV)),
+	}, nil).Once()
+
+	store := &Store{
+		Location: "s3://bucket/threat_intel/",
+		S3:       s3Mock,
+	}
+	assert.NoError(store.Refresh(context.Background()))
+	s3Mock.AssertExpectations(t)
+	assert.Len(store.Tables(), 2)
+	assert.Equal([]string{"c2"}, store.MatchThreatIntel(pantherlog.FieldIPAddress, "1.2.3.4"))
+	assert.Equal([]string{"scanners"}, store.MatchThreatIntel(pantherlog.FieldIPAddress, "5.6.7.8"))
+	assert.Empty(store.MatchThreatIntel(pantherlog.FieldIPAddress, "9.9.9.9"))
+
+	// Tables are fresh
+	assert.NoError(store.Refresh(context.Background()))
+	s3Mock.AssertExpectations(t)
+}
+
+func TestStoreDir(t *testing.T) {
+	assert := require.New(t)
+	dir, err := ioutil.TempDir("", "threatintel")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+	assert.NoError(ioutil.WriteFile(filepath.Join(dir, "c2.csv"), []byte(testCSV), 0600))
+	assert.NoError(ioutil.WriteFile(filepath.Join(dir, "notes.txt"), []byte("foo"), 0600))
+	store := &Store{Location: dir}
+	assert.NoError(store.Load(context.Background()))
+	assert.Len(store.Tables(), 1)
+	assert.Equal([]string{"c2"}, store.MatchThreatIntel(pantherlog.FieldDomainName, "EVIL.example.com"))
+
+	store = &Store{Location: filepath.Join(dir, "missing")}
+	assert.Error(store.Load(context.Background()))
+}
+
+func TestMatchResult(t *testing.T) {
+	assert := require.New(t)
+	table, err := ReadTable("c2", FormatCSV, strings.NewReader(testCSV))
+	assert.NoError(err)
+	store := &Store{tables: []*Table{table}}
+	result := pantherlog.Result{
+		CoreFields: pantherlog.CoreFields{
+			PantherLogType: "Foo",
+		},
+		Event: &struct {
+			RemoteAddr string `json:"remote_addr" panther:"ip"`
+			Host       string `json:"host" panther:"domain"`
+		}{
+			RemoteAddr: "1.2.3.4",
+			Host:       "evil.example.com",
+		},
+		ThreatIntel: store,
+	}
+	data, err := jsoniter.Marshal(&result)
+	assert.NoError(err)
+	assert.Equal(`{"c2":["1.2.3.4","evil.example.com"]}`, jsoniter.Get(data, pantherlog.FieldThreatIntelMatchesJSON).ToString())
+}
@@ -0,0 +1,501 @@
+package transform
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bytes"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+)
+
+// path is a dot separated path to an event field using the JSON field names
+type path []string
+
+func (p path) String() string {
+	return strings.Join(p, ".")
+}
+
+func parsePath(s string) (path, error) {
+	if s == "" {
+		return nil, errors.New("empty field path")
+	}
+	p := strings.Split(s, ".")
+	for _, name := range p {
+		if name == "" {
+			return nil, errors.Errorf("invalid field path %q", s)
+		}
+	}
+	return p, nil
+}
+
+func parsePaths(fields []string) ([]path, error) {
+	paths := make([]path, 0, len(fields))
+	for _, field := range fields {
+		p, err := parsePath(field)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, p)
+	}
+	return paths, nil
+}
+
+var (
+	// jsonAPI decodes numbers as json.Number so that JSON values are re-encoded without loss
+	jsonAPI = jsoniter.Config{UseNumber: true}.Froze()
+
+	typeTime = reflect.TypeOf(time.Time{})
+
+	errMissing = errors.New("missing value")
+)
+
+// FieldPath is a parsed field path
+type FieldPath struct {
+	p path
+}
+
+// ParseFieldPath parses a dot separated path to an event field using the JSON field names
+func ParseFieldPath(field string) (FieldPath, error) {
+	p, err := parsePath(field)
+	return FieldPath{p: p}, err
+}
+
+// String implements fmt.Stringer
+func (p FieldPath) String() string {
+	return p.p.String()
+}
+
+// Read returns the text of the value of an event field and whether the value is set
+func (p FieldPath) Read(event interface{}) (string, bool, error) {
+	return read(event, p.p)
+}
+
+// read returns the text of the value at a path and whether the value is set
+func read(event interface{}, p path) (string, bool, error) {
+	s, rest, err := lookup(event, p)
+	if err != nil {
+		if err == errMissing {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	v := indirect(s.value)
+	if !v.IsValid() {
+		return "", false, nil
+	}
+	if isJSON(v.Type()) {
+		if len(rest) == 0 {
+			text, ok := jsonText(v.Bytes())
+			return text, ok, nil
+		}
+		keys := make([]interface{}, len(rest))
+		for i, name := range rest {
+			keys[i] = jsonKey(name)
+		}
+		any := jsonAPI.Get(v.Bytes(), keys...)
+		switch any.ValueType() {
+		case jsoniter.InvalidValue, jsoniter.NilValue:
+			return "", false, nil
+		default:
+			return any.ToString(), true, nil
+		}
+	}
+	text, ok := valueText(v)
+	return text, ok, nil
+}
+
+// rewrite replaces the text of the string values at a path
+func rewrite(event interface{}, p path, fn func(string) string) error {
+	s, rest, err := lookup(event, p)
+	if err != nil {
+		if err == errMissing {
+			return nil
+		}
+		return err
+	}
+	return rewriteValue(s, rest, fn)
+}
+
+func rewriteValue(s *slot, rest path, fn func(string) string) error {
+	v := indirect(s.value)
+	if !v.IsValid() {
+		return nil
+	}
+	typ := v.Type()
+	switch {
+	case isJSON(typ):
+		raw := v.Bytes()
+		if len(rest) == 0 {
+			text, ok := jsonText(raw)
+			if !ok {
+				return nil
+			}
+			data, err := jsonAPI.Marshal(fn(text))
+			if err != nil {
+				return err
+			}
+			return s.replace(v, reflect.ValueOf(data).Convert(typ))
+		}
+		data, err := editJSON(raw, rest, func(value interface{}) (interface{}, bool) {
+			switch value := value.(type) {
+			case nil:
+				return nil, true
+			case string:
+				return fn(value), true
+			default:
+				text, _ := jsonAPI.MarshalToString(value)
+				return fn(text), true
+			}
+		})
+		if err != nil {
+			return err
+		}
+		return s.replace(v, reflect.ValueOf(data).Convert(typ))
+	case typ.Kind() == reflect.String:
+		return s.replace(v, reflect.ValueOf(fn(v.String())).Convert(typ))
+	case typ.Kind() == reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			if err := rewriteValue(&slot{value: v.Index(i)}, nil, fn); err != nil {
+				return err
+			}
+		}
+		return nil
+	case isNullable(typ) && typ.Field(0).Type.Kind() == reflect.String:
+		if !v.Field(1).Bool() {
+			return nil
+		}
+		// Copy the value in case it is not addressable
+		value := reflect.New(typ).Elem()
+		value.Set(v)
+		value.Field(0).SetString(fn(v.Field(0).String()))
+		return s.replace(v, value)
+	default:
+		return errors.Errorf("cannot redact value of type %s", typ)
+	}
+}
+
+// remove clears the value at a path and returns the text of the string values it removed
+func remove(event interface{}, p path) ([]string, error) {
+	s, rest, err := lookup(event, p)
+	if err != nil {
+		if err == errMissing {
+			return nil, nil
+		}
+		return nil, err
+	}
+	v := indirect(s.value)
+	if !v.IsValid() {
+		return nil, nil
+	}
+	var removed []string
+	// Collect the removed strings by rewriting them in place
+	_ = rewriteValue(&slot{value: v}, rest, func(text string) string {
+		removed = append(removed, text)
+		return text
+	})
+	if len(rest) > 0 {
+		data, err := editJSON(v.Bytes(), rest, func(_ interface{}) (interface{}, bool) {
+			return nil, false
+		})
+		if err != nil {
+			return nil, err
+		}
+		return removed, s.replace(v, reflect.ValueOf(data).Convert(v.Type()))
+	}
+	if isJSON(s.value.Type()) {
+		// Empty raw JSON values produce invalid JSON when serialized
+		return removed, s.replace(v, reflect.ValueOf([]byte(`null`)).Convert(v.Type()))
+	}
+	return removed, s.clear()
+}
+
+// slot is a value in an event that can be replaced
+type slot struct {
+	value reflect.Value
+	// Map values are not addressable, they are replaced in the map
+	m   reflect.Value
+	key reflect.Value
+}
+
+// replace replaces the value v resolved from the slot
+func (s *slot) replace(v, value reflect.Value) error {
+	if v.CanSet() {
+		v.Set(value)
+		return nil
+	}
+	if s.m.IsValid() {
+		s.m.SetMapIndex(s.key, value)
+		return nil
+	}
+	if s.value.CanSet() && value.Type().AssignableTo(s.value.Type()) {
+		s.value.Set(value)
+		return nil
+	}
+	return errors.Errorf("cannot modify value of type %s", v.Type())
+}
+
+func (s *slot) clear() error {
+	if s.m.IsValid() {
+		s.m.SetMapIndex(s.key, reflect.Value{})
+		return nil
+	}
+	if !s.value.CanSet() {
+		return errors.Errorf("cannot modify value of type %s", s.value.Type())
+	}
+	s.value.Set(reflect.Zero(s.value.Type()))
+	return nil
+}
+
+// lookup resolves the value at a path.
+// If the path continues inside a JSON value it returns the slot of the JSON value and the remaining path.
+func lookup(event interface{}, p path) (*slot, path, error) {
+	s := &slot{value: reflect.ValueOf(event)}
+	for i, name := range p {
+		v := indirect(s.value)
+		if !v.IsValid() {
+			return nil, nil, errMissing
+		}
+		typ := v.Type()
+		switch {
+		case isJSON(typ):
+			return s, p[i:], nil
+		case typ.Kind() == reflect.Struct:
+			index, ok := structFields(typ)[name]
+			if !ok {
+				return nil, nil, errors.Errorf("unknown field %q", p[:i+1])
+			}
+			field, ok := fieldByIndex(v, index)
+			if !ok {
+				return nil, nil, errMissing
+			}
+			s = &slot{value: field}
+		case typ.Kind() == reflect.Map && typ.Key().Kind() == reflect.String:
+			key := reflect.ValueOf(name).Convert(typ.Key())
+			value := v.MapIndex(key)
+			if !value.IsValid() {
+				return nil, nil, errMissing
+			}
+			s = &slot{value: value, m: v, key: key}
+		default:
+			return nil, nil, errors.Errorf("field %q is not an object", p[:i])
+		}
+	}
+	return s, nil, nil
+}
+
+// indirect resolves pointers and interfaces, it returns an invalid value for nil
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// fieldByIndex resolves a struct field through embedded pointers
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, n := range index {
+		if i > 0 {
+			if v = indirect(v); !v.IsValid() {
+				return reflect.Value{}, false
+			}
+		}
+		v = v.Field(n)
+	}
+	return v, true
+}
+
+// fieldsCache holds the fields of struct types by JSON name
+var fieldsCache sync.Map
+
+func structFields(typ reflect.Type) map[string][]int {
+	if fields, ok := fieldsCache.Load(typ); ok {
+		return fields.(map[string][]int)
+	}
+	fields := map[string][]int{}
+	collectFields(fields, typ, nil)
+	fieldsCache.Store(typ, fields)
+	return fields
+}
+
+func collectFields(fields map[string][]int, typ reflect.Type, parent []int) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := tag
+		if n := strings.IndexByte(tag, ','); n != -1 {
+			name = tag[:n]
+		}
+		index := append(append([]int(nil), parent...), i)
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				collectFields(fields, embedded, index)
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		// Shallow fields hide embedded ones, like in encoding/json
+		if other, ok := fields[name]; ok && len(other) <= len(index) {
+			continue
+		}
+		fields[name] = index
+	}
+}
+
+// isJSON checks if a type holds raw JSON (ie jsoniter.RawMessage)
+func isJSON(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.Uint8 && strings.HasSuffix(typ.Name(), "RawMessage")
+}
+
+// isNullable checks if a type is a nullable value of the `null` package
+func isNullable(typ reflect.Type) bool {
+	if typ.Kind() != reflect.Struct || typ.NumField() != 2 {
+		return false
+	}
+	return typ.Field(0).Name == "Value" && typ.Field(1).Name == "Exists" && typ.Field(1).Type.Kind() == reflect.Bool
+}
+
+// valueText formats a value as text
+func valueText(v reflect.Value) (string, bool) {
+	typ := v.Type()
+	switch typ.Kind() {
+	case reflect.String:
+		return v.String(), true
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), true
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64), true
+	case reflect.Struct:
+		if isNullable(typ) {
+			if !v.Field(1).Bool() {
+				return "", false
+			}
+			return valueText(v.Field(0))
+		}
+		if typ.ConvertibleTo(typeTime) {
+			tm := v.Convert(typeTime).Interface().(time.Time)
+			if tm.IsZero() {
+				return "", false
+			}
+			return tm.UTC().Format(time.RFC3339Nano), true
+		}
+	}
+	if !v.CanInterface() {
+		return "", false
+	}
+	data, err := jsonAPI.Marshal(v.Interface())
+	if err != nil {
+		return "", false
+	}
+	return jsonText(data)
+}
+
+// jsonText returns the text of a JSON value, strings are unquoted
+func jsonText(data []byte) (string, bool) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || string(data) == `null` {
+		return "", false
+	}
+	if data[0] == '"' {
+		var s string
+		if err := jsonAPI.Unmarshal(data, &s); err != nil {
+			return "", false
+		}
+		return s, true
+	}
+	return string(data), true
+}
+
+// jsonKey converts array indexes in paths inside JSON values
+func jsonKey(name string) interface{} {
+	if n, err := strconv.Atoi(name); err == nil && n >= 0 {
+		return n
+	}
+	return name
+}
+
+// editJSON replaces the value at a path inside a JSON document.
+// The edit function returns the new value or false to delete it.
+func editJSON(data []byte, p path, edit func(value interface{}) (interface{}, bool)) ([]byte, error) {
+	var doc interface{}
+	if err := jsonAPI.Unmarshal(data, &doc); err != nil {
+		return nil, errors.Wrap(err, "invalid JSON value")
+	}
+	container := doc
+	for i, name := range p {
+		last := i == len(p)-1
+		switch c := container.(type) {
+		case map[string]interface{}:
+			value, ok := c[name]
+			if !ok {
+				return data, nil
+			}
+			if !last {
+				container = value
+				continue
+			}
+			if value, ok = edit(value); ok {
+				c[name] = value
+			} else {
+				delete(c, name)
+			}
+		case []interface{}:
+			n, err := strconv.Atoi(name)
+			if err != nil || n < 0 || n >= len(c) {
+				return data, nil
+			}
+			if !last {
+				container = c[n]
+				continue
+			}
+			if value, ok := edit(c[n]); ok {
+				c[n] = value
+			} else {
+				c[n] = nil
+			}
+		default:
+			return data, nil
+		}
+	}
+	return jsonAPI.Marshal(doc)
+}
@@ -0,0 +1,388 @@
+// Package transform filters and redacts events before they are stored in the data lake.
+package transform
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+)
+
+// Condition operators
+const (
+	OpEquals    = "eq"
+	OpNotEquals = "ne"
+	OpIn        = "in"
+	OpPrefix    = "prefix"
+	OpSuffix    = "suffix"
+	OpContains  = "contains"
+	OpRegex     = "regex"
+	OpExists    = "exists"
+	OpMissing   = "missing"
+)
+
+// MaskValue replaces the values of masked fields
+const MaskValue = "****"
+
+// Config is the declarative transform of the events of a log type.
+// Fields are addressed by a dot separated path of their JSON names (ie `userIdentity.arn`).
+// Paths can continue inside JSON values (ie `requestParameters.bucketName`) and use indexes for JSON arrays.
+type Config struct {
+	// LogType is the log type of the transformed events
+	LogType string `json:"logType" validate:"required"`
+	// Drop discards events matching any of the rules
+	Drop []Rule `json:"drop,omitempty" validate:"omitempty,dive"`
+	// Keep discards events not matching any of the rules
+	Keep []Rule `json:"keep,omitempty" validate:"omitempty,dive"`
+	// Mask replaces the values of string fields with MaskValue
+	Mask []string `json:"mask,omitempty"`
+	// Hash replaces the values of string fields with their hex encoded SHA256 hash
+	Hash []string `json:"hash,omitempty"`
+	// HashKey switches hashing to HMAC-SHA256 so that hashed values cannot be reversed using a dictionary
+	HashKey string `genericapi:"redact" json:"hashKey,omitempty"`
+	// Remove clears fields
+	Remove []string `json:"remove,omitempty"`
+}
+
+// Rule matches events for which all conditions are true
+type Rule struct {
+	Match []Condition `json:"match" validate:"min=1,dive"`
+}
+
+// Condition checks the value of an event field
+type Condition struct {
+	Field string `json:"field" validate:"required"`
+	Op    string `json:"op" validate:"oneof=eq ne in prefix suffix contains regex exists missing"`
+	// Value is the operand of all operators except `in`, `exists` and `missing`
+	Value string `json:"value,omitempty"`
+	// Values is the operand of the `in` operator
+	Values []string `json:"values,omitempty"`
+}
+
+// Validate checks a transform configuration
+func (c *Config) Validate() error {
+	_, err := New(c)
+	return err
+}
+
+// Validate checks the transform configurations of a source
+func Validate(configs []Config) error {
+	_, err := Compile(configs)
+	return err
+}
+
+// Transform filters and redacts the events of a log type
+type Transform struct {
+	logType string
+	drop    []rule
+	keep    []rule
+	mask    []path
+	hash    []path
+	remove  []path
+	newHash func() hash.Hash
+}
+
+// New compiles a transform configuration
+func New(config *Config) (*Transform, error) {
+	if config.LogType == "" {
+		return nil, errors.New("missing log type")
+	}
+	t := Transform{
+		logType: config.LogType,
+		newHash: sha256.New,
+	}
+	if key := config.HashKey; key != "" {
+		t.newHash = func() hash.Hash {
+			return hmac.New(sha256.New, []byte(key))
+		}
+	}
+	var err error
+	if t.drop, err = compileRules(config.Drop); err != nil {
+		return nil, errors.WithMessage(err, "invalid drop rule")
+	}
+	if t.keep, err = compileRules(config.Keep); err != nil {
+		return nil, errors.WithMessage(err, "invalid keep rule")
+	}
+	if t.mask, err = parsePaths(config.Mask); err != nil {
+		return nil, errors.WithMessage(err, "invalid mask field")
+	}
+	if t.hash, err = parsePaths(config.Hash); err != nil {
+		return nil, errors.WithMessage(err, "invalid hash field")
+	}
+	if t.remove, err = parsePaths(config.Remove); err != nil {
+		return nil, errors.WithMessage(err, "invalid remove field")
+	}
+	return &t, nil
+}
+
+// Apply transforms the event of a result in place.
+// It returns false if the event should be dropped.
+func (t *Transform) Apply(result *pantherlog.Result) (bool, error) {
+	event := result.Event
+	if event == nil {
+		return true, nil
+	}
+	for i := range t.drop {
+		match, err := t.drop[i].match(event)
+		if err != nil {
+			return false, err
+		}
+		if match {
+			return false, nil
+		}
+	}
+	if len(t.keep) > 0 {
+		keep := false
+		for i := range t.keep {
+			match, err := t.keep[i].match(event)
+			if err != nil {
+				return false, err
+			}
+			if match {
+				keep = true
+				break
+			}
+		}
+		if !keep {
+			return false, nil
+		}
+	}
+
+	// Collect the original values to scrub them from the indicator fields of legacy events
+	var redacted []string
+	for _, p := range t.remove {
+		values, err := remove(event, p)
+		if err != nil {
+			return false, errors.WithMessagef(err, "failed to remove %q", p)
+		}
+		redacted = append(redacted, values...)
+	}
+	for _, p := range t.hash {
+		err := rewrite(event, p, func(s string) string {
+			redacted = append(redacted, s)
+			h := t.newHash()
+			_, _ = h.Write([]byte(s))
+			return hex.EncodeToString(h.Sum(nil))
+		})
+		if err != nil {
+			return false, errors.WithMessagef(err, "failed to hash %q", p)
+		}
+	}
+	for _, p := range t.mask {
+		err := rewrite(event, p, func(s string) string {
+			redacted = append(redacted, s)
+			return MaskValue
+		})
+		if err != nil {
+			return false, errors.WithMessagef(err, "failed to mask %q", p)
+		}
+	}
+	if len(redacted) > 0 {
+		scrubIndicators(event, redacted)
+	}
+	return true, nil
+}
+
+// Set is the transforms of a source by log type
+type Set map[string]*Transform
+
+// Compile compiles the transform configurations of a source.
+// It returns a nil Set if there are no transforms.
+func Compile(configs []Config) (Set, error) {
+	if len(configs) == 0 {
+		return nil, nil
+	}
+	set := make(Set, len(configs))
+	for i := range configs {
+		config := &configs[i]
+		if _, duplicate := set[config.LogType]; duplicate {
+			return nil, errors.Errorf("duplicate transform for log type %q", config.LogType)
+		}
+		t, err := New(config)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "invalid transform for log type %q", config.LogType)
+		}
+		set[config.LogType] = t
+	}
+	return set, nil
+}
+
+// Apply transforms a result using the transform of its log type.
+// It returns false if the event should be dropped.
+func (s Set) Apply(result *pantherlog.Result) (bool, error) {
+	t, ok := s[result.PantherLogType]
+	if !ok {
+		return true, nil
+	}
+	return t.Apply(result)
+}
+
+type rule []condition
+
+func compileRules(rules []Rule) ([]rule, error) {
+	var compiled []rule
+	for _, r := range rules {
+		if len(r.Match) == 0 {
+			return nil, errors.New("rule has no conditions")
+		}
+		var conditions rule
+		for _, c := range r.Match {
+			cond, err := compileCondition(c)
+			if err != nil {
+				return nil, err
+			}
+			conditions = append(conditions, cond)
+		}
+		compiled = append(compiled, conditions)
+	}
+	return compiled, nil
+}
+
+func (r rule) match(event interface{}) (bool, error) {
+	for _, c := range r {
+		value, ok, err := read(event, c.field)
+		if err != nil {
+			return false, errors.WithMessagef(err, "failed to read %q", c.field)
+		}
+		if !c.test(value, ok) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+type condition struct {
+	field path
+	test  func(value string, ok bool) bool
+}
+
+func compileCondition(c Condition) (condition, error) {
+	field, err := parsePath(c.Field)
+	if err != nil {
+		return condition{}, err
+	}
+	operand := c.Value
+	var test func(value string, ok bool) bool
+	switch c.Op {
+	case OpEquals:
+		test = func(value string, ok bool) bool {
+			return ok && value == operand
+		}
+	case OpNotEquals:
+		test = func(value string, ok bool) bool {
+			return !ok || value != operand
+		}
+	case OpIn:
+		if len(c.Values) == 0 {
+			return condition{}, errors.Errorf("operator %q requires values", c.Op)
+		}
+		values := make(map[string]struct{}, len(c.Values))
+		for _, v := range c.Values {
+			values[v] = struct{}{}
+		}
+		test = func(value string, ok bool) bool {
+			_, in := values[value]
+			return ok && in
+		}
+	case OpPrefix:
+		test = func(value string, ok bool) bool {
+			return ok && strings.HasPrefix(value, operand)
+		}
+	case OpSuffix:
+		test = func(value string, ok bool) bool {
+			return ok && strings.HasSuffix(value, operand)
+		}
+	case OpContains:
+		test = func(value string, ok bool) bool {
+			return ok && strings.Contains(value, operand)
+		}
+	case OpRegex:
+		re, err := regexp.Compile(operand)
+		if err != nil {
+			return condition{}, errors.Wrapf(err, "invalid regular expression for %q", c.Field)
+		}
+		test = func(value string, ok bool) bool {
+			return ok && re.MatchString(value)
+		}
+	case OpExists:
+		test = func(_ string, ok bool) bool {
+			return ok
+		}
+	case OpMissing:
+		test = func(_ string, ok bool) bool {
+			return !ok
+		}
+	default:
+		return condition{}, errors.Errorf("invalid operator %q", c.Op)
+	}
+	return condition{
+		field: field,
+		test:  test,
+	}, nil
+}
+
+// legacyEvent is implemented by events that embed parsers.PantherLog
+type legacyEvent interface {
+	Log() *parsers.PantherLog
+}
+
+// scrubIndicators removes redacted values from the indicator fields of legacy events.
+// Legacy events collect indicator values when they are parsed, new events collect them when they are serialized.
+func scrubIndicators(event interface{}, values []string) {
+	e, ok := event.(legacyEvent)
+	if !ok {
+		return
+	}
+	pl := e.Log()
+	for _, any := range []*parsers.PantherAnyString{
+		&pl.PantherAnyIPAddresses,
+		&pl.PantherAnyDomainNames,
+		&pl.PantherAnySHA1Hashes,
+		&pl.PantherAnyMD5Hashes,
+		&pl.PantherAnySHA256Hashes,
+	} {
+		*any = scrubValues(*any, values)
+	}
+}
+
+func scrubValues(any parsers.PantherAnyString, values []string) parsers.PantherAnyString {
+	if len(any) == 0 {
+		return any
+	}
+	scrubbed := any[:0]
+nextValue:
+	for _, v := range any {
+		for _, redacted := range values {
+			if v == redacted {
+				continue nextValue
+			}
+		}
+		scrubbed = append(scrubbed, v)
+	}
+	return scrubbed
+}
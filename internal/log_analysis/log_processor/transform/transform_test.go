@@ -0,0 +1,278 @@
+package transform
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/pantherlog/null"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/pkg/box"
+)
+
+type testIdentity struct {
+	Email  pantherlog.String `json:"email"`
+	UserID *string           `json:"userId,omitempty"`
+}
+
+type testEvent struct {
+	EventName  string               `json:"eventName"`
+	SourceIP   pantherlog.String    `json:"sourceIPAddress"`
+	Identity   *testIdentity        `json:"userIdentity,omitempty"`
+	Params     *jsoniter.RawMessage `json:"requestParameters,omitempty"`
+	Tags       map[string]string    `json:"tags,omitempty"`
+	Recipients []string             `json:"recipients,omitempty"`
+	Bytes      pantherlog.Int64     `json:"bytes"`
+	Time       time.Time            `json:"time"`
+}
+
+func testResult(event interface{}) *pantherlog.Result {
+	return &pantherlog.Result{
+		CoreFields: pantherlog.CoreFields{
+			PantherLogType: "Test.Event",
+		},
+		Event: event,
+	}
+}
+
+func newTestEvent() *testEvent {
+	params := jsoniter.RawMessage(`{"bucketName":"secret-bucket","keys":["a","b"],"count":2}`)
+	return &testEvent{
+		EventName: "DescribeInstances",
+		SourceIP:  null.FromString("1.2.3.4"),
+		Identity: &testIdentity{
+			Email:  null.FromString("alice@example.com"),
+			UserID: box.String("alice"),
+		},
+		Params:     &params,
+		Tags:       map[string]string{"owner": "bob"},
+		Recipients: []string{"bob@example.com", "carol@example.com"},
+		Bytes:      pantherlog.Int64{Value: 42, Exists: true},
+		Time:       time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func sha256Hex(s string) string {
+	h := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(h[:])
+}
+
+func TestConditions(t *testing.T) {
+	for _, tc := range []struct {
+		Condition Condition
+		Match     bool
+	}{
+		{Condition{Field: "eventName", Op: OpEquals, Value: "DescribeInstances"}, true},
+		{Condition{Field: "eventName", Op: OpNotEquals, Value: "DescribeInstances"}, false},
+		{Condition{Field: "eventName", Op: OpPrefix, Value: "Describe"}, true},
+		{Condition{Field: "eventName", Op: OpSuffix, Value: "Instances"}, true},
+		{Condition{Field: "eventName", Op: OpContains, Value: "Instance"}, true},
+		{Condition{Field: "eventName", Op: OpRegex, Value: `^(Describe|List)`}, true},
+		{Condition{Field: "eventName", Op: OpIn, Values: []string{"Foo", "DescribeInstances"}}, true},
+		{Condition{Field: "sourceIPAddress", Op: OpEquals, Value: "1.2.3.4"}, true},
+		{Condition{Field: "userIdentity.email", Op: OpSuffix, Value: "@example.com"}, true},
+		{Condition{Field: "userIdentity.userId", Op: OpEquals, Value: "alice"}, true},
+		{Condition{Field: "requestParameters.bucketName", Op: OpEquals, Value: "secret-bucket"}, true},
+		{Condition{Field: "requestParameters.keys.1", Op: OpEquals, Value: "b"}, true},
+		{Condition{Field: "requestParameters.count", Op: OpEquals, Value: "2"}, true},
+		{Condition{Field: "requestParameters.missing", Op: OpMissing}, true},
+		{Condition{Field: "tags.owner", Op: OpEquals, Value: "bob"}, true},
+		{Condition{Field: "tags.other", Op: OpExists}, false},
+		{Condition{Field: "tags.other", Op: OpNotEquals, Value: "bob"}, true},
+		{Condition{Field: "bytes", Op: OpEquals, Value: "42"}, true},
+		{Condition{Field: "time", Op: OpPrefix, Value: "2020-10-01T12:00:00"}, true},
+		{Condition{Field: "recipients", Op: OpContains, Value: "carol@example.com"}, true},
+	} {
+		tc := tc
+		t.Run(tc.Condition.Field+" "+tc.Condition.Op, func(t *testing.T) {
+			tf, err := New(&Config{
+				LogType: "Test.Event",
+				Drop:    []Rule{{Match: []Condition{tc.Condition}}},
+			})
+			require.NoError(t, err)
+			keep, err := tf.Apply(testResult(newTestEvent()))
+			require.NoError(t, err)
+			require.Equal(t, tc.Match, !keep)
+		})
+	}
+}
+
+func TestRules(t *testing.T) {
+	assert := require.New(t)
+	// Drop rejected traffic from known scanners
+	set, err := Compile([]Config{{
+		LogType: "Test.Event",
+		Drop: []Rule{{
+			Match: []Condition{
+				{Field: "eventName", Op: OpEquals, Value: "REJECT"},
+				{Field: "sourceIPAddress", Op: OpIn, Values: []string{"1.2.3.4", "5.6.7.8"}},
+			},
+		}},
+	}})
+	assert.NoError(err)
+	event := newTestEvent()
+	keep, err := set.Apply(testResult(event))
+	assert.NoError(err)
+	assert.True(keep)
+	event.EventName = "REJECT"
+	keep, err = set.Apply(testResult(event))
+	assert.NoError(err)
+	assert.False(keep)
+
+	// Other log types are not affected
+	result := testResult(event)
+	result.PantherLogType = "Other.Event"
+	keep, err = set.Apply(result)
+	assert.NoError(err)
+	assert.True(keep)
+
+	tf, err := New(&Config{
+		LogType: "Test.Event",
+		Keep: []Rule{
+			{Match: []Condition{{Field: "eventName", Op: OpPrefix, Value: "Create"}}},
+			{Match: []Condition{{Field: "eventName", Op: OpPrefix, Value: "Delete"}}},
+		},
+	})
+	assert.NoError(err)
+	keep, err = tf.Apply(testResult(newTestEvent()))
+	assert.NoError(err)
+	assert.False(keep)
+	event = newTestEvent()
+	event.EventName = "DeleteBucket"
+	keep, err = tf.Apply(testResult(event))
+	assert.NoError(err)
+	assert.True(keep)
+}
+
+func TestRedact(t *testing.T) {
+	assert := require.New(t)
+	tf, err := New(&Config{
+		LogType: "Test.Event",
+		Mask:    []string{"userIdentity.email", "requestParameters.bucketName", "tags.owner", "recipients"},
+		Hash:    []string{"sourceIPAddress", "userIdentity.userId", "requestParameters.keys.0"},
+		Remove:  []string{"bytes", "requestParameters.count"},
+	})
+	assert.NoError(err)
+	event := newTestEvent()
+	keep, err := tf.Apply(testResult(event))
+	assert.NoError(err)
+	assert.True(keep)
+	assert.Equal(null.FromString(MaskValue), event.Identity.Email)
+	assert.Equal(null.FromString(sha256Hex("1.2.3.4")), event.SourceIP)
+	assert.Equal(sha256Hex("alice"), *event.Identity.UserID)
+	assert.Equal(map[string]string{"owner": MaskValue}, event.Tags)
+	assert.Equal([]string{MaskValue, MaskValue}, event.Recipients)
+	assert.Equal(pantherlog.Int64{}, event.Bytes)
+	assert.JSONEq(`{"bucketName":"****","keys":["`+sha256Hex("a")+`","b"]}`, string(*event.Params))
+
+	// Missing values are left as is
+	event = &testEvent{}
+	keep, err = tf.Apply(testResult(event))
+	assert.NoError(err)
+	assert.True(keep)
+	assert.Equal(&testEvent{}, event)
+
+	// Keyed hashes
+	tf, err = New(&Config{
+		LogType: "Test.Event",
+		Hash:    []string{"eventName"},
+		HashKey: "secret",
+	})
+	assert.NoError(err)
+	event = newTestEvent()
+	_, err = tf.Apply(testResult(event))
+	assert.NoError(err)
+	assert.Len(event.EventName, 64)
+	assert.NotEqual(sha256Hex("DescribeInstances"), event.EventName)
+
+	// Only strings can be masked
+	tf, err = New(&Config{
+		LogType: "Test.Event",
+		Mask:    []string{"bytes"},
+	})
+	assert.NoError(err)
+	_, err = tf.Apply(testResult(newTestEvent()))
+	assert.Error(err)
+
+	// Unknown fields are errors
+	tf, err = New(&Config{
+		LogType: "Test.Event",
+		Remove:  []string{"userIdentity.foo"},
+	})
+	assert.NoError(err)
+	_, err = tf.Apply(testResult(newTestEvent()))
+	assert.Error(err)
+}
+
+type testLegacyEvent struct {
+	ClientIP *string `json:"clientIp,omitempty"`
+	Host     *string `json:"host,omitempty"`
+
+	parsers.PantherLog
+}
+
+func TestRedactLegacyIndicators(t *testing.T) {
+	assert := require.New(t)
+	event := &testLegacyEvent{
+		ClientIP: box.String("10.0.0.1"),
+		Host:     box.String("10.0.0.2"),
+	}
+	event.SetCoreFields("Test.Event", nil, event)
+	event.AppendAnyIPAddressPtr(event.ClientIP)
+	event.AppendAnyIPAddressPtr(event.Host)
+	tf, err := New(&Config{
+		LogType: "Test.Event",
+		Mask:    []string{"clientIp"},
+	})
+	assert.NoError(err)
+	keep, err := tf.Apply(event.Result())
+	assert.NoError(err)
+	assert.True(keep)
+	assert.Equal(MaskValue, *event.ClientIP)
+	assert.Equal(parsers.PantherAnyString{"10.0.0.2"}, event.PantherAnyIPAddresses)
+}
+
+func TestCompile(t *testing.T) {
+	assert := require.New(t)
+	set, err := Compile(nil)
+	assert.NoError(err)
+	assert.Nil(set)
+
+	for _, config := range []Config{
+		{},
+		{LogType: "Test.Event", Mask: []string{""}},
+		{LogType: "Test.Event", Hash: []string{"foo..bar"}},
+		{LogType: "Test.Event", Drop: []Rule{{}}},
+		{LogType: "Test.Event", Drop: []Rule{{Match: []Condition{{Field: "foo", Op: "like"}}}}},
+		{LogType: "Test.Event", Keep: []Rule{{Match: []Condition{{Field: "foo", Op: OpRegex, Value: "("}}}}},
+		{LogType: "Test.Event", Keep: []Rule{{Match: []Condition{{Field: "foo", Op: OpIn}}}}},
+	} {
+		config := config
+		assert.Error(config.Validate(), "%+v", config)
+	}
+	assert.Error(Validate([]Config{{LogType: "Test.Event"}, {LogType: "Test.Event"}}))
+	assert.NoError(Validate([]Config{{LogType: "Test.Event"}, {LogType: "Other.Event"}}))
+}
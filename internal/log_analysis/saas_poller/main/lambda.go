@@ -0,0 +1,141 @@
+package main
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	awslambda "github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/kelseyhightower/envconfig"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+
+	"github.com/panther-labs/panther/internal/core/logtypesapi"
+	"github.com/panther-labs/panther/internal/core/source_api/secrets"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/destinations"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/logtypes"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/metrics"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/registry"
+	"github.com/panther-labs/panther/internal/log_analysis/saas_poller/poller"
+	"github.com/panther-labs/panther/pkg/awsretry"
+	"github.com/panther-labs/panther/pkg/lambdalogger"
+)
+
+// EnvConfig is the configuration of the SaaS poller.
+// The poller writes events to the processed data bucket just like the log processor.
+type EnvConfig struct {
+	AwsLambdaFunctionMemorySize int    `required:"true" split_words:"true"`
+	ProcessedDataBucket         string `required:"true" split_words:"true"`
+	SnsTopicARN                 string `required:"true" split_words:"true"`
+	GeoIPCityDatabase           string `envconfig:"GEOIP_CITY_DATABASE"`
+	GeoIPASNDatabase            string `envconfig:"GEOIP_ASN_DATABASE"`
+	ThreatIntelTables           string `split_words:"true"`
+	SamplingCountersTable       string `split_words:"true"`
+	MaxPages                    int    `default:"50" split_words:"true"`
+}
+
+// Timeout of each request to a vendor API
+const requestTimeout = time.Minute
+
+var scheduler *poller.Scheduler
+
+func main() {
+	setup()
+	lambda.Start(handle)
+}
+
+func setup() {
+	var env EnvConfig
+	envconfig.MustProcess("", &env)
+
+	// The processor and the destination use the log processor globals
+	common.Config.AwsLambdaFunctionMemorySize = env.AwsLambdaFunctionMemorySize
+	common.Config.ProcessedDataBucket = env.ProcessedDataBucket
+	common.Config.SnsTopicARN = env.SnsTopicARN
+	common.Config.GeoIPCityDatabase = env.GeoIPCityDatabase
+	common.Config.GeoIPASNDatabase = env.GeoIPASNDatabase
+	common.Config.ThreatIntelTables = env.ThreatIntelTables
+	common.Config.SamplingCountersTable = env.SamplingCountersTable
+	common.Session = session.Must(session.NewSession()) // use default retries for fetching creds, avoids hangs!
+	clientsSession := common.Session.Copy(request.WithRetryer(aws.NewConfig().WithMaxRetries(common.MaxRetries),
+		awsretry.NewConnectionErrRetryer(common.MaxRetries)))
+	common.LambdaClient = awslambda.New(clientsSession)
+	common.SnsClient = sns.New(clientsSession)
+	common.S3Client = s3.New(clientsSession)
+	metrics.Setup()
+	common.SetupSampling(dynamodb.New(clientsSession))
+	common.SetupGeoIP()
+	common.SetupThreatIntel()
+
+	resolver := &logtypesapi.Resolver{
+		LogTypesAPI: &logtypesapi.LogTypesAPILambdaClient{
+			LambdaName: logtypesapi.LambdaName,
+			LambdaAPI:  common.LambdaClient,
+			Validate:   validator.New().Struct,
+		},
+		NativeLogTypes: registry.NativeLogTypes(),
+	}
+	secretsCache := &secrets.Cache{
+		Client: secretsmanager.New(clientsSession),
+	}
+	scheduler = &poller.Scheduler{
+		LambdaClient: common.LambdaClient,
+		Poller: &poller.Poller{
+			Client:       &http.Client{Timeout: requestTimeout},
+			LoadSecret:   secretsCache.Get,
+			NewProcessor: processor.NewFactory(logtypes.ParserResolver(resolver)),
+			NewDestination: func() destinations.Destination {
+				return destinations.CreateS3Destination(common.ConfigForDataLakeWriters())
+			},
+			MaxPages: env.MaxPages,
+		},
+	}
+}
+
+func handle(ctx context.Context, _ events.CloudWatchEvent) (err error) {
+	lc, _ := lambdalogger.ConfigureGlobal(ctx, nil)
+	operation := common.OpLogManager.Start(lc.InvokedFunctionArn, common.OpLogLambdaServiceDim).
+		WithMemUsed(lambdacontext.MemoryLimitInMB)
+	defer func() {
+		operation.Stop().Log(err)
+	}()
+	// Pick up changes to the threat intel lookup tables
+	common.RefreshThreatIntel(ctx)
+	defer func() {
+		// Sync metrics at the end of each run
+		if err := metrics.CWManager.Sync(); err != nil {
+			zap.L().Warn("failed to sync metrics", zap.Error(err))
+		}
+	}()
+	return scheduler.Run(ctx)
+}
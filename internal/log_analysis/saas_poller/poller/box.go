@@ -0,0 +1,127 @@
+package poller
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/boxlogs"
+)
+
+const (
+	boxDefaultURL = "https://api.box.com"
+	boxPageSize   = 500
+)
+
+// boxStream pulls the Box enterprise events.
+// The cursor is the stream position returned by the API.
+// See https://developer.box.com/guides/events/for-enterprise/
+type boxStream struct {
+	api     *APIClient
+	baseURL string
+	token   *token
+}
+
+func newBoxStreams(api *APIClient, config *models.PollerConfig) ([]Stream, error) {
+	base, err := api.baseURL(config, boxDefaultURL)
+	if err != nil {
+		return nil, err
+	}
+	if config.Subject == "" {
+		return nil, errors.New("missing Box enterprise id")
+	}
+	s := &boxStream{
+		api:     api,
+		baseURL: base,
+	}
+	s.token = &token{
+		// Client credentials grant for the service account of the enterprise
+		fetch: func(ctx context.Context) (string, time.Duration, error) {
+			form := url.Values{
+				"grant_type":       []string{"client_credentials"},
+				"client_id":        []string{config.ClientID},
+				"client_secret":    []string{config.ClientSecret},
+				"box_subject_type": []string{"enterprise"},
+				"box_subject_id":   []string{config.Subject},
+			}
+			req, err := http.NewRequest(http.MethodPost, base+"/oauth2/token", strings.NewReader(form.Encode()))
+			if err != nil {
+				return "", 0, err
+			}
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			var reply tokenResponse
+			if _, err := api.Do(ctx, req, &reply); err != nil {
+				return "", 0, err
+			}
+			return reply.result()
+		},
+	}
+	return []Stream{s}, nil
+}
+
+func (s *boxStream) LogType() string {
+	return boxlogs.TypeEvent
+}
+
+func (s *boxStream) Next(ctx context.Context, cursor string) (*Page, error) {
+	accessToken, err := s.token.Get(ctx, s.api.now())
+	if err != nil {
+		return nil, err
+	}
+	params := url.Values{
+		"stream_type": []string{"admin_logs"},
+		"limit":       []string{strconv.Itoa(boxPageSize)},
+	}
+	if cursor != "" {
+		params.Set("stream_position", cursor)
+	} else {
+		params.Set("created_after", s.api.Start.Format(time.RFC3339))
+	}
+	req, err := http.NewRequest(http.MethodGet, s.baseURL+"/2.0/events?"+params.Encode(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid Box request")
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	var reply struct {
+		// The position is a number too large for float64, it can also be a string
+		NextStreamPosition json.RawMessage   `json:"next_stream_position"`
+		Entries            []json.RawMessage `json:"entries"`
+	}
+	if _, err := s.api.Do(ctx, req, &reply); err != nil {
+		return nil, err
+	}
+	next := strings.Trim(string(reply.NextStreamPosition), `"`)
+	if next == "" || next == "null" {
+		next = cursor
+	}
+	return &Page{
+		Entries: reply.Entries,
+		Cursor:  next,
+		More:    len(reply.Entries) >= boxPageSize,
+	}, nil
+}
@@ -0,0 +1,189 @@
+package poller
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/pkg/stringset"
+)
+
+// maxErrorBody limits the part of an error response included in errors
+const maxErrorBody = 512
+
+// newStreamsFunc builds the streams of a vendor for the log types of a source
+type newStreamsFunc func(api *APIClient, config *models.PollerConfig) ([]Stream, error)
+
+var vendors = map[string]newStreamsFunc{
+	models.PollerVendorOkta:     newOktaStreams,
+	models.PollerVendorDuo:      newDuoStreams,
+	models.PollerVendorOneLogin: newOneLoginStreams,
+	models.PollerVendorBox:      newBoxStreams,
+	models.PollerVendorGSuite:   newGSuiteStreams,
+	models.PollerVendorSlack:    newSlackStreams,
+}
+
+// NewStreams builds a stream for each log type of a SaaS poller source
+func NewStreams(config *models.PollerConfig, api *APIClient) ([]Stream, error) {
+	newStreams, ok := vendors[config.Vendor]
+	if !ok {
+		return nil, errors.Errorf("unsupported vendor %q", config.Vendor)
+	}
+	if err := config.ValidateLogTypes(); err != nil {
+		return nil, err
+	}
+	return newStreams(api, config)
+}
+
+// APIClient holds the settings shared by all vendor API calls
+type APIClient struct {
+	Client *http.Client
+	// Start is where streams without a cursor begin
+	Start time.Time
+	// Now returns the current time
+	Now func() time.Time
+	// allowHTTP accepts base URLs without TLS, it is only set by tests that use local servers
+	allowHTTP bool
+}
+
+func (api *APIClient) now() time.Time {
+	if api.Now != nil {
+		return api.Now()
+	}
+	return time.Now()
+}
+
+// Do sends a request and decodes the JSON response
+func (api *APIClient) Do(ctx context.Context, req *http.Request, out interface{}) (http.Header, error) {
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "application/json")
+	resp, err := api.Client.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s %s failed", req.Method, req.URL.Path)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return nil, errors.Errorf("%s %s failed with status %d: %s", req.Method, req.URL.Path, resp.StatusCode,
+			strings.TrimSpace(string(body)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return nil, errors.Wrapf(err, "%s %s returned an invalid response", req.Method, req.URL.Path)
+	}
+	return resp.Header, nil
+}
+
+// token is an access token fetched for the streams of a source
+type token struct {
+	value  string
+	expiry time.Time
+	fetch  func(ctx context.Context) (value string, expiresIn time.Duration, err error)
+}
+
+// Get returns a valid access token, fetching a new one if needed
+func (t *token) Get(ctx context.Context, now time.Time) (string, error) {
+	// Leave a margin for the requests that use the token
+	if t.value != "" && now.Add(time.Minute).Before(t.expiry) {
+		return t.value, nil
+	}
+	value, expiresIn, err := t.fetch(ctx)
+	if err != nil {
+		return "", errors.WithMessage(err, "failed to get access token")
+	}
+	t.value, t.expiry = value, now.Add(expiresIn)
+	return t.value, nil
+}
+
+// tokenResponse is the response of OAuth2 token endpoints
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+func (r *tokenResponse) result() (string, time.Duration, error) {
+	if r.AccessToken == "" {
+		return "", 0, errors.New("empty access token")
+	}
+	return r.AccessToken, time.Duration(r.ExpiresIn) * time.Second, nil
+}
+
+// baseURL returns the configured base URL of a vendor API or its default.
+// Credentials are sent to the vendor API so the URL needs to use https.
+func (api *APIClient) baseURL(config *models.PollerConfig, defaultURL string) (string, error) {
+	base := config.BaseURL
+	if base == "" {
+		base = defaultURL
+	}
+	if base == "" {
+		return "", errors.Errorf("%s sources need a base URL", config.Vendor)
+	}
+	u, err := url.Parse(base)
+	if err != nil || u.Host == "" || !(u.Scheme == "https" || (api.allowHTTP && u.Scheme == "http")) {
+		return "", errors.Errorf("invalid base URL %q", base)
+	}
+	return strings.TrimSuffix(base, "/"), nil
+}
+
+// cursor holds the state of a stream as URL encoded values
+type cursor struct {
+	url.Values
+}
+
+func parseCursor(s string) (cursor, error) {
+	values, err := url.ParseQuery(s)
+	if err != nil {
+		return cursor{}, errors.Wrapf(err, "invalid cursor %q", s)
+	}
+	return cursor{Values: values}, nil
+}
+
+// Time returns a time value of the cursor in unix milliseconds
+func (c cursor) Time(key string, defaultTime time.Time) time.Time {
+	if v, err := strconv.ParseInt(c.Get(key), 10, 64); err == nil {
+		return time.Unix(0, v*int64(time.Millisecond)).UTC()
+	}
+	return defaultTime
+}
+
+// SetTime sets a time value of the cursor in unix milliseconds
+func (c cursor) SetTime(key string, tm time.Time) {
+	c.Set(key, formatMillis(tm))
+}
+
+// Keep returns a cursor with only the values of the keys
+func (c cursor) Keep(keys ...string) cursor {
+	values := url.Values{}
+	for key, v := range c.Values {
+		if stringset.Contains(keys, key) {
+			values[key] = v
+		}
+	}
+	return cursor{Values: values}
+}
@@ -0,0 +1,215 @@
+package poller
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1" // nolint: gosec
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/duologs"
+)
+
+const (
+	duoPageSize = 1000
+	// Duo logs can be delayed, the authentication logs API recommends a 2 minute delay
+	duoDelay = 2 * time.Minute
+)
+
+// duoStream pulls a log of the Duo Admin API.
+// Authentication logs use the v2 API which pages with an offset inside a fixed time window,
+// the other logs use the v1 API which returns up to 1000 events after a timestamp.
+// See https://duo.com/docs/adminapi#logs
+type duoStream struct {
+	api     *APIClient
+	baseURL string
+	host    string
+	ikey    string
+	skey    string
+	logType string
+	path    string
+}
+
+func newDuoStreams(api *APIClient, config *models.PollerConfig) ([]Stream, error) {
+	base, err := api.baseURL(config, "")
+	if err != nil {
+		return nil, err
+	}
+	u, _ := url.Parse(base)
+	if config.ClientID == "" {
+		return nil, errors.New("missing Duo integration key")
+	}
+	paths := map[string]string{
+		duologs.TypeAuthentication:    "/admin/v2/logs/authentication",
+		duologs.TypeAdministrator:     "/admin/v1/logs/administrator",
+		duologs.TypeTelephony:         "/admin/v1/logs/telephony",
+		duologs.TypeOfflineEnrollment: "/admin/v1/logs/offline_enrollment",
+	}
+	streams := make([]Stream, len(config.LogTypes))
+	for i, logType := range config.LogTypes {
+		streams[i] = &duoStream{
+			api:     api,
+			baseURL: base,
+			host:    strings.ToLower(u.Host),
+			ikey:    config.ClientID,
+			skey:    config.ClientSecret,
+			logType: logType,
+			path:    paths[logType],
+		}
+	}
+	return streams, nil
+}
+
+func (s *duoStream) LogType() string {
+	return s.logType
+}
+
+func (s *duoStream) Next(ctx context.Context, cur string) (*Page, error) {
+	c, err := parseCursor(cur)
+	if err != nil {
+		return nil, err
+	}
+	if s.logType == duologs.TypeAuthentication {
+		return s.nextV2(ctx, c)
+	}
+	return s.nextV1(ctx, c)
+}
+
+// nextV2 pulls authentication logs in [mintime, maxtime] (in milliseconds)
+func (s *duoStream) nextV2(ctx context.Context, c cursor) (*Page, error) {
+	minTime := c.Time("mintime", s.api.Start)
+	maxTime := c.Time("maxtime", s.api.now().Add(-duoDelay))
+	if !maxTime.After(minTime) {
+		return &Page{Cursor: c.Encode()}, nil
+	}
+	params := url.Values{
+		"limit": []string{strconv.Itoa(duoPageSize)},
+		"sort":  []string{"ts:asc"},
+	}
+	params.Set("mintime", formatMillis(minTime))
+	params.Set("maxtime", formatMillis(maxTime))
+	if offset := c.Get("next_offset"); offset != "" {
+		params.Set("next_offset", offset)
+	}
+	var reply struct {
+		Stat     string `json:"stat"`
+		Response struct {
+			AuthLogs []json.RawMessage `json:"authlogs"`
+			Metadata struct {
+				NextOffset []string `json:"next_offset"`
+			} `json:"metadata"`
+		} `json:"response"`
+	}
+	if err := s.get(ctx, params, &reply); err != nil {
+		return nil, err
+	}
+	if reply.Stat != "OK" {
+		return nil, errors.Errorf("Duo API returned status %q", reply.Stat)
+	}
+	next := cursor{Values: url.Values{}}
+	if offset := reply.Response.Metadata.NextOffset; len(offset) > 0 {
+		// Keep the time window until all pages are pulled
+		next.SetTime("mintime", minTime)
+		next.SetTime("maxtime", maxTime)
+		next.Set("next_offset", strings.Join(offset, ","))
+		return &Page{Entries: reply.Response.AuthLogs, Cursor: next.Encode(), More: true}, nil
+	}
+	next.SetTime("mintime", maxTime.Add(time.Millisecond))
+	return &Page{Entries: reply.Response.AuthLogs, Cursor: next.Encode()}, nil
+}
+
+// nextV1 pulls logs after mintime (in seconds)
+func (s *duoStream) nextV1(ctx context.Context, c cursor) (*Page, error) {
+	minTime := c.Time("mintime", s.api.Start)
+	params := url.Values{
+		"mintime": []string{strconv.FormatInt(minTime.Unix(), 10)},
+	}
+	var reply struct {
+		Stat     string            `json:"stat"`
+		Response []json.RawMessage `json:"response"`
+	}
+	if err := s.get(ctx, params, &reply); err != nil {
+		return nil, err
+	}
+	if reply.Stat != "OK" {
+		return nil, errors.Errorf("Duo API returned status %q", reply.Stat)
+	}
+	last := minTime.Unix() - 1
+	for _, entry := range reply.Response {
+		if ts := jsoniter.Get(entry, "timestamp").ToInt64(); ts > last {
+			last = ts
+		}
+	}
+	next := cursor{Values: url.Values{}}
+	next.SetTime("mintime", time.Unix(last+1, 0))
+	return &Page{
+		Entries: reply.Response,
+		Cursor:  next.Encode(),
+		More:    len(reply.Response) >= duoPageSize,
+	}, nil
+}
+
+func (s *duoStream) get(ctx context.Context, params url.Values, out interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, s.baseURL+s.path+"?"+duoEncode(params), nil)
+	if err != nil {
+		return errors.Wrap(err, "invalid Duo request")
+	}
+	date := s.api.now().UTC().Format(time.RFC1123Z)
+	signature := duoSign(s.skey, date, http.MethodGet, s.host, s.path, params)
+	req.Header.Set("Date", date)
+	req.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(s.ikey+":"+signature)))
+	_, err = s.api.Do(ctx, req, out)
+	return err
+}
+
+// duoSign signs a request of the Duo Admin API
+// See https://duo.com/docs/adminapi#authentication
+func duoSign(skey, date, method, host, path string, params url.Values) string {
+	canonical := strings.Join([]string{
+		date,
+		strings.ToUpper(method),
+		strings.ToLower(host),
+		path,
+		duoEncode(params),
+	}, "\n")
+	mac := hmac.New(sha1.New, []byte(skey))
+	_, _ = mac.Write([]byte(canonical))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// duoEncode encodes sorted parameters using RFC 3986 escaping
+func duoEncode(params url.Values) string {
+	return strings.ReplaceAll(params.Encode(), "+", "%20")
+}
+
+func formatMillis(tm time.Time) string {
+	return strconv.FormatInt(tm.UnixNano()/int64(time.Millisecond), 10)
+}
@@ -0,0 +1,242 @@
+package poller
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/gsuitelogs"
+)
+
+const (
+	gsuiteDefaultURL = "https://admin.googleapis.com"
+	gsuiteScope      = "https://www.googleapis.com/auth/admin.reports.audit.readonly"
+)
+
+// gsuiteApplications are the applications with activity reports
+var gsuiteApplications = []string{
+	"admin",
+	"login",
+	"token",
+	"saml",
+	"user_accounts",
+	"groups",
+	"mobile",
+	"drive",
+}
+
+// gsuiteStream pulls the activity reports of all G Suite applications.
+// Activities are returned newest first, so the stream pages through all activities after the cursor time
+// of each application before moving it to the newest activity.
+// The stream authenticates with a service account key and domain-wide delegation for an admin user.
+// See https://developers.google.com/admin-sdk/reports/v1/guides/manage-audit-login
+type gsuiteStream struct {
+	api     *APIClient
+	baseURL string
+	token   *token
+}
+
+// gsuiteServiceAccount is the JSON key of a service account
+type gsuiteServiceAccount struct {
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+func newGSuiteStreams(api *APIClient, config *models.PollerConfig) ([]Stream, error) {
+	base, err := api.baseURL(config, gsuiteDefaultURL)
+	if err != nil {
+		return nil, err
+	}
+	var account gsuiteServiceAccount
+	if err := jsoniter.UnmarshalFromString(config.ClientSecret, &account); err != nil {
+		return nil, errors.Wrap(err, "invalid service account key")
+	}
+	key, err := parseRSAKey(account.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	if account.ClientEmail == "" || account.TokenURI == "" {
+		return nil, errors.New("incomplete service account key")
+	}
+	if config.Subject == "" {
+		return nil, errors.New("missing G Suite admin user")
+	}
+	s := &gsuiteStream{
+		api:     api,
+		baseURL: base,
+	}
+	s.token = &token{
+		// JWT bearer grant, see https://developers.google.com/identity/protocols/oauth2/service-account#httprest
+		fetch: func(ctx context.Context) (string, time.Duration, error) {
+			assertion, err := signJWT(key, map[string]interface{}{
+				"iss":   account.ClientEmail,
+				"sub":   config.Subject,
+				"scope": gsuiteScope,
+				"aud":   account.TokenURI,
+				"iat":   api.now().Unix(),
+				"exp":   api.now().Add(time.Hour).Unix(),
+			})
+			if err != nil {
+				return "", 0, err
+			}
+			form := url.Values{
+				"grant_type": []string{"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+				"assertion":  []string{assertion},
+			}
+			req, err := http.NewRequest(http.MethodPost, account.TokenURI, strings.NewReader(form.Encode()))
+			if err != nil {
+				return "", 0, err
+			}
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			var reply tokenResponse
+			if _, err := api.Do(ctx, req, &reply); err != nil {
+				return "", 0, err
+			}
+			return reply.result()
+		},
+	}
+	return []Stream{s}, nil
+}
+
+func (s *gsuiteStream) LogType() string {
+	return gsuitelogs.TypeReports
+}
+
+// Next pulls a page of each application.
+// The cursor holds the time, the newest activity and the page token of each application.
+func (s *gsuiteStream) Next(ctx context.Context, cur string) (*Page, error) {
+	c, err := parseCursor(cur)
+	if err != nil {
+		return nil, err
+	}
+	accessToken, err := s.token.Get(ctx, s.api.now())
+	if err != nil {
+		return nil, err
+	}
+	page := &Page{}
+	next := cursor{Values: url.Values{}}
+	for _, app := range gsuiteApplications {
+		since := c.Time(app+".since", s.api.Start)
+		newest := c.Time(app+".newest", since)
+		pageToken := c.Get(app + ".page")
+		params := url.Values{
+			"startTime":  []string{since.Format(time.RFC3339Nano)},
+			"maxResults": []string{"1000"},
+		}
+		if pageToken != "" {
+			params.Set("pageToken", pageToken)
+		}
+		path := "/admin/reports/v1/activity/users/all/applications/" + app
+		req, err := http.NewRequest(http.MethodGet, s.baseURL+path+"?"+params.Encode(), nil)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid G Suite request")
+		}
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+		var reply struct {
+			Items         []json.RawMessage `json:"items"`
+			NextPageToken string            `json:"nextPageToken"`
+		}
+		if _, err := s.api.Do(ctx, req, &reply); err != nil {
+			return nil, errors.WithMessagef(err, "failed to pull %s activities", app)
+		}
+		for _, item := range reply.Items {
+			tm, err := time.Parse(time.RFC3339Nano, jsoniter.Get(item, "id", "time").ToString())
+			if err == nil && tm.After(newest) {
+				newest = tm
+			}
+		}
+		page.Entries = append(page.Entries, reply.Items...)
+		if reply.NextPageToken != "" {
+			next.SetTime(app+".since", since)
+			next.SetTime(app+".newest", newest)
+			next.Set(app+".page", reply.NextPageToken)
+			page.More = true
+			continue
+		}
+		if newest.After(since) {
+			// The start time is inclusive
+			newest = newest.Add(time.Millisecond)
+		}
+		next.SetTime(app+".since", newest)
+	}
+	page.Cursor = next.Encode()
+	return page, nil
+}
+
+func parseRSAKey(data string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("invalid service account private key")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid service account private key")
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("service account private key is not an RSA key")
+	}
+	return key, nil
+}
+
+// signJWT creates a JWT signed with RS256
+func signJWT(key *rsa.PrivateKey, claims map[string]interface{}) (string, error) {
+	encode := func(v interface{}) (string, error) {
+		data, err := jsoniter.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return base64.RawURLEncoding.EncodeToString(data), nil
+	}
+	header, err := encode(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	payload, err := encode(claims)
+	if err != nil {
+		return "", err
+	}
+	signed := header + "." + payload
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", errors.Wrap(err, "failed to sign JWT")
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
@@ -0,0 +1,113 @@
+package poller
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/oktalogs"
+)
+
+const oktaPageSize = "1000"
+
+// oktaStream pulls the Okta System Log.
+// The cursor is the next link of the last page, Okta returns a next link even when there are no more events
+// so that clients can keep polling.
+// See https://developer.okta.com/docs/reference/api/system-log/#polling-requests
+type oktaStream struct {
+	api     *APIClient
+	baseURL string
+	token   string
+}
+
+func newOktaStreams(api *APIClient, config *models.PollerConfig) ([]Stream, error) {
+	base, err := api.baseURL(config, "")
+	if err != nil {
+		return nil, err
+	}
+	return []Stream{&oktaStream{
+		api:     api,
+		baseURL: base,
+		token:   config.ClientSecret,
+	}}, nil
+}
+
+func (s *oktaStream) LogType() string {
+	return oktalogs.TypeSystemLog
+}
+
+func (s *oktaStream) Next(ctx context.Context, cursor string) (*Page, error) {
+	pageURL := cursor
+	if pageURL == "" {
+		query := url.Values{
+			"since":     []string{s.api.Start.Format(time.RFC3339)},
+			"limit":     []string{oktaPageSize},
+			"sortOrder": []string{"ASCENDING"},
+		}
+		pageURL = s.baseURL + "/api/v1/logs?" + query.Encode()
+	}
+	// Do not send the API token anywhere else
+	if !strings.HasPrefix(pageURL, s.baseURL+"/") {
+		return nil, errors.Errorf("invalid Okta cursor %q", cursor)
+	}
+	req, err := http.NewRequest(http.MethodGet, pageURL, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid Okta request")
+	}
+	req.Header.Set("Authorization", "SSWS "+s.token)
+	var events []json.RawMessage
+	header, err := s.api.Do(ctx, req, &events)
+	if err != nil {
+		return nil, err
+	}
+	next := linkURL(header, "next")
+	if next == "" {
+		next = pageURL
+	}
+	return &Page{
+		Entries: events,
+		Cursor:  next,
+		More:    len(events) > 0 && next != pageURL,
+	}, nil
+}
+
+// linkURL finds the URL of a relation in the Link headers of a response
+func linkURL(header http.Header, rel string) string {
+	for _, value := range header.Values("Link") {
+		for _, link := range strings.Split(value, ",") {
+			parts := strings.Split(link, ";")
+			target := strings.Trim(strings.TrimSpace(parts[0]), "<>")
+			for _, param := range parts[1:] {
+				param = strings.ReplaceAll(strings.TrimSpace(param), `"`, "")
+				if param == "rel="+rel {
+					return target
+				}
+			}
+		}
+	}
+	return ""
+}
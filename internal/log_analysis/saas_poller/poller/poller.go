@@ -0,0 +1,219 @@
+package poller
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/common"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/destinations"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor/logstream"
+)
+
+const (
+	// DefaultMaxPages is the default limit of pages pulled for each log type in a single poll
+	DefaultMaxPages = 50
+	// DefaultLookback is how far back the first poll of a source starts by default
+	DefaultLookback = 24 * time.Hour
+)
+
+// Page is a batch of events pulled from a SaaS API
+type Page struct {
+	// Entries are the raw JSON events
+	Entries []json.RawMessage
+	// Cursor is the position to resume from after this page
+	Cursor string
+	// More is set if more events can be pulled right away
+	More bool
+}
+
+// Stream pulls the events of a log type from a SaaS API
+type Stream interface {
+	// LogType is the log type of the events
+	LogType() string
+	// Next pulls the events after a cursor.
+	// An empty cursor starts from the time the source was first polled.
+	Next(ctx context.Context, cursor string) (*Page, error)
+}
+
+// Poller pulls the events of SaaS poller sources and processes them like the log processor does.
+type Poller struct {
+	// Client is the HTTP client for the vendor APIs
+	Client *http.Client
+	// LoadSecret loads the client secret of a source by id
+	LoadSecret func(ctx context.Context, id string) (string, error)
+	// NewProcessor builds the processor for the events of a log type
+	NewProcessor processor.Factory
+	// NewDestination builds the destination for the events of a log type
+	NewDestination func() destinations.Destination
+	// MaxPages limits the pages pulled for each log type in a single poll
+	MaxPages int
+	// Lookback is how far back the first poll of a source starts
+	Lookback time.Duration
+	// Now returns the current time
+	Now func() time.Time
+	// allowHTTP accepts base URLs without TLS, it is only set by tests that use local servers
+	allowHTTP bool
+}
+
+// Poll pulls new events for all log types of a source and returns the checkpoint to store on the source.
+// The cursor of a log type only advances after its events have been written to the destination,
+// so a failed poll will pull the same events again.
+func (p *Poller) Poll(ctx context.Context, src *models.SourceIntegration) (*models.UpdatePollerCheckpointInput, error) {
+	now := p.now()
+	checkpoint := &models.UpdatePollerCheckpointInput{
+		IntegrationID: src.IntegrationID,
+		Cursors:       map[string]string{},
+		LastPollTime:  now,
+	}
+	for logType, cursor := range src.PollerConfig.Cursors {
+		checkpoint.Cursors[logType] = cursor
+	}
+	secret, err := p.LoadSecret(ctx, src.IntegrationID)
+	if err != nil {
+		err = errors.WithMessage(err, "failed to load client secret")
+		checkpoint.LastPollError = err.Error()
+		return checkpoint, err
+	}
+	// The secret is only set on the configuration of the streams
+	config := *src.PollerConfig
+	config.ClientSecret = secret
+	streams, err := NewStreams(&config, &APIClient{
+		Client:    p.client(),
+		Start:     now.Add(-p.lookback()),
+		Now:       p.now,
+		allowHTTP: p.allowHTTP,
+	})
+	if err != nil {
+		checkpoint.LastPollError = err.Error()
+		return checkpoint, err
+	}
+	var pollErr error
+	for _, stream := range streams {
+		logType := stream.LogType()
+		cursor, err := p.pollStream(ctx, src, stream, checkpoint.Cursors[logType])
+		checkpoint.Cursors[logType] = cursor
+		if err != nil {
+			zap.L().Warn("failed to poll log type",
+				zap.String("sourceId", src.IntegrationID),
+				zap.String("logType", logType),
+				zap.Error(err))
+			if pollErr == nil {
+				pollErr = errors.WithMessagef(err, "failed to poll %s", logType)
+			}
+		}
+	}
+	if pollErr != nil {
+		checkpoint.LastPollError = pollErr.Error()
+	}
+	return checkpoint, pollErr
+}
+
+// pollStream pulls the pages of a log type and returns the cursor of the last processed page
+func (p *Poller) pollStream(ctx context.Context, src *models.SourceIntegration, stream Stream, cursor string) (string, error) {
+	var (
+		entries []json.RawMessage
+		next    = cursor
+		pullErr error
+	)
+	for i := 0; i < p.maxPages(); i++ {
+		page, err := stream.Next(ctx, next)
+		if err != nil {
+			// Keep the events of the pages pulled so far
+			pullErr = err
+			break
+		}
+		entries = append(entries, page.Entries...)
+		next = page.Cursor
+		if !page.More {
+			break
+		}
+	}
+	if len(entries) == 0 {
+		return next, pullErr
+	}
+	if err := p.process(ctx, src, stream.LogType(), entries); err != nil {
+		// The events will be pulled again on the next poll
+		return cursor, err
+	}
+	return next, pullErr
+}
+
+// process writes the events of a log type to the destination
+func (p *Poller) process(ctx context.Context, src *models.SourceIntegration, logType string, entries []json.RawMessage) error {
+	buf := bytes.Buffer{}
+	for _, entry := range entries {
+		// Each event needs to be on a single line
+		if err := json.Compact(&buf, entry); err != nil {
+			return errors.Wrapf(err, "invalid %s event", logType)
+		}
+		buf.WriteByte('\n')
+	}
+	// The events of a stream are classified only with its own log type
+	streamSource := *src
+	config := *src.PollerConfig
+	config.LogTypes = []string{logType}
+	streamSource.PollerConfig = &config
+
+	streams := make(chan *common.DataStream, 1)
+	streams <- &common.DataStream{
+		Stream:       logstream.NewLineStream(&buf, logstream.DefaultBufferSize),
+		Source:       &streamSource,
+		S3ObjectSize: int64(buf.Len()),
+	}
+	close(streams)
+	return processor.Process(ctx, streams, p.NewDestination(), p.NewProcessor)
+}
+
+func (p *Poller) client() *http.Client {
+	if p.Client != nil {
+		return p.Client
+	}
+	return http.DefaultClient
+}
+
+func (p *Poller) now() time.Time {
+	if p.Now != nil {
+		return p.Now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+func (p *Poller) maxPages() int {
+	if p.MaxPages > 0 {
+		return p.MaxPages
+	}
+	return DefaultMaxPages
+}
+
+func (p *Poller) lookback() time.Duration {
+	if p.Lookback > 0 {
+		return p.Lookback
+	}
+	return DefaultLookback
+}
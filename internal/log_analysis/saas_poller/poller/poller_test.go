@@ -0,0 +1,219 @@
+package poller
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/destinations"
+	logmetrics "github.com/panther-labs/panther/internal/log_analysis/log_processor/metrics"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/processor"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/registry"
+)
+
+var testNow = time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC)
+
+func TestMain(m *testing.M) {
+	// Counters are only written when synced
+	logmetrics.Setup()
+	os.Exit(m.Run())
+}
+
+type testDestination struct {
+	events []*parsers.Result
+	err    error
+}
+
+var _ destinations.Destination = (*testDestination)(nil)
+
+func (d *testDestination) SendEvents(results chan *parsers.Result, errors chan error) {
+	for result := range results {
+		d.events = append(d.events, result)
+	}
+	if d.err != nil {
+		errors <- d.err
+	}
+}
+
+func testPoller(dest *testDestination) *Poller {
+	return &Poller{
+		LoadSecret: func(_ context.Context, _ string) (string, error) {
+			return "token", nil
+		},
+		NewProcessor: processor.NewFactory(registry.NativeParsersResolver()),
+		NewDestination: func() destinations.Destination {
+			return dest
+		},
+		MaxPages: 3,
+		Now: func() time.Time {
+			return testNow
+		},
+		allowHTTP: true,
+	}
+}
+
+func oktaEvent(id string) string {
+	return fmt.Sprintf(`{"uuid":%q,"published":"2020-10-01T11:00:00Z","eventType":"user.session.start","version":"0","severity":"INFO"}`, id)
+}
+
+// testStream returns pages of events with the page number as cursor
+type testStream struct {
+	pages [][]json.RawMessage
+	err   error
+}
+
+func (s *testStream) LogType() string {
+	return "Okta.SystemLog"
+}
+
+func (s *testStream) Next(_ context.Context, cursor string) (*Page, error) {
+	n := 0
+	if cursor != "" {
+		_, _ = fmt.Sscanf(cursor, "%d", &n)
+	}
+	if n >= len(s.pages) {
+		if s.err != nil {
+			return nil, s.err
+		}
+		return &Page{Cursor: cursor}, nil
+	}
+	return &Page{
+		Entries: s.pages[n],
+		Cursor:  fmt.Sprint(n + 1),
+		More:    true,
+	}, nil
+}
+
+func TestPoll(t *testing.T) {
+	assert := require.New(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "SSWS token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Link", fmt.Sprintf(`<http://%s/api/v1/logs?after=2>; rel="next"`, r.Host))
+		if r.URL.Query().Get("after") != "" {
+			_, _ = w.Write([]byte(`[]`))
+			return
+		}
+		_, _ = w.Write([]byte("[" + oktaEvent("1") + "," + oktaEvent("2") + "]"))
+	}))
+	defer srv.Close()
+
+	src := &models.SourceIntegration{}
+	src.IntegrationID = "source-id"
+	src.IntegrationLabel = "okta"
+	src.IntegrationType = models.IntegrationTypePoller
+	src.PollerConfig = &models.PollerConfig{
+		Vendor:   models.PollerVendorOkta,
+		LogTypes: []string{"Okta.SystemLog"},
+		BaseURL:  srv.URL,
+	}
+	dest := &testDestination{}
+	checkpoint, err := testPoller(dest).Poll(context.Background(), src)
+	assert.NoError(err)
+	assert.Equal("source-id", checkpoint.IntegrationID)
+	assert.Equal(testNow, checkpoint.LastPollTime)
+	assert.Empty(checkpoint.LastPollError)
+	assert.Equal(map[string]string{
+		"Okta.SystemLog": srv.URL + "/api/v1/logs?after=2",
+	}, checkpoint.Cursors)
+	assert.Len(dest.events, 2)
+	for _, event := range dest.events {
+		assert.Equal("Okta.SystemLog", event.PantherLogType)
+	}
+	// The source is not modified
+	assert.Nil(src.PollerConfig.Cursors)
+
+	// The secret is not set on the source
+	assert.Empty(src.PollerConfig.ClientSecret)
+
+	// Errors are stored in the checkpoint
+	poller := testPoller(dest)
+	poller.LoadSecret = func(_ context.Context, _ string) (string, error) {
+		return "", errors.New("access denied")
+	}
+	checkpoint, err = poller.Poll(context.Background(), src)
+	assert.Error(err)
+	assert.Contains(checkpoint.LastPollError, "failed to load client secret")
+
+	src.PollerConfig.LogTypes = []string{"Duo.Authentication"}
+	checkpoint, err = testPoller(dest).Poll(context.Background(), src)
+	assert.Error(err)
+	assert.Contains(checkpoint.LastPollError, "Duo.Authentication")
+}
+
+func TestPollStream(t *testing.T) {
+	src := &models.SourceIntegration{}
+	src.IntegrationID = "source-id"
+	src.IntegrationType = models.IntegrationTypePoller
+	src.PollerConfig = &models.PollerConfig{
+		Vendor:   models.PollerVendorOkta,
+		LogTypes: []string{"Okta.SystemLog"},
+	}
+	pages := [][]json.RawMessage{
+		{json.RawMessage(oktaEvent("1"))},
+		{json.RawMessage(oktaEvent("2")), json.RawMessage(oktaEvent("3"))},
+		{json.RawMessage(oktaEvent("4"))},
+		{json.RawMessage(oktaEvent("5"))},
+	}
+
+	t.Run("max pages", func(t *testing.T) {
+		dest := &testDestination{}
+		cursor, err := testPoller(dest).pollStream(context.Background(), src, &testStream{pages: pages}, "")
+		require.NoError(t, err)
+		require.Equal(t, "3", cursor)
+		require.Len(t, dest.events, 4)
+	})
+	t.Run("resume", func(t *testing.T) {
+		dest := &testDestination{}
+		cursor, err := testPoller(dest).pollStream(context.Background(), src, &testStream{pages: pages}, "3")
+		require.NoError(t, err)
+		require.Equal(t, "4", cursor)
+		require.Len(t, dest.events, 1)
+	})
+	t.Run("pull error", func(t *testing.T) {
+		// The events pulled before the error are stored
+		dest := &testDestination{}
+		stream := &testStream{pages: pages[:1], err: errors.New("failed")}
+		cursor, err := testPoller(dest).pollStream(context.Background(), src, stream, "")
+		require.Error(t, err)
+		require.Equal(t, "1", cursor)
+		require.Len(t, dest.events, 1)
+	})
+	t.Run("destination error", func(t *testing.T) {
+		// The cursor does not advance
+		dest := &testDestination{err: errors.New("failed")}
+		cursor, err := testPoller(dest).pollStream(context.Background(), src, &testStream{pages: pages}, "1")
+		require.Error(t, err)
+		require.Equal(t, "1", cursor)
+	})
+}
@@ -0,0 +1,89 @@
+package poller
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/lambda/lambdaiface"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/pkg/genericapi"
+)
+
+const (
+	sourceAPIFunctionName = "panther-source-api"
+	// Do not start polling a source if the invocation is about to time out
+	minPollTime = 30 * time.Second
+)
+
+// Scheduler polls the SaaS poller sources that are due and stores their checkpoints on the source records.
+type Scheduler struct {
+	LambdaClient lambdaiface.LambdaAPI
+	Poller       *Poller
+}
+
+// Run polls all sources that are due
+func (s *Scheduler) Run(ctx context.Context) error {
+	var sources []*models.SourceIntegration
+	err := genericapi.Invoke(s.LambdaClient, sourceAPIFunctionName, &models.LambdaInput{
+		ListIntegrations: &models.ListIntegrationsInput{
+			IntegrationType: aws.String(models.IntegrationTypePoller),
+		},
+	}, &sources)
+	if err != nil {
+		return errors.Wrap(err, "failed to list SaaS poller sources")
+	}
+
+	var runErr error
+	for _, src := range sources {
+		if src.PollerConfig == nil || !src.PollerConfig.PollDue(s.Poller.now()) {
+			zap.L().Debug("skipping source", zap.String("sourceId", src.IntegrationID))
+			continue
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < minPollTime {
+			// The remaining sources are due, they will be polled by the next run
+			zap.L().Warn("not enough time left to poll sources")
+			break
+		}
+		checkpoint, err := s.Poller.Poll(ctx, src)
+		if err != nil {
+			// Errors are stored on the source, keep polling the other sources
+			zap.L().Error("failed to poll source",
+				zap.String("sourceId", src.IntegrationID),
+				zap.String("vendor", src.PollerConfig.Vendor),
+				zap.Error(err))
+		}
+		err = genericapi.Invoke(s.LambdaClient, sourceAPIFunctionName, &models.LambdaInput{
+			UpdatePollerCheckpoint: checkpoint,
+		}, nil)
+		if err != nil {
+			// The events will be pulled again by the next poll
+			zap.L().Error("failed to store source checkpoint", zap.String("sourceId", src.IntegrationID), zap.Error(err))
+			if runErr == nil {
+				runErr = errors.Wrapf(err, "failed to store checkpoint of source %s", src.IntegrationID)
+			}
+		}
+	}
+	return runErr
+}
@@ -0,0 +1,93 @@
+package poller
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/lambda"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/pkg/testutils"
+)
+
+func invokeAction(action string) interface{} {
+	return mock.MatchedBy(func(input *lambda.InvokeInput) bool {
+		return strings.Contains(string(input.Payload), `"`+action+`":{`)
+	})
+}
+
+func TestScheduler(t *testing.T) {
+	assert := require.New(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("[" + oktaEvent("1") + "]"))
+	}))
+	defer srv.Close()
+
+	lastPoll := testNow.Add(-time.Minute)
+	newSource := func(id string, lastPollTime *time.Time) *models.SourceIntegration {
+		src := &models.SourceIntegration{}
+		src.IntegrationID = id
+		src.IntegrationType = models.IntegrationTypePoller
+		src.PollerConfig = &models.PollerConfig{
+			Vendor:       models.PollerVendorOkta,
+			LogTypes:     []string{"Okta.SystemLog"},
+			BaseURL:      srv.URL,
+			LastPollTime: lastPollTime,
+		}
+		return src
+	}
+	sources := []*models.SourceIntegration{
+		newSource("due", nil),
+		newSource("not-due", &lastPoll),
+	}
+	payload, err := jsoniter.Marshal(sources)
+	assert.NoError(err)
+
+	lambdaMock := &testutils.LambdaMock{}
+	lambdaMock.On("Invoke", invokeAction("listIntegrations")).Return(&lambda.InvokeOutput{Payload: payload}, nil).Once()
+	var checkpoints []*models.UpdatePollerCheckpointInput
+	lambdaMock.On("Invoke", invokeAction("updatePollerCheckpoint")).Return(&lambda.InvokeOutput{}, nil).Run(func(args mock.Arguments) {
+		var input models.LambdaInput
+		assert.NoError(jsoniter.Unmarshal(args.Get(0).(*lambda.InvokeInput).Payload, &input))
+		checkpoints = append(checkpoints, input.UpdatePollerCheckpoint)
+	})
+
+	dest := &testDestination{}
+	scheduler := &Scheduler{
+		LambdaClient: lambdaMock,
+		Poller:       testPoller(dest),
+	}
+	assert.NoError(scheduler.Run(context.Background()))
+	lambdaMock.AssertExpectations(t)
+	assert.Len(checkpoints, 1)
+	assert.Equal("due", checkpoints[0].IntegrationID)
+	assert.Equal(testNow, checkpoints[0].LastPollTime)
+	assert.Equal(srv.URL+"/api/v1/logs?limit=1000&since=2020-09-30T12%3A00%3A00Z&sortOrder=ASCENDING",
+		checkpoints[0].Cursors["Okta.SystemLog"])
+	assert.Len(dest.events, 1)
+}
@@ -0,0 +1,170 @@
+package poller
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/parsers/slacklogs"
+)
+
+const (
+	slackAuditURL = "https://api.slack.com"
+	slackWebURL   = "https://slack.com"
+)
+
+// slackStream pulls a Slack log.
+// All Slack logs are returned newest first, so the stream pages through all events newer than the cursor time
+// before moving the cursor to the newest event.
+// Audit logs page with a cursor, access and integration logs page with page numbers.
+// See https://api.slack.com/admins/audit-logs and https://api.slack.com/methods/team.accessLogs
+type slackStream struct {
+	api     *APIClient
+	token   string
+	logType string
+	// The URL of the API method
+	url string
+	// The field holding the entries in the response
+	entriesField string
+	// The field holding the unix timestamp of the entries
+	timeField string
+}
+
+func newSlackStreams(api *APIClient, config *models.PollerConfig) ([]Stream, error) {
+	auditURL, err := api.baseURL(config, slackAuditURL)
+	if err != nil {
+		return nil, err
+	}
+	webURL, err := api.baseURL(config, slackWebURL)
+	if err != nil {
+		return nil, err
+	}
+	streams := make([]Stream, len(config.LogTypes))
+	for i, logType := range config.LogTypes {
+		s := &slackStream{
+			api:     api,
+			token:   config.ClientSecret,
+			logType: logType,
+		}
+		switch logType {
+		case slacklogs.TypeAuditLogs:
+			s.url, s.entriesField, s.timeField = auditURL+"/audit/v1/logs", "entries", "date_create"
+		case slacklogs.TypeAccessLogs:
+			s.url, s.entriesField, s.timeField = webURL+"/api/team.accessLogs", "logins", "date_last"
+		case slacklogs.TypeIntegrationLogs:
+			s.url, s.entriesField, s.timeField = webURL+"/api/team.integrationLogs", "logs", "date"
+		}
+		streams[i] = s
+	}
+	return streams, nil
+}
+
+func (s *slackStream) LogType() string {
+	return s.logType
+}
+
+func (s *slackStream) Next(ctx context.Context, cur string) (*Page, error) {
+	c, err := parseCursor(cur)
+	if err != nil {
+		return nil, err
+	}
+	since := c.Time("since", s.api.Start).Unix()
+	page := 1
+	if p, err := strconv.Atoi(c.Get("page")); err == nil && p > 0 {
+		page = p
+	}
+
+	params := url.Values{}
+	if s.logType == slacklogs.TypeAuditLogs {
+		params.Set("oldest", strconv.FormatInt(since+1, 10))
+		params.Set("limit", "1000")
+		if apiCursor := c.Get("cursor"); apiCursor != "" {
+			params.Set("cursor", apiCursor)
+		}
+	} else {
+		params.Set("count", "1000")
+		params.Set("page", strconv.Itoa(page))
+	}
+	req, err := http.NewRequest(http.MethodGet, s.url+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid Slack request")
+	}
+	req.Header.Set("Authorization", "Bearer "+s.token)
+	var reply map[string]json.RawMessage
+	if _, err := s.api.Do(ctx, req, &reply); err != nil {
+		return nil, err
+	}
+	// The Web API replies with errors in the body
+	if ok, hasOK := reply["ok"]; hasOK && string(ok) != "true" {
+		return nil, errors.Errorf("Slack API error: %s", jsoniter.Get(reply["error"]).ToString())
+	}
+	var entries []json.RawMessage
+	if data := reply[s.entriesField]; len(data) > 0 {
+		if err := jsoniter.Unmarshal(data, &entries); err != nil {
+			return nil, errors.Wrap(err, "invalid Slack response")
+		}
+	}
+
+	newest := c.Time("newest", time.Unix(since, 0)).Unix()
+	// Entries older than the cursor were pulled by a previous poll
+	reachedSince := false
+	newEntries := entries[:0]
+	for _, entry := range entries {
+		tm := jsoniter.Get(entry, s.timeField).ToInt64()
+		if tm <= since {
+			reachedSince = true
+			continue
+		}
+		if tm > newest {
+			newest = tm
+		}
+		newEntries = append(newEntries, entry)
+	}
+
+	next := cursor{Values: url.Values{}}
+	more := false
+	if len(entries) > 0 && !reachedSince {
+		if s.logType == slacklogs.TypeAuditLogs {
+			apiCursor := jsoniter.Get(reply["response_metadata"], "next_cursor").ToString()
+			if apiCursor != "" {
+				next.Set("cursor", apiCursor)
+				more = true
+			}
+		} else if page < jsoniter.Get(reply["paging"], "pages").ToInt() {
+			next.Set("page", strconv.Itoa(page+1))
+			more = true
+		}
+	}
+	if more {
+		next.SetTime("since", time.Unix(since, 0))
+		next.SetTime("newest", time.Unix(newest, 0))
+	} else {
+		next.SetTime("since", time.Unix(newest, 0))
+	}
+	return &Page{Entries: newEntries, Cursor: next.Encode(), More: more}, nil
+}
@@ -0,0 +1,453 @@
+package poller
+
+/**
+ * Panther is a Cloud-Native SIEM for the Modern Security Team.
+ * Copyright (C) 2020 Panther Labs Inc
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/panther-labs/panther/api/lambda/source/models"
+	"github.com/panther-labs/panther/internal/log_analysis/log_processor/registry"
+)
+
+func testAPIClient() *APIClient {
+	return &APIClient{
+		Client:    http.DefaultClient,
+		allowHTTP: true,
+		Start:     testNow.Add(-time.Hour),
+		Now: func() time.Time {
+			return testNow
+		},
+	}
+}
+
+func newTestStream(t *testing.T, config *models.PollerConfig) Stream {
+	streams, err := NewStreams(config, testAPIClient())
+	require.NoError(t, err)
+	require.Len(t, streams, 1)
+	return streams[0]
+}
+
+// pull reads pages until there are no more events available
+func pull(t *testing.T, stream Stream, cursor string) (entries []string, next string) {
+	next = cursor
+	for i := 0; i < 10; i++ {
+		page, err := stream.Next(context.Background(), next)
+		require.NoError(t, err)
+		for _, entry := range page.Entries {
+			entries = append(entries, string(entry))
+		}
+		next = page.Cursor
+		if !page.More {
+			return entries, next
+		}
+	}
+	require.Fail(t, "too many pages")
+	return nil, ""
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func TestNewStreams(t *testing.T) {
+	_, err := NewStreams(&models.PollerConfig{Vendor: "foo"}, testAPIClient())
+	require.Error(t, err)
+	_, err = NewStreams(&models.PollerConfig{
+		Vendor:   models.PollerVendorSlack,
+		LogTypes: []string{"Okta.SystemLog"},
+	}, testAPIClient())
+	require.Error(t, err)
+	_, err = NewStreams(&models.PollerConfig{
+		Vendor:   models.PollerVendorOkta,
+		LogTypes: []string{"Okta.SystemLog"},
+	}, testAPIClient())
+	require.Error(t, err, "Okta sources need a base URL")
+	streams, err := NewStreams(&models.PollerConfig{
+		Vendor:   models.PollerVendorSlack,
+		LogTypes: []string{"Slack.AuditLogs", "Slack.AccessLogs"},
+	}, testAPIClient())
+	require.NoError(t, err)
+	require.Len(t, streams, 2)
+	require.Equal(t, "Slack.AuditLogs", streams[0].LogType())
+	require.Equal(t, "Slack.AccessLogs", streams[1].LogType())
+}
+
+func TestBaseURL(t *testing.T) {
+	assert := require.New(t)
+	api := &APIClient{}
+	config := &models.PollerConfig{Vendor: models.PollerVendorOkta, BaseURL: "https://example.okta.com/"}
+	base, err := api.baseURL(config, "")
+	assert.NoError(err)
+	assert.Equal("https://example.okta.com", base)
+	// Credentials are never sent without TLS
+	config.BaseURL = "http://example.okta.com"
+	_, err = api.baseURL(config, "")
+	assert.Error(err)
+	config.BaseURL = "example.okta.com"
+	_, err = api.baseURL(config, "")
+	assert.Error(err)
+	// Local test servers use http
+	api.allowHTTP = true
+	config.BaseURL = "http://127.0.0.1:8080"
+	base, err = api.baseURL(config, "")
+	assert.NoError(err)
+	assert.Equal("http://127.0.0.1:8080", base)
+}
+
+func TestOktaStream(t *testing.T) {
+	assert := require.New(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal("/api/v1/logs", r.URL.Path)
+		assert.Equal("SSWS token", r.Header.Get("Authorization"))
+		next := fmt.Sprintf("http://%s/api/v1/logs?after=", r.Host)
+		switch after := r.URL.Query().Get("after"); after {
+		case "":
+			assert.Equal(testNow.Add(-time.Hour).Format(time.RFC3339), r.URL.Query().Get("since"))
+			w.Header().Add("Link", fmt.Sprintf(`<http://%s/api/v1/logs?since=foo>; rel="self"`, r.Host))
+			w.Header().Add("Link", fmt.Sprintf(`<%s1>; rel="next"`, next))
+			_, _ = w.Write([]byte(`[{"uuid":"1"}]`))
+		case "1":
+			w.Header().Add("Link", fmt.Sprintf(`<%s2>; rel="next"`, next))
+			_, _ = w.Write([]byte(`[{"uuid":"2"}]`))
+		default:
+			w.Header().Add("Link", fmt.Sprintf(`<%s2>; rel="next"`, next))
+			_, _ = w.Write([]byte(`[]`))
+		}
+	}))
+	defer srv.Close()
+
+	stream := newTestStream(t, &models.PollerConfig{
+		Vendor:       models.PollerVendorOkta,
+		LogTypes:     []string{"Okta.SystemLog"},
+		BaseURL:      srv.URL,
+		ClientSecret: "token",
+	})
+	entries, cursor := pull(t, stream, "")
+	assert.Equal([]string{`{"uuid":"1"}`, `{"uuid":"2"}`}, entries)
+	assert.Equal(srv.URL+"/api/v1/logs?after=2", cursor)
+
+	// The API token is only sent to the Okta domain
+	_, err := stream.Next(context.Background(), "https://example.com/api/v1/logs")
+	assert.Error(err)
+}
+
+func TestDuoStream(t *testing.T) {
+	assert := require.New(t)
+	adminTime := testNow.Add(-30 * time.Minute).Unix()
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Check the request signature
+		auth, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(r.Header.Get("Authorization"), "Basic "))
+		assert.NoError(err)
+		u, _ := url.Parse(srv.URL)
+		expect := duoSign("secret", r.Header.Get("Date"), r.Method, u.Host, r.URL.Path, r.URL.Query())
+		assert.Equal("ikey:"+expect, string(auth))
+
+		query := r.URL.Query()
+		switch r.URL.Path {
+		case "/admin/v2/logs/authentication":
+			assert.Equal(formatMillis(testNow.Add(-duoDelay)), query.Get("maxtime"))
+			if query.Get("next_offset") == "" {
+				assert.Equal(formatMillis(testNow.Add(-time.Hour)), query.Get("mintime"))
+				writeJSON(w, map[string]interface{}{
+					"stat": "OK",
+					"response": map[string]interface{}{
+						"authlogs": []interface{}{map[string]string{"txid": "1"}},
+						"metadata": map[string]interface{}{"next_offset": []string{"1600000000000", "1"}},
+					},
+				})
+				return
+			}
+			assert.Equal("1600000000000,1", query.Get("next_offset"))
+			writeJSON(w, map[string]interface{}{
+				"stat": "OK",
+				"response": map[string]interface{}{
+					"authlogs": []interface{}{map[string]string{"txid": "2"}},
+					"metadata": map[string]interface{}{},
+				},
+			})
+		case "/admin/v1/logs/administrator":
+			assert.Equal(fmt.Sprint(testNow.Add(-time.Hour).Unix()), query.Get("mintime"))
+			writeJSON(w, map[string]interface{}{
+				"stat":     "OK",
+				"response": []interface{}{map[string]int64{"timestamp": adminTime}, map[string]int64{"timestamp": adminTime + 10}},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	streams, err := NewStreams(&models.PollerConfig{
+		Vendor:       models.PollerVendorDuo,
+		LogTypes:     []string{"Duo.Authentication", "Duo.Administrator"},
+		BaseURL:      srv.URL,
+		ClientID:     "ikey",
+		ClientSecret: "secret",
+	}, testAPIClient())
+	assert.NoError(err)
+
+	entries, cursor := pull(t, streams[0], "")
+	assert.Equal([]string{`{"txid":"1"}`, `{"txid":"2"}`}, entries)
+	c, err := parseCursor(cursor)
+	assert.NoError(err)
+	assert.Equal(testNow.Add(-duoDelay).Add(time.Millisecond), c.Time("mintime", time.Time{}))
+
+	entries, cursor = pull(t, streams[1], "")
+	assert.Len(entries, 2)
+	c, err = parseCursor(cursor)
+	assert.NoError(err)
+	assert.Equal(time.Unix(adminTime+11, 0).UTC(), c.Time("mintime", time.Time{}))
+}
+
+func TestOneLoginStream(t *testing.T) {
+	assert := require.New(t)
+	tokens := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/auth/oauth2/v2/token":
+			tokens++
+			assert.Equal("client_id:id, client_secret:secret", r.Header.Get("Authorization"))
+			writeJSON(w, map[string]interface{}{"access_token": "token", "expires_in": 36000})
+		case "/api/1/events":
+			assert.Equal("bearer:token", r.Header.Get("Authorization"))
+			if r.URL.Query().Get("after_cursor") == "" {
+				writeJSON(w, map[string]interface{}{
+					"pagination": map[string]string{"after_cursor": "next"},
+					"data": []interface{}{map[string]interface{}{
+						"id": 1234567890, "account_id": 1, "event_type_id": 5, "created_at": "2020-10-01T11:30:00.123Z",
+					}},
+				})
+				return
+			}
+			writeJSON(w, map[string]interface{}{
+				"pagination": map[string]interface{}{"after_cursor": nil},
+				"data": []interface{}{map[string]interface{}{
+					"id": 1234567889, "account_id": 1, "event_type_id": 5, "created_at": "2020-10-01T11:20:00Z",
+				}},
+			})
+		}
+	}))
+	defer srv.Close()
+
+	stream := newTestStream(t, &models.PollerConfig{
+		Vendor:       models.PollerVendorOneLogin,
+		LogTypes:     []string{"OneLogin.Events"},
+		BaseURL:      srv.URL,
+		ClientID:     "id",
+		ClientSecret: "secret",
+	})
+	entries, cursor := pull(t, stream, "")
+	assert.Len(entries, 2)
+	assert.Equal(1, tokens)
+	c, err := parseCursor(cursor)
+	assert.NoError(err)
+	assert.Equal(time.Date(2020, 10, 1, 11, 30, 0, 124000000, time.UTC), c.Time("since", time.Time{}))
+
+	// The events are converted to the format of the log type
+	assert.Equal(int64(1234567890), jsoniter.Get([]byte(entries[0]), "id").ToInt64())
+	assert.Equal("2020-10-01 11:30:00 UTC", jsoniter.Get([]byte(entries[0]), "event_timestamp").ToString())
+	parser, err := registry.NativeParsersResolver().ResolveParser(context.Background(), "OneLogin.Events")
+	assert.NoError(err)
+	for _, entry := range entries {
+		results, err := parser.ParseLog(entry)
+		assert.NoError(err)
+		assert.Len(results, 1)
+	}
+}
+
+func TestBoxStream(t *testing.T) {
+	assert := require.New(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/oauth2/token":
+			assert.NoError(r.ParseForm())
+			assert.Equal("client_credentials", r.PostForm.Get("grant_type"))
+			assert.Equal("12345", r.PostForm.Get("box_subject_id"))
+			writeJSON(w, map[string]interface{}{"access_token": "token", "expires_in": 3600})
+		case "/2.0/events":
+			assert.Equal("Bearer token", r.Header.Get("Authorization"))
+			assert.Equal("admin_logs", r.URL.Query().Get("stream_type"))
+			if r.URL.Query().Get("stream_position") == "" {
+				assert.NotEmpty(r.URL.Query().Get("created_after"))
+				_, _ = w.Write([]byte(`{"chunk_size":1,"next_stream_position":1152922976252290886,"entries":[{"event_id":"1"}]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"chunk_size":0,"next_stream_position":"1152922976252290887","entries":[]}`))
+		}
+	}))
+	defer srv.Close()
+
+	stream := newTestStream(t, &models.PollerConfig{
+		Vendor:       models.PollerVendorBox,
+		LogTypes:     []string{"Box.Event"},
+		BaseURL:      srv.URL,
+		ClientID:     "id",
+		ClientSecret: "secret",
+		Subject:      "12345",
+	})
+	entries, cursor := pull(t, stream, "")
+	assert.Equal([]string{`{"event_id":"1"}`}, entries)
+	assert.Equal("1152922976252290886", cursor)
+	entries, cursor = pull(t, stream, cursor)
+	assert.Empty(entries)
+	assert.Equal("1152922976252290887", cursor)
+}
+
+func TestGSuiteStream(t *testing.T) {
+	assert := require.New(t)
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(err)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			assert.NoError(r.ParseForm())
+			parts := strings.Split(r.PostForm.Get("assertion"), ".")
+			assert.Len(parts, 3)
+			claims, err := base64.RawURLEncoding.DecodeString(parts[1])
+			assert.NoError(err)
+			assert.Equal("admin@example.com", jsoniter.Get(claims, "sub").ToString())
+			assert.Equal(gsuiteScope, jsoniter.Get(claims, "scope").ToString())
+			writeJSON(w, map[string]interface{}{"access_token": "token", "expires_in": 3600})
+			return
+		}
+		assert.Equal("Bearer token", r.Header.Get("Authorization"))
+		app := strings.TrimPrefix(r.URL.Path, "/admin/reports/v1/activity/users/all/applications/")
+		if app != "login" {
+			writeJSON(w, map[string]interface{}{"kind": "admin#reports#activities"})
+			return
+		}
+		if r.URL.Query().Get("pageToken") == "" {
+			writeJSON(w, map[string]interface{}{
+				"items":         []interface{}{map[string]interface{}{"id": map[string]string{"time": "2020-10-01T11:50:00.000Z"}}},
+				"nextPageToken": "next",
+			})
+			return
+		}
+		writeJSON(w, map[string]interface{}{
+			"items": []interface{}{map[string]interface{}{"id": map[string]string{"time": "2020-10-01T11:40:00.000Z"}}},
+		})
+	}))
+	defer srv.Close()
+
+	account, err := jsoniter.MarshalToString(map[string]string{
+		"client_email": "poller@example.iam.gserviceaccount.com",
+		"private_key":  string(keyPEM),
+		"token_uri":    srv.URL + "/token",
+	})
+	assert.NoError(err)
+	stream := newTestStream(t, &models.PollerConfig{
+		Vendor:       models.PollerVendorGSuite,
+		LogTypes:     []string{"GSuite.Reports"},
+		BaseURL:      srv.URL,
+		ClientSecret: account,
+		Subject:      "admin@example.com",
+	})
+	entries, cursor := pull(t, stream, "")
+	assert.Len(entries, 2)
+	c, err := parseCursor(cursor)
+	assert.NoError(err)
+	assert.Equal(time.Date(2020, 10, 1, 11, 50, 0, int(time.Millisecond), time.UTC), c.Time("login.since", time.Time{}))
+	assert.Equal(testNow.Add(-time.Hour), c.Time("admin.since", time.Time{}))
+}
+
+func TestSlackStream(t *testing.T) {
+	assert := require.New(t)
+	since := testNow.Add(-time.Hour).Unix()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal("Bearer token", r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/audit/v1/logs":
+			assert.Equal(fmt.Sprint(since+1), r.URL.Query().Get("oldest"))
+			if r.URL.Query().Get("cursor") == "" {
+				writeJSON(w, map[string]interface{}{
+					"entries":           []interface{}{map[string]int64{"date_create": since + 20}},
+					"response_metadata": map[string]string{"next_cursor": "next"},
+				})
+				return
+			}
+			writeJSON(w, map[string]interface{}{
+				"entries":           []interface{}{map[string]int64{"date_create": since + 10}},
+				"response_metadata": map[string]string{"next_cursor": ""},
+			})
+		case "/api/team.accessLogs":
+			page := r.URL.Query().Get("page")
+			if page == "1" {
+				writeJSON(w, map[string]interface{}{
+					"ok":     true,
+					"logins": []interface{}{map[string]int64{"date_last": since + 30}},
+					"paging": map[string]int{"page": 1, "pages": 3},
+				})
+				return
+			}
+			// Older entries were pulled by a previous poll
+			assert.Equal("2", page)
+			writeJSON(w, map[string]interface{}{
+				"ok":     true,
+				"logins": []interface{}{map[string]int64{"date_last": since + 5}, map[string]int64{"date_last": since}},
+				"paging": map[string]int{"page": 2, "pages": 3},
+			})
+		case "/api/team.integrationLogs":
+			writeJSON(w, map[string]interface{}{"ok": false, "error": "not_allowed_token_type"})
+		}
+	}))
+	defer srv.Close()
+
+	streams, err := NewStreams(&models.PollerConfig{
+		Vendor:       models.PollerVendorSlack,
+		LogTypes:     []string{"Slack.AuditLogs", "Slack.AccessLogs", "Slack.IntegrationLogs"},
+		BaseURL:      srv.URL,
+		ClientSecret: "token",
+	}, testAPIClient())
+	assert.NoError(err)
+
+	entries, cursor := pull(t, streams[0], "")
+	assert.Len(entries, 2)
+	c, err := parseCursor(cursor)
+	assert.NoError(err)
+	assert.Equal(since+20, c.Time("since", time.Time{}).Unix())
+
+	entries, cursor = pull(t, streams[1], "")
+	assert.Len(entries, 2)
+	c, err = parseCursor(cursor)
+	assert.NoError(err)
+	assert.Equal(since+30, c.Time("since", time.Time{}).Unix())
+
+	_, err = streams[2].Next(context.Background(), "")
+	assert.Error(err)
+	assert.Contains(err.Error(), "not_allowed_token_type")
+}
@@ -39,6 +39,8 @@ import (
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager/s3manageriface"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
 	"github.com/aws/aws-sdk-go/service/sns"
 	"github.com/aws/aws-sdk-go/service/sns/snsiface"
 	"github.com/aws/aws-sdk-go/service/sqs"
@@ -186,6 +188,16 @@ func (m *DynamoDBMock) UpdateItem(input *dynamodb.UpdateItemInput) (*dynamodb.Up
 	return args.Get(0).(*dynamodb.UpdateItemOutput), args.Error(1)
 }
 
+func (m *DynamoDBMock) UpdateItemWithContext(
+	ctx context.Context,
+	input *dynamodb.UpdateItemInput,
+	_ ...request.Option,
+) (*dynamodb.UpdateItemOutput, error) {
+
+	args := m.Called(ctx, input)
+	return args.Get(0).(*dynamodb.UpdateItemOutput), args.Error(1)
+}
+
 func (m *DynamoDBMock) GetItem(input *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error) {
 	args := m.Called(input)
 	return args.Get(0).(*dynamodb.GetItemOutput), args.Error(1)
@@ -201,6 +213,18 @@ func (m *DynamoDBMock) Query(input *dynamodb.QueryInput) (*dynamodb.QueryOutput,
 	return args.Get(0).(*dynamodb.QueryOutput), args.Error(1)
 }
 
+func (m *DynamoDBMock) QueryPagesWithContext(
+	ctx context.Context,
+	input *dynamodb.QueryInput,
+	scan func(page *dynamodb.QueryOutput, isLast bool) bool,
+	_ ...request.Option,
+) error {
+
+	args := m.Called(ctx, input, scan)
+	scan(args.Get(0).(*dynamodb.QueryOutput), true)
+	return args.Error(1)
+}
+
 func (m *DynamoDBMock) Scan(input *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
 	args := m.Called(input)
 	return args.Get(0).(*dynamodb.ScanOutput), args.Error(1)
@@ -456,3 +480,44 @@ func (m *FirehoseMock) PutRecordBatchWithContext(
 	args := m.Called(ctx, input, options)
 	return args.Get(0).(*firehose.PutRecordBatchOutput), args.Error(1)
 }
+
+type SecretsManagerMock struct {
+	secretsmanageriface.SecretsManagerAPI
+	mock.Mock
+}
+
+func (m *SecretsManagerMock) CreateSecretWithContext(
+	ctx aws.Context,
+	input *secretsmanager.CreateSecretInput,
+	options ...request.Option) (*secretsmanager.CreateSecretOutput, error) {
+
+	args := m.Called(ctx, input, options)
+	return args.Get(0).(*secretsmanager.CreateSecretOutput), args.Error(1)
+}
+
+func (m *SecretsManagerMock) PutSecretValueWithContext(
+	ctx aws.Context,
+	input *secretsmanager.PutSecretValueInput,
+	options ...request.Option) (*secretsmanager.PutSecretValueOutput, error) {
+
+	args := m.Called(ctx, input, options)
+	return args.Get(0).(*secretsmanager.PutSecretValueOutput), args.Error(1)
+}
+
+func (m *SecretsManagerMock) GetSecretValueWithContext(
+	ctx aws.Context,
+	input *secretsmanager.GetSecretValueInput,
+	options ...request.Option) (*secretsmanager.GetSecretValueOutput, error) {
+
+	args := m.Called(ctx, input, options)
+	return args.Get(0).(*secretsmanager.GetSecretValueOutput), args.Error(1)
+}
+
+func (m *SecretsManagerMock) DeleteSecretWithContext(
+	ctx aws.Context,
+	input *secretsmanager.DeleteSecretInput,
+	options ...request.Option) (*secretsmanager.DeleteSecretOutput, error) {
+
+	args := m.Called(ctx, input, options)
+	return args.Get(0).(*secretsmanager.DeleteSecretOutput), args.Error(1)
+}